@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	_ "github.com/joho/godotenv/autoload"
+	"github.com/satimoto/go-datastore/pkg/db"
+	"github.com/satimoto/go-datastore/pkg/node"
+	"github.com/satimoto/go-datastore/pkg/util"
+	"github.com/satimoto/go-lnm/internal/channel"
+)
+
+var (
+	database *sql.DB
+
+	dbHost  = os.Getenv("DB_HOST")
+	dbName  = os.Getenv("DB_NAME")
+	dbPass  = os.Getenv("DB_PASS")
+	dbUser  = os.Getenv("DB_USER")
+	sslMode = util.GetEnv("SSL_MODE", "disable")
+)
+
+func init() {
+	if len(dbHost) == 0 || len(dbName) == 0 || len(dbPass) == 0 || len(dbUser) == 0 {
+		log.Fatalf("Database env variables not defined")
+	}
+
+	dataSourceName := fmt.Sprintf("postgres://%s:%s@%s/%s?binary_parameters=yes&sslmode=%s", dbUser, dbPass, dbHost, dbName, sslMode)
+	d, err := sql.Open("postgres", dataSourceName)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	database = d
+}
+
+// Imports the hand maintained channels.csv into the channels of a node. The
+// node is the "me" row of the file unless a pubkey is given.
+func main() {
+	defer database.Close()
+
+	filename := flag.String("file", "data/channels.csv", "channels.csv file to import")
+	pubkey := flag.String("pubkey", "", "pubkey of the node, defaults to the \"me\" row")
+	flag.Parse()
+
+	file, err := os.Open(*filename)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer file.Close()
+
+	records, err := channel.ParseChannelsCsv(file)
+
+	if err != nil {
+		log.Fatalf("Error parsing %v: %v", *filename, err)
+	}
+
+	for _, record := range records {
+		if len(*pubkey) == 0 && record.Type == channel.CHANNEL_RECORD_TYPE_ME {
+			*pubkey = record.RemotePubkey
+		}
+	}
+
+	ctx := context.Background()
+	repositoryService := db.NewRepositoryService(database)
+	n, err := node.NewRepository(repositoryService).GetNodeByPubkey(ctx, *pubkey)
+
+	if err != nil {
+		log.Fatalf("Error getting node %v: %v", *pubkey, err)
+	}
+
+	imported, err := channel.NewResolver(repositoryService).ImportChannels(ctx, n.ID, records)
+
+	if err != nil {
+		log.Fatalf("Error importing channels: %v", err)
+	}
+
+	log.Printf("Imported %v channels for node %v", imported, n.Pubkey)
+}
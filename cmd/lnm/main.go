@@ -54,15 +54,18 @@ func main() {
 	shutdownCtx, cancelFunc := context.WithCancel(context.Background())
 	waitGroup := &sync.WaitGroup{}
 
-	services := service.NewService()
+	services := service.NewService(repositoryService)
 	services.FerpService.Start(shutdownCtx, waitGroup)
+	services.LightningNodes.Start(shutdownCtx, waitGroup)
+	services.LeaseService.Start(shutdownCtx, waitGroup)
+	services.SessionScheduler.Start(shutdownCtx, waitGroup)
 
 	metricsService := metrics.NewMetrics()
 	metricsService.StartMetrics(shutdownCtx, waitGroup)
 
 	monitorService := monitor.NewMonitor(shutdownCtx, repositoryService, services)
 
-	restService := rest.NewRest(database)
+	restService := rest.NewRest(database, services)
 	restService.StartRest(shutdownCtx, waitGroup)
 
 	rpcService := rpc.NewRpc(shutdownCtx, database, services, monitorService)
@@ -16,14 +16,19 @@ type Backup interface {
 
 type BackupService struct {
 	S3Backup   s3.S3Backup
+	prefix     string
 }
 
-func NewService() Backup {
+// NewService creates a backup service. Backups are named with the prefix
+// when set, so the backups of each node are kept apart.
+func NewService(prefix string) Backup {
 	backupAwsRegion := os.Getenv("BACKUP_AWS_REGION")
 	backupAwsAccessKeyID := os.Getenv("BACKUP_AWS_ACCESS_KEY_ID")
 	backupAwsSecretAccessKey := os.Getenv("BACKUP_AWS_SECRET_ACCESS_KEY")
 	backupS3Bucket := os.Getenv("BACKUP_S3_BUCKET")
-	service := &BackupService{}
+	service := &BackupService{
+		prefix: prefix,
+	}
 
 	if len(backupS3Bucket) > 0 {
 		service.S3Backup = s3.NewHandler(backupAwsRegion, backupAwsAccessKeyID, backupAwsSecretAccessKey, backupS3Bucket)
@@ -39,6 +44,10 @@ func (s *BackupService) BackupChannels(data []byte) {
 func (s *BackupService) BackupChannelsWithRetry(data []byte, retries int) {
 	name := fmt.Sprintf("%s.backup", strconv.FormatInt(time.Now().Unix(), 10))
 
+	if len(s.prefix) > 0 {
+		name = fmt.Sprintf("%s/%s", s.prefix, name)
+	}
+
 	if s.S3Backup != nil {
 		s.S3Backup.BackupChannelsWithRetry(name, data, retries)
 	}
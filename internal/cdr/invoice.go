@@ -2,6 +2,7 @@ package cdr
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"log"
@@ -10,13 +11,16 @@ import (
 	"github.com/satimoto/go-datastore/pkg/db"
 	"github.com/satimoto/go-datastore/pkg/param"
 	dbUtil "github.com/satimoto/go-datastore/pkg/util"
+	"github.com/satimoto/go-ferp/pkg/rate"
+	"github.com/satimoto/go-lnm/internal/lease"
 	"github.com/satimoto/go-lnm/internal/lightningnetwork"
 	metrics "github.com/satimoto/go-lnm/internal/metric"
 	"github.com/satimoto/go-lnm/internal/notification"
+	"github.com/satimoto/go-lnm/internal/webhook"
 	"github.com/satimoto/go-lnm/pkg/util"
 )
 
-func (r *CdrResolver) IssueRebate(ctx context.Context, session db.Session, userID int64, invoiceParams util.InvoiceParams, chargeParams util.ChargeParams) {
+func (r *CdrResolver) IssueRebate(ctx context.Context, session db.Session, sessionUser db.User, invoiceParams util.InvoiceParams, chargeParams util.ChargeParams) {
 	updateUnsettledInvoices := dbUtil.GetEnvBool("UPDATE_UNSETTLED_INVOICES", false)
 
 	if updateUnsettledInvoices {
@@ -25,7 +29,7 @@ func (r *CdrResolver) IssueRebate(ctx context.Context, session db.Session, userI
 		// First try to rebute via deducting from an unsettled session invoice
 		if err == nil && invoiceParams.TotalFiat.Valid && sessionInvoice.TotalFiat > invoiceParams.TotalFiat.Float64 {
 			// An unsettled session invoice exists, try to update it
-			if updatedSessionInvoice := r.updateSessionInvoice(ctx, session, sessionInvoice, invoiceParams, chargeParams); updatedSessionInvoice != nil {
+			if updatedSessionInvoice := r.updateSessionInvoice(ctx, sessionUser, session, sessionInvoice, invoiceParams, chargeParams); updatedSessionInvoice != nil {
 				return
 			}
 		}
@@ -34,7 +38,9 @@ func (r *CdrResolver) IssueRebate(ctx context.Context, session db.Session, userI
 	// Then issue an invoice request if no session invoice exists
 	memo := fmt.Sprintf("Satimoto: %s", session.Uid)
 
-	if invoiceRequest, err := r.IssueInvoiceRequest(ctx, userID, &session.ID, "REBATE", invoiceParams.Currency, memo, invoiceParams); err == nil {
+	if invoiceRequest, err := r.IssueInvoiceRequest(ctx, sessionUser.ID, &session, "REBATE", invoiceParams.Currency, memo, invoiceParams); err == nil {
+		r.QueueRebateIssuedEvent(ctx, sessionUser.ID, *invoiceRequest, &session)
+
 		updateSessionByUidParams := param.NewUpdateSessionByUidParams(session)
 		updateSessionByUidParams.InvoiceRequestID = dbUtil.SqlNullInt64(invoiceRequest.ID)
 
@@ -47,8 +53,20 @@ func (r *CdrResolver) IssueRebate(ctx context.Context, session db.Session, userI
 	}
 }
 
-func (r *CdrResolver) IssueInvoiceRequest(ctx context.Context, userID int64, sessionID *int64, promotionCode string, currency string, memo string, invoiceParams util.InvoiceParams) (*db.InvoiceRequest, error) {
-	currencyRate, err := r.FerpService.GetRate(currency)
+// IssueInvoiceRequest issues an invoice request to the user, or adds to an
+// unsettled one. Invoice requests of a session use the rate locked for the
+// session, so rebates are priced at the same rate as the session invoices.
+func (r *CdrResolver) IssueInvoiceRequest(ctx context.Context, userID int64, session *db.Session, promotionCode string, currency string, memo string, invoiceParams util.InvoiceParams) (*db.InvoiceRequest, error) {
+	var currencyRate *rate.CurrencyRate
+	var err error
+	sessionID := sql.NullInt64{}
+
+	if session != nil {
+		sessionID = dbUtil.SqlNullInt64(session.ID)
+		currencyRate, err = r.SessionResolver.LockRate(ctx, *session, currency)
+	} else {
+		currencyRate, err = r.FerpService.GetRate(currency)
+	}
 
 	if err != nil {
 		metrics.RecordError("LNM111", "Error retrieving exchange rate", err)
@@ -72,13 +90,16 @@ func (r *CdrResolver) IssueInvoiceRequest(ctx context.Context, userID int64, ses
 		return nil, errors.New("error retrieving promotion")
 	}
 
-	rateMsat := float64(currencyRate.RateMsat)
-	invoiceParams = util.FillInvoiceRequestParams(invoiceParams, rateMsat)
+	invoiceParams = r.SessionResolver.ConversionPolicy.FillInvoiceRequestParams(invoiceParams, currencyRate.RateMsat)
 
+	// Only items priced at the same rate are added to an unsettled invoice
+	// request, so its stored rate explains its total
 	getUnsettledInvoiceRequestParams := db.GetUnsettledInvoiceRequestParams{
-		UserID:      user.ID,
-		PromotionID: promotion.ID,
-		Memo:        memo,
+		UserID:           user.ID,
+		PromotionID:      promotion.ID,
+		Memo:             memo,
+		Currency:         currency,
+		CurrencyRateMsat: currencyRate.RateMsat,
 	}
 
 	invoiceRequest, err := r.InvoiceRequestRepository.GetUnsettledInvoiceRequest(ctx, getUnsettledInvoiceRequestParams)
@@ -102,26 +123,39 @@ func (r *CdrResolver) IssueInvoiceRequest(ctx context.Context, userID int64, ses
 			return nil, errors.New("error updating invoice request")
 		}
 	} else {
+		// The invoice request is only inserted while the lease is still held
+		fence := lease.GetFence(ctx)
 		createInvoiceRequestParams := db.CreateInvoiceRequestParams{
-			UserID:         user.ID,
-			PromotionID:    promotion.ID,
-			SessionID:      dbUtil.SqlNullInt64(sessionID),
-			Currency:       currency,
-			Memo:           memo,
-			PriceFiat:      invoiceParams.PriceFiat,
-			PriceMsat:      invoiceParams.PriceMsat,
-			CommissionFiat: invoiceParams.CommissionFiat,
-			CommissionMsat: invoiceParams.CommissionMsat,
-			TaxFiat:        invoiceParams.TaxFiat,
-			TaxMsat:        invoiceParams.TaxMsat,
-			TotalFiat:      invoiceParams.TotalFiat.Float64,
-			TotalMsat:      invoiceParams.TotalMsat.Int64,
-			ReleaseDate:    invoiceParams.ReleaseDate,
-			IsSettled:      false,
+			UserID:                user.ID,
+			PromotionID:           promotion.ID,
+			SessionID:             sessionID,
+			Currency:              currency,
+			CurrencyRate:          currencyRate.Rate,
+			CurrencyRateMsat:      currencyRate.RateMsat,
+			CurrencyRateSpreadPpm: r.SessionResolver.ConversionPolicy.GetSpreadPpm(currency),
+			Memo:                  memo,
+			PriceFiat:             invoiceParams.PriceFiat,
+			PriceMsat:             invoiceParams.PriceMsat,
+			CommissionFiat:        invoiceParams.CommissionFiat,
+			CommissionMsat:        invoiceParams.CommissionMsat,
+			TaxFiat:               invoiceParams.TaxFiat,
+			TaxMsat:               invoiceParams.TaxMsat,
+			TotalFiat:             invoiceParams.TotalFiat.Float64,
+			TotalMsat:             invoiceParams.TotalMsat.Int64,
+			ReleaseDate:           invoiceParams.ReleaseDate,
+			IsSettled:             false,
+			LeaseResourceType:     fence.ResourceType,
+			LeaseResourceID:       fence.ResourceID,
+			LeaseFencingToken:     fence.FencingToken,
 		}
 
 		invoiceRequest, err = r.InvoiceRequestRepository.CreateInvoiceRequest(ctx, createInvoiceRequestParams)
 
+		if fence.FencingToken.Valid && errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Invoice request lease lost before creating: UserID=%v", user.ID)
+			return nil, lease.ErrLeaseLost
+		}
+
 		if err != nil {
 			metrics.RecordError("LNM115", "Error creating invoice request", err)
 			log.Printf("LNM115: Params=%#v", createInvoiceRequestParams)
@@ -172,8 +206,8 @@ func (r *CdrResolver) IssueInvoiceRequest(ctx context.Context, userID int64, ses
 	return &invoiceRequest, nil
 }
 
-func (r *CdrResolver) updateSessionInvoice(ctx context.Context, session db.Session, sessionInvoice db.SessionInvoice, invoiceParams util.InvoiceParams, chargeParams util.ChargeParams) *db.SessionInvoice {
-	currencyRate, err := r.FerpService.GetRate(invoiceParams.Currency)
+func (r *CdrResolver) updateSessionInvoice(ctx context.Context, sessionUser db.User, session db.Session, sessionInvoice db.SessionInvoice, invoiceParams util.InvoiceParams, chargeParams util.ChargeParams) *db.SessionInvoice {
+	currencyRate, err := r.SessionResolver.LockRate(ctx, session, invoiceParams.Currency)
 
 	if err != nil {
 		metrics.RecordError("LNM171", "Error retrieving exchange rate", err)
@@ -181,7 +215,6 @@ func (r *CdrResolver) updateSessionInvoice(ctx context.Context, session db.Sessi
 		return nil
 	}
 
-	rateMsat := float64(currencyRate.RateMsat)
 	updateInvoiceParams := util.InvoiceParams{
 		Currency:       invoiceParams.Currency,
 		PriceFiat:      util.MinusNullFloat64(dbUtil.SqlNullFloat64(sessionInvoice.PriceFiat), invoiceParams.PriceFiat),
@@ -191,7 +224,7 @@ func (r *CdrResolver) updateSessionInvoice(ctx context.Context, session db.Sessi
 	}
 
 	memo := fmt.Sprintf("Satimoto: %s", session.Uid)
-	updateInvoiceParams = util.FillInvoiceRequestParams(updateInvoiceParams, rateMsat)
+	updateInvoiceParams = r.SessionResolver.ConversionPolicy.FillInvoiceRequestParams(updateInvoiceParams, currencyRate.RateMsat)
 
 	if !invoiceParams.TotalMsat.Valid {
 		metrics.RecordError("LNM172", "Error filling request params", errors.New("invoiceParams TotalMsat not valid"))
@@ -199,7 +232,13 @@ func (r *CdrResolver) updateSessionInvoice(ctx context.Context, session db.Sessi
 		return nil
 	}
 
-	if paymentRequest, signature, err := lightningnetwork.CreateLightningInvoice(r.LightningService, memo, invoiceParams.TotalMsat.Int64); err == nil {
+	lightningService, err := r.SessionResolver.LightningNodes.GetService(sessionUser.NodeID)
+
+	if err != nil {
+		return nil
+	}
+
+	if paymentRequest, signature, err := lightningnetwork.CreateLightningInvoice(lightningService, memo, invoiceParams.TotalMsat.Int64); err == nil {
 		// Get the session invoice again to check if it's been settled or updated
 		latestSessionInvoice, err := r.SessionResolver.Repository.GetSessionInvoice(ctx, sessionInvoice.ID)
 
@@ -207,6 +246,7 @@ func (r *CdrResolver) updateSessionInvoice(ctx context.Context, session db.Sessi
 			sessionInvoiceParams := param.NewUpdateSessionInvoiceParams(latestSessionInvoice)
 			sessionInvoiceParams.CurrencyRate = currencyRate.Rate
 			sessionInvoiceParams.CurrencyRateMsat = currencyRate.RateMsat
+			sessionInvoiceParams.CurrencyRateSpreadPpm = r.SessionResolver.ConversionPolicy.GetSpreadPpm(invoiceParams.Currency)
 			sessionInvoiceParams.PriceFiat = invoiceParams.PriceFiat.Float64
 			sessionInvoiceParams.PriceMsat = invoiceParams.PriceMsat.Int64
 			sessionInvoiceParams.CommissionFiat = invoiceParams.CommissionFiat.Float64
@@ -223,7 +263,7 @@ func (r *CdrResolver) updateSessionInvoice(ctx context.Context, session db.Sessi
 			sessionInvoiceParams.MeteredEnergy = chargeParams.MeteredEnergy
 			sessionInvoiceParams.MeteredTime = chargeParams.MeteredTime
 
-			_, err := r.SessionResolver.Repository.UpdateSessionInvoice(ctx, sessionInvoiceParams)
+			updatedSessionInvoice, err := r.SessionResolver.Repository.UpdateSessionInvoice(ctx, sessionInvoiceParams)
 
 			if err != nil {
 				metrics.RecordError("LNM173", "Error updating session invoice", err)
@@ -231,6 +271,8 @@ func (r *CdrResolver) updateSessionInvoice(ctx context.Context, session db.Sessi
 				return nil
 			}
 
+			r.SessionResolver.QueueSessionInvoiceEvent(ctx, webhook.SESSION_INVOICED, updatedSessionInvoice)
+
 			go r.SessionResolver.WaitForInvoiceExpiry(paymentRequest)
 
 			return &sessionInvoice
@@ -3,6 +3,7 @@ package cdr_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/satimoto/go-datastore/pkg/db"
 	dbMocks "github.com/satimoto/go-datastore/pkg/db/mocks"
@@ -240,3 +241,59 @@ func TestIssueInvoiceRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestIssueInvoiceRequestLockedRate(t *testing.T) {
+	ctx := context.Background()
+	mockRepository := dbMocks.NewMockRepositoryService()
+	mockFerpService := ferpMocks.NewService()
+	mockLightningService := lightningnetworkMocks.NewService()
+	mockNotificationService := notificationMocks.NewService()
+	mockOcpiService := ocpiMocks.NewService()
+	mockServices := serviceMocks.NewService(mockFerpService, mockLightningService, mockNotificationService, mockOcpiService)
+	cdrResolver := cdrMocks.NewResolver(mockRepository, mockServices)
+	cdrResolver.SessionResolver.RateLockWindow = time.Hour
+
+	session := db.Session{
+		ID:               1,
+		Uid:              "SESSION0001",
+		Currency:         "EUR",
+		CurrencyRate:     dbUtil.SqlNullInt64(int64(4000)),
+		CurrencyRateMsat: dbUtil.SqlNullInt64(int64(4000000)),
+		RateLockedAt:     dbUtil.SqlNullTime(time.Now()),
+	}
+
+	// The current rate differs from the rate locked for the session
+	mockFerpService.SetGetRateMockData(&rate.CurrencyRate{
+		Rate:        4500,
+		RateMsat:    4500000,
+		LastUpdated: time.Now(),
+	})
+
+	mockRepository.SetGetSessionMockData(dbMocks.SessionMockData{Session: session})
+	mockRepository.SetGetUserMockData(dbMocks.UserMockData{User: db.User{
+		ID: 1,
+	}})
+	mockRepository.SetGetPromotionByCodeMockData(dbMocks.PromotionMockData{Promotion: db.Promotion{
+		Code: "REBATE",
+	}})
+
+	_, err := cdrResolver.IssueInvoiceRequest(ctx, 1, &session, "REBATE", "EUR", "Satimoto: SESSION0001", util.InvoiceParams{
+		Currency:  "EUR",
+		PriceFiat: dbUtil.SqlNullFloat64(1.0),
+		TotalFiat: dbUtil.SqlNullFloat64(1.0),
+	})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	invoiceRequest, err := mockRepository.GetCreateInvoiceRequestMockData()
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if invoiceRequest.CurrencyRateMsat != 4000000 || invoiceRequest.SessionID.Int64 != 1 {
+		t.Errorf("Invoice request mismatch: %v, %v expecting %v, %v", invoiceRequest.CurrencyRateMsat, invoiceRequest.SessionID.Int64, 4000000, 1)
+	}
+}
@@ -14,6 +14,7 @@ func NewResolver(repositoryService *mocks.MockRepositoryService, services *servi
 	return &cdr.CdrResolver{
 		Repository:               cdrMocks.NewRepository(repositoryService),
 		FerpService:              services.FerpService,
+		LeaseService:             services.LeaseService,
 		LightningService:         services.LightningService,
 		NotificationService:      services.NotificationService,
 		OcpiService:              services.OcpiService,
@@ -1,6 +1,8 @@
 package cdr
 
 import (
+	"context"
+
 	"github.com/satimoto/go-datastore/pkg/db"
 	"github.com/satimoto/go-lnm/internal/notification"
 )
@@ -8,5 +10,5 @@ import (
 func (r *CdrResolver) SendInvoiceRequestNotification(user db.User, invoiceRequest db.InvoiceRequest) {
 	dto := notification.CreateInvoiceRequestNotificationDto(invoiceRequest)
 
-	r.NotificationService.SendUserNotification(user, dto, notification.INVOICE_REQUEST)
+	r.SessionResolver.NotificationOutboxResolver.QueueUserNotification(context.Background(), user, dto, notification.INVOICE_REQUEST)
 }
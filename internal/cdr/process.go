@@ -10,12 +10,16 @@ import (
 	"github.com/satimoto/go-datastore/pkg/db"
 	"github.com/satimoto/go-datastore/pkg/param"
 	dbUtil "github.com/satimoto/go-datastore/pkg/util"
+	"github.com/satimoto/go-lnm/internal/lease"
 	metrics "github.com/satimoto/go-lnm/internal/metric"
 	"github.com/satimoto/go-lnm/internal/session"
 	"github.com/satimoto/go-lnm/internal/user"
+	"github.com/satimoto/go-lnm/internal/webhook"
 	"github.com/satimoto/go-lnm/pkg/util"
 )
 
+var ErrCdrAlreadyProcessed = errors.New("cdr session is already invoiced")
+
 func (r *CdrResolver) ProcessCdr(cdr db.Cdr) error {
 	/** Cdr has been created.
 	 *  Calculate final invoiced amount.
@@ -23,6 +27,28 @@ func (r *CdrResolver) ProcessCdr(cdr db.Cdr) error {
 	 */
 
 	ctx := context.Background()
+
+	// Only one instance processes a cdr at a time
+	heldLease, err := r.LeaseService.Acquire(ctx, lease.LEASE_CDR, cdr.ID, nil)
+
+	if err != nil {
+		if errors.Is(err, lease.ErrLeaseHeld) {
+			log.Printf("Cdr %s is already being processed", cdr.Uid)
+		}
+
+		return err
+	}
+
+	defer r.LeaseService.Release(ctx, heldLease)
+
+	// Invoices are fenced by the cdr lease
+	ctx = lease.WithLease(ctx, heldLease)
+
+	// Another instance may have processed the cdr before the lease was taken
+	if latestCdr, err := r.Repository.GetCdrByUid(ctx, cdr.Uid); err == nil {
+		cdr = latestCdr
+	}
+
 	authorizationId := cdr.AuthorizationID
 	cdrIsFlagged := false
 
@@ -98,6 +124,11 @@ func (r *CdrResolver) ProcessCdr(cdr db.Cdr) error {
 		return errors.New("error retrieving cdr session")
 	}
 
+	if sess.Status == db.SessionStatusTypeINVOICED {
+		log.Printf("Cdr %s already processed", cdr.Uid)
+		return ErrCdrAlreadyProcessed
+	}
+
 	currency := sess.Currency
 	sessionUser, err := r.SessionResolver.UserResolver.Repository.GetUser(ctx, sess.UserID)
 
@@ -157,6 +188,12 @@ func (r *CdrResolver) ProcessCdr(cdr db.Cdr) error {
 		cdrTotalFiat, cdrTotalEnergy, cdrTotalTime = r.SessionResolver.ProcessChargingPeriods(sessionIto, tariffIto, estimatedChargePower, timeLocation, cdr.LastUpdated)
 	}
 
+	// Check the lease is still held before invoicing
+	if err := r.LeaseService.Check(ctx, heldLease); err != nil {
+		log.Printf("Cdr %s lease lost before invoicing", cdr.Uid)
+		return err
+	}
+
 	// Set session as invoiced
 	sessionParams := param.NewUpdateSessionByUidParams(sess)
 	sessionParams.Status = db.SessionStatusTypeINVOICED
@@ -165,6 +202,8 @@ func (r *CdrResolver) ProcessCdr(cdr db.Cdr) error {
 		sess = updatedSession
 	}
 
+	r.QueueCdrProcessedEvent(ctx, sessionUser.ID, cdr, &sess)
+
 	if cdrTotalFiat > 0 {
 		chargeParams := util.ChargeParams{
 			EstimatedEnergy: cdrTotalEnergy,
@@ -202,10 +241,11 @@ func (r *CdrResolver) ProcessCdr(cdr db.Cdr) error {
 					TotalFiat:      dbUtil.SqlNullFloat64(rebateTotalFiat),
 				}
 
-				r.IssueRebate(ctx, sess, sessionUser.ID, invoiceParams, chargeParams)
+				r.IssueRebate(ctx, sess, sessionUser, invoiceParams, chargeParams)
 			}
 
 			r.SessionResolver.SendSessionUpdateNotification(sessionUser, sess)
+			r.SessionResolver.QueueSessionEvent(ctx, webhook.SESSION_UPDATED, sess)
 		}
 
 		// Issue invoice request for session confirmation
@@ -222,7 +262,7 @@ func (r *CdrResolver) ProcessCdr(cdr db.Cdr) error {
 				TotalFiat:      dbUtil.SqlNullFloat64(confirmationTotalFiat),
 			}
 
-			r.IssueInvoiceRequest(ctx, sessionUser.ID, &sess.ID, "SESSION_CONFIRMED", currency, "Satimoto: Confirmed", invoiceParams)
+			r.IssueInvoiceRequest(ctx, sessionUser.ID, &sess, "SESSION_CONFIRMED", currency, "Satimoto: Confirmed", invoiceParams)
 		}
 
 		// Issue invoice request based on location charge count
@@ -238,7 +278,7 @@ func (r *CdrResolver) ProcessCdr(cdr db.Cdr) error {
 				TotalMsat:      dbUtil.SqlNullInt64(totalMsat),
 			}
 
-			r.IssueInvoiceRequest(ctx, sessionUser.ID, &sess.ID, "FIRST_LOCATION_CHARGE", currency, "Satimoto: First", invoiceParams)
+			r.IssueInvoiceRequest(ctx, sessionUser.ID, &sess, "FIRST_LOCATION_CHARGE", currency, "Satimoto: First", invoiceParams)
 		}
 
 		// Issue invoice request based on user charge count
@@ -255,7 +295,7 @@ func (r *CdrResolver) ProcessCdr(cdr db.Cdr) error {
 					TotalMsat:      dbUtil.SqlNullInt64(totalMsat),
 				}
 
-				r.IssueInvoiceRequest(ctx, sessionUser.ID, &sess.ID, "FIRST_USER_CHARGE", currency, "Satimoto: Hello", invoiceParams)
+				r.IssueInvoiceRequest(ctx, sessionUser.ID, &sess, "FIRST_USER_CHARGE", currency, "Satimoto: Hello", invoiceParams)
 			} else if cdrsCount == 21 {
 				totalMsat := int64(2100000)
 				confirmationPriceMsat, confirmationCommissionMsat, confirmationTaxMsat := session.ReverseCommissionInt64(totalMsat, sessionUser.CommissionPercent, taxPercent)
@@ -268,7 +308,7 @@ func (r *CdrResolver) ProcessCdr(cdr db.Cdr) error {
 					TotalMsat:      dbUtil.SqlNullInt64(totalMsat),
 				}
 
-				r.IssueInvoiceRequest(ctx, sessionUser.ID, &sess.ID, "21_CHARGES", currency, "Satimoto: 21", invoiceParams)
+				r.IssueInvoiceRequest(ctx, sessionUser.ID, &sess, "21_CHARGES", currency, "Satimoto: 21", invoiceParams)
 			}
 		}
 
@@ -5,7 +5,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/appleboy/go-fcm"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/satimoto/go-datastore/pkg/db"
 	dbMocks "github.com/satimoto/go-datastore/pkg/db/mocks"
@@ -15,6 +14,7 @@ import (
 	ferpMocks "github.com/satimoto/go-lnm/internal/ferp/mocks"
 	lightningnetworkMocks "github.com/satimoto/go-lnm/internal/lightningnetwork/mocks"
 	invoiceMocks "github.com/satimoto/go-lnm/internal/monitor/invoice/mocks"
+	"github.com/satimoto/go-lnm/internal/notification"
 	notificationMocks "github.com/satimoto/go-lnm/internal/notification/mocks"
 	serviceMocks "github.com/satimoto/go-lnm/internal/service/mocks"
 	ocpiMocks "github.com/satimoto/go-ocpi/pkg/ocpi/mocks"
@@ -100,6 +100,28 @@ func TestProcessCdrErrors(t *testing.T) {
 			AuthorizationID: util.SqlNullString("AUTH0001"),
 		},
 		err: nil,
+	}, {
+		desc: "Session already invoiced",
+		before: func(mockRepository *dbMocks.MockRepositoryService, mockFerpService *ferpMocks.MockFerpService, mockLightningService *lightningnetworkMocks.MockLightningNetworkService, mockNotificationService *notificationMocks.MockNotificationService, mockOcpiService *ocpiMocks.MockOcpiService) {
+			mockRepository.SetGetSessionByAuthorizationIDMockData(dbMocks.SessionMockData{Session: db.Session{
+				ID:              1,
+				Uid:             "SESSION0001",
+				AuthorizationID: util.SqlNullString("AUTH0001"),
+				Status:          db.SessionStatusTypeINVOICED,
+			}})
+		},
+		cdr: db.Cdr{
+			ID:              1,
+			Uid:             "CDR0001",
+			AuthorizationID: util.SqlNullString("AUTH0001"),
+			TotalCost:       1,
+		},
+		after: func(t *testing.T, mockRepository *dbMocks.MockRepositoryService, mockLightningService *lightningnetworkMocks.MockLightningNetworkService, mockOcpiService *ocpiMocks.MockOcpiService) {
+			if _, err := mockRepository.GetUpdateSessionInvoiceMockData(); err == nil {
+				t.Errorf("Expected session invoice not to be updated")
+			}
+		},
+		err: util.NilString("cdr session is already invoiced"),
 	}, {
 		desc: "Success",
 		before: func(mockRepository *dbMocks.MockRepositoryService, mockFerpService *ferpMocks.MockFerpService, mockLightningService *lightningnetworkMocks.MockLightningNetworkService, mockNotificationService *notificationMocks.MockNotificationService, mockOcpiService *ocpiMocks.MockOcpiService) {
@@ -136,7 +158,7 @@ func TestProcessCdrErrors(t *testing.T) {
 
 			mockRepository.SetGetTokenAuthorizationByAuthorizationIDMockData(dbMocks.TokenAuthorizationMockData{TokenAuthorization: db.TokenAuthorization{}})
 
-			mockNotificationService.SetSendNotificationMockData(&fcm.Response{})
+			mockNotificationService.SetSendNotificationMockData(&notification.Response{})
 		},
 		cdr: db.Cdr{
 			ID:              1,
@@ -7,6 +7,7 @@ import (
 	"github.com/satimoto/go-datastore/pkg/pendingnotification"
 	"github.com/satimoto/go-datastore/pkg/promotion"
 	"github.com/satimoto/go-lnm/internal/ferp"
+	"github.com/satimoto/go-lnm/internal/lease"
 	"github.com/satimoto/go-lnm/internal/lightningnetwork"
 	"github.com/satimoto/go-lnm/internal/notification"
 	"github.com/satimoto/go-lnm/internal/service"
@@ -17,6 +18,7 @@ import (
 type CdrResolver struct {
 	Repository                    cdr.CdrRepository
 	FerpService                   ferp.Ferp
+	LeaseService                  lease.Lease
 	LightningService              lightningnetwork.LightningNetwork
 	NotificationService           notification.Notification
 	OcpiService                   ocpi.Ocpi
@@ -30,6 +32,7 @@ func NewResolver(repositoryService *db.RepositoryService, services *service.Serv
 	return &CdrResolver{
 		Repository:                    cdr.NewRepository(repositoryService),
 		FerpService:                   services.FerpService,
+		LeaseService:                  services.LeaseService,
 		LightningService:              services.LightningService,
 		NotificationService:           services.NotificationService,
 		OcpiService:                   services.OcpiService,
@@ -0,0 +1,20 @@
+package cdr
+
+import (
+	"context"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+	"github.com/satimoto/go-lnm/internal/webhook"
+)
+
+func (r *CdrResolver) QueueCdrProcessedEvent(ctx context.Context, userID int64, cdr db.Cdr, session *db.Session) {
+	dto := webhook.NewCdrDto(cdr, session)
+
+	r.SessionResolver.NotificationOutboxResolver.QueueWebhookEvent(ctx, userID, webhook.CDR_PROCESSED, dto)
+}
+
+func (r *CdrResolver) QueueRebateIssuedEvent(ctx context.Context, userID int64, invoiceRequest db.InvoiceRequest, session *db.Session) {
+	dto := webhook.NewInvoiceRequestDto(invoiceRequest, session)
+
+	r.SessionResolver.NotificationOutboxResolver.QueueWebhookEvent(ctx, userID, webhook.REBATE_ISSUED, dto)
+}
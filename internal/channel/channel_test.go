@@ -0,0 +1,124 @@
+package channel_test
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+	"github.com/satimoto/go-lnm/internal/channel"
+)
+
+func TestParseChannelsCsv(t *testing.T) {
+	t.Run("Parse records", func(t *testing.T) {
+		csv := "alias, type, open_date, node_id, chan_id, capacity, opening_fee, commit_fee, commit_weight, fee_per_kw, rebalance_msats, status\n" +
+			"lsp1, me, 20220701, 029e62, 0, 0, 0, 0, 0, 0, 0, open\n" +
+			"lnmarkets, manual, 20220702, 032713, 817314272010174464, 5000000, 154, 3160, 772, 2518, 18225, closed\n" +
+			"fronti, lnplus, 20220713, 0216fc, 818920658546524160, 154, 9066, 772, 7773, 0, open\n" +
+			"\n"
+
+		records, err := channel.ParseChannelsCsv(strings.NewReader(csv))
+
+		if err != nil {
+			t.Fatalf("Error parsing: %v", err)
+		}
+
+		if len(records) != 3 {
+			t.Fatalf("Record count mismatch: %v expecting %v", len(records), 3)
+		}
+
+		if records[0].Type != channel.CHANNEL_RECORD_TYPE_ME || records[0].RemotePubkey != "029e62" {
+			t.Errorf("Own node mismatch: %#v", records[0])
+		}
+
+		if records[1].ChanID != 817314272010174464 || records[1].Capacity != 5000000 || records[1].CommitFeeSat != 3160 || records[1].RebalanceMsat != 18225 || records[1].Status != "closed" {
+			t.Errorf("Record mismatch: %#v", records[1])
+		}
+
+		if records[2].Capacity != 0 || records[2].OpeningFeeSat != 154 || records[2].CommitFeeSat != 9066 || records[2].Status != "open" {
+			t.Errorf("Record without capacity mismatch: %#v", records[2])
+		}
+
+		if openDate := time.Date(2022, 7, 13, 0, 0, 0, 0, time.UTC); !records[2].OpenDate.Equal(openDate) {
+			t.Errorf("Open date mismatch: %v expecting %v", records[2].OpenDate, openDate)
+		}
+	})
+
+	t.Run("Invalid record", func(t *testing.T) {
+		csv := "alias, type, open_date\n" +
+			"lnmarkets, manual, 20220702\n"
+
+		if _, err := channel.ParseChannelsCsv(strings.NewReader(csv)); err == nil {
+			t.Errorf("Expected error parsing invalid record")
+		}
+	})
+}
+
+func TestUptime(t *testing.T) {
+	openedAt := time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+	now := openedAt.Add(100 * time.Second)
+
+	t.Run("Accumulate active time", func(t *testing.T) {
+		updateChannelParams := &db.UpdateChannelParams{
+			OpenedAt: sql.NullTime{Time: openedAt, Valid: true},
+		}
+
+		channel.SetActive(updateChannelParams, true, openedAt.Add(10*time.Second))
+		channel.SetActive(updateChannelParams, false, openedAt.Add(40*time.Second))
+		channel.SetActive(updateChannelParams, false, openedAt.Add(50*time.Second))
+		channel.SetActive(updateChannelParams, true, openedAt.Add(60*time.Second))
+
+		if updateChannelParams.ActiveSeconds != 30 || !updateChannelParams.IsActive {
+			t.Errorf("Active time mismatch: %#v", updateChannelParams)
+		}
+
+		uptime := channel.GetUptime(db.Channel{
+			ActiveSeconds: updateChannelParams.ActiveSeconds,
+			ActiveSince:   updateChannelParams.ActiveSince,
+			OpenedAt:      updateChannelParams.OpenedAt,
+		}, now)
+
+		if uptime.ActiveSeconds != 70 || uptime.LifetimeSeconds != 100 || uptime.Ratio != 0.7 {
+			t.Errorf("Uptime mismatch: %#v", uptime)
+		}
+	})
+
+	t.Run("Closed channel", func(t *testing.T) {
+		uptime := channel.GetUptime(db.Channel{
+			ActiveSeconds: 25,
+			OpenedAt:      sql.NullTime{Time: openedAt, Valid: true},
+			ClosedAt:      sql.NullTime{Time: openedAt.Add(50 * time.Second), Valid: true},
+		}, now)
+
+		if uptime.ActiveSeconds != 25 || uptime.LifetimeSeconds != 50 || uptime.Ratio != 0.5 {
+			t.Errorf("Uptime mismatch: %#v", uptime)
+		}
+	})
+
+	t.Run("Imported closed channel", func(t *testing.T) {
+		for _, status := range []db.ChannelStatus{db.ChannelStatusCLOSED, db.ChannelStatusCLOSING} {
+			uptime := channel.GetUptime(db.Channel{
+				ActiveSeconds: 25,
+				Status:        status,
+				OpenedAt:      sql.NullTime{Time: openedAt, Valid: true},
+			}, now)
+
+			if uptime.LifetimeSeconds != 0 || uptime.Ratio != 0 {
+				t.Errorf("Uptime mismatch: %#v", uptime)
+			}
+		}
+	})
+}
+
+func TestCalculatePnl(t *testing.T) {
+	pnl := channel.CalculatePnl(db.Channel{
+		OpeningFeeSat: 154,
+		CommitFeeSat:  3160,
+		RebalanceMsat: 18225,
+	}, 5000000)
+
+	if pnl.TotalMsat != 5000000-154000-3160000-18225 {
+		t.Errorf("Pnl mismatch: %#v", pnl)
+	}
+}
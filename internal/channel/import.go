@@ -0,0 +1,175 @@
+package channel
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+	"github.com/satimoto/go-datastore/pkg/param"
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+)
+
+const (
+	CHANNEL_RECORD_TYPE_ME = "me"
+)
+
+// ChannelRecord is a row of the hand maintained channels.csv
+type ChannelRecord struct {
+	Alias         string
+	Type          string
+	OpenDate      time.Time
+	RemotePubkey  string
+	ChanID        int64
+	Capacity      int64
+	OpeningFeeSat int64
+	CommitFeeSat  int64
+	RebalanceMsat int64
+	Status        string
+}
+
+// ParseChannelsCsv parses rows of alias, type, open_date, node_id, chan_id,
+// capacity, opening_fee, commit_fee, commit_weight, fee_per_kw, rebalance_msats
+// and status. Older rows without a capacity have a capacity of 0.
+func ParseChannelsCsv(reader io.Reader) ([]ChannelRecord, error) {
+	records := []ChannelRecord{}
+	scanner := bufio.NewScanner(reader)
+	line := 0
+
+	for scanner.Scan() {
+		line++
+		fields := strings.Split(scanner.Text(), ",")
+
+		for i, field := range fields {
+			fields[i] = strings.TrimSpace(field)
+		}
+
+		if line == 1 || len(fields) == 1 && len(fields[0]) == 0 {
+			continue
+		}
+
+		if len(fields) == 11 {
+			fields = append(fields[:5], append([]string{"0"}, fields[5:]...)...)
+		} else if len(fields) != 12 {
+			return nil, fmt.Errorf("line %v: expecting 12 fields, found %v", line, len(fields))
+		}
+
+		openDate, err := time.Parse("20060102", fields[2])
+
+		if err != nil {
+			return nil, fmt.Errorf("line %v: invalid open date: %v", line, err)
+		}
+
+		values := []int64{}
+
+		for _, field := range []string{fields[4], fields[5], fields[6], fields[7], fields[10]} {
+			value, err := parseInt(field)
+
+			if err != nil {
+				return nil, fmt.Errorf("line %v: %v", line, err)
+			}
+
+			values = append(values, value)
+		}
+
+		records = append(records, ChannelRecord{
+			Alias:         fields[0],
+			Type:          fields[1],
+			OpenDate:      openDate,
+			RemotePubkey:  fields[3],
+			ChanID:        values[0],
+			Capacity:      values[1],
+			OpeningFeeSat: values[2],
+			CommitFeeSat:  values[3],
+			RebalanceMsat: values[4],
+			Status:        fields[11],
+		})
+	}
+
+	return records, scanner.Err()
+}
+
+// ImportChannels creates or updates the channels of a node from channels.csv
+// records. Channel points are filled in by the channel event monitor once the
+// channels are listed from LND. The records have no close date, so closed and
+// closing channels are imported without one and left out of uptime.
+func (r *ChannelResolver) ImportChannels(ctx context.Context, nodeID int64, records []ChannelRecord) (int, error) {
+	imported := 0
+
+	for _, record := range records {
+		if record.Type == CHANNEL_RECORD_TYPE_ME || record.ChanID == 0 {
+			continue
+		}
+
+		if c, err := r.Repository.GetChannelByChanID(ctx, record.ChanID); err == nil {
+			updateChannelParams := param.NewUpdateChannelParams(c)
+			updateChannelParams.Alias = record.Alias
+			updateChannelParams.OpeningFeeSat = record.OpeningFeeSat
+			updateChannelParams.CommitFeeSat = record.CommitFeeSat
+			updateChannelParams.RebalanceMsat = record.RebalanceMsat
+
+			if !updateChannelParams.OpenedAt.Valid {
+				updateChannelParams.OpenedAt = sql.NullTime{Time: record.OpenDate, Valid: true}
+			}
+
+			if updateChannelParams.Capacity == 0 {
+				updateChannelParams.Capacity = record.Capacity
+			}
+
+			if _, err := r.Repository.UpdateChannel(ctx, updateChannelParams); err != nil {
+				metrics.RecordError("LNM253", "Error updating channel", err)
+				log.Printf("LNM253: Params=%#v", updateChannelParams)
+				return imported, err
+			}
+		} else {
+			createChannelParams := db.CreateChannelParams{
+				NodeID:        nodeID,
+				ChanID:        record.ChanID,
+				RemotePubkey:  record.RemotePubkey,
+				Alias:         record.Alias,
+				Capacity:      record.Capacity,
+				Status:        recordStatus(record.Status),
+				OpeningFeeSat: record.OpeningFeeSat,
+				CommitFeeSat:  record.CommitFeeSat,
+				RebalanceMsat: record.RebalanceMsat,
+				OpenedAt:      sql.NullTime{Time: record.OpenDate, Valid: true},
+				LastUpdated:   time.Now(),
+			}
+
+			if _, err := r.Repository.CreateChannel(ctx, createChannelParams); err != nil {
+				metrics.RecordError("LNM254", "Error creating channel", err)
+				log.Printf("LNM254: Params=%#v", createChannelParams)
+				return imported, err
+			}
+		}
+
+		imported++
+	}
+
+	return imported, nil
+}
+
+func parseInt(field string) (int64, error) {
+	if len(field) == 0 {
+		return 0, nil
+	}
+
+	return strconv.ParseInt(field, 10, 64)
+}
+
+func recordStatus(status string) db.ChannelStatus {
+	switch status {
+	case "closed":
+		return db.ChannelStatusCLOSED
+	case "closing":
+		return db.ChannelStatusCLOSING
+	}
+
+	return db.ChannelStatusOPEN
+}
@@ -0,0 +1,15 @@
+package mocks
+
+import (
+	channelMocks "github.com/satimoto/go-datastore/pkg/channel/mocks"
+	mocks "github.com/satimoto/go-datastore/pkg/db/mocks"
+	routingeventMocks "github.com/satimoto/go-datastore/pkg/routingevent/mocks"
+	"github.com/satimoto/go-lnm/internal/channel"
+)
+
+func NewResolver(repositoryService *mocks.MockRepositoryService) *channel.ChannelResolver {
+	return &channel.ChannelResolver{
+		Repository:             channelMocks.NewRepository(repositoryService),
+		RoutingEventRepository: routingeventMocks.NewRepository(repositoryService),
+	}
+}
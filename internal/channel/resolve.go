@@ -0,0 +1,19 @@
+package channel
+
+import (
+	"github.com/satimoto/go-datastore/pkg/channel"
+	"github.com/satimoto/go-datastore/pkg/db"
+	"github.com/satimoto/go-datastore/pkg/routingevent"
+)
+
+type ChannelResolver struct {
+	Repository             channel.ChannelRepository
+	RoutingEventRepository routingevent.RoutingEventRepository
+}
+
+func NewResolver(repositoryService *db.RepositoryService) *ChannelResolver {
+	return &ChannelResolver{
+		Repository:             channel.NewRepository(repositoryService),
+		RoutingEventRepository: routingevent.NewRepository(repositoryService),
+	}
+}
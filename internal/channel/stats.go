@@ -0,0 +1,94 @@
+package channel
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+)
+
+type Uptime struct {
+	ActiveSeconds   int64
+	LifetimeSeconds int64
+	Ratio           float64
+}
+
+type Pnl struct {
+	FeesEarnedMsat int64
+	OpeningFeeMsat int64
+	CommitFeeMsat  int64
+	RebalanceMsat  int64
+	TotalMsat      int64
+}
+
+// SetActive sets whether a channel is active and accumulates its active time
+// when it becomes inactive
+func SetActive(updateChannelParams *db.UpdateChannelParams, isActive bool, now time.Time) {
+	if isActive && !updateChannelParams.ActiveSince.Valid {
+		updateChannelParams.ActiveSince = sql.NullTime{Time: now, Valid: true}
+	} else if !isActive && updateChannelParams.ActiveSince.Valid {
+		updateChannelParams.ActiveSeconds += int64(now.Sub(updateChannelParams.ActiveSince.Time).Seconds())
+		updateChannelParams.ActiveSince = sql.NullTime{}
+	}
+
+	updateChannelParams.IsActive = isActive
+}
+
+// GetUptime returns the time a channel has been active since it was opened.
+// Closed or closing channels imported without a close date are left out of
+// uptime as their lifetime is unknown.
+func GetUptime(channel db.Channel, now time.Time) *Uptime {
+	uptime := &Uptime{
+		ActiveSeconds: channel.ActiveSeconds,
+	}
+
+	if isClosed := channel.Status == db.ChannelStatusCLOSED || channel.Status == db.ChannelStatusCLOSING; isClosed && !channel.ClosedAt.Valid {
+		return uptime
+	}
+
+	if channel.ActiveSince.Valid {
+		uptime.ActiveSeconds += int64(now.Sub(channel.ActiveSince.Time).Seconds())
+	}
+
+	if channel.OpenedAt.Valid {
+		closedAt := now
+
+		if channel.ClosedAt.Valid {
+			closedAt = channel.ClosedAt.Time
+		}
+
+		uptime.LifetimeSeconds = int64(closedAt.Sub(channel.OpenedAt.Time).Seconds())
+	}
+
+	if uptime.LifetimeSeconds > 0 {
+		uptime.Ratio = float64(uptime.ActiveSeconds) / float64(uptime.LifetimeSeconds)
+	}
+
+	return uptime
+}
+
+// GetPnl returns the fees earned forwarding out of a channel less its costs.
+// The opening and commitment fees are paid by the node initiating the channel.
+func (r *ChannelResolver) GetPnl(ctx context.Context, channel db.Channel) (*Pnl, error) {
+	feesEarnedMsat, err := r.RoutingEventRepository.SumRoutingEventFeeMsatByOutgoingChanID(ctx, channel.ChanID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return CalculatePnl(channel, feesEarnedMsat), nil
+}
+
+func CalculatePnl(channel db.Channel, feesEarnedMsat int64) *Pnl {
+	pnl := &Pnl{
+		FeesEarnedMsat: feesEarnedMsat,
+		OpeningFeeMsat: channel.OpeningFeeSat * 1000,
+		CommitFeeMsat:  channel.CommitFeeSat * 1000,
+		RebalanceMsat:  channel.RebalanceMsat,
+	}
+
+	pnl.TotalMsat = pnl.FeesEarnedMsat - pnl.OpeningFeeMsat - pnl.CommitFeeMsat - pnl.RebalanceMsat
+
+	return pnl
+}
@@ -0,0 +1,131 @@
+package ferp
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/satimoto/go-ferp/pkg/rate"
+)
+
+var (
+	ErrRateNotFound = errors.New("no currency rate available")
+	ErrRateStale    = errors.New("currency rate is stale")
+)
+
+type RateCache struct {
+	maxAge        time.Duration
+	retention     time.Duration
+	currencyRates rate.LatestCurrencyRates
+	history       map[string][]rate.CurrencyRate
+	mutex         sync.RWMutex
+}
+
+func NewRateCache(maxAge, retention time.Duration) *RateCache {
+	return &RateCache{
+		maxAge:        maxAge,
+		retention:     retention,
+		currencyRates: make(rate.LatestCurrencyRates),
+		history:       make(map[string][]rate.CurrencyRate),
+	}
+}
+
+func (c *RateCache) Get(currency string) (*rate.CurrencyRate, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if currencyRate, ok := c.currencyRates[currency]; ok {
+		if c.maxAge > 0 && time.Since(currencyRate.LastUpdated) > c.maxAge {
+			return &currencyRate, ErrRateStale
+		}
+
+		return &currencyRate, nil
+	}
+
+	return nil, ErrRateNotFound
+}
+
+// GetAt returns the rate in effect at the time. The history is only kept in
+// memory for the retention period, the rate used by an invoice is recorded
+// with the invoice itself.
+func (c *RateCache) GetAt(currency string, t time.Time) (*rate.CurrencyRate, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	currencyRates := c.history[currency]
+
+	// Find the first rate received after the time, the rate before it
+	// was in effect at the time
+	i := sort.Search(len(currencyRates), func(i int) bool {
+		return currencyRates[i].LastUpdated.After(t)
+	})
+
+	if i == 0 {
+		return nil, ErrRateNotFound
+	}
+
+	currencyRate := currencyRates[i-1]
+
+	return &currencyRate, nil
+}
+
+func (c *RateCache) GetAges() map[string]time.Duration {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	ages := make(map[string]time.Duration)
+
+	for currency, currencyRate := range c.currencyRates {
+		ages[currency] = time.Since(currencyRate.LastUpdated)
+	}
+
+	return ages
+}
+
+// GetLastUpdated returns the time of the most recently updated rate
+func (c *RateCache) GetLastUpdated() time.Time {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	lastUpdated := time.Time{}
+
+	for _, currencyRate := range c.currencyRates {
+		if currencyRate.LastUpdated.After(lastUpdated) {
+			lastUpdated = currencyRate.LastUpdated
+		}
+	}
+
+	return lastUpdated
+}
+
+func (c *RateCache) Set(currency string, currencyRate rate.CurrencyRate) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	currencyRates := c.history[currency]
+
+	if len(currencyRates) > 0 && !currencyRates[len(currencyRates)-1].LastUpdated.Before(currencyRate.LastUpdated) {
+		// Ignore rates received out of order so a stale rate
+		// never replaces the latest rate or unsorts the history
+		return
+	}
+
+	c.currencyRates[currency] = currencyRate
+	currencyRates = append(currencyRates, currencyRate)
+
+	if c.retention > 0 {
+		// Prune rates older than the retention period, always keeping
+		// the latest rate before the cutoff so lookups at the cutoff succeed
+		cutoff := time.Now().Add(-c.retention)
+		i := sort.Search(len(currencyRates), func(i int) bool {
+			return currencyRates[i].LastUpdated.After(cutoff)
+		})
+
+		if i > 1 {
+			currencyRates = append([]rate.CurrencyRate{}, currencyRates[i-1:]...)
+		}
+	}
+
+	c.history[currency] = currencyRates
+}
@@ -0,0 +1,21 @@
+package ferp
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricRateAgeSeconds = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "lsp_ferp_rate_age_seconds",
+		Help: "The age of the latest currency rate in seconds",
+	}, []string{"address", "currency"})
+	metricRateFallbacksTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lsp_ferp_rate_fallbacks_total",
+		Help: "The total number of currency rates served by a fallback provider",
+	}, []string{"currency"})
+	metricRateStaleTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lsp_ferp_rate_stale_total",
+		Help: "The total number of currency rate requests failed because the rate is stale",
+	}, []string{"currency"})
+)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"sync"
+	"time"
 
 	"github.com/satimoto/go-ferp/pkg/rate"
 )
@@ -11,6 +12,7 @@ import (
 type MockFerpService struct {
 	getRateMockData     []*rate.CurrencyRate
 	convertRateMockData []*int64
+	lastRateTime        time.Time
 }
 
 func NewService() *MockFerpService {
@@ -33,6 +35,10 @@ func (s *MockFerpService) SetGetRateMockData(currencyRate *rate.CurrencyRate) {
 	s.getRateMockData = append(s.getRateMockData, currencyRate)
 }
 
+func (s *MockFerpService) GetRateAt(currency string, t time.Time) (*rate.CurrencyRate, error) {
+	return s.GetRate(currency)
+}
+
 func (s *MockFerpService) ConvertRate(currency string, amount float64) (*int64, error) {
 	if len(s.convertRateMockData) == 0 {
 		return nil, errors.New("NotFound")
@@ -46,3 +52,11 @@ func (s *MockFerpService) ConvertRate(currency string, amount float64) (*int64,
 func (s *MockFerpService) SetConvertRateMockData(amountMsat *int64) {
 	s.convertRateMockData = append(s.convertRateMockData, amountMsat)
 }
+
+func (s *MockFerpService) GetLastRateTime() time.Time {
+	return s.lastRateTime
+}
+
+func (s *MockFerpService) SetLastRateTime(lastRateTime time.Time) {
+	s.lastRateTime = lastRateTime
+}
@@ -0,0 +1,32 @@
+package ferp
+
+import (
+	"time"
+
+	"github.com/satimoto/go-ferp/pkg/rate"
+)
+
+type RateProvider interface {
+	GetRate(currency string) (*rate.CurrencyRate, error)
+}
+
+type StaticRateProvider struct {
+	currencyRates rate.LatestCurrencyRates
+}
+
+func NewStaticRateProvider(currencyRates rate.LatestCurrencyRates) RateProvider {
+	return &StaticRateProvider{
+		currencyRates: currencyRates,
+	}
+}
+
+func (p *StaticRateProvider) GetRate(currency string) (*rate.CurrencyRate, error) {
+	if currencyRate, ok := p.currencyRates[currency]; ok {
+		// Static rates never become stale
+		currencyRate.LastUpdated = time.Now()
+
+		return &currencyRate, nil
+	}
+
+	return nil, ErrRateNotFound
+}
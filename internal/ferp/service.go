@@ -7,10 +7,12 @@ import (
 	"sync"
 	"time"
 
-	"github.com/satimoto/go-datastore/pkg/util"
+	dbUtil "github.com/satimoto/go-datastore/pkg/util"
 	"github.com/satimoto/go-ferp/ferprpc"
 	"github.com/satimoto/go-ferp/pkg/ferp"
 	"github.com/satimoto/go-ferp/pkg/rate"
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+	"github.com/satimoto/go-lnm/pkg/util"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -18,64 +20,142 @@ import (
 type Ferp interface {
 	Start(shutdownCtx context.Context, waitGroup *sync.WaitGroup)
 	GetRate(currency string) (*rate.CurrencyRate, error)
+	GetRateAt(currency string, t time.Time) (*rate.CurrencyRate, error)
 	ConvertRate(currency string, amount float64) (*int64, error)
+	GetLastRateTime() time.Time
 }
 
 type FerpService struct {
-	FerpRpc       ferp.Ferp
-	RatesClient   ferprpc.RateService_SubscribeRatesClient
-	currencyRates rate.LatestCurrencyRates
+	FerpRpc          ferp.Ferp
+	RatesClient      ferprpc.RateService_SubscribeRatesClient
+	RateCache        *RateCache
+	Providers        []RateProvider
+	ConversionPolicy *util.ConversionPolicy
+	address          string
 }
 
-func NewService(address string) Ferp {
+func NewService(conversionPolicy *util.ConversionPolicy, address string, fallbackAddresses ...string) Ferp {
+	maxAge := time.Duration(dbUtil.GetEnvInt32("FERP_RATE_MAX_AGE", 900)) * time.Second
+	retention := time.Duration(dbUtil.GetEnvInt32("FERP_RATE_HISTORY_RETENTION", 604800)) * time.Second
+	providers := []RateProvider{}
+
+	for _, fallbackAddress := range fallbackAddresses {
+		providers = append(providers, newService(fallbackAddress, maxAge, retention))
+	}
+
+	service := newService(address, maxAge, retention)
+	service.ConversionPolicy = conversionPolicy
+	service.Providers = providers
+
+	return service
+}
+
+func newService(address string, maxAge, retention time.Duration) *FerpService {
 	return &FerpService{
-		FerpRpc:       ferp.NewService(address),
-		currencyRates: make(rate.LatestCurrencyRates),
+		FerpRpc:   ferp.NewService(address),
+		RateCache: NewRateCache(maxAge, retention),
+		address:   address,
 	}
 }
 
 func (s *FerpService) Start(shutdownCtx context.Context, waitGroup *sync.WaitGroup) {
-	log.Printf("Starting up FERP client")
+	log.Printf("Starting up FERP client: %v", s.address)
 	ratesChan := make(chan ferprpc.SubscribeRatesResponse)
 
 	go s.waitForRates(shutdownCtx, waitGroup, ratesChan)
 	go s.subscribeRates(shutdownCtx, ratesChan)
+
+	for _, provider := range s.Providers {
+		if fallbackService, ok := provider.(Ferp); ok {
+			fallbackService.Start(shutdownCtx, waitGroup)
+		}
+	}
 }
 
 func (s *FerpService) GetRate(currency string) (*rate.CurrencyRate, error) {
-	if currencyRate, ok := s.currencyRates[currency]; ok {
-		return &currencyRate, nil
+	currencyRate, err := s.RateCache.Get(currency)
+
+	if err == nil {
+		return currencyRate, nil
+	}
+
+	if errors.Is(err, ErrRateStale) {
+		metricRateStaleTotal.WithLabelValues(currency).Inc()
+	}
+
+	// Fallback to secondary providers in order
+	for _, provider := range s.Providers {
+		if providerRate, providerErr := provider.GetRate(currency); providerErr == nil {
+			metricRateFallbacksTotal.WithLabelValues(currency).Inc()
+
+			return providerRate, nil
+		}
 	}
 
-	return nil, errors.New("no currency rate available")
+	if currencyRate != nil {
+		metrics.RecordError("LNM175", "Error currency rate is stale", err)
+		log.Printf("LNM175: Currency=%v, LastUpdated=%v", currency, currencyRate.LastUpdated)
+	}
+
+	return nil, err
+}
+
+func (s *FerpService) GetRateAt(currency string, t time.Time) (*rate.CurrencyRate, error) {
+	currencyRate, err := s.RateCache.GetAt(currency, t)
+
+	if err != nil {
+		metrics.RecordError("LNM176", "Error retrieving historical currency rate", err)
+		log.Printf("LNM176: Currency=%v, Time=%v", currency, t)
+		return nil, err
+	}
+
+	return currencyRate, nil
 }
 
 func (s *FerpService) ConvertRate(currency string, amount float64) (*int64, error) {
-	if currencyRate, ok := s.currencyRates[currency]; ok {
-		rateMsat := float64(currencyRate.RateMsat)
-		amountMsat := int64(amount * rateMsat)
+	currencyRate, err := s.GetRate(currency)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if s.ConversionPolicy != nil {
+		amountMsat := s.ConversionPolicy.ConvertFiat(currency, amount, currencyRate.RateMsat)
 
 		return &amountMsat, nil
 	}
 
-	return nil, errors.New("no currency rate available")
+	rateMsat := float64(currencyRate.RateMsat)
+	amountMsat := int64(amount * rateMsat)
+
+	return &amountMsat, nil
+}
+
+func (s *FerpService) GetLastRateTime() time.Time {
+	return s.RateCache.GetLastUpdated()
 }
 
 func (s *FerpService) handleRate(currencyRate ferprpc.SubscribeRatesResponse) {
 	/** Rate received.
-	 *
+	 *  Update the cached rate for the currency.
 	 */
 
-	s.currencyRates[currencyRate.Currency] = rate.CurrencyRate{
+	s.RateCache.Set(currencyRate.Currency, rate.CurrencyRate{
 		Rate:        currencyRate.Rate,
 		RateMsat:    currencyRate.RateMsat,
 		LastUpdated: time.Unix(currencyRate.LastUpdated, 0),
+	})
+}
+
+func (s *FerpService) updateRateMetrics() {
+	for currency, age := range s.RateCache.GetAges() {
+		metricRateAgeSeconds.WithLabelValues(s.address, currency).Set(age.Seconds())
 	}
 }
 
 func (s *FerpService) subscribeRates(shutdownCtx context.Context, ratesChan chan<- ferprpc.SubscribeRatesResponse) {
 	ratesClient, err := s.waitForSubscribeRatesClient(shutdownCtx, 0, 1000)
-	util.PanicOnError("LNM060", "Error creating FERP client", err)
+	dbUtil.PanicOnError("LNM060", "Error creating FERP client", err)
 	s.RatesClient = ratesClient
 
 	for {
@@ -90,13 +170,15 @@ func (s *FerpService) subscribeRates(shutdownCtx context.Context, ratesChan chan
 			}
 
 			s.RatesClient, err = s.waitForSubscribeRatesClient(shutdownCtx, 100, 1000)
-			util.PanicOnError("LNM061", "Error creating FERP client", err)
+			dbUtil.PanicOnError("LNM061", "Error creating FERP client", err)
 		}
 	}
 }
 
 func (s *FerpService) waitForRates(shutdownCtx context.Context, waitGroup *sync.WaitGroup, ratesChan chan ferprpc.SubscribeRatesResponse) {
 	waitGroup.Add(1)
+	metricsTicker := time.NewTicker(15 * time.Second)
+	defer metricsTicker.Stop()
 
 waitLoop:
 	for {
@@ -106,6 +188,8 @@ waitLoop:
 			break waitLoop
 		case subscribeRatesResponse := <-ratesChan:
 			s.handleRate(subscribeRatesResponse)
+		case <-metricsTicker.C:
+			s.updateRateMetrics()
 		}
 	}
 
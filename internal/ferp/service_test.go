@@ -0,0 +1,137 @@
+package ferp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/satimoto/go-ferp/pkg/rate"
+	"github.com/satimoto/go-lnm/internal/ferp"
+)
+
+func TestGetRate(t *testing.T) {
+	cases := []struct {
+		desc      string
+		cached    *rate.CurrencyRate
+		providers []ferp.RateProvider
+		rateMsat  int64
+		err       error
+	}{{
+		desc:   "Fresh rate",
+		cached: &rate.CurrencyRate{Rate: 4500, RateMsat: 4500000, LastUpdated: time.Now()},
+		providers: []ferp.RateProvider{
+			ferp.NewStaticRateProvider(rate.LatestCurrencyRates{"EUR": {Rate: 5000, RateMsat: 5000000}}),
+		},
+		rateMsat: 4500000,
+		err:      nil,
+	}, {
+		desc:      "Stale rate",
+		cached:    &rate.CurrencyRate{Rate: 4500, RateMsat: 4500000, LastUpdated: time.Now().Add(-time.Hour)},
+		providers: []ferp.RateProvider{},
+		err:       ferp.ErrRateStale,
+	}, {
+		desc:   "Stale rate with fallback",
+		cached: &rate.CurrencyRate{Rate: 4500, RateMsat: 4500000, LastUpdated: time.Now().Add(-time.Hour)},
+		providers: []ferp.RateProvider{
+			ferp.NewStaticRateProvider(rate.LatestCurrencyRates{"USD": {Rate: 4000, RateMsat: 4000000}}),
+			ferp.NewStaticRateProvider(rate.LatestCurrencyRates{"EUR": {Rate: 5000, RateMsat: 5000000}}),
+		},
+		rateMsat: 5000000,
+		err:      nil,
+	}, {
+		desc:      "No rate",
+		providers: []ferp.RateProvider{},
+		err:       ferp.ErrRateNotFound,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ferpService := &ferp.FerpService{
+				RateCache: ferp.NewRateCache(time.Minute, time.Hour),
+				Providers: tc.providers,
+			}
+
+			if tc.cached != nil {
+				ferpService.RateCache.Set("EUR", *tc.cached)
+			}
+
+			currencyRate, err := ferpService.GetRate("EUR")
+
+			if err != tc.err {
+				t.Errorf("Error mismatch: %v expecting %v", err, tc.err)
+			}
+
+			if err == nil && currencyRate.RateMsat != tc.rateMsat {
+				t.Errorf("Rate mismatch: %v expecting %v", currencyRate.RateMsat, tc.rateMsat)
+			}
+		})
+	}
+}
+
+func TestGetRateAt(t *testing.T) {
+	now := time.Now()
+	rateCache := ferp.NewRateCache(time.Minute, time.Hour)
+	rateCache.Set("EUR", rate.CurrencyRate{RateMsat: 4000000, LastUpdated: now.Add(-2 * time.Hour)})
+	rateCache.Set("EUR", rate.CurrencyRate{RateMsat: 4500000, LastUpdated: now.Add(-90 * time.Minute)})
+	rateCache.Set("EUR", rate.CurrencyRate{RateMsat: 5000000, LastUpdated: now.Add(-30 * time.Minute)})
+	rateCache.Set("EUR", rate.CurrencyRate{RateMsat: 5500000, LastUpdated: now.Add(-10 * time.Minute)})
+
+	cases := []struct {
+		desc     string
+		time     time.Time
+		rateMsat int64
+		err      error
+	}{{
+		desc: "Before retention",
+		time: now.Add(-100 * time.Minute),
+		err:  ferp.ErrRateNotFound,
+	}, {
+		desc:     "At retention cutoff",
+		time:     now.Add(-time.Hour),
+		rateMsat: 4500000,
+	}, {
+		desc:     "Between rates",
+		time:     now.Add(-20 * time.Minute),
+		rateMsat: 5000000,
+	}, {
+		desc:     "Latest rate",
+		time:     now,
+		rateMsat: 5500000,
+	}}
+
+	ferpService := &ferp.FerpService{
+		RateCache: rateCache,
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			currencyRate, err := ferpService.GetRateAt("EUR", tc.time)
+
+			if err != tc.err {
+				t.Errorf("Error mismatch: %v expecting %v", err, tc.err)
+			}
+
+			if err == nil && currencyRate.RateMsat != tc.rateMsat {
+				t.Errorf("Rate mismatch: %v expecting %v", currencyRate.RateMsat, tc.rateMsat)
+			}
+		})
+	}
+}
+
+func TestSetOutOfOrder(t *testing.T) {
+	now := time.Now()
+	rateCache := ferp.NewRateCache(time.Minute, time.Hour)
+	rateCache.Set("EUR", rate.CurrencyRate{RateMsat: 5000000, LastUpdated: now})
+	rateCache.Set("EUR", rate.CurrencyRate{RateMsat: 4500000, LastUpdated: now.Add(-time.Minute)})
+
+	currencyRate, err := rateCache.Get("EUR")
+
+	if err != nil || currencyRate.RateMsat != 5000000 {
+		t.Errorf("Rate mismatch: %v, %v expecting %v", currencyRate, err, 5000000)
+	}
+
+	currencyRate, err = rateCache.GetAt("EUR", now.Add(-time.Minute))
+
+	if err != ferp.ErrRateNotFound {
+		t.Errorf("Error mismatch: %v, %v expecting %v", currencyRate, err, ferp.ErrRateNotFound)
+	}
+}
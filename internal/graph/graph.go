@@ -0,0 +1,385 @@
+package graph
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+type Graph interface {
+	// Load replaces the view with our channels to peers and the channels of
+	// our peers, returning the peer policies toward us that changed
+	Load(localPubkey string, edges []*lnrpc.ChannelEdge) []*PolicyChange
+	// UpdateEdges applies a graph topology update, returning the peer
+	// policies toward us that changed
+	UpdateEdges(graphTopologyUpdate *lnrpc.GraphTopologyUpdate) []*PolicyChange
+
+	GetPeerPolicy(chanID uint64) (*Policy, bool)
+	GetFeeSummary(peerPubkey string) *FeeSummary
+	ListCompetitorPolicies(peerPubkey string) []*EdgePolicy
+	ListPeers() []string
+}
+
+type Policy struct {
+	FeeBaseMsat   int64
+	FeeRatePpm    int64
+	TimeLockDelta uint32
+	MinHtlcMsat   int64
+	MaxHtlcMsat   uint64
+	Disabled      bool
+	LastUpdate    time.Time
+}
+
+// EdgePolicy is the policy a node advertises to forward over a channel
+type EdgePolicy struct {
+	ChanID   uint64
+	Capacity int64
+	Pubkey   string
+	Policy   *Policy
+}
+
+type PolicyChange struct {
+	ChanID   uint64
+	Pubkey   string
+	Previous *Policy
+	Current  *Policy
+}
+
+// FeeSummary compares the fee rates we charge to forward to a peer with the
+// fee rates competitors charge on parallel routes to the peer
+type FeeSummary struct {
+	PeerPubkey        string
+	LocalFeeRatePpm   int64
+	Competitors       int
+	MinFeeRatePpm     int64
+	MedianFeeRatePpm  int64
+	MaxFeeRatePpm     int64
+	MedianFeeBaseMsat int64
+}
+
+type edge struct {
+	chanID   uint64
+	capacity int64
+	node1    string
+	node2    string
+	policies map[string]*Policy
+}
+
+// GraphService is a compact in-memory view of our channels, the channels of
+// our peers and their policies
+type GraphService struct {
+	mutex       sync.RWMutex
+	localPubkey string
+	peers       map[string]bool
+	edges       map[uint64]*edge
+	nodeEdges   map[string]map[uint64]bool
+}
+
+func NewGraph() Graph {
+	return &GraphService{
+		peers:     make(map[string]bool),
+		edges:     make(map[uint64]*edge),
+		nodeEdges: make(map[string]map[uint64]bool),
+	}
+}
+
+func (g *GraphService) Load(localPubkey string, edges []*lnrpc.ChannelEdge) []*PolicyChange {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	previousPeerPolicies := g.peerPolicies()
+
+	g.localPubkey = localPubkey
+	g.peers = make(map[string]bool)
+	g.edges = make(map[uint64]*edge)
+	g.nodeEdges = make(map[string]map[uint64]bool)
+
+	for _, channelEdge := range edges {
+		if channelEdge.Node1Pub == localPubkey {
+			g.peers[channelEdge.Node2Pub] = true
+		} else if channelEdge.Node2Pub == localPubkey {
+			g.peers[channelEdge.Node1Pub] = true
+		}
+	}
+
+	for _, channelEdge := range edges {
+		if !g.isTracked(channelEdge.Node1Pub) && !g.isTracked(channelEdge.Node2Pub) {
+			continue
+		}
+
+		e := g.addEdge(channelEdge.ChannelId, channelEdge.Capacity, channelEdge.Node1Pub, channelEdge.Node2Pub)
+
+		if channelEdge.Node1Policy != nil {
+			e.policies[channelEdge.Node1Pub] = NewPolicy(channelEdge.Node1Policy)
+		}
+
+		if channelEdge.Node2Policy != nil {
+			e.policies[channelEdge.Node2Pub] = NewPolicy(channelEdge.Node2Policy)
+		}
+	}
+
+	policyChanges := []*PolicyChange{}
+
+	for chanID, current := range g.peerPolicies() {
+		if previous, ok := previousPeerPolicies[chanID]; ok && previous.Policy.isChanged(current.Policy) {
+			policyChanges = append(policyChanges, &PolicyChange{
+				ChanID:   chanID,
+				Pubkey:   current.Pubkey,
+				Previous: previous.Policy,
+				Current:  current.Policy,
+			})
+		}
+	}
+
+	return policyChanges
+}
+
+func (g *GraphService) UpdateEdges(graphTopologyUpdate *lnrpc.GraphTopologyUpdate) []*PolicyChange {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	policyChanges := []*PolicyChange{}
+
+	for _, channelEdgeUpdate := range graphTopologyUpdate.ChannelUpdates {
+		advertisingNode := channelEdgeUpdate.AdvertisingNode
+		connectingNode := channelEdgeUpdate.ConnectingNode
+
+		if len(g.localPubkey) > 0 && connectingNode == g.localPubkey {
+			g.peers[advertisingNode] = true
+		} else if len(g.localPubkey) > 0 && advertisingNode == g.localPubkey {
+			g.peers[connectingNode] = true
+		}
+
+		if !g.isTracked(advertisingNode) && !g.isTracked(connectingNode) {
+			continue
+		}
+
+		e, ok := g.edges[channelEdgeUpdate.ChanId]
+
+		if !ok {
+			e = g.addEdge(channelEdgeUpdate.ChanId, channelEdgeUpdate.Capacity, advertisingNode, connectingNode)
+		}
+
+		if channelEdgeUpdate.RoutingPolicy == nil {
+			continue
+		}
+
+		previous := e.policies[advertisingNode]
+		current := NewPolicy(channelEdgeUpdate.RoutingPolicy)
+		e.policies[advertisingNode] = current
+
+		if connectingNode == g.localPubkey && previous != nil && previous.isChanged(current) {
+			policyChanges = append(policyChanges, &PolicyChange{
+				ChanID:   e.chanID,
+				Pubkey:   advertisingNode,
+				Previous: previous,
+				Current:  current,
+			})
+		}
+	}
+
+	for _, closedChannelUpdate := range graphTopologyUpdate.ClosedChans {
+		g.removeEdge(closedChannelUpdate.ChanId)
+	}
+
+	return policyChanges
+}
+
+// GetPeerPolicy returns the policy of the peer toward us on a channel
+func (g *GraphService) GetPeerPolicy(chanID uint64) (*Policy, bool) {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	if edgePolicy, ok := g.peerPolicies()[chanID]; ok {
+		return edgePolicy.Policy, true
+	}
+
+	return nil, false
+}
+
+// GetFeeSummary returns our fee rate to forward to a peer and the fee rates
+// of competitors on parallel routes to the peer
+func (g *GraphService) GetFeeSummary(peerPubkey string) *FeeSummary {
+	feeSummary := &FeeSummary{
+		PeerPubkey: peerPubkey,
+	}
+
+	competitorPolicies := g.ListCompetitorPolicies(peerPubkey)
+	feeRates := []int64{}
+	feeBases := []int64{}
+
+	for _, edgePolicy := range competitorPolicies {
+		if edgePolicy.Policy.Disabled {
+			continue
+		}
+
+		feeRates = append(feeRates, edgePolicy.Policy.FeeRatePpm)
+		feeBases = append(feeBases, edgePolicy.Policy.FeeBaseMsat)
+	}
+
+	if len(feeRates) > 0 {
+		sort.Slice(feeRates, func(i, j int) bool { return feeRates[i] < feeRates[j] })
+		sort.Slice(feeBases, func(i, j int) bool { return feeBases[i] < feeBases[j] })
+
+		feeSummary.Competitors = len(feeRates)
+		feeSummary.MinFeeRatePpm = feeRates[0]
+		feeSummary.MedianFeeRatePpm = feeRates[len(feeRates)/2]
+		feeSummary.MaxFeeRatePpm = feeRates[len(feeRates)-1]
+		feeSummary.MedianFeeBaseMsat = feeBases[len(feeBases)/2]
+	}
+
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	for chanID := range g.nodeEdges[peerPubkey] {
+		e := g.edges[chanID]
+
+		if policy, ok := e.policies[g.localPubkey]; ok && e.otherNode(peerPubkey) == g.localPubkey {
+			feeSummary.LocalFeeRatePpm = policy.FeeRatePpm
+		}
+	}
+
+	return feeSummary
+}
+
+// ListCompetitorPolicies returns the policies other nodes advertise to
+// forward to a peer, which compete with our channels to the peer
+func (g *GraphService) ListCompetitorPolicies(peerPubkey string) []*EdgePolicy {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	edgePolicies := []*EdgePolicy{}
+
+	for chanID := range g.nodeEdges[peerPubkey] {
+		e := g.edges[chanID]
+		competitor := e.otherNode(peerPubkey)
+
+		if competitor == g.localPubkey {
+			continue
+		}
+
+		if policy, ok := e.policies[competitor]; ok {
+			edgePolicies = append(edgePolicies, &EdgePolicy{
+				ChanID:   e.chanID,
+				Capacity: e.capacity,
+				Pubkey:   competitor,
+				Policy:   policy,
+			})
+		}
+	}
+
+	sort.Slice(edgePolicies, func(i, j int) bool { return edgePolicies[i].ChanID < edgePolicies[j].ChanID })
+
+	return edgePolicies
+}
+
+func (g *GraphService) ListPeers() []string {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	peers := []string{}
+
+	for peer := range g.peers {
+		peers = append(peers, peer)
+	}
+
+	sort.Strings(peers)
+
+	return peers
+}
+
+func (g *GraphService) addEdge(chanID uint64, capacity int64, node1, node2 string) *edge {
+	e := &edge{
+		chanID:   chanID,
+		capacity: capacity,
+		node1:    node1,
+		node2:    node2,
+		policies: make(map[string]*Policy),
+	}
+
+	g.edges[chanID] = e
+
+	for _, node := range []string{node1, node2} {
+		if _, ok := g.nodeEdges[node]; !ok {
+			g.nodeEdges[node] = make(map[uint64]bool)
+		}
+
+		g.nodeEdges[node][chanID] = true
+	}
+
+	return e
+}
+
+func (g *GraphService) removeEdge(chanID uint64) {
+	e, ok := g.edges[chanID]
+
+	if !ok {
+		return
+	}
+
+	delete(g.edges, chanID)
+
+	for _, node := range []string{e.node1, e.node2} {
+		delete(g.nodeEdges[node], chanID)
+
+		if len(g.nodeEdges[node]) == 0 {
+			delete(g.nodeEdges, node)
+		}
+	}
+}
+
+func (g *GraphService) isTracked(pubkey string) bool {
+	return (len(g.localPubkey) > 0 && pubkey == g.localPubkey) || g.peers[pubkey]
+}
+
+// peerPolicies returns the policies of peers toward us by channel ID
+func (g *GraphService) peerPolicies() map[uint64]*EdgePolicy {
+	peerPolicies := make(map[uint64]*EdgePolicy)
+
+	for chanID := range g.nodeEdges[g.localPubkey] {
+		e := g.edges[chanID]
+		peer := e.otherNode(g.localPubkey)
+
+		if policy, ok := e.policies[peer]; ok {
+			peerPolicies[chanID] = &EdgePolicy{
+				ChanID:   chanID,
+				Capacity: e.capacity,
+				Pubkey:   peer,
+				Policy:   policy,
+			}
+		}
+	}
+
+	return peerPolicies
+}
+
+func (e *edge) otherNode(pubkey string) string {
+	if e.node1 == pubkey {
+		return e.node2
+	}
+
+	return e.node1
+}
+
+func NewPolicy(routingPolicy *lnrpc.RoutingPolicy) *Policy {
+	return &Policy{
+		FeeBaseMsat:   routingPolicy.FeeBaseMsat,
+		FeeRatePpm:    routingPolicy.FeeRateMilliMsat,
+		TimeLockDelta: routingPolicy.TimeLockDelta,
+		MinHtlcMsat:   routingPolicy.MinHtlc,
+		MaxHtlcMsat:   routingPolicy.MaxHtlcMsat,
+		Disabled:      routingPolicy.Disabled,
+		LastUpdate:    time.Unix(int64(routingPolicy.LastUpdate), 0),
+	}
+}
+
+func (p *Policy) isChanged(policy *Policy) bool {
+	return p.FeeBaseMsat != policy.FeeBaseMsat ||
+		p.FeeRatePpm != policy.FeeRatePpm ||
+		p.TimeLockDelta != policy.TimeLockDelta ||
+		p.MinHtlcMsat != policy.MinHtlcMsat ||
+		p.MaxHtlcMsat != policy.MaxHtlcMsat ||
+		p.Disabled != policy.Disabled
+}
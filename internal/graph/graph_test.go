@@ -0,0 +1,137 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/satimoto/go-lnm/internal/graph"
+)
+
+func newEdges() []*lnrpc.ChannelEdge {
+	return []*lnrpc.ChannelEdge{{
+		ChannelId:   1,
+		Capacity:    1000000,
+		Node1Pub:    "local",
+		Node2Pub:    "peer",
+		Node1Policy: &lnrpc.RoutingPolicy{FeeBaseMsat: 1000, FeeRateMilliMsat: 100},
+		Node2Policy: &lnrpc.RoutingPolicy{FeeBaseMsat: 1000, FeeRateMilliMsat: 200},
+	}, {
+		ChannelId:   2,
+		Capacity:    2000000,
+		Node1Pub:    "peer",
+		Node2Pub:    "competitor1",
+		Node1Policy: &lnrpc.RoutingPolicy{FeeBaseMsat: 1000, FeeRateMilliMsat: 10},
+		Node2Policy: &lnrpc.RoutingPolicy{FeeBaseMsat: 0, FeeRateMilliMsat: 300},
+	}, {
+		ChannelId:   3,
+		Capacity:    3000000,
+		Node1Pub:    "competitor2",
+		Node2Pub:    "peer",
+		Node1Policy: &lnrpc.RoutingPolicy{FeeBaseMsat: 500, FeeRateMilliMsat: 50},
+		Node2Policy: &lnrpc.RoutingPolicy{FeeBaseMsat: 1000, FeeRateMilliMsat: 10},
+	}, {
+		ChannelId:   4,
+		Capacity:    4000000,
+		Node1Pub:    "competitor3",
+		Node2Pub:    "peer",
+		Node1Policy: &lnrpc.RoutingPolicy{FeeBaseMsat: 1000, FeeRateMilliMsat: 1000, Disabled: true},
+	}, {
+		ChannelId: 5,
+		Capacity:  5000000,
+		Node1Pub:  "competitor1",
+		Node2Pub:  "competitor2",
+	}}
+}
+
+func TestLoad(t *testing.T) {
+	g := graph.NewGraph()
+
+	if policyChanges := g.Load("local", newEdges()); len(policyChanges) != 0 {
+		t.Errorf("Policy changes mismatch: %#v", policyChanges)
+	}
+
+	if peers := g.ListPeers(); len(peers) != 1 || peers[0] != "peer" {
+		t.Errorf("Peers mismatch: %#v", peers)
+	}
+
+	if policy, ok := g.GetPeerPolicy(1); !ok || policy.FeeRatePpm != 200 {
+		t.Errorf("Peer policy mismatch: %#v", policy)
+	}
+
+	if _, ok := g.GetPeerPolicy(5); ok {
+		t.Error("Untracked channel in graph")
+	}
+
+	edges := newEdges()
+	edges[0].Node2Policy.FeeRateMilliMsat = 250
+
+	if policyChanges := g.Load("local", edges); len(policyChanges) != 1 || policyChanges[0].Previous.FeeRatePpm != 200 || policyChanges[0].Current.FeeRatePpm != 250 {
+		t.Errorf("Policy changes mismatch: %#v", policyChanges)
+	}
+}
+
+func TestUpdateEdges(t *testing.T) {
+	g := graph.NewGraph()
+	g.Load("local", newEdges())
+
+	cases := []struct {
+		desc          string
+		update        *lnrpc.ChannelEdgeUpdate
+		policyChanges int
+	}{
+		{"Peer policy unchanged", &lnrpc.ChannelEdgeUpdate{
+			ChanId:          1,
+			AdvertisingNode: "peer",
+			ConnectingNode:  "local",
+			RoutingPolicy:   &lnrpc.RoutingPolicy{FeeBaseMsat: 1000, FeeRateMilliMsat: 200, LastUpdate: 1656633600},
+		}, 0},
+		{"Peer policy changed", &lnrpc.ChannelEdgeUpdate{
+			ChanId:          1,
+			AdvertisingNode: "peer",
+			ConnectingNode:  "local",
+			RoutingPolicy:   &lnrpc.RoutingPolicy{FeeBaseMsat: 1000, FeeRateMilliMsat: 400},
+		}, 1},
+		{"Local policy changed", &lnrpc.ChannelEdgeUpdate{
+			ChanId:          1,
+			AdvertisingNode: "local",
+			ConnectingNode:  "peer",
+			RoutingPolicy:   &lnrpc.RoutingPolicy{FeeBaseMsat: 1000, FeeRateMilliMsat: 150},
+		}, 0},
+		{"Competitor policy changed", &lnrpc.ChannelEdgeUpdate{
+			ChanId:          2,
+			AdvertisingNode: "competitor1",
+			ConnectingNode:  "peer",
+			RoutingPolicy:   &lnrpc.RoutingPolicy{FeeBaseMsat: 0, FeeRateMilliMsat: 200},
+		}, 0},
+		{"Untracked channel", &lnrpc.ChannelEdgeUpdate{
+			ChanId:          6,
+			AdvertisingNode: "competitor1",
+			ConnectingNode:  "competitor2",
+			RoutingPolicy:   &lnrpc.RoutingPolicy{FeeBaseMsat: 0, FeeRateMilliMsat: 200},
+		}, 0},
+	}
+
+	for _, tc := range cases {
+		policyChanges := g.UpdateEdges(&lnrpc.GraphTopologyUpdate{
+			ChannelUpdates: []*lnrpc.ChannelEdgeUpdate{tc.update},
+		})
+
+		if len(policyChanges) != tc.policyChanges {
+			t.Errorf("%v: policy changes %v expecting %v", tc.desc, len(policyChanges), tc.policyChanges)
+		}
+	}
+
+	feeSummary := g.GetFeeSummary("peer")
+
+	if feeSummary.LocalFeeRatePpm != 150 || feeSummary.Competitors != 2 || feeSummary.MinFeeRatePpm != 50 || feeSummary.MaxFeeRatePpm != 200 {
+		t.Errorf("Fee summary mismatch: %#v", feeSummary)
+	}
+
+	g.UpdateEdges(&lnrpc.GraphTopologyUpdate{
+		ClosedChans: []*lnrpc.ClosedChannelUpdate{{ChanId: 2}},
+	})
+
+	if competitorPolicies := g.ListCompetitorPolicies("peer"); len(competitorPolicies) != 2 || competitorPolicies[0].Pubkey != "competitor2" {
+		t.Errorf("Competitor policies mismatch: %#v", competitorPolicies)
+	}
+}
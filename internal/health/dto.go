@@ -0,0 +1,34 @@
+package health
+
+const (
+	STATUS_DOWN = "DOWN"
+	STATUS_UP   = "UP"
+)
+
+type HealthDto struct {
+	Status string               `json:"status"`
+	Checks map[string]*CheckDto `json:"checks"`
+}
+
+// CheckDto is the result of a check. An optional check is reported without
+// changing the overall status.
+type CheckDto struct {
+	Status   string                 `json:"status"`
+	Error    string                 `json:"error,omitempty"`
+	Optional bool                   `json:"optional,omitempty"`
+	Details  map[string]interface{} `json:"details,omitempty"`
+}
+
+func NewCheckDto(status string) *CheckDto {
+	return &CheckDto{
+		Status:  status,
+		Details: make(map[string]interface{}),
+	}
+}
+
+func NewErrorCheckDto(err error) *CheckDto {
+	check := NewCheckDto(STATUS_DOWN)
+	check.Error = err.Error()
+
+	return check
+}
@@ -0,0 +1,204 @@
+package health
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/satimoto/go-lnm/internal/lightningnetwork"
+	"github.com/satimoto/go-lnm/pkg/util"
+)
+
+var (
+	heartbeats      = make(map[string]*Heartbeat)
+	heartbeatsMutex sync.RWMutex
+)
+
+// Heartbeat tracks the last event received by a monitor and, for monitors
+// consuming a stream, whether the stream is currently subscribed or standing
+// by while another instance consumes it
+type Heartbeat struct {
+	Name         string
+	IsStream     bool
+	IsOptional   bool
+	MaxAge       time.Duration
+	mutex        sync.RWMutex
+	startTime    time.Time
+	lastEvent    time.Time
+	isSubscribed bool
+	isStandby    bool
+}
+
+// RegisterHeartbeat registers a heartbeat reported by the health checks.
+// A max age of 0 only reports the last event time without checking it.
+func RegisterHeartbeat(name string, isStream bool, maxAge time.Duration) *Heartbeat {
+	heartbeatsMutex.Lock()
+	defer heartbeatsMutex.Unlock()
+
+	if heartbeat, ok := heartbeats[name]; ok {
+		return heartbeat
+	}
+
+	heartbeat := &Heartbeat{
+		Name:     name,
+		IsStream: isStream,
+		MaxAge:   maxAge,
+	}
+
+	heartbeats[name] = heartbeat
+
+	return heartbeat
+}
+
+// RegisterNodeHeartbeat registers a heartbeat for the monitor of a lightning
+// node. The heartbeat is optional if readiness does not depend on the node.
+func RegisterNodeHeartbeat(lightningNode *lightningnetwork.LightningNode, name string, isStream bool, maxAge time.Duration) *Heartbeat {
+	heartbeat := RegisterHeartbeat(nodeCheckName(name, lightningNode), isStream, maxAge)
+
+	heartbeat.mutex.Lock()
+	heartbeat.IsOptional = !isRequiredNode(lightningNode)
+	heartbeat.mutex.Unlock()
+
+	return heartbeat
+}
+
+func ListHeartbeats() []*Heartbeat {
+	heartbeatsMutex.RLock()
+	defer heartbeatsMutex.RUnlock()
+
+	list := []*Heartbeat{}
+
+	for _, heartbeat := range heartbeats {
+		list = append(list, heartbeat)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+
+	return list
+}
+
+func (h *Heartbeat) Beat() {
+	if h == nil {
+		return
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.lastEvent = time.Now()
+
+	if h.startTime.IsZero() {
+		h.startTime = h.lastEvent
+	}
+}
+
+func (h *Heartbeat) SetSubscribed(isSubscribed bool) {
+	if h == nil {
+		return
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.isSubscribed = isSubscribed
+
+	if isSubscribed && h.startTime.IsZero() {
+		h.startTime = time.Now()
+	}
+}
+
+// SetStandby sets whether the stream is left to another instance. A stream
+// standing by is not expected to be subscribed.
+func (h *Heartbeat) SetStandby(isStandby bool) {
+	if h == nil {
+		return
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.isStandby = isStandby
+}
+
+func (h *Heartbeat) LastEvent() time.Time {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	return h.lastEvent
+}
+
+// Check reports the heartbeat. When checking readiness the heartbeat is down
+// when its last event, or its start if no event has been received, is older
+// than the max age, or when its stream is not subscribed. Liveness only
+// reports the heartbeat, a quiet monitor does not need the process restarted.
+func (h *Heartbeat) Check(isReady bool) *CheckDto {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	check := NewCheckDto(STATUS_UP)
+	check.Optional = h.IsOptional
+	lastActive := h.startTime
+
+	if !h.lastEvent.IsZero() {
+		lastActive = h.lastEvent
+		check.Details["lastEvent"] = h.lastEvent.UTC().Format(time.RFC3339)
+	}
+
+	if h.IsStream {
+		check.Details["subscribed"] = h.isSubscribed
+
+		if h.isStandby {
+			check.Details["standby"] = true
+		}
+	}
+
+	if !isReady {
+		return check
+	}
+
+	if h.IsStream && !h.isSubscribed && !h.isStandby {
+		check.Status = STATUS_DOWN
+		check.Error = "not subscribed"
+	}
+
+	if h.MaxAge > 0 && !lastActive.IsZero() && time.Since(lastActive) > h.MaxAge {
+		check.Status = STATUS_DOWN
+		check.Error = "last event too old"
+	}
+
+	return check
+}
+
+// isRequiredNode reports if readiness depends on the node. The first
+// configured node serves users without a node and is always required, other
+// nodes are only required when listed in HEALTH_REQUIRED_NODES.
+func isRequiredNode(lightningNode *lightningnetwork.LightningNode) bool {
+	if lightningNode == nil || lightningNode.Name == lightningnetwork.DEFAULT_NODE_NAME {
+		return true
+	}
+
+	if nodeConfigs := lightningnetwork.GetNodeConfigs(); len(nodeConfigs) > 0 && nodeConfigs[0].Name == lightningNode.Name {
+		return true
+	}
+
+	for _, name := range util.GetEnvStrings("HEALTH_REQUIRED_NODES") {
+		if name == lightningNode.Name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nodeCheckName appends the node name to the check name so each node is
+// reported separately. The default node keeps the check name unchanged.
+func nodeCheckName(name string, lightningNode *lightningnetwork.LightningNode) string {
+	if lightningNode == nil || lightningNode.Name == lightningnetwork.DEFAULT_NODE_NAME {
+		return name
+	}
+
+	return fmt.Sprintf("%s_%s", name, lightningNode.Name)
+}
@@ -0,0 +1,120 @@
+package health_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/satimoto/go-lnm/internal/health"
+	"github.com/satimoto/go-lnm/internal/lightningnetwork"
+)
+
+func TestHeartbeatCheck(t *testing.T) {
+	cases := []struct {
+		desc       string
+		isStream   bool
+		subscribed bool
+		beat       bool
+		maxAge     time.Duration
+		live       string
+		ready      string
+	}{{
+		desc:  "Not started",
+		live:  health.STATUS_UP,
+		ready: health.STATUS_UP,
+	}, {
+		desc:   "Recent event",
+		beat:   true,
+		maxAge: time.Hour,
+		live:   health.STATUS_UP,
+		ready:  health.STATUS_UP,
+	}, {
+		desc:   "Last event too old",
+		beat:   true,
+		maxAge: time.Millisecond,
+		live:   health.STATUS_UP,
+		ready:  health.STATUS_DOWN,
+	}, {
+		desc:       "Stream subscribed",
+		isStream:   true,
+		subscribed: true,
+		live:       health.STATUS_UP,
+		ready:      health.STATUS_UP,
+	}, {
+		desc:     "Stream not subscribed",
+		isStream: true,
+		live:     health.STATUS_UP,
+		ready:    health.STATUS_DOWN,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			heartbeat := health.RegisterHeartbeat("test_heartbeat_"+tc.desc, tc.isStream, tc.maxAge)
+			heartbeat.SetSubscribed(tc.subscribed)
+
+			if tc.beat {
+				heartbeat.Beat()
+			}
+
+			time.Sleep(5 * time.Millisecond)
+
+			if check := heartbeat.Check(false); check.Status != tc.live {
+				t.Errorf("Live status mismatch: %v expecting %v", check.Status, tc.live)
+			}
+
+			if check := heartbeat.Check(true); check.Status != tc.ready {
+				t.Errorf("Ready status mismatch: %v expecting %v", check.Status, tc.ready)
+			}
+		})
+	}
+}
+
+func TestRegisterHeartbeat(t *testing.T) {
+	heartbeat := health.RegisterHeartbeat("test_register", true, time.Minute)
+
+	if registered := health.RegisterHeartbeat("test_register", false, time.Hour); registered != heartbeat {
+		t.Errorf("Expected the registered heartbeat to be returned")
+	}
+
+	var nilHeartbeat *health.Heartbeat
+
+	// Monitors without a registered heartbeat can still beat
+	nilHeartbeat.Beat()
+	nilHeartbeat.SetSubscribed(true)
+}
+
+func TestRegisterNodeHeartbeat(t *testing.T) {
+	os.Setenv("LND_NODES", "alpha,beta,gamma")
+	os.Setenv("HEALTH_REQUIRED_NODES", "gamma")
+	defer os.Unsetenv("LND_NODES")
+	defer os.Unsetenv("HEALTH_REQUIRED_NODES")
+
+	cases := []struct {
+		desc     string
+		node     string
+		optional bool
+	}{{
+		desc:     "First node",
+		node:     "alpha",
+		optional: false,
+	}, {
+		desc:     "Other node",
+		node:     "beta",
+		optional: true,
+	}, {
+		desc:     "Required node",
+		node:     "gamma",
+		optional: false,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			lightningNode := lightningnetwork.NewLightningNode(lightningnetwork.NodeConfig{Name: tc.node}, nil)
+			heartbeat := health.RegisterNodeHeartbeat(lightningNode, "test_node", false, 0)
+
+			if check := heartbeat.Check(true); check.Optional != tc.optional {
+				t.Errorf("Optional mismatch: %v expecting %v", check.Optional, tc.optional)
+			}
+		})
+	}
+}
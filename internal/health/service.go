@@ -0,0 +1,242 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	dbUtil "github.com/satimoto/go-datastore/pkg/util"
+	"github.com/satimoto/go-lnm/internal/ferp"
+	"github.com/satimoto/go-lnm/internal/lightningnetwork"
+	"github.com/satimoto/go-lnm/pkg/util"
+	"github.com/satimoto/go-ocpi/ocpirpc"
+	"github.com/satimoto/go-ocpi/pkg/ocpi"
+)
+
+type CheckFunc func(ctx context.Context) *CheckDto
+
+type Health interface {
+	Live(ctx context.Context) *HealthDto
+	Ready(ctx context.Context) *HealthDto
+}
+
+type HealthService struct {
+	Checks         map[string]CheckFunc
+	OptionalChecks map[string]bool
+	CheckTimeout   time.Duration
+}
+
+func NewService(database *sql.DB, ferpService ferp.Ferp, lightningNodes lightningnetwork.LightningNodes, ocpiService ocpi.Ocpi) Health {
+	rateMaxAge := time.Duration(dbUtil.GetEnvInt32("FERP_RATE_MAX_AGE", 900)) * time.Second
+	checks := map[string]CheckFunc{
+		"database": NewDatabaseCheck(database),
+		"ferp":     NewFerpCheck(ferpService, rateMaxAge),
+		"ocpi":     NewOcpiCheck(ocpiService),
+	}
+
+	optionalChecks := make(map[string]bool)
+
+	for _, lightningNode := range lightningNodes.ListNodes() {
+		name := nodeCheckName("lnd", lightningNode)
+		checks[name] = NewLightningCheck(lightningNode.LightningService)
+		optionalChecks[name] = !isRequiredNode(lightningNode)
+	}
+
+	return &HealthService{
+		Checks:         checks,
+		OptionalChecks: optionalChecks,
+		CheckTimeout:   time.Duration(dbUtil.GetEnvInt32("HEALTH_CHECK_TIMEOUT", 5)) * time.Second,
+	}
+}
+
+// Live reports if the process is running. Heartbeats are reported without
+// checking their age or subscription, which are only checked by Ready.
+func (s *HealthService) Live(ctx context.Context) *HealthDto {
+	return newHealthDto(heartbeatChecks(false))
+}
+
+// Ready reports if the service and its dependencies can handle requests
+func (s *HealthService) Ready(ctx context.Context) *HealthDto {
+	checks := heartbeatChecks(true)
+	ctx, cancel := context.WithTimeout(ctx, s.CheckTimeout)
+	defer cancel()
+
+	var waitGroup sync.WaitGroup
+	var mutex sync.Mutex
+
+	for name, checkFunc := range s.Checks {
+		waitGroup.Add(1)
+
+		go func(name string, checkFunc CheckFunc) {
+			defer waitGroup.Done()
+			check := runCheck(ctx, checkFunc)
+			check.Optional = s.OptionalChecks[name]
+
+			mutex.Lock()
+			checks[name] = check
+			mutex.Unlock()
+		}(name, checkFunc)
+	}
+
+	waitGroup.Wait()
+
+	return newHealthDto(checks)
+}
+
+func NewDatabaseCheck(database *sql.DB) CheckFunc {
+	return func(ctx context.Context) *CheckDto {
+		if err := database.PingContext(ctx); err != nil {
+			return NewErrorCheckDto(err)
+		}
+
+		return NewCheckDto(STATUS_UP)
+	}
+}
+
+func NewFerpCheck(ferpService ferp.Ferp, rateMaxAge time.Duration) CheckFunc {
+	return func(ctx context.Context) *CheckDto {
+		if err := ctx.Err(); err != nil {
+			return NewErrorCheckDto(err)
+		}
+
+		lastRateTime := ferpService.GetLastRateTime()
+
+		if lastRateTime.IsZero() {
+			return NewErrorCheckDto(errors.New("no rates received"))
+		}
+
+		check := NewCheckDto(STATUS_UP)
+		check.Details["lastRate"] = lastRateTime.UTC().Format(time.RFC3339)
+
+		if time.Since(lastRateTime) > rateMaxAge {
+			check.Status = STATUS_DOWN
+			check.Error = "last rate too old"
+		}
+
+		return check
+	}
+}
+
+func NewLightningCheck(lightningService lightningnetwork.LightningNetwork) CheckFunc {
+	return func(ctx context.Context) *CheckDto {
+		connectionState := lightningService.GetConnectionState()
+
+		if connectionState == lightningnetwork.CONNECTION_TRANSIENT_FAILURE || connectionState == lightningnetwork.CONNECTION_SHUTDOWN {
+			check := NewCheckDto(STATUS_DOWN)
+			check.Details["connectionState"] = connectionState
+			check.Error = "not connected"
+
+			return check
+		}
+
+		getInfoResponse, err := getInfo(ctx, lightningService)
+
+		if err != nil {
+			check := NewErrorCheckDto(err)
+			check.Details["connectionState"] = connectionState
+
+			return check
+		}
+
+		check := NewCheckDto(STATUS_UP)
+		check.Details["connectionState"] = connectionState
+		check.Details["blockHeight"] = getInfoResponse.BlockHeight
+		check.Details["syncedToChain"] = getInfoResponse.SyncedToChain
+		check.Details["syncedToGraph"] = getInfoResponse.SyncedToGraph
+
+		if !getInfoResponse.SyncedToChain {
+			check.Status = STATUS_DOWN
+			check.Error = "not synced to chain"
+		}
+
+		return check
+	}
+}
+
+func NewOcpiCheck(ocpiService ocpi.Ocpi) CheckFunc {
+	return func(ctx context.Context) *CheckDto {
+		rpcAddr, err := util.GetRpcAddress()
+
+		if err != nil {
+			return NewErrorCheckDto(err)
+		}
+
+		// The OCPI service tests the connection back to our RPC service
+		if _, err := ocpiService.TestConnection(ctx, &ocpirpc.TestConnectionRequest{Addr: rpcAddr}); err != nil {
+			return NewErrorCheckDto(err)
+		}
+
+		return NewCheckDto(STATUS_UP)
+	}
+}
+
+// getInfo returns the node info, or the context error if the context ends
+// before the node responds
+func getInfo(ctx context.Context, lightningService lightningnetwork.LightningNetwork) (*lnrpc.GetInfoResponse, error) {
+	type getInfoResult struct {
+		response *lnrpc.GetInfoResponse
+		err      error
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	resultChan := make(chan getInfoResult, 1)
+
+	go func() {
+		response, err := lightningService.GetInfo(&lnrpc.GetInfoRequest{})
+		resultChan <- getInfoResult{response: response, err: err}
+	}()
+
+	select {
+	case result := <-resultChan:
+		return result.response, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func heartbeatChecks(isReady bool) map[string]*CheckDto {
+	checks := make(map[string]*CheckDto)
+
+	for _, heartbeat := range ListHeartbeats() {
+		checks[heartbeat.Name] = heartbeat.Check(isReady)
+	}
+
+	return checks
+}
+
+// runCheck runs the check, reporting it down if it does not complete within the context
+func runCheck(ctx context.Context, checkFunc CheckFunc) *CheckDto {
+	checkChan := make(chan *CheckDto, 1)
+
+	go func() {
+		checkChan <- checkFunc(ctx)
+	}()
+
+	select {
+	case check := <-checkChan:
+		return check
+	case <-ctx.Done():
+		return NewErrorCheckDto(ctx.Err())
+	}
+}
+
+func newHealthDto(checks map[string]*CheckDto) *HealthDto {
+	health := &HealthDto{
+		Status: STATUS_UP,
+		Checks: checks,
+	}
+
+	for _, check := range checks {
+		if check.Status != STATUS_UP && !check.Optional {
+			health.Status = STATUS_DOWN
+		}
+	}
+
+	return health
+}
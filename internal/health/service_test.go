@@ -0,0 +1,190 @@
+package health_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	ferpMocks "github.com/satimoto/go-lnm/internal/ferp/mocks"
+	"github.com/satimoto/go-lnm/internal/health"
+	"github.com/satimoto/go-lnm/internal/lightningnetwork"
+	lightningnetworkMocks "github.com/satimoto/go-lnm/internal/lightningnetwork/mocks"
+)
+
+func TestLive(t *testing.T) {
+	heartbeat := health.RegisterHeartbeat("test_live", true, time.Millisecond)
+	heartbeat.Beat()
+	time.Sleep(5 * time.Millisecond)
+
+	healthService := &health.HealthService{
+		Checks: map[string]health.CheckFunc{
+			"failing": func(ctx context.Context) *health.CheckDto {
+				return health.NewCheckDto(health.STATUS_DOWN)
+			},
+		},
+		CheckTimeout: time.Second,
+	}
+
+	liveHealth := healthService.Live(context.Background())
+
+	if liveHealth.Status != health.STATUS_UP {
+		t.Errorf("Live status mismatch: %v expecting %v", liveHealth.Status, health.STATUS_UP)
+	}
+
+	if _, ok := liveHealth.Checks["failing"]; ok {
+		t.Errorf("Unexpected dependency check in liveness")
+	}
+
+	readyHealth := healthService.Ready(context.Background())
+
+	if readyHealth.Status != health.STATUS_DOWN {
+		t.Errorf("Ready status mismatch: %v expecting %v", readyHealth.Status, health.STATUS_DOWN)
+	}
+
+	if check, ok := readyHealth.Checks["test_live"]; !ok || check.Status != health.STATUS_DOWN {
+		t.Errorf("Expected stale heartbeat down: %#v", check)
+	}
+}
+
+func TestReadyTimeout(t *testing.T) {
+	healthService := &health.HealthService{
+		Checks: map[string]health.CheckFunc{
+			"slow": func(ctx context.Context) *health.CheckDto {
+				time.Sleep(time.Second)
+				return health.NewCheckDto(health.STATUS_UP)
+			},
+		},
+		CheckTimeout: 10 * time.Millisecond,
+	}
+
+	readyHealth := healthService.Ready(context.Background())
+
+	if check := readyHealth.Checks["slow"]; check == nil || check.Status != health.STATUS_DOWN {
+		t.Errorf("Expected slow check down: %#v", check)
+	}
+}
+
+func TestReadyOptionalCheck(t *testing.T) {
+	// Heartbeats registered by other tests are also reported
+	baseHealth := (&health.HealthService{CheckTimeout: time.Second}).Ready(context.Background())
+
+	healthService := &health.HealthService{
+		Checks: map[string]health.CheckFunc{
+			"lnd_beta": func(ctx context.Context) *health.CheckDto {
+				return health.NewCheckDto(health.STATUS_DOWN)
+			},
+		},
+		OptionalChecks: map[string]bool{"lnd_beta": true},
+		CheckTimeout:   time.Second,
+	}
+
+	readyHealth := healthService.Ready(context.Background())
+
+	if check := readyHealth.Checks["lnd_beta"]; check == nil || check.Status != health.STATUS_DOWN || !check.Optional {
+		t.Errorf("Expected optional check down: %#v", check)
+	}
+
+	if readyHealth.Status != baseHealth.Status {
+		t.Errorf("Ready status mismatch: %v expecting %v", readyHealth.Status, baseHealth.Status)
+	}
+}
+
+func TestFerpCheck(t *testing.T) {
+	cases := []struct {
+		desc         string
+		lastRateTime time.Time
+		cancelled    bool
+		status       string
+	}{{
+		desc:   "No rates received",
+		status: health.STATUS_DOWN,
+	}, {
+		desc:         "Recent rate",
+		lastRateTime: time.Now(),
+		status:       health.STATUS_UP,
+	}, {
+		desc:         "Rate too old",
+		lastRateTime: time.Now().Add(-time.Hour),
+		status:       health.STATUS_DOWN,
+	}, {
+		desc:         "Context cancelled",
+		lastRateTime: time.Now(),
+		cancelled:    true,
+		status:       health.STATUS_DOWN,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			if tc.cancelled {
+				cancel()
+			}
+
+			mockFerpService := ferpMocks.NewService()
+			mockFerpService.SetLastRateTime(tc.lastRateTime)
+
+			if check := health.NewFerpCheck(mockFerpService, 15*time.Minute)(ctx); check.Status != tc.status {
+				t.Errorf("Status mismatch: %v expecting %v", check.Status, tc.status)
+			}
+		})
+	}
+}
+
+func TestLightningCheck(t *testing.T) {
+	cases := []struct {
+		desc            string
+		connectionState lightningnetwork.ConnectionState
+		getInfo         *lnrpc.GetInfoResponse
+		cancelled       bool
+		status          string
+	}{{
+		desc:            "Not connected",
+		connectionState: lightningnetwork.CONNECTION_TRANSIENT_FAILURE,
+		status:          health.STATUS_DOWN,
+	}, {
+		desc:            "Synced to chain",
+		connectionState: lightningnetwork.CONNECTION_READY,
+		getInfo:         &lnrpc.GetInfoResponse{BlockHeight: 100, SyncedToChain: true},
+		status:          health.STATUS_UP,
+	}, {
+		desc:            "Not synced to chain",
+		connectionState: lightningnetwork.CONNECTION_READY,
+		getInfo:         &lnrpc.GetInfoResponse{BlockHeight: 100},
+		status:          health.STATUS_DOWN,
+	}, {
+		desc:            "GetInfo error",
+		connectionState: lightningnetwork.CONNECTION_READY,
+		status:          health.STATUS_DOWN,
+	}, {
+		desc:            "Context cancelled",
+		connectionState: lightningnetwork.CONNECTION_READY,
+		getInfo:         &lnrpc.GetInfoResponse{BlockHeight: 100, SyncedToChain: true},
+		cancelled:       true,
+		status:          health.STATUS_DOWN,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			if tc.cancelled {
+				cancel()
+			}
+
+			mockLightningService := lightningnetworkMocks.NewService()
+			mockLightningService.SetConnectionState(tc.connectionState)
+
+			if tc.getInfo != nil {
+				mockLightningService.SetGetInfoMockData(tc.getInfo)
+			}
+
+			if check := health.NewLightningCheck(mockLightningService)(ctx); check.Status != tc.status {
+				t.Errorf("Status mismatch: %v expecting %v", check.Status, tc.status)
+			}
+		})
+	}
+}
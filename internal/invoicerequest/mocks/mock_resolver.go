@@ -4,12 +4,13 @@ import (
 	mocks "github.com/satimoto/go-datastore/pkg/db/mocks"
 	invoicerequestMocks "github.com/satimoto/go-datastore/pkg/invoicerequest/mocks"
 	"github.com/satimoto/go-lnm/internal/invoicerequest"
+	notificationoutboxMocks "github.com/satimoto/go-lnm/internal/notificationoutbox/mocks"
 	"github.com/satimoto/go-lnm/internal/service"
 )
 
 func NewResolver(repositoryService *mocks.MockRepositoryService, services *service.ServiceResolver) *invoicerequest.InvoiceRequestResolver {
 	return &invoicerequest.InvoiceRequestResolver{
-		Repository:          invoicerequestMocks.NewRepository(repositoryService),
-		NotificationService: services.NotificationService,
+		Repository:                 invoicerequestMocks.NewRepository(repositoryService),
+		NotificationOutboxResolver: notificationoutboxMocks.NewResolver(repositoryService, services),
 	}
 }
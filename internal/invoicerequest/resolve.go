@@ -3,18 +3,18 @@ package invoicerequest
 import (
 	"github.com/satimoto/go-datastore/pkg/db"
 	"github.com/satimoto/go-datastore/pkg/invoicerequest"
-	"github.com/satimoto/go-lnm/internal/notification"
+	"github.com/satimoto/go-lnm/internal/notificationoutbox"
 	"github.com/satimoto/go-lnm/internal/service"
 )
 
 type InvoiceRequestResolver struct {
-	Repository          invoicerequest.InvoiceRequestRepository
-	NotificationService notification.Notification
+	Repository                 invoicerequest.InvoiceRequestRepository
+	NotificationOutboxResolver *notificationoutbox.NotificationOutboxResolver
 }
 
 func NewResolver(repositoryService *db.RepositoryService, services *service.ServiceResolver) *InvoiceRequestResolver {
 	return &InvoiceRequestResolver{
-		Repository:          invoicerequest.NewRepository(repositoryService),
-		NotificationService: services.NotificationService,
+		Repository:                 invoicerequest.NewRepository(repositoryService),
+		NotificationOutboxResolver: notificationoutbox.NewResolver(repositoryService, services),
 	}
 }
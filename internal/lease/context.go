@@ -0,0 +1,44 @@
+package lease
+
+import (
+	"context"
+	"database/sql"
+
+	dbUtil "github.com/satimoto/go-datastore/pkg/util"
+)
+
+type heldLeaseKey struct{}
+
+// Fence identifies the lease guarding a write. Inserts carrying a fence are
+// only applied while the lease still has the same fencing token.
+type Fence struct {
+	ResourceType sql.NullString
+	ResourceID   sql.NullInt64
+	FencingToken sql.NullInt64
+}
+
+// WithLease returns a context carrying the held lease
+func WithLease(ctx context.Context, heldLease *HeldLease) context.Context {
+	return context.WithValue(ctx, heldLeaseKey{}, heldLease)
+}
+
+// GetLease returns the held lease carried by the context
+func GetLease(ctx context.Context) (*HeldLease, bool) {
+	heldLease, ok := ctx.Value(heldLeaseKey{}).(*HeldLease)
+
+	return heldLease, ok && heldLease != nil
+}
+
+// GetFence returns the fence of the lease carried by the context, or an
+// empty fence if the write is not guarded by a lease
+func GetFence(ctx context.Context) Fence {
+	if heldLease, ok := GetLease(ctx); ok {
+		return Fence{
+			ResourceType: dbUtil.SqlNullString(heldLease.ResourceType),
+			ResourceID:   dbUtil.SqlNullInt64(heldLease.ResourceID),
+			FencingToken: dbUtil.SqlNullInt64(heldLease.FencingToken),
+		}
+	}
+
+	return Fence{}
+}
@@ -0,0 +1,21 @@
+package lease
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricLeasesHeld = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "lsp_leases_held",
+		Help: "The number of leases held by this instance",
+	}, []string{"resource_type"})
+	metricLeasesAcquiredTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lsp_leases_acquired_total",
+		Help: "The total number of leases acquired",
+	}, []string{"resource_type"})
+	metricLeasesLostTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lsp_leases_lost_total",
+		Help: "The total number of leases lost to another instance",
+	}, []string{"resource_type"})
+)
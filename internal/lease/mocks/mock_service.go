@@ -0,0 +1,62 @@
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/satimoto/go-lnm/internal/lease"
+)
+
+type MockLeaseService struct {
+	acquireMockData []error
+	checkMockData   []error
+}
+
+func NewService() *MockLeaseService {
+	return &MockLeaseService{}
+}
+
+func (s *MockLeaseService) Acquire(ctx context.Context, resourceType string, resourceID int64, lostFunc func()) (*lease.HeldLease, error) {
+	if len(s.acquireMockData) > 0 {
+		err := s.acquireMockData[0]
+		s.acquireMockData = s.acquireMockData[1:]
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &lease.HeldLease{
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		FencingToken: 1,
+	}, nil
+}
+
+func (s *MockLeaseService) SetAcquireMockData(err error) {
+	s.acquireMockData = append(s.acquireMockData, err)
+}
+
+func (s *MockLeaseService) Check(ctx context.Context, heldLease *lease.HeldLease) error {
+	if len(s.checkMockData) == 0 {
+		return nil
+	}
+
+	err := s.checkMockData[0]
+	s.checkMockData = s.checkMockData[1:]
+	return err
+}
+
+func (s *MockLeaseService) SetCheckMockData(err error) {
+	s.checkMockData = append(s.checkMockData, err)
+}
+
+func (s *MockLeaseService) End(ctx context.Context, heldLease *lease.HeldLease) {}
+
+func (s *MockLeaseService) IsHeld(resourceType string, resourceID int64) bool {
+	return false
+}
+
+func (s *MockLeaseService) Release(ctx context.Context, heldLease *lease.HeldLease) {}
+
+func (s *MockLeaseService) Start(shutdownCtx context.Context, waitGroup *sync.WaitGroup) {}
@@ -0,0 +1,286 @@
+package lease
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+	"github.com/satimoto/go-datastore/pkg/lease"
+	"github.com/satimoto/go-datastore/pkg/util"
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+)
+
+type Lease interface {
+	Acquire(ctx context.Context, resourceType string, resourceID int64, lostFunc func()) (*HeldLease, error)
+	Check(ctx context.Context, heldLease *HeldLease) error
+	End(ctx context.Context, heldLease *HeldLease)
+	IsHeld(resourceType string, resourceID int64) bool
+	Release(ctx context.Context, heldLease *HeldLease)
+	Start(shutdownCtx context.Context, waitGroup *sync.WaitGroup)
+}
+
+type LeaseService struct {
+	Repository lease.LeaseRepository
+	owner      string
+	duration   time.Duration
+	mutex      sync.Mutex
+	heldLeases map[string]*HeldLease
+}
+
+func NewService(repositoryService *db.RepositoryService) Lease {
+	hostname, _ := os.Hostname()
+	owner := util.GetEnv("LEASE_OWNER", fmt.Sprintf("%s-%d", hostname, os.Getpid()))
+	duration := time.Duration(util.GetEnvInt32("LEASE_DURATION", 60)) * time.Second
+
+	return NewServiceWithRepository(lease.NewRepository(repositoryService), owner, duration)
+}
+
+func NewServiceWithRepository(repository lease.LeaseRepository, owner string, duration time.Duration) Lease {
+	return &LeaseService{
+		Repository: repository,
+		owner:      owner,
+		duration:   duration,
+		heldLeases: make(map[string]*HeldLease),
+	}
+}
+
+// Acquire takes the lease for a resource if it is free or expired. A lease
+// already held, by this or another instance, returns ErrLeaseHeld so the
+// resource is only worked on once, and an ended lease returns ErrLeaseEnded.
+// The lost function is called if the lease cannot be renewed before it expires.
+func (s *LeaseService) Acquire(ctx context.Context, resourceType string, resourceID int64, lostFunc func()) (*HeldLease, error) {
+	key := leaseKey(resourceType, resourceID)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.heldLeases[key]; ok {
+		return nil, ErrLeaseHeld
+	}
+
+	acquireLeaseParams := db.AcquireLeaseParams{
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Owner:        s.owner,
+		ExpiryDate:   time.Now().Add(s.duration),
+	}
+
+	l, err := s.Repository.AcquireLease(ctx, acquireLeaseParams)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			if l, err := s.Repository.GetLease(ctx, db.GetLeaseParams{ResourceType: resourceType, ResourceID: resourceID}); err == nil && l.IsEnded {
+				return nil, ErrLeaseEnded
+			}
+
+			return nil, ErrLeaseHeld
+		}
+
+		metrics.RecordError("LNM237", "Error acquiring lease", err)
+		log.Printf("LNM237: Params=%#v", acquireLeaseParams)
+		return nil, err
+	}
+
+	heldLease := &HeldLease{
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		FencingToken: l.FencingToken,
+		expiryDate:   l.ExpiryDate,
+		lostFunc:     lostFunc,
+	}
+
+	s.heldLeases[key] = heldLease
+	metricLeasesHeld.WithLabelValues(resourceType).Inc()
+	metricLeasesAcquiredTotal.WithLabelValues(resourceType).Inc()
+
+	return heldLease, nil
+}
+
+// Check verifies the lease is still held with the same fencing token.
+// Call it before writes that must only be made by the owner.
+func (s *LeaseService) Check(ctx context.Context, heldLease *HeldLease) error {
+	if heldLease == nil || !s.IsHeld(heldLease.ResourceType, heldLease.ResourceID) {
+		return ErrLeaseLost
+	}
+
+	l, err := s.Repository.GetLease(ctx, db.GetLeaseParams{
+		ResourceType: heldLease.ResourceType,
+		ResourceID:   heldLease.ResourceID,
+	})
+
+	if err != nil {
+		metrics.RecordError("LNM238", "Error retrieving lease", err)
+		log.Printf("LNM238: ResourceType=%v, ResourceID=%v", heldLease.ResourceType, heldLease.ResourceID)
+		return err
+	}
+
+	if l.Owner != s.owner || l.FencingToken != heldLease.FencingToken || time.Now().After(l.ExpiryDate) {
+		log.Printf("LNM239: ResourceType=%v, ResourceID=%v, FencingToken=%v, Owner=%v, LeaseFencingToken=%v", heldLease.ResourceType, heldLease.ResourceID, heldLease.FencingToken, l.Owner, l.FencingToken)
+		s.lose(heldLease)
+		return ErrLeaseLost
+	}
+
+	return nil
+}
+
+// End gives up the lease and records the work on the resource as finished,
+// so the lease cannot be acquired again
+func (s *LeaseService) End(ctx context.Context, heldLease *HeldLease) {
+	if heldLease == nil || !s.remove(heldLease) {
+		return
+	}
+
+	endLeaseParams := db.EndLeaseParams{
+		ResourceType: heldLease.ResourceType,
+		ResourceID:   heldLease.ResourceID,
+		Owner:        s.owner,
+		FencingToken: heldLease.FencingToken,
+	}
+
+	if err := s.Repository.EndLease(ctx, endLeaseParams); err != nil {
+		metrics.RecordError("LNM281", "Error ending lease", err)
+		log.Printf("LNM281: Params=%#v", endLeaseParams)
+	}
+}
+
+func (s *LeaseService) IsHeld(resourceType string, resourceID int64) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	_, ok := s.heldLeases[leaseKey(resourceType, resourceID)]
+
+	return ok
+}
+
+// Release gives up the lease so another instance can take it over immediately
+func (s *LeaseService) Release(ctx context.Context, heldLease *HeldLease) {
+	if heldLease == nil || !s.remove(heldLease) {
+		return
+	}
+
+	releaseLeaseParams := db.ReleaseLeaseParams{
+		ResourceType: heldLease.ResourceType,
+		ResourceID:   heldLease.ResourceID,
+		Owner:        s.owner,
+		FencingToken: heldLease.FencingToken,
+	}
+
+	if err := s.Repository.ReleaseLease(ctx, releaseLeaseParams); err != nil {
+		metrics.RecordError("LNM240", "Error releasing lease", err)
+		log.Printf("LNM240: Params=%#v", releaseLeaseParams)
+	}
+}
+
+func (s *LeaseService) Start(shutdownCtx context.Context, waitGroup *sync.WaitGroup) {
+	log.Printf("Starting up Lease service as %v", s.owner)
+	waitGroup.Add(1)
+
+	go s.renewLeases(shutdownCtx, waitGroup)
+}
+
+func (s *LeaseService) renewLeases(shutdownCtx context.Context, waitGroup *sync.WaitGroup) {
+	defer waitGroup.Done()
+
+	ticker := time.NewTicker(s.duration / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-shutdownCtx.Done():
+			log.Printf("Shutting down Lease service")
+			s.releaseAll()
+			return
+		case <-ticker.C:
+			for _, heldLease := range s.listHeldLeases() {
+				s.renew(heldLease)
+			}
+		}
+	}
+}
+
+func (s *LeaseService) renew(heldLease *HeldLease) {
+	ctx := context.Background()
+	renewLeaseParams := db.RenewLeaseParams{
+		ResourceType: heldLease.ResourceType,
+		ResourceID:   heldLease.ResourceID,
+		Owner:        s.owner,
+		FencingToken: heldLease.FencingToken,
+		ExpiryDate:   time.Now().Add(s.duration),
+	}
+
+	l, err := s.Repository.RenewLease(ctx, renewLeaseParams)
+
+	if err == nil {
+		s.mutex.Lock()
+		heldLease.expiryDate = l.ExpiryDate
+		s.mutex.Unlock()
+		return
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		// Another instance has taken over the lease
+		log.Printf("LNM241: Params=%#v", renewLeaseParams)
+		s.lose(heldLease)
+		return
+	}
+
+	metrics.RecordError("LNM242", "Error renewing lease", err)
+	log.Printf("LNM242: Params=%#v", renewLeaseParams)
+
+	if time.Now().After(heldLease.expiryDate) {
+		// The lease may have been taken over while the database was unreachable
+		s.lose(heldLease)
+	}
+}
+
+func (s *LeaseService) lose(heldLease *HeldLease) {
+	if s.remove(heldLease) {
+		metricLeasesLostTotal.WithLabelValues(heldLease.ResourceType).Inc()
+
+		if heldLease.lostFunc != nil {
+			go heldLease.lostFunc()
+		}
+	}
+}
+
+func (s *LeaseService) remove(heldLease *HeldLease) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	key := leaseKey(heldLease.ResourceType, heldLease.ResourceID)
+
+	if current, ok := s.heldLeases[key]; ok && current == heldLease {
+		delete(s.heldLeases, key)
+		metricLeasesHeld.WithLabelValues(heldLease.ResourceType).Dec()
+		return true
+	}
+
+	return false
+}
+
+func (s *LeaseService) listHeldLeases() []*HeldLease {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	heldLeases := []*HeldLease{}
+
+	for _, heldLease := range s.heldLeases {
+		heldLeases = append(heldLeases, heldLease)
+	}
+
+	return heldLeases
+}
+
+func (s *LeaseService) releaseAll() {
+	ctx := context.Background()
+
+	for _, heldLease := range s.listHeldLeases() {
+		s.Release(ctx, heldLease)
+	}
+}
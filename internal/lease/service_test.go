@@ -0,0 +1,253 @@
+package lease_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+	"github.com/satimoto/go-lnm/internal/lease"
+)
+
+type leaseTable struct {
+	mutex  sync.Mutex
+	leases map[string]db.Lease
+}
+
+func newLeaseTable() *leaseTable {
+	return &leaseTable{leases: make(map[string]db.Lease)}
+}
+
+func (t *leaseTable) AcquireLease(ctx context.Context, arg db.AcquireLeaseParams) (db.Lease, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	key := fmt.Sprintf("%v:%v", arg.ResourceType, arg.ResourceID)
+	l, ok := t.leases[key]
+
+	if ok && (l.IsEnded || (l.Owner != arg.Owner && time.Now().Before(l.ExpiryDate))) {
+		return db.Lease{}, sql.ErrNoRows
+	}
+
+	if !ok || l.Owner != arg.Owner {
+		l.FencingToken++
+	}
+
+	l.ResourceType, l.ResourceID, l.Owner, l.ExpiryDate = arg.ResourceType, arg.ResourceID, arg.Owner, arg.ExpiryDate
+	t.leases[key] = l
+
+	return l, nil
+}
+
+func (t *leaseTable) EndLease(ctx context.Context, arg db.EndLeaseParams) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	key := fmt.Sprintf("%v:%v", arg.ResourceType, arg.ResourceID)
+
+	if l, ok := t.leases[key]; ok && l.Owner == arg.Owner && l.FencingToken == arg.FencingToken {
+		l.ExpiryDate = time.Time{}
+		l.IsEnded = true
+		t.leases[key] = l
+	}
+
+	return nil
+}
+
+func (t *leaseTable) GetLease(ctx context.Context, arg db.GetLeaseParams) (db.Lease, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if l, ok := t.leases[fmt.Sprintf("%v:%v", arg.ResourceType, arg.ResourceID)]; ok {
+		return l, nil
+	}
+
+	return db.Lease{}, sql.ErrNoRows
+}
+
+func (t *leaseTable) ReleaseLease(ctx context.Context, arg db.ReleaseLeaseParams) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	key := fmt.Sprintf("%v:%v", arg.ResourceType, arg.ResourceID)
+
+	if l, ok := t.leases[key]; ok && l.Owner == arg.Owner && l.FencingToken == arg.FencingToken {
+		l.ExpiryDate = time.Time{}
+		t.leases[key] = l
+	}
+
+	return nil
+}
+
+func (t *leaseTable) RenewLease(ctx context.Context, arg db.RenewLeaseParams) (db.Lease, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	key := fmt.Sprintf("%v:%v", arg.ResourceType, arg.ResourceID)
+	l, ok := t.leases[key]
+
+	if !ok || l.Owner != arg.Owner || l.FencingToken != arg.FencingToken {
+		return db.Lease{}, sql.ErrNoRows
+	}
+
+	l.ExpiryDate = arg.ExpiryDate
+	t.leases[key] = l
+
+	return l, nil
+}
+
+func TestLease(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Lease held by another owner", func(t *testing.T) {
+		table := newLeaseTable()
+		leaseService1 := lease.NewServiceWithRepository(table, "instance1", time.Minute)
+		leaseService2 := lease.NewServiceWithRepository(table, "instance2", time.Minute)
+
+		if _, err := leaseService1.Acquire(ctx, lease.LEASE_SESSION, 1, nil); err != nil {
+			t.Fatalf("Error acquiring lease: %v", err)
+		}
+
+		if _, err := leaseService2.Acquire(ctx, lease.LEASE_SESSION, 1, nil); !errors.Is(err, lease.ErrLeaseHeld) {
+			t.Errorf("Error mismatch: %v expecting %v", err, lease.ErrLeaseHeld)
+		}
+
+		if !leaseService1.IsHeld(lease.LEASE_SESSION, 1) || leaseService2.IsHeld(lease.LEASE_SESSION, 1) {
+			t.Error("Lease held mismatch")
+		}
+	})
+
+	t.Run("Lease held by this instance", func(t *testing.T) {
+		table := newLeaseTable()
+		leaseService := lease.NewServiceWithRepository(table, "instance1", time.Minute)
+
+		heldLease, err := leaseService.Acquire(ctx, lease.LEASE_SESSION, 1, nil)
+
+		if err != nil {
+			t.Fatalf("Error acquiring lease: %v", err)
+		}
+
+		if _, err := leaseService.Acquire(ctx, lease.LEASE_SESSION, 1, nil); !errors.Is(err, lease.ErrLeaseHeld) {
+			t.Errorf("Error mismatch: %v expecting %v", err, lease.ErrLeaseHeld)
+		}
+
+		// The first holder still owns the lease
+		if err := leaseService.Check(ctx, heldLease); err != nil {
+			t.Errorf("Error checking lease: %v", err)
+		}
+	})
+
+	t.Run("Takeover after release", func(t *testing.T) {
+		table := newLeaseTable()
+		leaseService1 := lease.NewServiceWithRepository(table, "instance1", time.Minute)
+		leaseService2 := lease.NewServiceWithRepository(table, "instance2", time.Minute)
+
+		heldLease1, _ := leaseService1.Acquire(ctx, lease.LEASE_SESSION, 1, nil)
+		leaseService1.Release(ctx, heldLease1)
+
+		heldLease2, err := leaseService2.Acquire(ctx, lease.LEASE_SESSION, 1, nil)
+
+		if err != nil {
+			t.Fatalf("Error acquiring lease: %v", err)
+		}
+
+		if heldLease2.FencingToken <= heldLease1.FencingToken {
+			t.Errorf("Fencing token mismatch: %v expecting greater than %v", heldLease2.FencingToken, heldLease1.FencingToken)
+		}
+
+		if err := leaseService1.Check(ctx, heldLease1); !errors.Is(err, lease.ErrLeaseLost) {
+			t.Errorf("Error mismatch: %v expecting %v", err, lease.ErrLeaseLost)
+		}
+
+		if err := leaseService2.Check(ctx, heldLease2); err != nil {
+			t.Errorf("Error checking lease: %v", err)
+		}
+	})
+
+	t.Run("No takeover after end", func(t *testing.T) {
+		table := newLeaseTable()
+		leaseService1 := lease.NewServiceWithRepository(table, "instance1", time.Minute)
+		leaseService2 := lease.NewServiceWithRepository(table, "instance2", time.Minute)
+
+		heldLease1, _ := leaseService1.Acquire(ctx, lease.LEASE_SESSION, 1, nil)
+		leaseService1.End(ctx, heldLease1)
+
+		if _, err := leaseService2.Acquire(ctx, lease.LEASE_SESSION, 1, nil); !errors.Is(err, lease.ErrLeaseEnded) {
+			t.Errorf("Error mismatch: %v expecting %v", err, lease.ErrLeaseEnded)
+		}
+
+		if leaseService1.IsHeld(lease.LEASE_SESSION, 1) {
+			t.Error("Lease held mismatch")
+		}
+	})
+
+	t.Run("Fence stale owner after expiry", func(t *testing.T) {
+		table := newLeaseTable()
+		leaseService1 := lease.NewServiceWithRepository(table, "instance1", 10*time.Millisecond)
+		leaseService2 := lease.NewServiceWithRepository(table, "instance2", time.Minute)
+		lostChan := make(chan struct{}, 1)
+
+		heldLease1, _ := leaseService1.Acquire(ctx, lease.LEASE_SESSION, 1, func() {
+			lostChan <- struct{}{}
+		})
+
+		time.Sleep(20 * time.Millisecond)
+
+		if _, err := leaseService2.Acquire(ctx, lease.LEASE_SESSION, 1, nil); err != nil {
+			t.Fatalf("Error taking over lease: %v", err)
+		}
+
+		if err := leaseService1.Check(ctx, heldLease1); !errors.Is(err, lease.ErrLeaseLost) {
+			t.Errorf("Error mismatch: %v expecting %v", err, lease.ErrLeaseLost)
+		}
+
+		select {
+		case <-lostChan:
+		case <-time.After(time.Second):
+			t.Error("Expected lost function to be called")
+		}
+	})
+
+	t.Run("Renew and release on shutdown", func(t *testing.T) {
+		shutdownCtx, cancelFunc := context.WithCancel(context.Background())
+		waitGroup := &sync.WaitGroup{}
+		table := newLeaseTable()
+		leaseService1 := lease.NewServiceWithRepository(table, "instance1", 30*time.Millisecond)
+		leaseService2 := lease.NewServiceWithRepository(table, "instance2", time.Minute)
+
+		leaseService1.Start(shutdownCtx, waitGroup)
+		leaseService1.Acquire(ctx, lease.LEASE_CDR, 1, nil)
+
+		time.Sleep(100 * time.Millisecond)
+
+		if _, err := leaseService2.Acquire(ctx, lease.LEASE_CDR, 1, nil); !errors.Is(err, lease.ErrLeaseHeld) {
+			t.Errorf("Error mismatch: %v expecting %v", err, lease.ErrLeaseHeld)
+		}
+
+		cancelFunc()
+		waitGroup.Wait()
+
+		if _, err := leaseService2.Acquire(ctx, lease.LEASE_CDR, 1, nil); err != nil {
+			t.Errorf("Error acquiring lease: %v", err)
+		}
+	})
+	t.Run("Carry lease in context", func(t *testing.T) {
+		table := newLeaseTable()
+		leaseService := lease.NewServiceWithRepository(table, "instance1", time.Minute)
+
+		if fence := lease.GetFence(ctx); fence.FencingToken.Valid {
+			t.Errorf("Fence mismatch: %#v expecting empty fence", fence)
+		}
+
+		heldLease, _ := leaseService.Acquire(ctx, lease.LEASE_SESSION, 1, nil)
+		leaseCtx := lease.WithLease(ctx, heldLease)
+
+		if contextLease, ok := lease.GetLease(leaseCtx); !ok || contextLease != heldLease {
+			t.Errorf("Lease mismatch: %v expecting %v", contextLease, heldLease)
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package lease
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+const (
+	LEASE_CDR                  = "CDR"
+	LEASE_CHANNEL_EVENT        = "CHANNEL_EVENT"
+	LEASE_PEER_EVENT           = "PEER_EVENT"
+	LEASE_PENDING_NOTIFICATION = "PENDING_NOTIFICATION"
+	LEASE_SESSION              = "SESSION"
+)
+
+var (
+	ErrLeaseEnded = errors.New("lease ended")
+	ErrLeaseHeld  = errors.New("lease already held")
+	ErrLeaseLost  = errors.New("lease lost")
+)
+
+// HeldLease is a lease owned by this instance. The fencing token increases
+// each time the lease changes owner, so writes guarded by an older token
+// can be rejected.
+type HeldLease struct {
+	ResourceType string
+	ResourceID   int64
+	FencingToken int64
+	expiryDate   time.Time
+	lostFunc     func()
+}
+
+func leaseKey(resourceType string, resourceID int64) string {
+	return resourceType + ":" + strconv.FormatInt(resourceID, 10)
+}
@@ -0,0 +1,343 @@
+package lightningnetwork
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"log"
+	"os"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/chainrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/walletrpc"
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+	"github.com/satimoto/go-lnm/pkg/util"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+)
+
+type ConnectionState string
+
+const (
+	CONNECTION_IDLE              ConnectionState = "IDLE"
+	CONNECTION_CONNECTING        ConnectionState = "CONNECTING"
+	CONNECTION_READY             ConnectionState = "READY"
+	CONNECTION_TRANSIENT_FAILURE ConnectionState = "TRANSIENT_FAILURE"
+	CONNECTION_SHUTDOWN          ConnectionState = "SHUTDOWN"
+)
+
+var connectionStates = []ConnectionState{
+	CONNECTION_IDLE,
+	CONNECTION_CONNECTING,
+	CONNECTION_READY,
+	CONNECTION_TRANSIENT_FAILURE,
+	CONNECTION_SHUTDOWN,
+}
+
+type ConnectionConfig struct {
+	CallTimeout       time.Duration
+	KeepaliveInterval time.Duration
+	KeepaliveTimeout  time.Duration
+	ReconnectMaxDelay time.Duration
+	ReloadInterval    time.Duration
+}
+
+// connection is a dialled LND connection. Calls and streams use contexts
+// derived from the connection context, which is cancelled when the
+// connection is replaced or closed.
+type connection struct {
+	clientConn          *grpc.ClientConn
+	chainNotifierClient chainrpc.ChainNotifierClient
+	lightningClient     lnrpc.LightningClient
+	routerClient        routerrpc.RouterClient
+	walletKitClient     walletrpc.WalletKitClient
+	ctx                 context.Context
+	cancel              context.CancelFunc
+}
+
+// nodeCredentials are the TLS certificate and macaroon of a node, read from
+// files when paths are configured so they can be reloaded when changed
+type nodeCredentials struct {
+	tlsCert         []byte
+	tlsCertModTime  time.Time
+	macaroon        []byte
+	macaroonModTime time.Time
+}
+
+func dial(grpcHost string, tlsCert []byte, connectionConfig ConnectionConfig) (*connection, error) {
+	transportCredentials, err := util.NewCredential(string(tlsCert))
+
+	if err != nil {
+		return nil, err
+	}
+
+	clientConn, err := grpc.Dial(grpcHost,
+		grpc.WithTransportCredentials(transportCredentials),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                connectionConfig.KeepaliveInterval,
+			Timeout:             connectionConfig.KeepaliveTimeout,
+			PermitWithoutStream: true,
+		}),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff: backoff.Config{
+				BaseDelay:  time.Second,
+				Multiplier: 1.6,
+				Jitter:     0.2,
+				MaxDelay:   connectionConfig.ReconnectMaxDelay,
+			},
+			MinConnectTimeout: 20 * time.Second,
+		}),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &connection{
+		clientConn:          clientConn,
+		chainNotifierClient: chainrpc.NewChainNotifierClient(clientConn),
+		lightningClient:     lnrpc.NewLightningClient(clientConn),
+		routerClient:        routerrpc.NewRouterClient(clientConn),
+		walletKitClient:     walletrpc.NewWalletKitClient(clientConn),
+		ctx:                 ctx,
+		cancel:              cancel,
+	}, nil
+}
+
+func (c *connection) close() {
+	c.cancel()
+	c.clientConn.Close()
+}
+
+func (c *connection) getState() ConnectionState {
+	return ConnectionState(c.clientConn.GetState().String())
+}
+
+func loadCredentials(nodeConfig NodeConfig) (*nodeCredentials, error) {
+	credentials := &nodeCredentials{}
+
+	if len(nodeConfig.TlsCertPath) > 0 {
+		tlsCert, modTime, err := readFile(nodeConfig.TlsCertPath)
+
+		if err != nil {
+			return nil, err
+		}
+
+		credentials.tlsCert = tlsCert
+		credentials.tlsCertModTime = modTime
+	} else {
+		tlsCert, err := base64.StdEncoding.DecodeString(nodeConfig.TlsCert)
+
+		if err != nil {
+			return nil, err
+		}
+
+		credentials.tlsCert = tlsCert
+	}
+
+	if len(nodeConfig.MacaroonPath) > 0 {
+		macaroon, modTime, err := readFile(nodeConfig.MacaroonPath)
+
+		if err != nil {
+			return nil, err
+		}
+
+		credentials.macaroon = macaroon
+		credentials.macaroonModTime = modTime
+	} else {
+		macaroon, err := base64.StdEncoding.DecodeString(nodeConfig.Macaroon)
+
+		if err != nil {
+			return nil, err
+		}
+
+		credentials.macaroon = macaroon
+	}
+
+	return credentials, nil
+}
+
+func readFile(path string) ([]byte, time.Time, error) {
+	fileInfo, err := os.Stat(path)
+
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return data, fileInfo.ModTime(), nil
+}
+
+func (c *nodeCredentials) isModified(nodeConfig NodeConfig) bool {
+	if len(nodeConfig.TlsCertPath) > 0 {
+		if fileInfo, err := os.Stat(nodeConfig.TlsCertPath); err == nil && !fileInfo.ModTime().Equal(c.tlsCertModTime) {
+			return true
+		}
+	}
+
+	if len(nodeConfig.MacaroonPath) > 0 {
+		if fileInfo, err := os.Stat(nodeConfig.MacaroonPath); err == nil && !fileInfo.ModTime().Equal(c.macaroonModTime) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// callContext returns a context for a unary call, cancelled after the call timeout
+func (s *LightningNetworkService) callContext() (context.Context, context.CancelFunc) {
+	conn := s.getConnection()
+	ctx, cancel := context.WithTimeout(conn.ctx, s.connectionConfig.CallTimeout)
+
+	return metadata.AppendToOutgoingContext(ctx, "macaroon", s.getMacaroon()), cancel
+}
+
+// streamContext returns a context for a stream, cancelled when the connection
+// is replaced or closed so the stream ends and can be subscribed again
+func (s *LightningNetworkService) streamContext() context.Context {
+	conn := s.getConnection()
+
+	return metadata.AppendToOutgoingContext(conn.ctx, "macaroon", s.getMacaroon())
+}
+
+func (s *LightningNetworkService) getConnection() *connection {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.connection
+}
+
+func (s *LightningNetworkService) getMacaroon() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return hex.EncodeToString(s.credentials.macaroon)
+}
+
+// manageConnection keeps the connection state metrics current, reloads the
+// credentials when changed and closes the connection on shutdown
+func (s *LightningNetworkService) manageConnection(shutdownCtx context.Context) {
+	reloadTicker := time.NewTicker(s.connectionConfig.ReloadInterval)
+	defer reloadTicker.Stop()
+
+	stateTicker := time.NewTicker(5 * time.Second)
+	defer stateTicker.Stop()
+
+	for {
+		select {
+		case <-shutdownCtx.Done():
+			log.Printf("Closing LND connection %v", s.nodeConfig.Name)
+			s.getConnection().close()
+			s.updateConnectionState()
+			return
+		case <-reloadTicker.C:
+			s.reloadCredentials(false)
+		case <-s.reloadChan:
+			s.reloadCredentials(true)
+		case <-stateTicker.C:
+			s.updateConnectionState()
+		}
+	}
+}
+
+// reloadCredentials reads the credentials again if their files have changed,
+// or when forced after LND rejects the macaroon. The connection is replaced
+// if the TLS certificate has changed.
+func (s *LightningNetworkService) reloadCredentials(force bool) {
+	s.mutex.RLock()
+	currentCredentials := s.credentials
+	s.mutex.RUnlock()
+
+	hasPaths := len(s.nodeConfig.TlsCertPath) > 0 || len(s.nodeConfig.MacaroonPath) > 0
+
+	if !hasPaths || (!force && !currentCredentials.isModified(s.nodeConfig)) {
+		return
+	}
+
+	credentials, err := loadCredentials(s.nodeConfig)
+
+	if err != nil {
+		metrics.RecordError("LNM245", "Error reloading LND credentials", err)
+		log.Printf("LNM245: Node=%v", s.nodeConfig.Name)
+		return
+	}
+
+	var replacedConnection *connection
+
+	if !bytes.Equal(credentials.tlsCert, currentCredentials.tlsCert) {
+		conn, err := dial(s.nodeConfig.GrpcHost, credentials.tlsCert, s.connectionConfig)
+
+		if err != nil {
+			metrics.RecordError("LNM246", "Error connecting to LND host", err)
+			log.Printf("LNM246: Node=%v", s.nodeConfig.Name)
+			return
+		}
+
+		s.mutex.Lock()
+		replacedConnection = s.connection
+		s.connection = conn
+		s.mutex.Unlock()
+
+		metricReconnectsTotal.WithLabelValues(s.getPubkey()).Inc()
+	}
+
+	s.mutex.Lock()
+	s.credentials = credentials
+	s.mutex.Unlock()
+
+	if replacedConnection != nil {
+		// Ends calls and streams on the replaced connection
+		replacedConnection.close()
+	}
+
+	metricCredentialsReloadsTotal.WithLabelValues(s.getPubkey()).Inc()
+	log.Printf("Reloaded LND credentials %v", s.nodeConfig.Name)
+}
+
+// requestReload asks for the credentials to be reloaded without blocking the caller
+func (s *LightningNetworkService) requestReload() {
+	select {
+	case s.reloadChan <- struct{}{}:
+	default:
+	}
+}
+
+func (s *LightningNetworkService) updateConnectionState() {
+	conn := s.getConnection()
+	state := conn.getState()
+
+	if conn.clientConn.GetState() == connectivity.Idle {
+		// Reconnect an idle connection so the state reflects the node availability
+		conn.clientConn.Connect()
+	}
+
+	pubkey := s.getPubkey()
+
+	if len(pubkey) == 0 {
+		// The state is labelled by the node pubkey, known once the node info is requested
+		return
+	}
+
+	for _, connectionState := range connectionStates {
+		value := 0.0
+
+		if connectionState == state {
+			value = 1
+		}
+
+		metricConnectionState.WithLabelValues(pubkey, string(connectionState)).Set(value)
+	}
+}
@@ -0,0 +1,75 @@
+package lightningnetwork
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadCredentials(t *testing.T) {
+	t.Run("Base64 credentials", func(t *testing.T) {
+		nodeConfig := NodeConfig{
+			TlsCert:  base64.StdEncoding.EncodeToString([]byte("cert")),
+			Macaroon: base64.StdEncoding.EncodeToString([]byte("macaroon")),
+		}
+
+		credentials, err := loadCredentials(nodeConfig)
+
+		if err != nil {
+			t.Fatalf("Error loading credentials: %v", err)
+		}
+
+		if string(credentials.tlsCert) != "cert" || string(credentials.macaroon) != "macaroon" {
+			t.Errorf("Credentials mismatch: %#v", credentials)
+		}
+
+		if credentials.isModified(nodeConfig) {
+			t.Errorf("Base64 credentials should not be modified")
+		}
+	})
+
+	t.Run("File credentials", func(t *testing.T) {
+		dir := t.TempDir()
+		nodeConfig := NodeConfig{
+			TlsCertPath:  filepath.Join(dir, "tls.cert"),
+			MacaroonPath: filepath.Join(dir, "admin.macaroon"),
+		}
+
+		os.WriteFile(nodeConfig.TlsCertPath, []byte("cert"), 0600)
+		os.WriteFile(nodeConfig.MacaroonPath, []byte("macaroon"), 0600)
+
+		credentials, err := loadCredentials(nodeConfig)
+
+		if err != nil {
+			t.Fatalf("Error loading credentials: %v", err)
+		}
+
+		if string(credentials.tlsCert) != "cert" || string(credentials.macaroon) != "macaroon" {
+			t.Errorf("Credentials mismatch: %#v", credentials)
+		}
+
+		if credentials.isModified(nodeConfig) {
+			t.Errorf("Credentials should not be modified")
+		}
+
+		os.WriteFile(nodeConfig.MacaroonPath, []byte("rotated"), 0600)
+		modTime := time.Now().Add(time.Minute)
+		os.Chtimes(nodeConfig.MacaroonPath, modTime, modTime)
+
+		if !credentials.isModified(nodeConfig) {
+			t.Errorf("Rotated macaroon should be modified")
+		}
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		nodeConfig := NodeConfig{
+			TlsCertPath: filepath.Join(t.TempDir(), "tls.cert"),
+		}
+
+		if _, err := loadCredentials(nodeConfig); err == nil {
+			t.Errorf("Expected error loading missing file")
+		}
+	})
+}
@@ -0,0 +1,34 @@
+package lightningnetwork
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricRequestDurationSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "lsp_lnd_request_duration_seconds",
+		Help:    "The time taken for LND to respond to a request",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"pubkey", "method"})
+	metricRequestErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lsp_lnd_request_errors_total",
+		Help: "The total number of LND requests responding with an error",
+	}, []string{"pubkey", "method"})
+	metricConnectionState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "lsp_lnd_connection_state",
+		Help: "The state of the LND connection, 1 for the current state",
+	}, []string{"pubkey", "state"})
+	metricCredentialsReloadsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lsp_lnd_credentials_reloads_total",
+		Help: "The total number of LND credential reloads",
+	}, []string{"pubkey"})
+	metricReconnectsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lsp_lnd_reconnects_total",
+		Help: "The total number of LND connections replaced after a TLS certificate change",
+	}, []string{"pubkey"})
+	metricNodeFallbacksTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lsp_lnd_node_fallbacks_total",
+		Help: "The total number of calls for users without a node using the first node",
+	}, []string{"pubkey"})
+)
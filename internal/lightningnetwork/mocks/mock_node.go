@@ -0,0 +1,11 @@
+package mocks
+
+import (
+	"github.com/satimoto/go-lnm/internal/lightningnetwork"
+)
+
+func NewNodes(lightningService *MockLightningNetworkService) lightningnetwork.LightningNodes {
+	return lightningnetwork.NewNodesWithNodes(lightningnetwork.NewLightningNode(lightningnetwork.NodeConfig{
+		Name: lightningnetwork.DEFAULT_NODE_NAME,
+	}, lightningService))
+}
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"io"
+
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
 	"google.golang.org/grpc"
@@ -20,6 +22,11 @@ func NewMockSendPaymentV2Client(recvChan <-chan *lnrpc.Payment) routerrpc.Router
 }
 
 func (c *MockSendPaymentV2Client) Recv() (*lnrpc.Payment, error) {
-	receive := <-c.recvChan
+	receive, ok := <-c.recvChan
+
+	if !ok {
+		return nil, io.EOF
+	}
+
 	return receive, nil
 }
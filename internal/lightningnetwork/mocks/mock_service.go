@@ -1,29 +1,40 @@
 package mocks
 
 import (
+	"context"
 	"encoding/hex"
 	"errors"
+	"sync"
 
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/chainrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/walletrpc"
+	"github.com/satimoto/go-lnm/internal/lightningnetwork"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type MockLightningNetworkService struct {
+	connectionState                 lightningnetwork.ConnectionState
 	allocateAliasMockData           []*lnrpc.AllocateAliasResponse
 	addInvoiceMockData              []*lnrpc.Invoice
 	channelAcceptorMockData         []lnrpc.Lightning_ChannelAcceptorClient
+	closedChannelsMockData          []*lnrpc.ClosedChannelsResponse
+	connectPeerMockData             []*lnrpc.ConnectPeerRequest
 	decodePayReqMockData            []*lnrpc.PayReq
 	estimateFeeMockData             []*walletrpc.EstimateFeeResponse
 	finalizePsbtMockData            []*walletrpc.FinalizePsbtResponse
 	fundingStateStepMockData        []*lnrpc.FundingStateStepResp
 	fundPsbtMockData                []*walletrpc.FundPsbtResponse
 	getInfoMockData                 []*lnrpc.GetInfoResponse
+	getNodeInfoMockData             []*lnrpc.NodeInfo
+	getTransactionsMockData         []*lnrpc.TransactionDetails
 	htlcInterceptorMockData         []routerrpc.Router_HtlcInterceptorClient
 	listChannelsMockData            []*lnrpc.ListChannelsResponse
 	listPeersMockData               []*lnrpc.ListPeersResponse
+	lookupInvoiceMockData           []*lnrpc.Invoice
 	openChannelMockData             []lnrpc.Lightning_OpenChannelClient
 	openChannelSyncMockData         []*lnrpc.ChannelPoint
 	publishTransactionMockData      []*walletrpc.PublishResponse
@@ -39,6 +50,7 @@ type MockLightningNetworkService struct {
 	subscribeInvoicesMockData       []lnrpc.Lightning_SubscribeInvoicesClient
 	subscribePeerEventsMockData     []lnrpc.Lightning_SubscribePeerEventsClient
 	subscribeTransactionsMockData   []lnrpc.Lightning_SubscribeTransactionsClient
+	trackPaymentV2MockData          []routerrpc.Router_TrackPaymentV2Client
 	updateChannelPolicyMockData     []*lnrpc.PolicyUpdateResponse
 	walletBalanceMockData           []*lnrpc.WalletBalanceResponse
 }
@@ -88,6 +100,20 @@ func (s *MockLightningNetworkService) NewChannelAcceptorMockData() (<-chan *lnrp
 	return sendChan, recvChan
 }
 
+func (s *MockLightningNetworkService) ClosedChannels(in *lnrpc.ClosedChannelsRequest, opts ...grpc.CallOption) (*lnrpc.ClosedChannelsResponse, error) {
+	if len(s.closedChannelsMockData) == 0 {
+		return &lnrpc.ClosedChannelsResponse{}, errors.New("NotFound")
+	}
+
+	response := s.closedChannelsMockData[0]
+	s.closedChannelsMockData = s.closedChannelsMockData[1:]
+	return response, nil
+}
+
+func (s *MockLightningNetworkService) SetClosedChannelsMockData(mockData *lnrpc.ClosedChannelsResponse) {
+	s.closedChannelsMockData = append(s.closedChannelsMockData, mockData)
+}
+
 func (s *MockLightningNetworkService) GetAddInvoiceMockData() (*lnrpc.Invoice, error) {
 	if len(s.addInvoiceMockData) == 0 {
 		return &lnrpc.Invoice{}, errors.New("NotFound")
@@ -98,6 +124,22 @@ func (s *MockLightningNetworkService) GetAddInvoiceMockData() (*lnrpc.Invoice, e
 	return response, nil
 }
 
+func (s *MockLightningNetworkService) ConnectPeer(in *lnrpc.ConnectPeerRequest, opts ...grpc.CallOption) (*lnrpc.ConnectPeerResponse, error) {
+	s.connectPeerMockData = append(s.connectPeerMockData, in)
+
+	return &lnrpc.ConnectPeerResponse{}, nil
+}
+
+func (s *MockLightningNetworkService) GetConnectPeerMockData() (*lnrpc.ConnectPeerRequest, error) {
+	if len(s.connectPeerMockData) == 0 {
+		return &lnrpc.ConnectPeerRequest{}, errors.New("NotFound")
+	}
+
+	response := s.connectPeerMockData[0]
+	s.connectPeerMockData = s.connectPeerMockData[1:]
+	return response, nil
+}
+
 func (s *MockLightningNetworkService) DecodePayReq(in *lnrpc.PayReqString, opts ...grpc.CallOption) (*lnrpc.PayReq, error) {
 	if len(s.decodePayReqMockData) == 0 {
 		return &lnrpc.PayReq{}, errors.New("NotFound")
@@ -182,6 +224,34 @@ func (s *MockLightningNetworkService) SetGetInfoMockData(mockData *lnrpc.GetInfo
 	s.getInfoMockData = append(s.getInfoMockData, mockData)
 }
 
+func (s *MockLightningNetworkService) GetNodeInfo(in *lnrpc.NodeInfoRequest, opts ...grpc.CallOption) (*lnrpc.NodeInfo, error) {
+	if len(s.getNodeInfoMockData) == 0 {
+		return &lnrpc.NodeInfo{}, errors.New("NotFound")
+	}
+
+	response := s.getNodeInfoMockData[0]
+	s.getNodeInfoMockData = s.getNodeInfoMockData[1:]
+	return response, nil
+}
+
+func (s *MockLightningNetworkService) SetGetNodeInfoMockData(mockData *lnrpc.NodeInfo) {
+	s.getNodeInfoMockData = append(s.getNodeInfoMockData, mockData)
+}
+
+func (s *MockLightningNetworkService) GetTransactions(in *lnrpc.GetTransactionsRequest, opts ...grpc.CallOption) (*lnrpc.TransactionDetails, error) {
+	if len(s.getTransactionsMockData) == 0 {
+		return &lnrpc.TransactionDetails{}, errors.New("NotFound")
+	}
+
+	response := s.getTransactionsMockData[0]
+	s.getTransactionsMockData = s.getTransactionsMockData[1:]
+	return response, nil
+}
+
+func (s *MockLightningNetworkService) SetGetTransactionsMockData(mockData *lnrpc.TransactionDetails) {
+	s.getTransactionsMockData = append(s.getTransactionsMockData, mockData)
+}
+
 func (s *MockLightningNetworkService) HtlcInterceptor(opts ...grpc.CallOption) (routerrpc.Router_HtlcInterceptorClient, error) {
 	if len(s.htlcInterceptorMockData) == 0 {
 		return nil, errors.New("NotFound")
@@ -228,6 +298,20 @@ func (s *MockLightningNetworkService) SetListPeersMockData(mockData *lnrpc.ListP
 	s.listPeersMockData = append(s.listPeersMockData, mockData)
 }
 
+func (s *MockLightningNetworkService) LookupInvoice(in *lnrpc.PaymentHash, opts ...grpc.CallOption) (*lnrpc.Invoice, error) {
+	if len(s.lookupInvoiceMockData) == 0 {
+		return &lnrpc.Invoice{}, errors.New("NotFound")
+	}
+
+	response := s.lookupInvoiceMockData[0]
+	s.lookupInvoiceMockData = s.lookupInvoiceMockData[1:]
+	return response, nil
+}
+
+func (s *MockLightningNetworkService) SetLookupInvoiceMockData(mockData *lnrpc.Invoice) {
+	s.lookupInvoiceMockData = append(s.lookupInvoiceMockData, mockData)
+}
+
 func (s *MockLightningNetworkService) OpenChannel(in *lnrpc.OpenChannelRequest, opts ...grpc.CallOption) (lnrpc.Lightning_OpenChannelClient, error) {
 	if len(s.openChannelMockData) == 0 {
 		return nil, errors.New("NotFound")
@@ -471,6 +555,23 @@ func (s *MockLightningNetworkService) NewSubscribeTransactionsMockData() chan<-
 	return recvChan
 }
 
+func (s *MockLightningNetworkService) TrackPaymentV2(in *routerrpc.TrackPaymentRequest, opts ...grpc.CallOption) (routerrpc.Router_TrackPaymentV2Client, error) {
+	if len(s.trackPaymentV2MockData) == 0 {
+		return nil, status.Error(codes.NotFound, "payment isn't initiated")
+	}
+
+	response := s.trackPaymentV2MockData[0]
+	s.trackPaymentV2MockData = s.trackPaymentV2MockData[1:]
+	return response, nil
+}
+
+func (s *MockLightningNetworkService) NewTrackPaymentV2MockData() chan<- *lnrpc.Payment {
+	recvChan := make(chan *lnrpc.Payment)
+	s.trackPaymentV2MockData = append(s.trackPaymentV2MockData, NewMockTrackPaymentV2Client(recvChan))
+
+	return recvChan
+}
+
 func (s *MockLightningNetworkService) UpdateChannelPolicy(in *lnrpc.PolicyUpdateRequest, opts ...grpc.CallOption) (*lnrpc.PolicyUpdateResponse, error) {
 	if len(s.updateChannelPolicyMockData) == 0 {
 		return &lnrpc.PolicyUpdateResponse{}, errors.New("NotFound")
@@ -498,3 +599,17 @@ func (s *MockLightningNetworkService) WalletBalance(in *lnrpc.WalletBalanceReque
 func (s *MockLightningNetworkService) SetWalletBalanceMockData(mockData *lnrpc.WalletBalanceResponse) {
 	s.walletBalanceMockData = append(s.walletBalanceMockData, mockData)
 }
+
+func (s *MockLightningNetworkService) GetConnectionState() lightningnetwork.ConnectionState {
+	if len(s.connectionState) == 0 {
+		return lightningnetwork.CONNECTION_READY
+	}
+
+	return s.connectionState
+}
+
+func (s *MockLightningNetworkService) SetConnectionState(connectionState lightningnetwork.ConnectionState) {
+	s.connectionState = connectionState
+}
+
+func (s *MockLightningNetworkService) Start(shutdownCtx context.Context, waitGroup *sync.WaitGroup) {}
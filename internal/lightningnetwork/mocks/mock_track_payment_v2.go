@@ -0,0 +1,32 @@
+package mocks
+
+import (
+	"io"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"google.golang.org/grpc"
+)
+
+type MockTrackPaymentV2Client struct {
+	grpc.ClientStream
+	recvChan <-chan *lnrpc.Payment
+}
+
+func NewMockTrackPaymentV2Client(recvChan <-chan *lnrpc.Payment) routerrpc.Router_TrackPaymentV2Client {
+	clientStream := NewMockClientStream()
+	return &MockTrackPaymentV2Client{
+		ClientStream: clientStream,
+		recvChan:     recvChan,
+	}
+}
+
+func (c *MockTrackPaymentV2Client) Recv() (*lnrpc.Payment, error) {
+	receive, ok := <-c.recvChan
+
+	if !ok {
+		return nil, io.EOF
+	}
+
+	return receive, nil
+}
@@ -0,0 +1,204 @@
+package lightningnetwork
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/satimoto/go-lnm/internal/graph"
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+	"github.com/satimoto/go-lnm/pkg/util"
+)
+
+const DEFAULT_NODE_NAME = "default"
+
+var ErrNodeUnavailable = errors.New("lightning node not managed by this process")
+
+type NodeConfig struct {
+	Name         string
+	GrpcHost     string
+	TlsCert      string
+	TlsCertPath  string
+	Macaroon     string
+	MacaroonPath string
+	P2PHost      string
+}
+
+// GetNodeConfigs returns the LND nodes named in LND_NODES, each configured by
+// LND_<NAME>_GRPC_HOST, LND_<NAME>_TLS_CERT, LND_<NAME>_MACAROON and
+// LND_<NAME>_P2P_HOST. Without LND_NODES a single default node is configured
+// by LND_GRPC_HOST, LND_TLS_CERT, LND_MACAROON and LND_P2P_HOST. The TLS
+// certificate and macaroon can instead be read from files set by the
+// _TLS_CERT_PATH and _MACAROON_PATH variables, which are reloaded when changed.
+func GetNodeConfigs() []NodeConfig {
+	names := util.GetEnvStrings("LND_NODES")
+
+	if len(names) == 0 {
+		return []NodeConfig{{
+			Name:         DEFAULT_NODE_NAME,
+			GrpcHost:     os.Getenv("LND_GRPC_HOST"),
+			TlsCert:      os.Getenv("LND_TLS_CERT"),
+			TlsCertPath:  os.Getenv("LND_TLS_CERT_PATH"),
+			Macaroon:     os.Getenv("LND_MACAROON"),
+			MacaroonPath: os.Getenv("LND_MACAROON_PATH"),
+			P2PHost:      os.Getenv("LND_P2P_HOST"),
+		}}
+	}
+
+	nodeConfigs := []NodeConfig{}
+
+	for _, name := range names {
+		prefix := fmt.Sprintf("LND_%s_", strings.ToUpper(name))
+
+		nodeConfigs = append(nodeConfigs, NodeConfig{
+			Name:         name,
+			GrpcHost:     os.Getenv(prefix + "GRPC_HOST"),
+			TlsCert:      os.Getenv(prefix + "TLS_CERT"),
+			TlsCertPath:  os.Getenv(prefix + "TLS_CERT_PATH"),
+			Macaroon:     os.Getenv(prefix + "MACAROON"),
+			MacaroonPath: os.Getenv(prefix + "MACAROON_PATH"),
+			P2PHost:      os.Getenv(prefix + "P2P_HOST"),
+		})
+	}
+
+	return nodeConfigs
+}
+
+// LightningNode is an LND node managed by this process. The node ID and
+// pubkey are set once the node is registered. The graph is a view of the
+// channels around the node, kept up to date by the channel graph monitor.
+type LightningNode struct {
+	Name             string
+	P2PHost          string
+	LightningService LightningNetwork
+	Graph            graph.Graph
+	mutex            sync.RWMutex
+	nodeID           int64
+	pubkey           string
+}
+
+func NewLightningNode(nodeConfig NodeConfig, lightningService LightningNetwork) *LightningNode {
+	return &LightningNode{
+		Name:             nodeConfig.Name,
+		P2PHost:          nodeConfig.P2PHost,
+		LightningService: lightningService,
+		Graph:            graph.NewGraph(),
+	}
+}
+
+// GetName returns the node name, or the default node name if there is no node
+func (n *LightningNode) GetName() string {
+	if n == nil {
+		return DEFAULT_NODE_NAME
+	}
+
+	return n.Name
+}
+
+func (n *LightningNode) GetNodeID() int64 {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+
+	return n.nodeID
+}
+
+// GetPubkey returns the node pubkey, known once the node is registered
+func (n *LightningNode) GetPubkey() string {
+	if n == nil {
+		return ""
+	}
+
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+
+	return n.pubkey
+}
+
+func (n *LightningNode) IsRegistered() bool {
+	return n.GetNodeID() > 0
+}
+
+func (n *LightningNode) SetRegistered(nodeID int64, pubkey string) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	n.nodeID = nodeID
+	n.pubkey = pubkey
+}
+
+type LightningNodes interface {
+	GetNode(nodeID int64) (*LightningNode, bool)
+	GetService(nodeID sql.NullInt64) (LightningNetwork, error)
+	ListNodes() []*LightningNode
+	Start(shutdownCtx context.Context, waitGroup *sync.WaitGroup)
+}
+
+type LightningNodesService struct {
+	nodes []*LightningNode
+}
+
+// NewNodes connects to each configured LND node
+func NewNodes() LightningNodes {
+	nodes := []*LightningNode{}
+
+	for _, nodeConfig := range GetNodeConfigs() {
+		nodes = append(nodes, NewLightningNode(nodeConfig, NewService(nodeConfig)))
+	}
+
+	return NewNodesWithNodes(nodes...)
+}
+
+func NewNodesWithNodes(nodes ...*LightningNode) LightningNodes {
+	return &LightningNodesService{
+		nodes: nodes,
+	}
+}
+
+// GetNode returns the registered node with the node ID
+func (s *LightningNodesService) GetNode(nodeID int64) (*LightningNode, bool) {
+	for _, node := range s.nodes {
+		if node.IsRegistered() && node.GetNodeID() == nodeID {
+			return node, true
+		}
+	}
+
+	return nil, false
+}
+
+// GetService returns the lightning service of the node with the node ID.
+// Users without a node use the first configured node. A node not managed by
+// this process returns ErrNodeUnavailable, so calls are not made on a node
+// the user has no channels with.
+func (s *LightningNodesService) GetService(nodeID sql.NullInt64) (LightningNetwork, error) {
+	if !nodeID.Valid {
+		fallbackNode := s.nodes[0]
+		metricNodeFallbacksTotal.WithLabelValues(fallbackNode.GetPubkey()).Inc()
+
+		return fallbackNode.LightningService, nil
+	}
+
+	if node, ok := s.GetNode(nodeID.Int64); ok {
+		return node.LightningService, nil
+	}
+
+	metrics.RecordError("LNM278", "Error finding lightning node", ErrNodeUnavailable)
+	log.Printf("LNM278: NodeID=%v", nodeID.Int64)
+
+	return nil, ErrNodeUnavailable
+}
+
+func (s *LightningNodesService) ListNodes() []*LightningNode {
+	return s.nodes
+}
+
+// Start manages the connection of each node until shutdown
+func (s *LightningNodesService) Start(shutdownCtx context.Context, waitGroup *sync.WaitGroup) {
+	for _, node := range s.nodes {
+		node.LightningService.Start(shutdownCtx, waitGroup)
+	}
+}
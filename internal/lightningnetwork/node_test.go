@@ -0,0 +1,81 @@
+package lightningnetwork_test
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/satimoto/go-datastore/pkg/util"
+	"github.com/satimoto/go-lnm/internal/lightningnetwork"
+	"github.com/satimoto/go-lnm/internal/lightningnetwork/mocks"
+)
+
+func TestGetNodeConfigs(t *testing.T) {
+	t.Run("Default node", func(t *testing.T) {
+		os.Setenv("LND_GRPC_HOST", "127.0.0.1:10009")
+		defer os.Unsetenv("LND_GRPC_HOST")
+
+		nodeConfigs := lightningnetwork.GetNodeConfigs()
+
+		if len(nodeConfigs) != 1 {
+			t.Fatalf("Length mismatch: %v expecting %v", len(nodeConfigs), 1)
+		}
+
+		if nodeConfigs[0].Name != lightningnetwork.DEFAULT_NODE_NAME || nodeConfigs[0].GrpcHost != "127.0.0.1:10009" {
+			t.Errorf("Config mismatch: %#v", nodeConfigs[0])
+		}
+	})
+
+	t.Run("Named nodes", func(t *testing.T) {
+		os.Setenv("LND_NODES", "alpha, beta")
+		os.Setenv("LND_ALPHA_GRPC_HOST", "10.0.0.1:10009")
+		os.Setenv("LND_BETA_GRPC_HOST", "10.0.0.2:10009")
+		os.Setenv("LND_BETA_P2P_HOST", "beta.example.com:9735")
+		defer os.Unsetenv("LND_NODES")
+		defer os.Unsetenv("LND_ALPHA_GRPC_HOST")
+		defer os.Unsetenv("LND_BETA_GRPC_HOST")
+		defer os.Unsetenv("LND_BETA_P2P_HOST")
+
+		nodeConfigs := lightningnetwork.GetNodeConfigs()
+
+		if len(nodeConfigs) != 2 {
+			t.Fatalf("Length mismatch: %v expecting %v", len(nodeConfigs), 2)
+		}
+
+		if nodeConfigs[0].Name != "alpha" || nodeConfigs[0].GrpcHost != "10.0.0.1:10009" {
+			t.Errorf("Config mismatch: %#v", nodeConfigs[0])
+		}
+
+		if nodeConfigs[1].Name != "beta" || nodeConfigs[1].GrpcHost != "10.0.0.2:10009" || nodeConfigs[1].P2PHost != "beta.example.com:9735" {
+			t.Errorf("Config mismatch: %#v", nodeConfigs[1])
+		}
+	})
+}
+
+func TestGetService(t *testing.T) {
+	alphaService := mocks.NewService()
+	betaService := mocks.NewService()
+	alphaNode := lightningnetwork.NewLightningNode(lightningnetwork.NodeConfig{Name: "alpha"}, alphaService)
+	betaNode := lightningnetwork.NewLightningNode(lightningnetwork.NodeConfig{Name: "beta"}, betaService)
+	lightningNodes := lightningnetwork.NewNodesWithNodes(alphaNode, betaNode)
+
+	if _, err := lightningNodes.GetService(util.SqlNullInt64(int64(2))); !errors.Is(err, lightningnetwork.ErrNodeUnavailable) {
+		t.Errorf("Error mismatch: %v expecting %v", err, lightningnetwork.ErrNodeUnavailable)
+	}
+
+	alphaNode.SetRegistered(1, "alpha-pubkey")
+	betaNode.SetRegistered(2, "beta-pubkey")
+
+	if lightningService, err := lightningNodes.GetService(util.SqlNullInt64(int64(2))); err != nil || lightningService != betaService {
+		t.Errorf("Registered node should use its own service: %v", err)
+	}
+
+	if _, err := lightningNodes.GetService(util.SqlNullInt64(int64(3))); !errors.Is(err, lightningnetwork.ErrNodeUnavailable) {
+		t.Errorf("Error mismatch: %v expecting %v", err, lightningnetwork.ErrNodeUnavailable)
+	}
+
+	if lightningService, err := lightningNodes.GetService(sql.NullInt64{}); err != nil || lightningService != alphaService {
+		t.Errorf("User without node should use the first node: %v", err)
+	}
+}
@@ -2,10 +2,8 @@ package lightningnetwork
 
 import (
 	"context"
-	"encoding/base64"
-	"encoding/hex"
 	"log"
-	"os"
+	"sync"
 	"time"
 
 	"github.com/lightningnetwork/lnd/lnrpc"
@@ -13,24 +11,29 @@ import (
 	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/walletrpc"
 	dbUtil "github.com/satimoto/go-datastore/pkg/util"
-	"github.com/satimoto/go-lnm/pkg/util"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type LightningNetwork interface {
 	AllocateAlias(in *lnrpc.AllocateAliasRequest, opts ...grpc.CallOption) (*lnrpc.AllocateAliasResponse, error)
 	AddInvoice(in *lnrpc.Invoice, opts ...grpc.CallOption) (*lnrpc.AddInvoiceResponse, error)
 	ChannelAcceptor(opts ...grpc.CallOption) (lnrpc.Lightning_ChannelAcceptorClient, error)
+	ClosedChannels(in *lnrpc.ClosedChannelsRequest, opts ...grpc.CallOption) (*lnrpc.ClosedChannelsResponse, error)
+	ConnectPeer(in *lnrpc.ConnectPeerRequest, opts ...grpc.CallOption) (*lnrpc.ConnectPeerResponse, error)
 	DecodePayReq(in *lnrpc.PayReqString, opts ...grpc.CallOption) (*lnrpc.PayReq, error)
 	EstimateFee(in *walletrpc.EstimateFeeRequest, opts ...grpc.CallOption) (*walletrpc.EstimateFeeResponse, error)
 	FinalizePsbt(in *walletrpc.FinalizePsbtRequest, opts ...grpc.CallOption) (*walletrpc.FinalizePsbtResponse, error)
 	FundingStateStep(in *lnrpc.FundingTransitionMsg, opts ...grpc.CallOption) (*lnrpc.FundingStateStepResp, error)
 	FundPsbt(in *walletrpc.FundPsbtRequest, opts ...grpc.CallOption) (*walletrpc.FundPsbtResponse, error)
 	GetInfo(in *lnrpc.GetInfoRequest, opts ...grpc.CallOption) (*lnrpc.GetInfoResponse, error)
+	GetNodeInfo(in *lnrpc.NodeInfoRequest, opts ...grpc.CallOption) (*lnrpc.NodeInfo, error)
+	GetTransactions(in *lnrpc.GetTransactionsRequest, opts ...grpc.CallOption) (*lnrpc.TransactionDetails, error)
 	HtlcInterceptor(opts ...grpc.CallOption) (routerrpc.Router_HtlcInterceptorClient, error)
 	ListChannels(in *lnrpc.ListChannelsRequest, opts ...grpc.CallOption) (*lnrpc.ListChannelsResponse, error)
 	ListPeers(in *lnrpc.ListPeersRequest, opts ...grpc.CallOption) (*lnrpc.ListPeersResponse, error)
+	LookupInvoice(in *lnrpc.PaymentHash, opts ...grpc.CallOption) (*lnrpc.Invoice, error)
 	OpenChannel(in *lnrpc.OpenChannelRequest, opts ...grpc.CallOption) (lnrpc.Lightning_OpenChannelClient, error)
 	OpenChannelSync(in *lnrpc.OpenChannelRequest, opts ...grpc.CallOption) (*lnrpc.ChannelPoint, error)
 	PublishTransaction(in *walletrpc.Transaction, opts ...grpc.CallOption) (*walletrpc.PublishResponse, error)
@@ -46,366 +49,560 @@ type LightningNetwork interface {
 	SubscribeInvoices(in *lnrpc.InvoiceSubscription, opts ...grpc.CallOption) (lnrpc.Lightning_SubscribeInvoicesClient, error)
 	SubscribePeerEvents(in *lnrpc.PeerEventSubscription, opts ...grpc.CallOption) (lnrpc.Lightning_SubscribePeerEventsClient, error)
 	SubscribeTransactions(in *lnrpc.GetTransactionsRequest, opts ...grpc.CallOption) (lnrpc.Lightning_SubscribeTransactionsClient, error)
+	TrackPaymentV2(in *routerrpc.TrackPaymentRequest, opts ...grpc.CallOption) (routerrpc.Router_TrackPaymentV2Client, error)
 	UpdateChannelPolicy(in *lnrpc.PolicyUpdateRequest, opts ...grpc.CallOption) (*lnrpc.PolicyUpdateResponse, error)
 	WalletBalance(in *lnrpc.WalletBalanceRequest, opts ...grpc.CallOption) (*lnrpc.WalletBalanceResponse, error)
-}
 
-type LightningNetworkService struct {
-	clientConn          *grpc.ClientConn
-	chainNotifierClient *chainrpc.ChainNotifierClient
-	lightningClient     *lnrpc.LightningClient
-	routerClient        *routerrpc.RouterClient
-	walletKitClient     *walletrpc.WalletKitClient
-	macaroonCtx         context.Context
+	GetConnectionState() ConnectionState
+	Start(shutdownCtx context.Context, waitGroup *sync.WaitGroup)
 }
 
-func NewService() LightningNetwork {
-	lndTlsCert, err := base64.StdEncoding.DecodeString(os.Getenv("LND_TLS_CERT"))
-	dbUtil.PanicOnError("LNM006", "Invalid LND TLS Certificate", err)
-
-	credentials, err := util.NewCredential(string(lndTlsCert))
-	dbUtil.PanicOnError("LNM007", "Error creating transport credentials", err)
+type LightningNetworkService struct {
+	nodeConfig       NodeConfig
+	connectionConfig ConnectionConfig
+	connection       *connection
+	credentials      *nodeCredentials
+	reloadChan       chan struct{}
+	mutex            sync.RWMutex
+	pubkey           string
+}
+
+func NewService(nodeConfig NodeConfig) LightningNetwork {
+	credentials, err := loadCredentials(nodeConfig)
+	dbUtil.PanicOnError("LNM006", "Invalid LND credentials", err)
+
+	connectionConfig := ConnectionConfig{
+		CallTimeout:       time.Duration(dbUtil.GetEnvInt32("LND_CALL_TIMEOUT", 60)) * time.Second,
+		KeepaliveInterval: time.Duration(dbUtil.GetEnvInt32("LND_KEEPALIVE_INTERVAL", 60)) * time.Second,
+		KeepaliveTimeout:  time.Duration(dbUtil.GetEnvInt32("LND_KEEPALIVE_TIMEOUT", 20)) * time.Second,
+		ReconnectMaxDelay: time.Duration(dbUtil.GetEnvInt32("LND_RECONNECT_MAX_DELAY", 60)) * time.Second,
+		ReloadInterval:    time.Duration(dbUtil.GetEnvInt32("LND_CREDENTIALS_RELOAD_INTERVAL", 60)) * time.Second,
+	}
 
-	clientConn, err := grpc.Dial(os.Getenv("LND_GRPC_HOST"), grpc.WithTransportCredentials(credentials))
+	conn, err := dial(nodeConfig.GrpcHost, credentials.tlsCert, connectionConfig)
 	dbUtil.PanicOnError("LNM008", "Error connecting to LND host", err)
 
-	lndMacaroon, err := base64.StdEncoding.DecodeString(os.Getenv("LND_MACAROON"))
-	dbUtil.PanicOnError("LNM009", "Invalid LND Macaroon", err)
-
-	macaroonCtx := metadata.AppendToOutgoingContext(context.Background(), "macaroon", hex.EncodeToString(lndMacaroon))
-
 	return &LightningNetworkService{
-		clientConn:  clientConn,
-		macaroonCtx: macaroonCtx,
+		nodeConfig:       nodeConfig,
+		connectionConfig: connectionConfig,
+		connection:       conn,
+		credentials:      credentials,
+		reloadChan:       make(chan struct{}, 1),
 	}
 }
 
+// Start manages the connection until shutdown. The connection reconnects
+// with backoff, calls have deadlines and streams end when the connection is
+// replaced so monitors can subscribe again.
+func (s *LightningNetworkService) Start(shutdownCtx context.Context, waitGroup *sync.WaitGroup) {
+	log.Printf("Starting LND connection manager %v", s.nodeConfig.Name)
+	waitGroup.Add(1)
+
+	go func() {
+		defer waitGroup.Done()
+		s.manageConnection(shutdownCtx)
+	}()
+}
+
+func (s *LightningNetworkService) GetConnectionState() ConnectionState {
+	return s.getConnection().getState()
+}
+
 func (s *LightningNetworkService) AllocateAlias(in *lnrpc.AllocateAliasRequest, opts ...grpc.CallOption) (*lnrpc.AllocateAliasResponse, error) {
+	ctx, cancel := s.callContext()
+	defer cancel()
+
 	timerStart := time.Now()
-	response, err := s.getLightningClient().AllocateAlias(s.macaroonCtx, in, opts...)
+	response, err := s.getLightningClient().AllocateAlias(ctx, in, opts...)
 	timerStop := time.Now()
 
 	log.Printf("AllocateAlias responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("AllocateAlias", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
 func (s *LightningNetworkService) AddInvoice(in *lnrpc.Invoice, opts ...grpc.CallOption) (*lnrpc.AddInvoiceResponse, error) {
+	ctx, cancel := s.callContext()
+	defer cancel()
+
 	timerStart := time.Now()
-	response, err := s.getLightningClient().AddInvoice(s.macaroonCtx, in, opts...)
+	response, err := s.getLightningClient().AddInvoice(ctx, in, opts...)
 	timerStop := time.Now()
 
 	log.Printf("AddInvoice responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("AddInvoice", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
 func (s *LightningNetworkService) ChannelAcceptor(opts ...grpc.CallOption) (lnrpc.Lightning_ChannelAcceptorClient, error) {
 	timerStart := time.Now()
-	response, err := s.getLightningClient().ChannelAcceptor(s.macaroonCtx, opts...)
+	response, err := s.getLightningClient().ChannelAcceptor(s.streamContext(), opts...)
 	timerStop := time.Now()
 
 	log.Printf("ChannelAcceptor responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("ChannelAcceptor", timerStop.Sub(timerStart), err)
+
+	return response, err
+}
+
+func (s *LightningNetworkService) ClosedChannels(in *lnrpc.ClosedChannelsRequest, opts ...grpc.CallOption) (*lnrpc.ClosedChannelsResponse, error) {
+	ctx, cancel := s.callContext()
+	defer cancel()
+
+	timerStart := time.Now()
+	response, err := s.getLightningClient().ClosedChannels(ctx, in, opts...)
+	timerStop := time.Now()
+
+	log.Printf("ClosedChannels responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("ClosedChannels", timerStop.Sub(timerStart), err)
+
+	return response, err
+}
+
+func (s *LightningNetworkService) ConnectPeer(in *lnrpc.ConnectPeerRequest, opts ...grpc.CallOption) (*lnrpc.ConnectPeerResponse, error) {
+	ctx, cancel := s.callContext()
+	defer cancel()
+
+	timerStart := time.Now()
+	response, err := s.getLightningClient().ConnectPeer(ctx, in, opts...)
+	timerStop := time.Now()
+
+	log.Printf("ConnectPeer responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("ConnectPeer", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
 func (s *LightningNetworkService) DecodePayReq(in *lnrpc.PayReqString, opts ...grpc.CallOption) (*lnrpc.PayReq, error) {
+	ctx, cancel := s.callContext()
+	defer cancel()
+
 	timerStart := time.Now()
-	response, err := s.getLightningClient().DecodePayReq(s.macaroonCtx, in, opts...)
+	response, err := s.getLightningClient().DecodePayReq(ctx, in, opts...)
 	timerStop := time.Now()
 
 	log.Printf("DecodePayReq responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("DecodePayReq", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
 func (s *LightningNetworkService) EstimateFee(in *walletrpc.EstimateFeeRequest, opts ...grpc.CallOption) (*walletrpc.EstimateFeeResponse, error) {
+	ctx, cancel := s.callContext()
+	defer cancel()
+
 	timerStart := time.Now()
-	response, err := s.getWalletKitClient().EstimateFee(s.macaroonCtx, in, opts...)
+	response, err := s.getWalletKitClient().EstimateFee(ctx, in, opts...)
 	timerStop := time.Now()
 
 	log.Printf("EstimateFee responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("EstimateFee", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
 func (s *LightningNetworkService) FinalizePsbt(in *walletrpc.FinalizePsbtRequest, opts ...grpc.CallOption) (*walletrpc.FinalizePsbtResponse, error) {
+	ctx, cancel := s.callContext()
+	defer cancel()
+
 	timerStart := time.Now()
-	response, err := s.getWalletKitClient().FinalizePsbt(s.macaroonCtx, in, opts...)
+	response, err := s.getWalletKitClient().FinalizePsbt(ctx, in, opts...)
 	timerStop := time.Now()
 
 	log.Printf("FinalizePsbt responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("FinalizePsbt", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
 func (s *LightningNetworkService) FundingStateStep(in *lnrpc.FundingTransitionMsg, opts ...grpc.CallOption) (*lnrpc.FundingStateStepResp, error) {
+	ctx, cancel := s.callContext()
+	defer cancel()
+
 	timerStart := time.Now()
-	response, err := s.getLightningClient().FundingStateStep(s.macaroonCtx, in, opts...)
+	response, err := s.getLightningClient().FundingStateStep(ctx, in, opts...)
 	timerStop := time.Now()
 
 	log.Printf("FundingStateStep responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("FundingStateStep", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
 func (s *LightningNetworkService) FundPsbt(in *walletrpc.FundPsbtRequest, opts ...grpc.CallOption) (*walletrpc.FundPsbtResponse, error) {
+	ctx, cancel := s.callContext()
+	defer cancel()
+
 	timerStart := time.Now()
-	response, err := s.getWalletKitClient().FundPsbt(s.macaroonCtx, in, opts...)
+	response, err := s.getWalletKitClient().FundPsbt(ctx, in, opts...)
 	timerStop := time.Now()
 
 	log.Printf("FundPsbt responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("FundPsbt", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
 func (s *LightningNetworkService) GetInfo(in *lnrpc.GetInfoRequest, opts ...grpc.CallOption) (*lnrpc.GetInfoResponse, error) {
+	ctx, cancel := s.callContext()
+	defer cancel()
+
 	timerStart := time.Now()
-	response, err := s.getLightningClient().GetInfo(s.macaroonCtx, in, opts...)
+	response, err := s.getLightningClient().GetInfo(ctx, in, opts...)
 	timerStop := time.Now()
 
 	log.Printf("GetInfo responded in %f seconds", timerStop.Sub(timerStart).Seconds())
 
+	if err == nil {
+		s.setPubkey(response.IdentityPubkey)
+	}
+
+	s.recordResponse("GetInfo", timerStop.Sub(timerStart), err)
+
+	return response, err
+}
+
+func (s *LightningNetworkService) GetNodeInfo(in *lnrpc.NodeInfoRequest, opts ...grpc.CallOption) (*lnrpc.NodeInfo, error) {
+	ctx, cancel := s.callContext()
+	defer cancel()
+
+	timerStart := time.Now()
+	response, err := s.getLightningClient().GetNodeInfo(ctx, in, opts...)
+	timerStop := time.Now()
+
+	log.Printf("GetNodeInfo responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("GetNodeInfo", timerStop.Sub(timerStart), err)
+
+	return response, err
+}
+
+func (s *LightningNetworkService) GetTransactions(in *lnrpc.GetTransactionsRequest, opts ...grpc.CallOption) (*lnrpc.TransactionDetails, error) {
+	ctx, cancel := s.callContext()
+	defer cancel()
+
+	timerStart := time.Now()
+	response, err := s.getLightningClient().GetTransactions(ctx, in, opts...)
+	timerStop := time.Now()
+
+	log.Printf("GetTransactions responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("GetTransactions", timerStop.Sub(timerStart), err)
+
 	return response, err
 }
 
 func (s *LightningNetworkService) HtlcInterceptor(opts ...grpc.CallOption) (routerrpc.Router_HtlcInterceptorClient, error) {
 	timerStart := time.Now()
-	response, err := s.getRouterClient().HtlcInterceptor(s.macaroonCtx, opts...)
+	response, err := s.getRouterClient().HtlcInterceptor(s.streamContext(), opts...)
 	timerStop := time.Now()
 
 	log.Printf("HtlcInterceptor responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("HtlcInterceptor", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
 func (s *LightningNetworkService) ListChannels(in *lnrpc.ListChannelsRequest, opts ...grpc.CallOption) (*lnrpc.ListChannelsResponse, error) {
+	ctx, cancel := s.callContext()
+	defer cancel()
+
 	timerStart := time.Now()
-	response, err := s.getLightningClient().ListChannels(s.macaroonCtx, in, opts...)
+	response, err := s.getLightningClient().ListChannels(ctx, in, opts...)
 	timerStop := time.Now()
 
 	log.Printf("ListChannels responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("ListChannels", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
 func (s *LightningNetworkService) ListPeers(in *lnrpc.ListPeersRequest, opts ...grpc.CallOption) (*lnrpc.ListPeersResponse, error) {
+	ctx, cancel := s.callContext()
+	defer cancel()
+
 	timerStart := time.Now()
-	response, err := s.getLightningClient().ListPeers(s.macaroonCtx, in, opts...)
+	response, err := s.getLightningClient().ListPeers(ctx, in, opts...)
 	timerStop := time.Now()
 
 	log.Printf("ListPeers responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("ListPeers", timerStop.Sub(timerStart), err)
+
+	return response, err
+}
+
+func (s *LightningNetworkService) LookupInvoice(in *lnrpc.PaymentHash, opts ...grpc.CallOption) (*lnrpc.Invoice, error) {
+	ctx, cancel := s.callContext()
+	defer cancel()
+
+	timerStart := time.Now()
+	response, err := s.getLightningClient().LookupInvoice(ctx, in, opts...)
+	timerStop := time.Now()
+
+	log.Printf("LookupInvoice responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("LookupInvoice", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
 func (s *LightningNetworkService) OpenChannel(in *lnrpc.OpenChannelRequest, opts ...grpc.CallOption) (lnrpc.Lightning_OpenChannelClient, error) {
 	timerStart := time.Now()
-	response, err := s.getLightningClient().OpenChannel(s.macaroonCtx, in, opts...)
+	response, err := s.getLightningClient().OpenChannel(s.streamContext(), in, opts...)
 	timerStop := time.Now()
 
 	log.Printf("OpenChannel responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("OpenChannel", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
 func (s *LightningNetworkService) OpenChannelSync(in *lnrpc.OpenChannelRequest, opts ...grpc.CallOption) (*lnrpc.ChannelPoint, error) {
+	ctx, cancel := s.callContext()
+	defer cancel()
+
 	timerStart := time.Now()
-	response, err := s.getLightningClient().OpenChannelSync(s.macaroonCtx, in, opts...)
+	response, err := s.getLightningClient().OpenChannelSync(ctx, in, opts...)
 	timerStop := time.Now()
 
 	log.Printf("OpenChannelSync responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("OpenChannelSync", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
 func (s *LightningNetworkService) PublishTransaction(in *walletrpc.Transaction, opts ...grpc.CallOption) (*walletrpc.PublishResponse, error) {
+	ctx, cancel := s.callContext()
+	defer cancel()
+
 	timerStart := time.Now()
-	response, err := s.getWalletKitClient().PublishTransaction(s.macaroonCtx, in, opts...)
+	response, err := s.getWalletKitClient().PublishTransaction(ctx, in, opts...)
 	timerStop := time.Now()
 
 	log.Printf("PublishTransaction responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("PublishTransaction", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
 func (s *LightningNetworkService) RegisterBlockEpochNtfn(in *chainrpc.BlockEpoch, opts ...grpc.CallOption) (chainrpc.ChainNotifier_RegisterBlockEpochNtfnClient, error) {
 	timerStart := time.Now()
-	response, err := s.getChainNotifierClient().RegisterBlockEpochNtfn(s.macaroonCtx, in, opts...)
+	response, err := s.getChainNotifierClient().RegisterBlockEpochNtfn(s.streamContext(), in, opts...)
 	timerStop := time.Now()
 
 	log.Printf("RegisterBlockEpochNtfn responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("RegisterBlockEpochNtfn", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
 func (s *LightningNetworkService) SendCustomMessage(in *lnrpc.SendCustomMessageRequest, opts ...grpc.CallOption) (*lnrpc.SendCustomMessageResponse, error) {
+	ctx, cancel := s.callContext()
+	defer cancel()
+
 	timerStart := time.Now()
-	response, err := s.getLightningClient().SendCustomMessage(s.macaroonCtx, in, opts...)
+	response, err := s.getLightningClient().SendCustomMessage(ctx, in, opts...)
 	timerStop := time.Now()
 
 	log.Printf("SendCustomMessage responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("SendCustomMessage", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
 func (s *LightningNetworkService) SendPaymentV2(in *routerrpc.SendPaymentRequest, opts ...grpc.CallOption) (routerrpc.Router_SendPaymentV2Client, error) {
 	timerStart := time.Now()
-	response, err := s.getRouterClient().SendPaymentV2(s.macaroonCtx, in, opts...)
+	response, err := s.getRouterClient().SendPaymentV2(s.streamContext(), in, opts...)
 	timerStop := time.Now()
 
 	log.Printf("SendPaymentV2 responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("SendPaymentV2", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
 func (s *LightningNetworkService) SignMessage(in *lnrpc.SignMessageRequest, opts ...grpc.CallOption) (*lnrpc.SignMessageResponse, error) {
+	ctx, cancel := s.callContext()
+	defer cancel()
+
 	timerStart := time.Now()
-	response, err := s.getLightningClient().SignMessage(s.macaroonCtx, in, opts...)
+	response, err := s.getLightningClient().SignMessage(ctx, in, opts...)
 	timerStop := time.Now()
 
 	log.Printf("SignMessage responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("SignMessage", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
 func (s *LightningNetworkService) SubscribeChannelBackups(in *lnrpc.ChannelBackupSubscription, opts ...grpc.CallOption) (lnrpc.Lightning_SubscribeChannelBackupsClient, error) {
 	timerStart := time.Now()
-	response, err := s.getLightningClient().SubscribeChannelBackups(s.macaroonCtx, in, opts...)
+	response, err := s.getLightningClient().SubscribeChannelBackups(s.streamContext(), in, opts...)
 	timerStop := time.Now()
 
 	log.Printf("SubscribeChannelBackups responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("SubscribeChannelBackups", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
 func (s *LightningNetworkService) SubscribeChannelEvents(in *lnrpc.ChannelEventSubscription, opts ...grpc.CallOption) (lnrpc.Lightning_SubscribeChannelEventsClient, error) {
 	timerStart := time.Now()
-	response, err := s.getLightningClient().SubscribeChannelEvents(s.macaroonCtx, in, opts...)
+	response, err := s.getLightningClient().SubscribeChannelEvents(s.streamContext(), in, opts...)
 	timerStop := time.Now()
 
 	log.Printf("SubscribeChannelEvents responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("SubscribeChannelEvents", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
 func (s *LightningNetworkService) SubscribeChannelGraph(in *lnrpc.GraphTopologySubscription, opts ...grpc.CallOption) (lnrpc.Lightning_SubscribeChannelGraphClient, error) {
 	timerStart := time.Now()
-	response, err := s.getLightningClient().SubscribeChannelGraph(s.macaroonCtx, in, opts...)
+	response, err := s.getLightningClient().SubscribeChannelGraph(s.streamContext(), in, opts...)
 	timerStop := time.Now()
 
 	log.Printf("SubscribeChannelGraph responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("SubscribeChannelGraph", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
 func (s *LightningNetworkService) SubscribeCustomMessages(in *lnrpc.SubscribeCustomMessagesRequest, opts ...grpc.CallOption) (lnrpc.Lightning_SubscribeCustomMessagesClient, error) {
 	timerStart := time.Now()
-	response, err := s.getLightningClient().SubscribeCustomMessages(s.macaroonCtx, in, opts...)
+	response, err := s.getLightningClient().SubscribeCustomMessages(s.streamContext(), in, opts...)
 	timerStop := time.Now()
 
 	log.Printf("SubscribeCustomMessages responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("SubscribeCustomMessages", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
 func (s *LightningNetworkService) SubscribeHtlcEvents(in *routerrpc.SubscribeHtlcEventsRequest, opts ...grpc.CallOption) (routerrpc.Router_SubscribeHtlcEventsClient, error) {
 	timerStart := time.Now()
-	response, err := s.getRouterClient().SubscribeHtlcEvents(s.macaroonCtx, in, opts...)
+	response, err := s.getRouterClient().SubscribeHtlcEvents(s.streamContext(), in, opts...)
 	timerStop := time.Now()
 
 	log.Printf("SubscribeHtlcEvents responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("SubscribeHtlcEvents", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
 func (s *LightningNetworkService) SubscribeInvoices(in *lnrpc.InvoiceSubscription, opts ...grpc.CallOption) (lnrpc.Lightning_SubscribeInvoicesClient, error) {
 	timerStart := time.Now()
-	response, err := s.getLightningClient().SubscribeInvoices(s.macaroonCtx, in, opts...)
+	response, err := s.getLightningClient().SubscribeInvoices(s.streamContext(), in, opts...)
 	timerStop := time.Now()
 
 	log.Printf("SubscribeInvoices responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("SubscribeInvoices", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
 func (s *LightningNetworkService) SubscribePeerEvents(in *lnrpc.PeerEventSubscription, opts ...grpc.CallOption) (lnrpc.Lightning_SubscribePeerEventsClient, error) {
 	timerStart := time.Now()
-	response, err := s.getLightningClient().SubscribePeerEvents(s.macaroonCtx, in, opts...)
+	response, err := s.getLightningClient().SubscribePeerEvents(s.streamContext(), in, opts...)
 	timerStop := time.Now()
 
 	log.Printf("SubscribePeerEvents responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("SubscribePeerEvents", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
 func (s *LightningNetworkService) SubscribeTransactions(in *lnrpc.GetTransactionsRequest, opts ...grpc.CallOption) (lnrpc.Lightning_SubscribeTransactionsClient, error) {
 	timerStart := time.Now()
-	response, err := s.getLightningClient().SubscribeTransactions(s.macaroonCtx, in, opts...)
+	response, err := s.getLightningClient().SubscribeTransactions(s.streamContext(), in, opts...)
 	timerStop := time.Now()
 
 	log.Printf("SubscribeTransactions responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("SubscribeTransactions", timerStop.Sub(timerStart), err)
+
+	return response, err
+}
+
+func (s *LightningNetworkService) TrackPaymentV2(in *routerrpc.TrackPaymentRequest, opts ...grpc.CallOption) (routerrpc.Router_TrackPaymentV2Client, error) {
+	timerStart := time.Now()
+	response, err := s.getRouterClient().TrackPaymentV2(s.streamContext(), in, opts...)
+	timerStop := time.Now()
+
+	log.Printf("TrackPaymentV2 responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("TrackPaymentV2", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
 func (s *LightningNetworkService) UpdateChannelPolicy(in *lnrpc.PolicyUpdateRequest, opts ...grpc.CallOption) (*lnrpc.PolicyUpdateResponse, error) {
+	ctx, cancel := s.callContext()
+	defer cancel()
+
 	timerStart := time.Now()
-	response, err := s.getLightningClient().UpdateChannelPolicy(s.macaroonCtx, in, opts...)
+	response, err := s.getLightningClient().UpdateChannelPolicy(ctx, in, opts...)
 	timerStop := time.Now()
 
 	log.Printf("UpdateChannelPolicy responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("UpdateChannelPolicy", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
 func (s *LightningNetworkService) WalletBalance(in *lnrpc.WalletBalanceRequest, opts ...grpc.CallOption) (*lnrpc.WalletBalanceResponse, error) {
+	ctx, cancel := s.callContext()
+	defer cancel()
+
 	timerStart := time.Now()
-	response, err := s.getLightningClient().WalletBalance(s.macaroonCtx, in, opts...)
+	response, err := s.getLightningClient().WalletBalance(ctx, in, opts...)
 	timerStop := time.Now()
 
 	log.Printf("WalletBalance responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+	s.recordResponse("WalletBalance", timerStop.Sub(timerStart), err)
 
 	return response, err
 }
 
-func (s *LightningNetworkService) getChainNotifierClient() chainrpc.ChainNotifierClient {
-	if s.chainNotifierClient == nil {
-		lc := chainrpc.NewChainNotifierClient(s.clientConn)
-		s.chainNotifierClient = &lc
-	}
+func (s *LightningNetworkService) getPubkey() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
 
-	return *s.chainNotifierClient
+	return s.pubkey
 }
 
-func (s *LightningNetworkService) getLightningClient() lnrpc.LightningClient {
-	if s.lightningClient == nil {
-		lc := lnrpc.NewLightningClient(s.clientConn)
-		s.lightningClient = &lc
-	}
+func (s *LightningNetworkService) setPubkey(pubkey string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 
-	return *s.lightningClient
+	s.pubkey = pubkey
 }
 
-func (s *LightningNetworkService) getRouterClient() routerrpc.RouterClient {
-	if s.routerClient == nil {
-		rc := routerrpc.NewRouterClient(s.clientConn)
-		s.routerClient = &rc
+// recordResponse records the response metrics labelled by the node pubkey,
+// which is known once the node has responded to GetInfo
+func (s *LightningNetworkService) recordResponse(method string, duration time.Duration, err error) {
+	pubkey := s.getPubkey()
+	metricRequestDurationSeconds.WithLabelValues(pubkey, method).Observe(duration.Seconds())
+
+	if err != nil {
+		metricRequestErrorsTotal.WithLabelValues(pubkey, method).Inc()
+
+		if status.Code(err) == codes.Unauthenticated {
+			// The macaroon may have been rotated
+			s.requestReload()
+		}
 	}
+}
 
-	return *s.routerClient
+func (s *LightningNetworkService) getChainNotifierClient() chainrpc.ChainNotifierClient {
+	return s.getConnection().chainNotifierClient
 }
 
-func (s *LightningNetworkService) getWalletKitClient() walletrpc.WalletKitClient {
-	if s.walletKitClient == nil {
-		rc := walletrpc.NewWalletKitClient(s.clientConn)
-		s.walletKitClient = &rc
-	}
+func (s *LightningNetworkService) getLightningClient() lnrpc.LightningClient {
+	return s.getConnection().lightningClient
+}
 
-	return *s.walletKitClient
+func (s *LightningNetworkService) getRouterClient() routerrpc.RouterClient {
+	return s.getConnection().routerClient
 }
 
-func (s *LightningNetworkService) getMacaroonCtx() context.Context {
-	return s.macaroonCtx
+func (s *LightningNetworkService) getWalletKitClient() walletrpc.WalletKitClient {
+	return s.getConnection().walletKitClient
 }
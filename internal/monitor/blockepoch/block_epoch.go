@@ -9,35 +9,42 @@ import (
 
 	"github.com/lightningnetwork/lnd/lnrpc/chainrpc"
 	"github.com/satimoto/go-datastore/pkg/db"
-	"github.com/satimoto/go-datastore/pkg/util"
+	"github.com/satimoto/go-lnm/internal/health"
 	"github.com/satimoto/go-lnm/internal/lightningnetwork"
 	"github.com/satimoto/go-lnm/internal/service"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"github.com/satimoto/go-lnm/internal/subscription"
 )
 
 type BlockEpochMonitor struct {
-	LightningService  lightningnetwork.LightningNetwork
-	BlockEpochsClient chainrpc.ChainNotifier_RegisterBlockEpochNtfnClient
-	nodeID            int64
+	LightningService lightningnetwork.LightningNetwork
+	Heartbeat        *health.Heartbeat
+	lightningNode    *lightningnetwork.LightningNode
+	nodeID           int64
 }
 
 func NewBlockEpochMonitor(repositoryService *db.RepositoryService, services *service.ServiceResolver) *BlockEpochMonitor {
 	return &BlockEpochMonitor{
 		LightningService: services.LightningService,
+		lightningNode:    services.LightningNode,
+		Heartbeat:        health.RegisterNodeHeartbeat(services.LightningNode, "monitor_block_epoch", true, 2*time.Hour),
 	}
 }
 
 func (m *BlockEpochMonitor) StartMonitor(nodeID int64, shutdownCtx context.Context, waitGroup *sync.WaitGroup) {
 	log.Printf("Starting up Block Epochs")
-	blockEpochChan := make(chan chainrpc.BlockEpoch)
 
 	m.nodeID = nodeID
-	go m.waitForBlockEpochs(shutdownCtx, waitGroup, blockEpochChan)
-	go m.subscribeBlockEpochNotifications(blockEpochChan)
+
+	subscription.NewRunner(subscription.Config[chainrpc.BlockEpoch]{
+		Name:      "block_epoch",
+		Pubkey:    m.lightningNode.GetPubkey(),
+		Subscribe: m.registerBlockEpochNtfn,
+		Handle:    m.handleBlockEpoch,
+		Heartbeat: m.Heartbeat,
+	}).Start(shutdownCtx, waitGroup)
 }
 
-func (m *BlockEpochMonitor) handleBlockEpoch(blockEpoch chainrpc.BlockEpoch) {
+func (m *BlockEpochMonitor) handleBlockEpoch(blockEpoch *chainrpc.BlockEpoch) {
 	/** Block Epoch received.
 	 *
 	 */
@@ -46,54 +53,6 @@ func (m *BlockEpochMonitor) handleBlockEpoch(blockEpoch chainrpc.BlockEpoch) {
 	log.Printf("Height: %v", blockEpoch.Height)
 }
 
-func (m *BlockEpochMonitor) subscribeBlockEpochNotifications(blockEpochChan chan<- chainrpc.BlockEpoch) {
-	htlcEventsClient, err := m.waitForRegisterBlockEpochNtfnClient(0, 1000)
-	util.PanicOnError("LNM073", "Error creating Block Epochs client", err)
-	m.BlockEpochsClient = htlcEventsClient
-
-	for {
-		blockEpoch, err := m.BlockEpochsClient.Recv()
-
-		if err == nil {
-			blockEpochChan <- *blockEpoch
-		} else {
-			m.BlockEpochsClient, err = m.waitForRegisterBlockEpochNtfnClient(100, 1000)
-			util.PanicOnError("LNM074", "Error creating Block Epochs client", err)
-		}
-	}
-}
-
-func (m *BlockEpochMonitor) waitForBlockEpochs(shutdownCtx context.Context, waitGroup *sync.WaitGroup, blockEpochChan chan chainrpc.BlockEpoch) {
-	waitGroup.Add(1)
-	defer close(blockEpochChan)
-	defer waitGroup.Done()
-
-	for {
-		select {
-		case <-shutdownCtx.Done():
-			log.Printf("Shutting down Block Epochs")
-			return
-		case blockEpoch := <-blockEpochChan:
-			m.handleBlockEpoch(blockEpoch)
-		}
-	}
-}
-
-func (m *BlockEpochMonitor) waitForRegisterBlockEpochNtfnClient(initialDelay, retryDelay time.Duration) (chainrpc.ChainNotifier_RegisterBlockEpochNtfnClient, error) {
-	for {
-		if initialDelay > 0 {
-			time.Sleep(retryDelay * time.Millisecond)
-		}
-
-		registerBlockEpochNtfnClient, err := m.LightningService.RegisterBlockEpochNtfn(&chainrpc.BlockEpoch{})
-
-		if err == nil {
-			return registerBlockEpochNtfnClient, nil
-		} else if status.Code(err) != codes.Unavailable {
-			return nil, err
-		}
-
-		log.Print("Waiting for Block Epochs client")
-		time.Sleep(retryDelay * time.Millisecond)
-	}
+func (m *BlockEpochMonitor) registerBlockEpochNtfn() (subscription.Stream[chainrpc.BlockEpoch], error) {
+	return m.LightningService.RegisterBlockEpochNtfn(&chainrpc.BlockEpoch{})
 }
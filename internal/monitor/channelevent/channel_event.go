@@ -0,0 +1,330 @@
+package channelevent
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/satimoto/go-datastore/pkg/db"
+	"github.com/satimoto/go-datastore/pkg/param"
+	"github.com/satimoto/go-lnm/internal/channel"
+	"github.com/satimoto/go-lnm/internal/health"
+	"github.com/satimoto/go-lnm/internal/lease"
+	"github.com/satimoto/go-lnm/internal/lightningnetwork"
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+	"github.com/satimoto/go-lnm/internal/service"
+	"github.com/satimoto/go-lnm/internal/subscription"
+	"github.com/satimoto/go-lnm/pkg/util"
+)
+
+type ChannelEventMonitor struct {
+	LeaseService     lease.Lease
+	LightningService lightningnetwork.LightningNetwork
+	Heartbeat        *health.Heartbeat
+	ChannelResolver  *channel.ChannelResolver
+	mutex            sync.Mutex
+	lightningNode    *lightningnetwork.LightningNode
+	nodeID           int64
+}
+
+func NewChannelEventMonitor(repositoryService *db.RepositoryService, services *service.ServiceResolver) *ChannelEventMonitor {
+	return &ChannelEventMonitor{
+		LeaseService:     services.LeaseService,
+		LightningService: services.LightningService,
+		lightningNode:    services.LightningNode,
+		ChannelResolver:  channel.NewResolver(repositoryService),
+		Heartbeat:        health.RegisterNodeHeartbeat(services.LightningNode, "monitor_channel_event", true, 0),
+	}
+}
+
+func (m *ChannelEventMonitor) StartMonitor(nodeID int64, shutdownCtx context.Context, waitGroup *sync.WaitGroup) {
+	log.Printf("Starting up Channel Events")
+
+	m.nodeID = nodeID
+
+	subscription.NewRunner(subscription.Config[lnrpc.ChannelEventUpdate]{
+		Name:      "channel_event",
+		Pubkey:    m.lightningNode.GetPubkey(),
+		Subscribe: m.subscribeChannelEvents,
+		Handle:    m.handleChannelEvent,
+		Heartbeat: m.Heartbeat,
+		// Only one instance handles the events of a node
+		LeaseService: m.LeaseService,
+		LeaseType:    lease.LEASE_CHANNEL_EVENT,
+		LeaseID:      nodeID,
+	}).Start(shutdownCtx, waitGroup)
+}
+
+func (m *ChannelEventMonitor) handleChannelEvent(channelEventUpdate *lnrpc.ChannelEventUpdate) {
+	/** Channel Event received.
+	 *  Find the channel by its channel point or create it.
+	 *  Update the channel with the details of the event
+	 *  and record the event against the channel.
+	 */
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	ctx := context.Background()
+	now := time.Now()
+
+	switch channelEventUpdate.Type {
+	case lnrpc.ChannelEventUpdate_PENDING_OPEN_CHANNEL:
+		pendingUpdate := channelEventUpdate.GetPendingOpenChannel()
+		channelPoint := util.FormatChannelPoint(pendingUpdate.Txid, pendingUpdate.OutputIndex)
+
+		m.updateChannel(ctx, channelPoint, 0, db.ChannelEventTypePENDINGOPEN, func(updateChannelParams *db.UpdateChannelParams) {
+			updateChannelParams.Status = db.ChannelStatusPENDING
+		})
+	case lnrpc.ChannelEventUpdate_OPEN_CHANNEL:
+		openChannel := channelEventUpdate.GetOpenChannel()
+
+		transactionFees := map[string]int64{}
+
+		if openChannel.Initiator {
+			transactionFees = m.getTransactionFees()
+		}
+
+		m.updateChannel(ctx, openChannel.ChannelPoint, int64(openChannel.ChanId), db.ChannelEventTypeOPEN, func(updateChannelParams *db.UpdateChannelParams) {
+			setOpenChannel(updateChannelParams, openChannel, transactionFees, now)
+		})
+	case lnrpc.ChannelEventUpdate_ACTIVE_CHANNEL:
+		channelPoint := formatChannelPoint(channelEventUpdate.GetActiveChannel())
+
+		m.updateChannel(ctx, channelPoint, 0, db.ChannelEventTypeACTIVE, func(updateChannelParams *db.UpdateChannelParams) {
+			channel.SetActive(updateChannelParams, true, now)
+		})
+	case lnrpc.ChannelEventUpdate_INACTIVE_CHANNEL:
+		channelPoint := formatChannelPoint(channelEventUpdate.GetInactiveChannel())
+
+		m.updateChannel(ctx, channelPoint, 0, db.ChannelEventTypeINACTIVE, func(updateChannelParams *db.UpdateChannelParams) {
+			channel.SetActive(updateChannelParams, false, now)
+		})
+	case lnrpc.ChannelEventUpdate_CLOSED_CHANNEL:
+		closedChannel := channelEventUpdate.GetClosedChannel()
+
+		m.updateChannel(ctx, closedChannel.ChannelPoint, int64(closedChannel.ChanId), db.ChannelEventTypeCLOSED, func(updateChannelParams *db.UpdateChannelParams) {
+			setClosedChannel(updateChannelParams, closedChannel, now)
+		})
+	case lnrpc.ChannelEventUpdate_FULLY_RESOLVED_CHANNEL:
+		channelPoint := formatChannelPoint(channelEventUpdate.GetFullyResolvedChannel())
+
+		m.updateChannel(ctx, channelPoint, 0, db.ChannelEventTypeFULLYRESOLVED, func(updateChannelParams *db.UpdateChannelParams) {
+			updateChannelParams.Status = db.ChannelStatusRESOLVED
+		})
+	}
+}
+
+// updateChannel gets or creates a channel, updates it and records the event
+func (m *ChannelEventMonitor) updateChannel(ctx context.Context, channelPoint string, chanID int64, eventType db.ChannelEventType, update func(*db.UpdateChannelParams)) {
+	c, err := m.getOrCreateChannel(ctx, channelPoint, chanID)
+
+	if err != nil {
+		return
+	}
+
+	updateChannelParams := param.NewUpdateChannelParams(c)
+	updateChannelParams.ChannelPoint = channelPoint
+	updateChannelParams.LastUpdated = time.Now()
+	update(&updateChannelParams)
+
+	if _, err := m.ChannelResolver.Repository.UpdateChannel(ctx, updateChannelParams); err != nil {
+		metrics.RecordError("LNM255", "Error updating channel", err)
+		log.Printf("LNM255: Params=%#v", updateChannelParams)
+		return
+	}
+
+	createChannelEventParams := db.CreateChannelEventParams{
+		ChannelID:   c.ID,
+		EventType:   eventType,
+		LastUpdated: updateChannelParams.LastUpdated,
+	}
+
+	if _, err := m.ChannelResolver.Repository.CreateChannelEvent(ctx, createChannelEventParams); err != nil {
+		metrics.RecordError("LNM256", "Error creating channel event", err)
+		log.Printf("LNM256: Params=%#v", createChannelEventParams)
+		return
+	}
+
+	metricChannelEventsTotal.WithLabelValues(m.lightningNode.GetPubkey(), string(eventType)).Inc()
+}
+
+// getOrCreateChannel finds a channel by its channel point, or by its channel ID
+// if it was imported without a channel point, otherwise a channel is created
+func (m *ChannelEventMonitor) getOrCreateChannel(ctx context.Context, channelPoint string, chanID int64) (db.Channel, error) {
+	if c, err := m.ChannelResolver.Repository.GetChannelByChannelPoint(ctx, channelPoint); err == nil {
+		return c, nil
+	}
+
+	if chanID > 0 {
+		if c, err := m.ChannelResolver.Repository.GetChannelByChanID(ctx, chanID); err == nil {
+			return c, nil
+		}
+	}
+
+	createChannelParams := db.CreateChannelParams{
+		NodeID:       m.nodeID,
+		ChannelPoint: channelPoint,
+		ChanID:       chanID,
+		Status:       db.ChannelStatusPENDING,
+		LastUpdated:  time.Now(),
+	}
+
+	c, err := m.ChannelResolver.Repository.CreateChannel(ctx, createChannelParams)
+
+	if err != nil {
+		metrics.RecordError("LNM257", "Error creating channel", err)
+		log.Printf("LNM257: Params=%#v", createChannelParams)
+	}
+
+	return c, err
+}
+
+func (m *ChannelEventMonitor) subscribeChannelEvents() (subscription.Stream[lnrpc.ChannelEventUpdate], error) {
+	channelEventsClient, err := m.LightningService.SubscribeChannelEvents(&lnrpc.ChannelEventSubscription{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	m.syncChannels()
+
+	return channelEventsClient, nil
+}
+
+// syncChannels updates the open and closed channels of the node from LND,
+// catching up on events missed while not subscribed
+func (m *ChannelEventMonitor) syncChannels() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	ctx := context.Background()
+	now := time.Now()
+
+	listChannelsResponse, err := m.LightningService.ListChannels(&lnrpc.ListChannelsRequest{})
+
+	if err != nil {
+		metrics.RecordError("LNM258", "Error listing channels", err)
+		log.Printf("LNM258: Node=%v", m.lightningNode.GetName())
+		return
+	}
+
+	transactionFees := m.getTransactionFees()
+
+	for _, openChannel := range listChannelsResponse.Channels {
+		c, err := m.getOrCreateChannel(ctx, openChannel.ChannelPoint, int64(openChannel.ChanId))
+
+		if err != nil {
+			continue
+		}
+
+		updateChannelParams := param.NewUpdateChannelParams(c)
+		updateChannelParams.LastUpdated = now
+		setOpenChannel(&updateChannelParams, openChannel, transactionFees, now)
+
+		if _, err := m.ChannelResolver.Repository.UpdateChannel(ctx, updateChannelParams); err != nil {
+			metrics.RecordError("LNM255", "Error updating channel", err)
+			log.Printf("LNM255: Params=%#v", updateChannelParams)
+		}
+	}
+
+	closedChannelsResponse, err := m.LightningService.ClosedChannels(&lnrpc.ClosedChannelsRequest{})
+
+	if err != nil {
+		metrics.RecordError("LNM282", "Error listing closed channels", err)
+		log.Printf("LNM282: Node=%v", m.lightningNode.GetName())
+		return
+	}
+
+	for _, closedChannel := range closedChannelsResponse.Channels {
+		c, err := m.getOrCreateChannel(ctx, closedChannel.ChannelPoint, int64(closedChannel.ChanId))
+
+		if err != nil || c.Status == db.ChannelStatusCLOSED || c.Status == db.ChannelStatusRESOLVED {
+			continue
+		}
+
+		updateChannelParams := param.NewUpdateChannelParams(c)
+		updateChannelParams.ChannelPoint = closedChannel.ChannelPoint
+		updateChannelParams.LastUpdated = now
+		setClosedChannel(&updateChannelParams, closedChannel, now)
+
+		if _, err := m.ChannelResolver.Repository.UpdateChannel(ctx, updateChannelParams); err != nil {
+			metrics.RecordError("LNM255", "Error updating channel", err)
+			log.Printf("LNM255: Params=%#v", updateChannelParams)
+		}
+	}
+}
+
+// getTransactionFees returns the fees paid for the wallet transactions of the
+// node by transaction hash
+func (m *ChannelEventMonitor) getTransactionFees() map[string]int64 {
+	transactionFees := make(map[string]int64)
+	transactionDetails, err := m.LightningService.GetTransactions(&lnrpc.GetTransactionsRequest{})
+
+	if err != nil {
+		metrics.RecordError("LNM283", "Error getting transactions", err)
+		log.Printf("LNM283: Node=%v", m.lightningNode.GetName())
+		return transactionFees
+	}
+
+	for _, transaction := range transactionDetails.Transactions {
+		transactionFees[transaction.TxHash] = transaction.TotalFees
+	}
+
+	return transactionFees
+}
+
+func setOpenChannel(updateChannelParams *db.UpdateChannelParams, openChannel *lnrpc.Channel, transactionFees map[string]int64, now time.Time) {
+	updateChannelParams.ChannelPoint = openChannel.ChannelPoint
+	updateChannelParams.ChanID = int64(openChannel.ChanId)
+	updateChannelParams.RemotePubkey = openChannel.RemotePubkey
+	updateChannelParams.Capacity = openChannel.Capacity
+	updateChannelParams.Status = db.ChannelStatusOPEN
+
+	if openChannel.Initiator {
+		// The initiator pays the commitment fee and the funding transaction fee
+		updateChannelParams.CommitFeeSat = openChannel.CommitFee
+		fundingTxid := strings.Split(openChannel.ChannelPoint, ":")[0]
+
+		if openingFeeSat, ok := transactionFees[fundingTxid]; ok {
+			updateChannelParams.OpeningFeeSat = openingFeeSat
+		}
+	}
+
+	if !updateChannelParams.OpenedAt.Valid {
+		// The lifetime is the time the channel has been monitored by LND
+		openedAt := now.Add(-time.Duration(openChannel.Lifetime) * time.Second)
+		updateChannelParams.OpenedAt = sql.NullTime{Time: openedAt, Valid: true}
+	}
+
+	channel.SetActive(updateChannelParams, openChannel.Active, now)
+}
+
+func setClosedChannel(updateChannelParams *db.UpdateChannelParams, closedChannel *lnrpc.ChannelCloseSummary, now time.Time) {
+	updateChannelParams.ChanID = int64(closedChannel.ChanId)
+	updateChannelParams.RemotePubkey = closedChannel.RemotePubkey
+	updateChannelParams.Capacity = closedChannel.Capacity
+	updateChannelParams.Status = db.ChannelStatusCLOSED
+	updateChannelParams.CloseType = sql.NullString{String: closedChannel.CloseType.String(), Valid: true}
+	updateChannelParams.SettledBalance = closedChannel.SettledBalance
+	updateChannelParams.TimeLockedBalance = closedChannel.TimeLockedBalance
+
+	if !updateChannelParams.ClosedAt.Valid {
+		updateChannelParams.ClosedAt = sql.NullTime{Time: now, Valid: true}
+	}
+
+	channel.SetActive(updateChannelParams, false, now)
+}
+
+func formatChannelPoint(channelPoint *lnrpc.ChannelPoint) string {
+	if fundingTxidStr := channelPoint.GetFundingTxidStr(); len(fundingTxidStr) > 0 {
+		return fmt.Sprintf("%s:%d", fundingTxidStr, channelPoint.OutputIndex)
+	}
+
+	return util.FormatChannelPoint(channelPoint.GetFundingTxidBytes(), channelPoint.OutputIndex)
+}
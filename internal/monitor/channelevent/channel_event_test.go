@@ -0,0 +1,171 @@
+package channelevent_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/satimoto/go-datastore/pkg/db"
+	dbMocks "github.com/satimoto/go-datastore/pkg/db/mocks"
+	ferpMocks "github.com/satimoto/go-lnm/internal/ferp/mocks"
+	lightningnetworkMocks "github.com/satimoto/go-lnm/internal/lightningnetwork/mocks"
+	channeleventMocks "github.com/satimoto/go-lnm/internal/monitor/channelevent/mocks"
+	notificationMocks "github.com/satimoto/go-lnm/internal/notification/mocks"
+	serviceMocks "github.com/satimoto/go-lnm/internal/service/mocks"
+	ocpiMocks "github.com/satimoto/go-ocpi/pkg/ocpi/mocks"
+)
+
+func TestChannelEvent(t *testing.T) {
+	t.Run("Channel closed", func(t *testing.T) {
+		shutdownCtx, cancelFunc := context.WithCancel(context.Background())
+		waitGroup := &sync.WaitGroup{}
+
+		mockRepository := dbMocks.NewMockRepositoryService()
+		mockFerpService := ferpMocks.NewService()
+		mockLightningService := lightningnetworkMocks.NewService()
+		mockNotificationService := notificationMocks.NewService()
+		mockOcpiService := ocpiMocks.NewService()
+		mockServices := serviceMocks.NewService(mockFerpService, mockLightningService, mockNotificationService, mockOcpiService)
+
+		channelEventMonitor := channeleventMocks.NewChannelEventMonitor(mockRepository, mockServices)
+		recvChan := mockLightningService.NewSubscribeChannelEventsMockData()
+		mockLightningService.SetListChannelsMockData(&lnrpc.ListChannelsResponse{})
+		mockLightningService.SetGetTransactionsMockData(&lnrpc.TransactionDetails{})
+		mockLightningService.SetClosedChannelsMockData(&lnrpc.ClosedChannelsResponse{})
+
+		mockRepository.SetGetChannelByChannelPointMockData(dbMocks.ChannelMockData{
+			Channel: db.Channel{
+				ID:           1,
+				ChannelPoint: "0102:1",
+				Status:       db.ChannelStatusOPEN,
+			},
+		})
+
+		channelEventMonitor.StartMonitor(1, shutdownCtx, waitGroup)
+
+		recvChan <- &lnrpc.ChannelEventUpdate{
+			Type: lnrpc.ChannelEventUpdate_CLOSED_CHANNEL,
+			Channel: &lnrpc.ChannelEventUpdate_ClosedChannel{
+				ClosedChannel: &lnrpc.ChannelCloseSummary{
+					ChannelPoint:   "0102:1",
+					ChanId:         817314272010174464,
+					Capacity:       5000000,
+					CloseType:      lnrpc.ChannelCloseSummary_REMOTE_FORCE_CLOSE,
+					SettledBalance: 1000000,
+				},
+			},
+		}
+
+		time.Sleep(time.Second * 2)
+
+		channel, err := mockRepository.GetUpdateChannelMockData()
+
+		if err != nil {
+			t.Error(err)
+		}
+
+		if channel.Status != db.ChannelStatusCLOSED || channel.CloseType.String != "REMOTE_FORCE_CLOSE" || channel.SettledBalance != 1000000 {
+			t.Errorf("Channel mismatch: %#v", channel)
+		}
+
+		channelEvent, err := mockRepository.GetCreateChannelEventMockData()
+
+		if err != nil {
+			t.Error(err)
+		}
+
+		if channelEvent.ChannelID != 1 || channelEvent.EventType != db.ChannelEventTypeCLOSED {
+			t.Errorf("Channel event mismatch: %#v", channelEvent)
+		}
+
+		cancelFunc()
+		waitGroup.Wait()
+	})
+	t.Run("Channels synced", func(t *testing.T) {
+		shutdownCtx, cancelFunc := context.WithCancel(context.Background())
+		waitGroup := &sync.WaitGroup{}
+
+		mockRepository := dbMocks.NewMockRepositoryService()
+		mockFerpService := ferpMocks.NewService()
+		mockLightningService := lightningnetworkMocks.NewService()
+		mockNotificationService := notificationMocks.NewService()
+		mockOcpiService := ocpiMocks.NewService()
+		mockServices := serviceMocks.NewService(mockFerpService, mockLightningService, mockNotificationService, mockOcpiService)
+
+		channelEventMonitor := channeleventMocks.NewChannelEventMonitor(mockRepository, mockServices)
+		mockLightningService.NewSubscribeChannelEventsMockData()
+
+		mockLightningService.SetListChannelsMockData(&lnrpc.ListChannelsResponse{
+			Channels: []*lnrpc.Channel{{
+				ChannelPoint: "0102:0",
+				ChanId:       817314272010174465,
+				Capacity:     2000000,
+				CommitFee:    3160,
+				Initiator:    true,
+				Lifetime:     3600,
+			}},
+		})
+
+		mockLightningService.SetGetTransactionsMockData(&lnrpc.TransactionDetails{
+			Transactions: []*lnrpc.Transaction{{
+				TxHash:    "0102",
+				TotalFees: 154,
+			}},
+		})
+
+		mockLightningService.SetClosedChannelsMockData(&lnrpc.ClosedChannelsResponse{
+			Channels: []*lnrpc.ChannelCloseSummary{{
+				ChannelPoint:   "0102:1",
+				ChanId:         817314272010174464,
+				Capacity:       5000000,
+				CloseType:      lnrpc.ChannelCloseSummary_COOPERATIVE_CLOSE,
+				SettledBalance: 1000000,
+			}},
+		})
+
+		mockRepository.SetGetChannelByChannelPointMockData(dbMocks.ChannelMockData{
+			Channel: db.Channel{
+				ID:           1,
+				ChannelPoint: "0102:0",
+				Status:       db.ChannelStatusPENDING,
+			},
+		})
+
+		mockRepository.SetGetChannelByChannelPointMockData(dbMocks.ChannelMockData{
+			Channel: db.Channel{
+				ID:           2,
+				ChannelPoint: "0102:1",
+				Status:       db.ChannelStatusOPEN,
+			},
+		})
+
+		channelEventMonitor.StartMonitor(1, shutdownCtx, waitGroup)
+
+		time.Sleep(time.Second * 2)
+
+		openChannel, err := mockRepository.GetUpdateChannelMockData()
+
+		if err != nil {
+			t.Error(err)
+		}
+
+		if openedAt := time.Since(openChannel.OpenedAt.Time); openChannel.Status != db.ChannelStatusOPEN || openChannel.CommitFeeSat != 3160 || openChannel.OpeningFeeSat != 154 || openedAt < time.Hour || openedAt > time.Hour+time.Minute {
+			t.Errorf("Open channel mismatch: %#v", openChannel)
+		}
+
+		closedChannel, err := mockRepository.GetUpdateChannelMockData()
+
+		if err != nil {
+			t.Error(err)
+		}
+
+		if closedChannel.ID != 2 || closedChannel.Status != db.ChannelStatusCLOSED || closedChannel.CloseType.String != "COOPERATIVE_CLOSE" || !closedChannel.ClosedAt.Valid {
+			t.Errorf("Closed channel mismatch: %#v", closedChannel)
+		}
+
+		cancelFunc()
+		waitGroup.Wait()
+	})
+}
@@ -0,0 +1,13 @@
+package channelevent
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricChannelEventsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lsp_channel_events_total",
+		Help: "The total number of channel events recorded",
+	}, []string{"pubkey", "event_type"})
+)
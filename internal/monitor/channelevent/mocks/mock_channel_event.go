@@ -0,0 +1,16 @@
+package mocks
+
+import (
+	mocks "github.com/satimoto/go-datastore/pkg/db/mocks"
+	channel "github.com/satimoto/go-lnm/internal/channel/mocks"
+	"github.com/satimoto/go-lnm/internal/monitor/channelevent"
+	"github.com/satimoto/go-lnm/internal/service"
+)
+
+func NewChannelEventMonitor(repositoryService *mocks.MockRepositoryService, services *service.ServiceResolver) *channelevent.ChannelEventMonitor {
+	return &channelevent.ChannelEventMonitor{
+		LeaseService:     services.LeaseService,
+		LightningService: services.LightningService,
+		ChannelResolver:  channel.NewResolver(repositoryService),
+	}
+}
@@ -0,0 +1,165 @@
+package channelgraph
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/satimoto/go-datastore/pkg/db"
+	dbUtil "github.com/satimoto/go-datastore/pkg/util"
+	"github.com/satimoto/go-lnm/internal/graph"
+	"github.com/satimoto/go-lnm/internal/health"
+	"github.com/satimoto/go-lnm/internal/lightningnetwork"
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+	"github.com/satimoto/go-lnm/internal/service"
+	"github.com/satimoto/go-lnm/internal/subscription"
+)
+
+type ChannelGraphMonitor struct {
+	LightningService lightningnetwork.LightningNetwork
+	Heartbeat        *health.Heartbeat
+	Graph            graph.Graph
+	mutex            sync.Mutex
+	lightningNode    *lightningnetwork.LightningNode
+}
+
+func NewChannelGraphMonitor(repositoryService *db.RepositoryService, services *service.ServiceResolver) *ChannelGraphMonitor {
+	return &ChannelGraphMonitor{
+		LightningService: services.LightningService,
+		Graph:            services.LightningNode.Graph,
+		lightningNode:    services.LightningNode,
+		Heartbeat:        health.RegisterNodeHeartbeat(services.LightningNode, "monitor_channel_graph", true, 0),
+	}
+}
+
+func (m *ChannelGraphMonitor) StartMonitor(nodeID int64, shutdownCtx context.Context, waitGroup *sync.WaitGroup) {
+	log.Printf("Starting up Channel Graph")
+
+	syncInterval := time.Duration(dbUtil.GetEnvInt32("GRAPH_SYNC_INTERVAL", 3600)) * time.Second
+
+	subscription.NewRunner(subscription.Config[lnrpc.GraphTopologyUpdate]{
+		Name:      "channel_graph",
+		Pubkey:    m.lightningNode.GetPubkey(),
+		Subscribe: m.subscribeChannelGraph,
+		Handle:    m.handleGraphTopologyUpdate,
+		Heartbeat: m.Heartbeat,
+	}).Start(shutdownCtx, waitGroup)
+
+	waitGroup.Add(1)
+	go m.startSyncLoop(syncInterval, shutdownCtx, waitGroup)
+}
+
+func (m *ChannelGraphMonitor) handleGraphTopologyUpdate(graphTopologyUpdate *lnrpc.GraphTopologyUpdate) {
+	/** Graph Topology Update received.
+	 *  Apply the channel and policy updates of our peers
+	 *  and the channels around them to the graph.
+	 *  Alert if a peer's policy toward us changed.
+	 */
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	policyChanges := m.Graph.UpdateEdges(graphTopologyUpdate)
+	metricGraphUpdatesTotal.WithLabelValues(m.lightningNode.GetPubkey()).Inc()
+
+	m.alertPolicyChanges(policyChanges)
+}
+
+// alertPolicyChanges alerts when a peer changes its policy toward us
+func (m *ChannelGraphMonitor) alertPolicyChanges(policyChanges []*graph.PolicyChange) {
+	for _, policyChange := range policyChanges {
+		metricGraphPeerPolicyChangesTotal.WithLabelValues(m.lightningNode.GetPubkey()).Inc()
+		metrics.RecordError("LNM266", "Peer policy changed", fmt.Errorf("peer %v changed policy on channel %v", policyChange.Pubkey, policyChange.ChanID))
+		log.Printf("LNM266: Node=%v, ChanID=%v, Previous=%#v, Current=%#v", m.lightningNode.GetName(), policyChange.ChanID, policyChange.Previous, policyChange.Current)
+	}
+}
+
+func (m *ChannelGraphMonitor) subscribeChannelGraph() (subscription.Stream[lnrpc.GraphTopologyUpdate], error) {
+	channelGraphClient, err := m.LightningService.SubscribeChannelGraph(&lnrpc.GraphTopologySubscription{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	m.syncGraph()
+
+	return channelGraphClient, nil
+}
+
+func (m *ChannelGraphMonitor) startSyncLoop(syncInterval time.Duration, shutdownCtx context.Context, waitGroup *sync.WaitGroup) {
+	defer waitGroup.Done()
+
+	ticker := time.NewTicker(syncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-shutdownCtx.Done():
+			log.Printf("Shutting down Channel Graph syncs")
+			return
+		case <-ticker.C:
+			m.syncGraph()
+		}
+	}
+}
+
+// syncGraph loads our channels and the channels of our peers from LND,
+// catching up on updates missed while not subscribed and on the channels
+// of new peers
+func (m *ChannelGraphMonitor) syncGraph() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	getInfoResponse, err := m.LightningService.GetInfo(&lnrpc.GetInfoRequest{})
+
+	if err != nil {
+		metrics.RecordError("LNM267", "Error getting info", err)
+		log.Printf("LNM267: Node=%v", m.lightningNode.GetName())
+		return
+	}
+
+	listChannelsResponse, err := m.LightningService.ListChannels(&lnrpc.ListChannelsRequest{})
+
+	if err != nil {
+		metrics.RecordError("LNM268", "Error listing channels", err)
+		log.Printf("LNM268: Node=%v", m.lightningNode.GetName())
+		return
+	}
+
+	peers := make(map[string]bool)
+	chanIDs := make(map[uint64]bool)
+	edges := []*lnrpc.ChannelEdge{}
+
+	for _, openChannel := range listChannelsResponse.Channels {
+		// Private channels are not announced, so neither are peers only
+		// connected by them
+		if openChannel.Private || peers[openChannel.RemotePubkey] {
+			continue
+		}
+
+		peers[openChannel.RemotePubkey] = true
+
+		nodeInfo, err := m.LightningService.GetNodeInfo(&lnrpc.NodeInfoRequest{
+			PubKey:          openChannel.RemotePubkey,
+			IncludeChannels: true,
+		})
+
+		if err != nil {
+			metrics.RecordError("LNM269", "Error getting node info", err)
+			log.Printf("LNM269: Node=%v, Pubkey=%v", m.lightningNode.GetName(), openChannel.RemotePubkey)
+			continue
+		}
+
+		for _, channelEdge := range nodeInfo.Channels {
+			if !chanIDs[channelEdge.ChannelId] {
+				chanIDs[channelEdge.ChannelId] = true
+				edges = append(edges, channelEdge)
+			}
+		}
+	}
+
+	m.alertPolicyChanges(m.Graph.Load(getInfoResponse.IdentityPubkey, edges))
+}
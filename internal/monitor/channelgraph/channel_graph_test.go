@@ -0,0 +1,201 @@
+package channelgraph_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	dbMocks "github.com/satimoto/go-datastore/pkg/db/mocks"
+	ferpMocks "github.com/satimoto/go-lnm/internal/ferp/mocks"
+	lightningnetworkMocks "github.com/satimoto/go-lnm/internal/lightningnetwork/mocks"
+	channelgraphMocks "github.com/satimoto/go-lnm/internal/monitor/channelgraph/mocks"
+	notificationMocks "github.com/satimoto/go-lnm/internal/notification/mocks"
+	serviceMocks "github.com/satimoto/go-lnm/internal/service/mocks"
+	ocpiMocks "github.com/satimoto/go-ocpi/pkg/ocpi/mocks"
+)
+
+func TestChannelGraph(t *testing.T) {
+	t.Run("Peer policy updated", func(t *testing.T) {
+		shutdownCtx, cancelFunc := context.WithCancel(context.Background())
+		waitGroup := &sync.WaitGroup{}
+
+		mockRepository := dbMocks.NewMockRepositoryService()
+		mockFerpService := ferpMocks.NewService()
+		mockLightningService := lightningnetworkMocks.NewService()
+		mockNotificationService := notificationMocks.NewService()
+		mockOcpiService := ocpiMocks.NewService()
+		mockServices := serviceMocks.NewService(mockFerpService, mockLightningService, mockNotificationService, mockOcpiService)
+
+		channelGraphMonitor := channelgraphMocks.NewChannelGraphMonitor(mockRepository, mockServices)
+		recvChan := mockLightningService.NewSubscribeChannelGraphMockData()
+
+		mockLightningService.SetGetInfoMockData(&lnrpc.GetInfoResponse{
+			IdentityPubkey: "02aaaa",
+		})
+
+		mockLightningService.SetListChannelsMockData(&lnrpc.ListChannelsResponse{
+			Channels: []*lnrpc.Channel{{
+				ChanId:       1,
+				RemotePubkey: "02bbbb",
+			}},
+		})
+
+		mockLightningService.SetGetNodeInfoMockData(&lnrpc.NodeInfo{
+			Channels: []*lnrpc.ChannelEdge{{
+				ChannelId:   1,
+				Capacity:    1000000,
+				Node1Pub:    "02aaaa",
+				Node2Pub:    "02bbbb",
+				Node1Policy: &lnrpc.RoutingPolicy{FeeBaseMsat: 1000, FeeRateMilliMsat: 100},
+				Node2Policy: &lnrpc.RoutingPolicy{FeeBaseMsat: 1000, FeeRateMilliMsat: 200},
+			}, {
+				ChannelId:   2,
+				Capacity:    2000000,
+				Node1Pub:    "02bbbb",
+				Node2Pub:    "02cccc",
+				Node1Policy: &lnrpc.RoutingPolicy{FeeBaseMsat: 1000, FeeRateMilliMsat: 50},
+				Node2Policy: &lnrpc.RoutingPolicy{FeeBaseMsat: 0, FeeRateMilliMsat: 300},
+			}},
+		})
+
+		channelGraphMonitor.StartMonitor(1, shutdownCtx, waitGroup)
+
+		recvChan <- &lnrpc.GraphTopologyUpdate{
+			ChannelUpdates: []*lnrpc.ChannelEdgeUpdate{{
+				ChanId:          1,
+				Capacity:        1000000,
+				AdvertisingNode: "02bbbb",
+				ConnectingNode:  "02aaaa",
+				RoutingPolicy:   &lnrpc.RoutingPolicy{FeeBaseMsat: 1000, FeeRateMilliMsat: 500},
+			}},
+		}
+
+		time.Sleep(time.Second * 2)
+
+		policy, ok := channelGraphMonitor.Graph.GetPeerPolicy(1)
+
+		if !ok || policy.FeeRatePpm != 500 {
+			t.Errorf("Peer policy mismatch: %#v", policy)
+		}
+
+		feeSummary := channelGraphMonitor.Graph.GetFeeSummary("02bbbb")
+
+		if feeSummary.Competitors != 1 || feeSummary.MinFeeRatePpm != 300 || feeSummary.LocalFeeRatePpm != 100 {
+			t.Errorf("Fee summary mismatch: %#v", feeSummary)
+		}
+
+		cancelFunc()
+		waitGroup.Wait()
+	})
+	t.Run("Skip private channel peers", func(t *testing.T) {
+		shutdownCtx, cancelFunc := context.WithCancel(context.Background())
+		waitGroup := &sync.WaitGroup{}
+
+		mockRepository := dbMocks.NewMockRepositoryService()
+		mockFerpService := ferpMocks.NewService()
+		mockLightningService := lightningnetworkMocks.NewService()
+		mockNotificationService := notificationMocks.NewService()
+		mockOcpiService := ocpiMocks.NewService()
+		mockServices := serviceMocks.NewService(mockFerpService, mockLightningService, mockNotificationService, mockOcpiService)
+
+		channelGraphMonitor := channelgraphMocks.NewChannelGraphMonitor(mockRepository, mockServices)
+		mockLightningService.NewSubscribeChannelGraphMockData()
+
+		mockLightningService.SetGetInfoMockData(&lnrpc.GetInfoResponse{
+			IdentityPubkey: "02aaaa",
+		})
+
+		mockLightningService.SetListChannelsMockData(&lnrpc.ListChannelsResponse{
+			Channels: []*lnrpc.Channel{{
+				ChanId:       3,
+				RemotePubkey: "02dddd",
+				Private:      true,
+			}, {
+				ChanId:       1,
+				RemotePubkey: "02bbbb",
+			}},
+		})
+
+		mockLightningService.SetGetNodeInfoMockData(&lnrpc.NodeInfo{
+			Channels: []*lnrpc.ChannelEdge{{
+				ChannelId:   1,
+				Capacity:    1000000,
+				Node1Pub:    "02aaaa",
+				Node2Pub:    "02bbbb",
+				Node1Policy: &lnrpc.RoutingPolicy{FeeBaseMsat: 1000, FeeRateMilliMsat: 100},
+				Node2Policy: &lnrpc.RoutingPolicy{FeeBaseMsat: 1000, FeeRateMilliMsat: 200},
+			}},
+		})
+
+		// Left over unless the private peer is looked up
+		mockLightningService.SetGetNodeInfoMockData(&lnrpc.NodeInfo{})
+
+		channelGraphMonitor.StartMonitor(1, shutdownCtx, waitGroup)
+
+		time.Sleep(time.Second * 2)
+
+		if policy, ok := channelGraphMonitor.Graph.GetPeerPolicy(1); !ok || policy.FeeRatePpm != 200 {
+			t.Errorf("Peer policy mismatch: %#v", policy)
+		}
+
+		if _, err := mockLightningService.GetNodeInfo(&lnrpc.NodeInfoRequest{}); err != nil {
+			t.Errorf("Expected private channel peer not to be looked up")
+		}
+
+		cancelFunc()
+		waitGroup.Wait()
+	})
+
+	t.Run("Continue after node info error", func(t *testing.T) {
+		shutdownCtx, cancelFunc := context.WithCancel(context.Background())
+		waitGroup := &sync.WaitGroup{}
+
+		mockRepository := dbMocks.NewMockRepositoryService()
+		mockFerpService := ferpMocks.NewService()
+		mockLightningService := lightningnetworkMocks.NewService()
+		mockNotificationService := notificationMocks.NewService()
+		mockOcpiService := ocpiMocks.NewService()
+		mockServices := serviceMocks.NewService(mockFerpService, mockLightningService, mockNotificationService, mockOcpiService)
+
+		channelGraphMonitor := channelgraphMocks.NewChannelGraphMonitor(mockRepository, mockServices)
+		mockLightningService.NewSubscribeChannelGraphMockData()
+
+		mockLightningService.SetGetInfoMockData(&lnrpc.GetInfoResponse{
+			IdentityPubkey: "02aaaa",
+		})
+
+		mockLightningService.SetListChannelsMockData(&lnrpc.ListChannelsResponse{
+			Channels: []*lnrpc.Channel{{
+				ChanId:       1,
+				RemotePubkey: "02bbbb",
+			}, {
+				ChanId:       4,
+				RemotePubkey: "02eeee",
+			}},
+		})
+
+		mockLightningService.SetGetNodeInfoMockData(&lnrpc.NodeInfo{
+			Channels: []*lnrpc.ChannelEdge{{
+				ChannelId:   1,
+				Capacity:    1000000,
+				Node1Pub:    "02aaaa",
+				Node2Pub:    "02bbbb",
+				Node1Policy: &lnrpc.RoutingPolicy{FeeBaseMsat: 1000, FeeRateMilliMsat: 100},
+				Node2Policy: &lnrpc.RoutingPolicy{FeeBaseMsat: 1000, FeeRateMilliMsat: 200},
+			}},
+		})
+
+		channelGraphMonitor.StartMonitor(1, shutdownCtx, waitGroup)
+
+		time.Sleep(time.Second * 2)
+
+		if _, ok := channelGraphMonitor.Graph.GetPeerPolicy(1); !ok {
+			t.Errorf("Expected graph to be loaded after node info error")
+		}
+
+		cancelFunc()
+		waitGroup.Wait()
+	})
+}
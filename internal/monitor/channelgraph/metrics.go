@@ -0,0 +1,17 @@
+package channelgraph
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricGraphUpdatesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lsp_graph_updates_total",
+		Help: "The total number of channel graph topology updates",
+	}, []string{"pubkey"})
+	metricGraphPeerPolicyChangesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lsp_graph_peer_policy_changes_total",
+		Help: "The total number of changes to the policies of peers toward us",
+	}, []string{"pubkey"})
+)
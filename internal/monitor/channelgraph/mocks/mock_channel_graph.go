@@ -0,0 +1,15 @@
+package mocks
+
+import (
+	mocks "github.com/satimoto/go-datastore/pkg/db/mocks"
+	"github.com/satimoto/go-lnm/internal/graph"
+	"github.com/satimoto/go-lnm/internal/monitor/channelgraph"
+	"github.com/satimoto/go-lnm/internal/service"
+)
+
+func NewChannelGraphMonitor(repositoryService *mocks.MockRepositoryService, services *service.ServiceResolver) *channelgraph.ChannelGraphMonitor {
+	return &channelgraph.ChannelGraphMonitor{
+		LightningService: services.LightningService,
+		Graph:            graph.NewGraph(),
+	}
+}
@@ -11,42 +11,49 @@ import (
 	"github.com/satimoto/go-datastore/pkg/routingevent"
 	"github.com/satimoto/go-datastore/pkg/util"
 	"github.com/satimoto/go-lnm/internal/ferp"
+	"github.com/satimoto/go-lnm/internal/health"
 	"github.com/satimoto/go-lnm/internal/lightningnetwork"
 	metrics "github.com/satimoto/go-lnm/internal/metric"
 	"github.com/satimoto/go-lnm/internal/service"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"github.com/satimoto/go-lnm/internal/subscription"
 )
 
 type HtlcEventMonitor struct {
-	FerpService              ferp.Ferp
-	LightningService         lightningnetwork.LightningNetwork
-	HtlcEventsClient         routerrpc.Router_SubscribeHtlcEventsClient
-	RoutingEventRepository   routingevent.RoutingEventRepository
-	accountingCurrency       string
-	nodeID                   int64
+	FerpService            ferp.Ferp
+	LightningService       lightningnetwork.LightningNetwork
+	Heartbeat              *health.Heartbeat
+	RoutingEventRepository routingevent.RoutingEventRepository
+	accountingCurrency     string
+	lightningNode          *lightningnetwork.LightningNode
+	nodeID                 int64
 }
 
 func NewHtlcEventMonitor(repositoryService *db.RepositoryService, services *service.ServiceResolver) *HtlcEventMonitor {
 	return &HtlcEventMonitor{
-		FerpService:              services.FerpService,
-		LightningService:         services.LightningService,
-		RoutingEventRepository:   routingevent.NewRepository(repositoryService),
+		FerpService:            services.FerpService,
+		LightningService:       services.LightningService,
+		lightningNode:          services.LightningNode,
+		RoutingEventRepository: routingevent.NewRepository(repositoryService),
+		Heartbeat:              health.RegisterNodeHeartbeat(services.LightningNode, "monitor_htlc_event", true, 0),
 	}
 }
 
 func (m *HtlcEventMonitor) StartMonitor(nodeID int64, shutdownCtx context.Context, waitGroup *sync.WaitGroup) {
 	log.Printf("Starting up Htlc Events")
-	htlcEventChan := make(chan routerrpc.HtlcEvent)
 
 	m.accountingCurrency = util.GetEnv("ACCOUNTING_CURRENCY", "EUR")
 	m.nodeID = nodeID
 
-	go m.waitForHtlcEvents(shutdownCtx, waitGroup, htlcEventChan)
-	go m.subscribeHtlcEventInterceptions(htlcEventChan)
+	subscription.NewRunner(subscription.Config[routerrpc.HtlcEvent]{
+		Name:      "htlc_event",
+		Pubkey:    m.lightningNode.GetPubkey(),
+		Subscribe: m.subscribeHtlcEvents,
+		Handle:    m.handleHtlcEvent,
+		Heartbeat: m.Heartbeat,
+	}).Start(shutdownCtx, waitGroup)
 }
 
-func (m *HtlcEventMonitor) handleHtlcEvent(htlcEvent routerrpc.HtlcEvent) {
+func (m *HtlcEventMonitor) handleHtlcEvent(htlcEvent *routerrpc.HtlcEvent) {
 	/** HTLC Event received.
 	 *  Check that the event type is a Forward event and that it is successful.
 	 *  Find the Channel Request HTLC by the circuit key params.
@@ -69,7 +76,7 @@ func (m *HtlcEventMonitor) handleHtlcEvent(htlcEvent routerrpc.HtlcEvent) {
 	}
 }
 
-func (m *HtlcEventMonitor) handleForwardHtlcEvent(ctx context.Context, htlcEvent routerrpc.HtlcEvent) {
+func (m *HtlcEventMonitor) handleForwardHtlcEvent(ctx context.Context, htlcEvent *routerrpc.HtlcEvent) {
 	forwardEvent := htlcEvent.GetForwardEvent()
 
 	currencyRate, err := m.FerpService.GetRate(m.accountingCurrency)
@@ -109,7 +116,7 @@ func (m *HtlcEventMonitor) handleForwardHtlcEvent(ctx context.Context, htlcEvent
 	}
 }
 
-func (m *HtlcEventMonitor) handleForwardFailHtlcEvent(ctx context.Context, htlcEvent routerrpc.HtlcEvent) {
+func (m *HtlcEventMonitor) handleForwardFailHtlcEvent(ctx context.Context, htlcEvent *routerrpc.HtlcEvent) {
 	incomingChannelId := int64(htlcEvent.IncomingChannelId)
 	incomingHtlcId := int64(htlcEvent.IncomingHtlcId)
 
@@ -141,7 +148,7 @@ func (m *HtlcEventMonitor) handleForwardFailHtlcEvent(ctx context.Context, htlcE
 	}
 }
 
-func (m *HtlcEventMonitor) handleLinkFailHtlcEvent(ctx context.Context, htlcEvent routerrpc.HtlcEvent) {
+func (m *HtlcEventMonitor) handleLinkFailHtlcEvent(ctx context.Context, htlcEvent *routerrpc.HtlcEvent) {
 	linkFailEvent := htlcEvent.GetLinkFailEvent()
 	incomingChannelId := int64(htlcEvent.IncomingChannelId)
 	incomingHtlcId := int64(htlcEvent.IncomingHtlcId)
@@ -204,7 +211,7 @@ func (m *HtlcEventMonitor) handleLinkFailHtlcEvent(ctx context.Context, htlcEven
 	}
 }
 
-func (m *HtlcEventMonitor) handleSettleHtlcEvent(ctx context.Context, htlcEvent routerrpc.HtlcEvent) {
+func (m *HtlcEventMonitor) handleSettleHtlcEvent(ctx context.Context, htlcEvent *routerrpc.HtlcEvent) {
 	incomingChannelId := int64(htlcEvent.IncomingChannelId)
 	incomingHtlcId := int64(htlcEvent.IncomingHtlcId)
 
@@ -236,54 +243,6 @@ func (m *HtlcEventMonitor) handleSettleHtlcEvent(ctx context.Context, htlcEvent
 	}
 }
 
-func (m *HtlcEventMonitor) subscribeHtlcEventInterceptions(htlcEventChan chan<- routerrpc.HtlcEvent) {
-	htlcEventsClient, err := m.waitForSubscribeHtlcEventsClient(0, 1000)
-	util.PanicOnError("LNM018", "Error creating Htlc Events client", err)
-	m.HtlcEventsClient = htlcEventsClient
-
-	for {
-		htlcInterceptRequest, err := m.HtlcEventsClient.Recv()
-
-		if err == nil {
-			htlcEventChan <- *htlcInterceptRequest
-		} else {
-			m.HtlcEventsClient, err = m.waitForSubscribeHtlcEventsClient(100, 1000)
-			util.PanicOnError("LNM019", "Error creating Htlc Events client", err)
-		}
-	}
-}
-
-func (m *HtlcEventMonitor) waitForHtlcEvents(shutdownCtx context.Context, waitGroup *sync.WaitGroup, htlcEventChan chan routerrpc.HtlcEvent) {
-	waitGroup.Add(1)
-	defer close(htlcEventChan)
-	defer waitGroup.Done()
-
-	for {
-		select {
-		case <-shutdownCtx.Done():
-			log.Printf("Shutting down Htlc Events")
-			return
-		case htlcInterceptRequest := <-htlcEventChan:
-			m.handleHtlcEvent(htlcInterceptRequest)
-		}
-	}
-}
-
-func (m *HtlcEventMonitor) waitForSubscribeHtlcEventsClient(initialDelay, retryDelay time.Duration) (routerrpc.Router_SubscribeHtlcEventsClient, error) {
-	for {
-		if initialDelay > 0 {
-			time.Sleep(retryDelay * time.Millisecond)
-		}
-
-		subscribeHtlcEventsClient, err := m.LightningService.SubscribeHtlcEvents(&routerrpc.SubscribeHtlcEventsRequest{})
-
-		if err == nil {
-			return subscribeHtlcEventsClient, nil
-		} else if status.Code(err) != codes.Unavailable {
-			return nil, err
-		}
-
-		log.Print("Waiting for SubscribeHtlcEvents client")
-		time.Sleep(retryDelay * time.Millisecond)
-	}
+func (m *HtlcEventMonitor) subscribeHtlcEvents() (subscription.Stream[routerrpc.HtlcEvent], error) {
+	return m.LightningService.SubscribeHtlcEvents(&routerrpc.SubscribeHtlcEventsRequest{})
 }
@@ -2,160 +2,258 @@ package invoice
 
 import (
 	"context"
+	"encoding/hex"
 	"log"
 	"sync"
-	"time"
 
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/satimoto/go-datastore/pkg/db"
+	"github.com/satimoto/go-datastore/pkg/node"
 	"github.com/satimoto/go-datastore/pkg/param"
-	"github.com/satimoto/go-datastore/pkg/util"
+	dbUtil "github.com/satimoto/go-datastore/pkg/util"
+	"github.com/satimoto/go-lnm/internal/health"
 	"github.com/satimoto/go-lnm/internal/lightningnetwork"
 	metrics "github.com/satimoto/go-lnm/internal/metric"
 	"github.com/satimoto/go-lnm/internal/service"
 	"github.com/satimoto/go-lnm/internal/session"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"github.com/satimoto/go-lnm/internal/sessionevent"
+	"github.com/satimoto/go-lnm/internal/subscription"
+	"github.com/satimoto/go-lnm/internal/webhook"
 )
 
 type InvoiceMonitor struct {
 	LightningService lightningnetwork.LightningNetwork
-	InvoicesClient   lnrpc.Lightning_SubscribeInvoicesClient
+	Heartbeat        *health.Heartbeat
+	NodeRepository   node.NodeRepository
 	SessionResolver  *session.SessionResolver
+	indexMutex       sync.Mutex
+	settleMutex      sync.Mutex
+	addIndex         uint64
+	settleIndex      uint64
+	lightningNode    *lightningnetwork.LightningNode
 	nodeID           int64
 }
 
 func NewInvoiceMonitor(repositoryService *db.RepositoryService, services *service.ServiceResolver) *InvoiceMonitor {
 	return &InvoiceMonitor{
 		LightningService: services.LightningService,
+		lightningNode:    services.LightningNode,
+		NodeRepository:   node.NewRepository(repositoryService),
 		SessionResolver:  session.NewResolver(repositoryService, services),
+		Heartbeat:        health.RegisterNodeHeartbeat(services.LightningNode, "monitor_invoice", true, 0),
 	}
 }
 
 func (m *InvoiceMonitor) StartMonitor(nodeID int64, shutdownCtx context.Context, waitGroup *sync.WaitGroup) {
 	log.Printf("Starting up Invoices")
-	invoiceChan := make(chan lnrpc.Invoice)
 
 	m.nodeID = nodeID
-	go m.waitForInvoices(shutdownCtx, waitGroup, invoiceChan)
-	go m.subscribeInvoiceInterceptions(invoiceChan)
+	m.loadInvoiceIndexes()
+
+	subscription.NewRunner(subscription.Config[lnrpc.Invoice]{
+		Name:      "invoice",
+		Pubkey:    m.lightningNode.GetPubkey(),
+		Subscribe: m.subscribeInvoices,
+		Handle:    m.handleInvoice,
+		Heartbeat: m.Heartbeat,
+	}).Start(shutdownCtx, waitGroup)
+
+	go m.reconcileSessionInvoices()
 }
 
-func (m *InvoiceMonitor) handleInvoice(invoice lnrpc.Invoice) {
+func (m *InvoiceMonitor) handleInvoice(invoice *lnrpc.Invoice) {
 	settled := invoice.State == lnrpc.Invoice_SETTLED
 
 	log.Print("Invoice")
 	log.Printf("PaymentRequest: %v", invoice.PaymentRequest)
 	log.Printf("Settled: %v", settled)
 
-	/** Invoice received.
-	 *  Check that the invoice is settled.
+	ctx := context.Background()
+
+	if settled {
+		m.settleSessionInvoice(ctx, invoice.PaymentRequest)
+	}
+
+	m.updateInvoiceIndexes(ctx, invoice.AddIndex, invoice.SettleIndex)
+}
+
+// settleSessionInvoice sets the session invoice with the payment request as
+// settled, then unrestricts the user if all their session invoices are settled
+func (m *InvoiceMonitor) settleSessionInvoice(ctx context.Context, paymentRequest string) {
+	/** Invoice settled.
 	 *  Find a Session Invoice that has a matching payment request.
 	 *  Set the Session Invoice as settled.
 	 *  Get users unsettled session invoices, if all are settled then unlock tokens
 	 */
-	ctx := context.Background()
 
-	if sessionInvoice, err := m.SessionResolver.Repository.GetSessionInvoiceByPaymentRequest(ctx, invoice.PaymentRequest); err == nil {
-		if settled {
-			// Settle session invoice
-			updateSessionInvoiceParams := param.NewUpdateSessionInvoiceParams(sessionInvoice)
-			updateSessionInvoiceParams.IsSettled = true
-
-			_, err = m.SessionResolver.Repository.UpdateSessionInvoice(ctx, updateSessionInvoiceParams)
-
-			if err != nil {
-				metrics.RecordError("LNM027", "Error updating session invoice", err)
-				log.Printf("LNM027: Params=%#v", updateSessionInvoiceParams)
-				return
-			}
-
-			// Metrics: Increment number of settled session invoices
-			metricSessionInvoicesSettledTotal.Inc()
-
-			// Get the user from the session ID
-			user, err := m.SessionResolver.UserResolver.Repository.GetUserBySessionID(ctx, sessionInvoice.SessionID)
-
-			if err != nil {
-				metrics.RecordError("LNM039", "Error retrieving session user", err)
-				log.Printf("LNM039: SessionID=%v", sessionInvoice.SessionID)
-				return
-			}
-
-			// List users unsettled session invoices
-			sessionInvoices, err := m.SessionResolver.Repository.ListSessionInvoicesByUserID(ctx, db.ListSessionInvoicesByUserIDParams{
-				ID:        user.ID,
-				IsSettled: false,
-				IsExpired: false,
-			})
-
-			if err != nil {
-				metrics.RecordError("LNM040", "Error retrieving user unsettled session invoices", err)
-				log.Printf("LNM040: SessionID=%v, UserID=%v", sessionInvoice.SessionID, user.ID)
-				return
-			}
-
-			// If there are no unsettled invoices then unlock user tokens
-			if len(sessionInvoices) == 0 {
-				err = m.SessionResolver.UserResolver.UnrestrictUser(ctx, user)
-
-				if err != nil {
-					metrics.RecordError("LNM041", "Error unrestricting user", err)
-					log.Printf("LNM041: SessionID=%v, UserID=%v", sessionInvoice.SessionID, user.ID)
-				}
-			}
+	// Settlements from the subscription and reconciliation can overlap
+	m.settleMutex.Lock()
+	defer m.settleMutex.Unlock()
+
+	sessionInvoice, err := m.SessionResolver.Repository.GetSessionInvoiceByPaymentRequest(ctx, paymentRequest)
+
+	if err != nil || sessionInvoice.IsSettled {
+		return
+	}
+
+	// Settle session invoice
+	updateSessionInvoiceParams := param.NewUpdateSessionInvoiceParams(sessionInvoice)
+	updateSessionInvoiceParams.IsSettled = true
+
+	updatedSessionInvoice, err := m.SessionResolver.Repository.UpdateSessionInvoice(ctx, updateSessionInvoiceParams)
+
+	if err != nil {
+		metrics.RecordError("LNM027", "Error updating session invoice", err)
+		log.Printf("LNM027: Params=%#v", updateSessionInvoiceParams)
+		return
+	}
+
+	// Metrics: Increment number of settled session invoices
+	metricSessionInvoicesSettledTotal.Inc()
+
+	m.SessionResolver.QueueSessionInvoiceEvent(ctx, webhook.INVOICE_SETTLED, updatedSessionInvoice)
+	m.SessionResolver.PublishSessionInvoiceEvent(ctx, sessionevent.INVOICE_SETTLED, updatedSessionInvoice)
+
+	// Get the user from the session ID
+	user, err := m.SessionResolver.UserResolver.Repository.GetUserBySessionID(ctx, sessionInvoice.SessionID)
+
+	if err != nil {
+		metrics.RecordError("LNM039", "Error retrieving session user", err)
+		log.Printf("LNM039: SessionID=%v", sessionInvoice.SessionID)
+		return
+	}
+
+	// List users unsettled session invoices
+	sessionInvoices, err := m.SessionResolver.Repository.ListSessionInvoicesByUserID(ctx, db.ListSessionInvoicesByUserIDParams{
+		ID:        user.ID,
+		IsSettled: false,
+		IsExpired: false,
+	})
+
+	if err != nil {
+		metrics.RecordError("LNM040", "Error retrieving user unsettled session invoices", err)
+		log.Printf("LNM040: SessionID=%v, UserID=%v", sessionInvoice.SessionID, user.ID)
+		return
+	}
+
+	// If there are no unsettled invoices then unlock user tokens
+	if len(sessionInvoices) == 0 {
+		err = m.SessionResolver.UserResolver.UnrestrictUser(ctx, user)
+
+		if err != nil {
+			metrics.RecordError("LNM041", "Error unrestricting user", err)
+			log.Printf("LNM041: SessionID=%v, UserID=%v", sessionInvoice.SessionID, user.ID)
 		}
 	}
 }
 
-func (m *InvoiceMonitor) subscribeInvoiceInterceptions(invoiceChan chan<- lnrpc.Invoice) {
-	invoicesClient, err := m.waitForSubscribeInvoicesClient(0, 1000)
-	util.PanicOnError("LNM020", "Error creating Invoices client", err)
-	m.InvoicesClient = invoicesClient
+// reconcileSessionInvoices settles the unsettled session invoices of the node
+// that were settled in LND while the invoice subscription was not running
+func (m *InvoiceMonitor) reconcileSessionInvoices() {
+	ctx := context.Background()
+	listSessionInvoicesParams := db.ListSessionInvoicesByNodeIDParams{
+		NodeID:    dbUtil.SqlNullInt64(m.nodeID),
+		IsExpired: false,
+		IsSettled: false,
+	}
 
-	for {
-		invoice, err := m.InvoicesClient.Recv()
+	sessionInvoices, err := m.SessionResolver.Repository.ListSessionInvoicesByNodeID(ctx, listSessionInvoicesParams)
 
-		if err == nil {
-			invoiceChan <- *invoice
-		} else {
-			m.InvoicesClient, err = m.waitForSubscribeInvoicesClient(100, 1000)
-			util.PanicOnError("LNM021", "Error creating Invoices client", err)
+	if err != nil {
+		metrics.RecordError("LNM250", "Error listing session invoices", err)
+		log.Printf("LNM250: Params=%#v", listSessionInvoicesParams)
+		return
+	}
+
+	for _, sessionInvoice := range sessionInvoices {
+		payReq, err := m.LightningService.DecodePayReq(&lnrpc.PayReqString{PayReq: sessionInvoice.PaymentRequest})
+
+		if err != nil {
+			metrics.RecordError("LNM251", "Error decoding payment request", err)
+			log.Printf("LNM251: SessionInvoiceID=%v", sessionInvoice.ID)
+			continue
+		}
+
+		paymentHash, err := hex.DecodeString(payReq.PaymentHash)
+
+		if err != nil {
+			metrics.RecordError("LNM251", "Error decoding payment request", err)
+			log.Printf("LNM251: SessionInvoiceID=%v, PaymentHash=%v", sessionInvoice.ID, payReq.PaymentHash)
+			continue
+		}
+
+		invoice, err := m.LightningService.LookupInvoice(&lnrpc.PaymentHash{RHash: paymentHash})
+
+		if err != nil {
+			metrics.RecordError("LNM252", "Error looking up invoice", err)
+			log.Printf("LNM252: SessionInvoiceID=%v, PaymentHash=%v", sessionInvoice.ID, payReq.PaymentHash)
+			continue
+		}
+
+		if invoice.State == lnrpc.Invoice_SETTLED {
+			log.Printf("Reconciling settled session invoice %v", sessionInvoice.ID)
+			metricSessionInvoicesReconciledTotal.Inc()
+			m.settleSessionInvoice(ctx, sessionInvoice.PaymentRequest)
 		}
 	}
 }
 
-func (m *InvoiceMonitor) waitForInvoices(shutdownCtx context.Context, waitGroup *sync.WaitGroup, invoiceChan chan lnrpc.Invoice) {
-	waitGroup.Add(1)
-	defer close(invoiceChan)
-	defer waitGroup.Done()
-
-	for {
-		select {
-		case <-shutdownCtx.Done():
-			log.Printf("Shutting down Invoices")
-			return
-		case invoice := <-invoiceChan:
-			m.handleInvoice(invoice)
-		}
+// loadInvoiceIndexes reads the add and settle indexes of the last invoice
+// handled so the subscription resumes from them
+func (m *InvoiceMonitor) loadInvoiceIndexes() {
+	n, err := m.NodeRepository.GetNode(context.Background(), m.nodeID)
+
+	if err != nil {
+		metrics.RecordError("LNM248", "Error retrieving node", err)
+		log.Printf("LNM248: NodeID=%v", m.nodeID)
+		return
 	}
+
+	m.indexMutex.Lock()
+	defer m.indexMutex.Unlock()
+
+	m.addIndex = uint64(n.InvoiceAddIndex)
+	m.settleIndex = uint64(n.InvoiceSettleIndex)
 }
 
-func (m *InvoiceMonitor) waitForSubscribeInvoicesClient(initialDelay, retryDelay time.Duration) (lnrpc.Lightning_SubscribeInvoicesClient, error) {
-	for {
-		if initialDelay > 0 {
-			time.Sleep(retryDelay * time.Millisecond)
-		}
+// updateInvoiceIndexes persists the add and settle indexes of a handled invoice
+func (m *InvoiceMonitor) updateInvoiceIndexes(ctx context.Context, addIndex, settleIndex uint64) {
+	m.indexMutex.Lock()
+	defer m.indexMutex.Unlock()
 
-		subscribeInvoicesClient, err := m.LightningService.SubscribeInvoices(&lnrpc.InvoiceSubscription{})
+	if addIndex <= m.addIndex && settleIndex <= m.settleIndex {
+		return
+	}
 
-		if err == nil {
-			return subscribeInvoicesClient, nil
-		} else if status.Code(err) != codes.Unavailable {
-			return nil, err
-		}
+	if addIndex > m.addIndex {
+		m.addIndex = addIndex
+	}
+
+	if settleIndex > m.settleIndex {
+		m.settleIndex = settleIndex
+	}
 
-		log.Print("Waiting for Invoices client")
-		time.Sleep(retryDelay * time.Millisecond)
+	updateNodeInvoiceIndexesParams := db.UpdateNodeInvoiceIndexesParams{
+		ID:                 m.nodeID,
+		InvoiceAddIndex:    int64(m.addIndex),
+		InvoiceSettleIndex: int64(m.settleIndex),
 	}
+
+	if err := m.NodeRepository.UpdateNodeInvoiceIndexes(ctx, updateNodeInvoiceIndexesParams); err != nil {
+		metrics.RecordError("LNM249", "Error updating node invoice indexes", err)
+		log.Printf("LNM249: Params=%#v", updateNodeInvoiceIndexesParams)
+	}
+}
+
+// subscribeInvoices subscribes to invoices added or settled after the last
+// invoice handled, so invoices settled while disconnected are not missed
+func (m *InvoiceMonitor) subscribeInvoices() (subscription.Stream[lnrpc.Invoice], error) {
+	m.indexMutex.Lock()
+	invoiceSubscription := &lnrpc.InvoiceSubscription{
+		AddIndex:    m.addIndex,
+		SettleIndex: m.settleIndex,
+	}
+	m.indexMutex.Unlock()
+
+	return m.LightningService.SubscribeInvoices(invoiceSubscription)
 }
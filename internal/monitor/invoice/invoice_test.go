@@ -91,4 +91,57 @@ func TestInvoice(t *testing.T) {
 		cancelFunc()
 		waitGroup.Wait()
 	})
+
+	t.Run("Session invoice settled while disconnected", func(t *testing.T) {
+		shutdownCtx, cancelFunc := context.WithCancel(context.Background())
+		waitGroup := &sync.WaitGroup{}
+
+		mockRepository := dbMocks.NewMockRepositoryService()
+		mockFerpService := ferpMocks.NewService()
+		mockLightningService := lightningnetworkMocks.NewService()
+		mockOcpiService := ocpiMocks.NewService()
+		mockNotificationService := notificationMocks.NewService()
+		mockServices := serviceMocks.NewService(mockFerpService, mockLightningService, mockNotificationService, mockOcpiService)
+
+		invoiceMonitor := invoiceMocks.NewInvoiceMonitor(mockRepository, mockServices)
+		mockLightningService.NewSubscribeInvoicesMockData()
+
+		mockRepository.SetListSessionInvoicesByNodeIDMockData(dbMocks.SessionInvoicesMockData{SessionInvoices: []db.SessionInvoice{{
+			PaymentRequest: "TestPaymentRequest",
+			IsSettled:      false,
+		}}})
+
+		mockRepository.SetGetSessionInvoiceByPaymentRequestMockData(dbMocks.SessionInvoiceMockData{
+			SessionInvoice: db.SessionInvoice{
+				PaymentRequest: "TestPaymentRequest",
+				IsSettled:      false,
+			},
+		})
+
+		mockLightningService.SetDecodePayReqMockData(&lnrpc.PayReq{
+			PaymentHash: "0102",
+		})
+
+		mockLightningService.SetLookupInvoiceMockData(&lnrpc.Invoice{
+			PaymentRequest: "TestPaymentRequest",
+			State:          lnrpc.Invoice_SETTLED,
+		})
+
+		invoiceMonitor.StartMonitor(1, shutdownCtx, waitGroup)
+
+		time.Sleep(time.Second * 2)
+
+		sessionInvoice, err := mockRepository.GetUpdateSessionInvoiceMockData()
+
+		if err != nil {
+			t.Error(err)
+		}
+
+		if sessionInvoice.IsSettled != true {
+			t.Error("Session not settled")
+		}
+
+		cancelFunc()
+		waitGroup.Wait()
+	})
 }
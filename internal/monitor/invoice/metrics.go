@@ -10,4 +10,8 @@ var (
 		Name: "lsp_session_invoices_settled_total",
 		Help: "The total number of session invoices settled",
 	})
+	metricSessionInvoicesReconciledTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "lsp_session_invoices_reconciled_total",
+		Help: "The total number of session invoices found settled in LND on startup",
+	})
 )
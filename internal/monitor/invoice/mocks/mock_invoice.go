@@ -2,6 +2,7 @@ package mocks
 
 import (
 	mocks "github.com/satimoto/go-datastore/pkg/db/mocks"
+	node "github.com/satimoto/go-datastore/pkg/node/mocks"
 	"github.com/satimoto/go-lnm/internal/monitor/invoice"
 	"github.com/satimoto/go-lnm/internal/service"
 	session "github.com/satimoto/go-lnm/internal/session/mocks"
@@ -10,6 +11,7 @@ import (
 func NewInvoiceMonitor(repositoryService *mocks.MockRepositoryService, services *service.ServiceResolver) *invoice.InvoiceMonitor {
 	return &invoice.InvoiceMonitor{
 		LightningService: services.LightningService,
+		NodeRepository:   node.NewRepository(repositoryService),
 		SessionResolver:  session.NewResolver(repositoryService, services),
 	}
 }
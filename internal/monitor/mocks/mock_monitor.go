@@ -8,21 +8,40 @@ import (
 	backup "github.com/satimoto/go-lnm/internal/backup/mocks"
 	"github.com/satimoto/go-lnm/internal/monitor"
 	channelbackup "github.com/satimoto/go-lnm/internal/monitor/channelbackup/mocks"
+	channelevent "github.com/satimoto/go-lnm/internal/monitor/channelevent/mocks"
+	channelgraph "github.com/satimoto/go-lnm/internal/monitor/channelgraph/mocks"
 	htlcevent "github.com/satimoto/go-lnm/internal/monitor/htlcevent/mocks"
 	invoice "github.com/satimoto/go-lnm/internal/monitor/invoice/mocks"
+	peerevent "github.com/satimoto/go-lnm/internal/monitor/peerevent/mocks"
 	transaction "github.com/satimoto/go-lnm/internal/monitor/transaction/mocks"
 	"github.com/satimoto/go-lnm/internal/service"
 )
 
 func NewMonitor(shutdownCtx context.Context, repositoryService *mocks.MockRepositoryService, services *service.ServiceResolver) *monitor.Monitor {
-	backupService := backup.NewService()
+	nodeMonitors := []*monitor.NodeMonitor{}
+
+	for _, lightningNode := range services.LightningNodes.ListNodes() {
+		nodeMonitors = append(nodeMonitors, NewNodeMonitor(shutdownCtx, repositoryService, services.ForNode(lightningNode)))
+	}
 
 	return &monitor.Monitor{
+		NodeMonitors: nodeMonitors,
+	}
+}
+
+func NewNodeMonitor(shutdownCtx context.Context, repositoryService *mocks.MockRepositoryService, services *service.ServiceResolver) *monitor.NodeMonitor {
+	backupService := backup.NewService()
+
+	return &monitor.NodeMonitor{
+		LightningNode:        services.LightningNode,
 		LightningService:     services.LightningService,
 		NodeRepository:       node.NewRepository(repositoryService),
 		ChannelBackupMonitor: channelbackup.NewChannelBackupMonitor(repositoryService, backupService, services),
+		ChannelEventMonitor:  channelevent.NewChannelEventMonitor(repositoryService, services),
+		ChannelGraphMonitor:  channelgraph.NewChannelGraphMonitor(repositoryService, services),
 		HtlcEventMonitor:     htlcevent.NewHtlcEventMonitor(repositoryService, services),
 		InvoiceMonitor:       invoice.NewInvoiceMonitor(repositoryService, services),
+		PeerEventMonitor:     peerevent.NewPeerEventMonitor(repositoryService, services),
 		TransactionMonitor:   transaction.NewTransactionMonitor(repositoryService, services),
 	}
 }
@@ -0,0 +1,211 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/satimoto/go-datastore/pkg/db"
+	"github.com/satimoto/go-datastore/pkg/node"
+	"github.com/satimoto/go-datastore/pkg/param"
+	"github.com/satimoto/go-lnm/internal/backup"
+	"github.com/satimoto/go-lnm/internal/lightningnetwork"
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+	"github.com/satimoto/go-lnm/internal/monitor/blockepoch"
+	"github.com/satimoto/go-lnm/internal/monitor/channelbackup"
+	"github.com/satimoto/go-lnm/internal/monitor/channelevent"
+	"github.com/satimoto/go-lnm/internal/monitor/channelgraph"
+	"github.com/satimoto/go-lnm/internal/monitor/htlcevent"
+	"github.com/satimoto/go-lnm/internal/monitor/invoice"
+	"github.com/satimoto/go-lnm/internal/monitor/peerevent"
+	"github.com/satimoto/go-lnm/internal/monitor/pendingnotification"
+	"github.com/satimoto/go-lnm/internal/monitor/startup"
+	"github.com/satimoto/go-lnm/internal/monitor/transaction"
+	"github.com/satimoto/go-lnm/internal/service"
+	"github.com/satimoto/go-lnm/pkg/util"
+)
+
+// NodeMonitor registers a lightning node and runs the monitors of the node
+type NodeMonitor struct {
+	LightningNode              *lightningnetwork.LightningNode
+	LightningService           lightningnetwork.LightningNetwork
+	StartupService             startup.Startup
+	NodeRepository             node.NodeRepository
+	BlockEpochMonitor          *blockepoch.BlockEpochMonitor
+	ChannelBackupMonitor       *channelbackup.ChannelBackupMonitor
+	ChannelEventMonitor        *channelevent.ChannelEventMonitor
+	ChannelGraphMonitor        *channelgraph.ChannelGraphMonitor
+	HtlcEventMonitor           *htlcevent.HtlcEventMonitor
+	InvoiceMonitor             *invoice.InvoiceMonitor
+	PeerEventMonitor           *peerevent.PeerEventMonitor
+	PendingNotificationMonitor *pendingnotification.PendingNotificationMonitor
+	TransactionMonitor         *transaction.TransactionMonitor
+	nodeID                     int64
+	shutdownCtx                context.Context
+}
+
+func NewNodeMonitor(shutdownCtx context.Context, repositoryService *db.RepositoryService, services *service.ServiceResolver) *NodeMonitor {
+	backupPrefix := ""
+
+	if services.LightningNode.Name != lightningnetwork.DEFAULT_NODE_NAME {
+		backupPrefix = services.LightningNode.Name
+	}
+
+	backupService := backup.NewService(backupPrefix)
+	startupService := startup.NewService(repositoryService, services)
+
+	return &NodeMonitor{
+		LightningNode:              services.LightningNode,
+		LightningService:           services.LightningService,
+		StartupService:             startupService,
+		NodeRepository:             node.NewRepository(repositoryService),
+		BlockEpochMonitor:          blockepoch.NewBlockEpochMonitor(repositoryService, services),
+		ChannelBackupMonitor:       channelbackup.NewChannelBackupMonitor(repositoryService, backupService, services),
+		ChannelEventMonitor:        channelevent.NewChannelEventMonitor(repositoryService, services),
+		ChannelGraphMonitor:        channelgraph.NewChannelGraphMonitor(repositoryService, services),
+		HtlcEventMonitor:           htlcevent.NewHtlcEventMonitor(repositoryService, services),
+		InvoiceMonitor:             invoice.NewInvoiceMonitor(repositoryService, services),
+		PeerEventMonitor:           peerevent.NewPeerEventMonitor(repositoryService, services),
+		PendingNotificationMonitor: pendingnotification.NewPendingNotificationMonitor(repositoryService, services),
+		TransactionMonitor:         transaction.NewTransactionMonitor(repositoryService, services),
+		shutdownCtx:                shutdownCtx,
+	}
+}
+
+func (m *NodeMonitor) StartMonitor(waitGroup *sync.WaitGroup) {
+	for {
+		err := m.register()
+
+		if err == nil {
+			break
+		} else if m.shutdownCtx.Err() != nil {
+			return
+		}
+
+		metrics.RecordError("LNM010", "Error registering LSP", err)
+		log.Printf("LNM010: Node=%v", m.LightningNode.Name)
+
+		select {
+		case <-m.shutdownCtx.Done():
+			return
+		case <-time.After(30 * time.Second):
+		}
+	}
+
+	m.StartupService.Start(m.nodeID, m.shutdownCtx, waitGroup)
+	m.BlockEpochMonitor.StartMonitor(m.nodeID, m.shutdownCtx, waitGroup)
+	m.ChannelBackupMonitor.StartMonitor(m.nodeID, m.shutdownCtx, waitGroup)
+	m.ChannelEventMonitor.StartMonitor(m.nodeID, m.shutdownCtx, waitGroup)
+	m.ChannelGraphMonitor.StartMonitor(m.nodeID, m.shutdownCtx, waitGroup)
+	m.HtlcEventMonitor.StartMonitor(m.nodeID, m.shutdownCtx, waitGroup)
+	m.InvoiceMonitor.StartMonitor(m.nodeID, m.shutdownCtx, waitGroup)
+	m.PeerEventMonitor.StartMonitor(m.nodeID, m.shutdownCtx, waitGroup)
+	m.PendingNotificationMonitor.StartMonitor(m.nodeID, m.shutdownCtx, waitGroup)
+	m.TransactionMonitor.StartMonitor(m.nodeID, m.shutdownCtx, waitGroup)
+}
+
+func (m *NodeMonitor) register() error {
+	ctx := context.Background()
+	waitingForSync := false
+
+	rpcAddr, err := util.GetRpcAddress()
+
+	if err != nil {
+		metrics.RecordError("LNM011", "Error getting IP address", err)
+		return err
+	}
+
+	for {
+		getInfoResponse, err := m.LightningService.GetInfo(&lnrpc.GetInfoRequest{})
+
+		if err != nil {
+			metrics.RecordError("LNM004", "Error getting info", err)
+			return err
+		}
+
+		if !waitingForSync {
+			log.Printf("Registering node %v", m.LightningNode.Name)
+			log.Printf("Version: %v", getInfoResponse.Version)
+			log.Printf("CommitHash: %v", getInfoResponse.CommitHash)
+			log.Printf("IdentityPubkey: %v", getInfoResponse.IdentityPubkey)
+			log.Printf("RPC Address: %v", rpcAddr)
+		}
+
+		if getInfoResponse.SyncedToChain {
+			// Register node
+			numChannels := int64(getInfoResponse.NumActiveChannels + getInfoResponse.NumInactiveChannels + getInfoResponse.NumPendingChannels)
+			numPeers := int64(getInfoResponse.NumPeers)
+			lightningAddr := util.NewLightningAddr(getInfoResponse.Uris[0])
+			lndAddr := m.LightningNode.P2PHost
+
+			if len(lndAddr) == 0 {
+				lndAddr = lightningAddr.Host
+			}
+
+			if n, err := m.NodeRepository.GetNodeByPubkey(ctx, getInfoResponse.IdentityPubkey); err == nil {
+				// Update node
+				updateNodeParams := param.NewUpdateNodeParams(n)
+				updateNodeParams.NodeAddr = lndAddr
+				updateNodeParams.RpcAddr = rpcAddr
+				updateNodeParams.Alias = getInfoResponse.Alias
+				updateNodeParams.Color = getInfoResponse.Color
+				updateNodeParams.CommitHash = getInfoResponse.CommitHash
+				updateNodeParams.Version = getInfoResponse.Version
+				updateNodeParams.Channels = numChannels
+				updateNodeParams.Peers = numPeers
+
+				updatedNode, err := m.NodeRepository.UpdateNode(ctx, updateNodeParams)
+
+				if err != nil {
+					metrics.RecordError("LNM075", "Error updating node", err)
+					log.Printf("LNM075: Params=%#v", updateNodeParams)
+				}
+
+				m.nodeID = updatedNode.ID
+			} else {
+				// Create node
+				createNodeParams := db.CreateNodeParams{
+					Pubkey:     getInfoResponse.IdentityPubkey,
+					NodeAddr:   lndAddr,
+					RpcAddr:    rpcAddr,
+					Alias:      getInfoResponse.Alias,
+					Color:      getInfoResponse.Color,
+					CommitHash: getInfoResponse.CommitHash,
+					Version:    getInfoResponse.Version,
+					Channels:   numChannels,
+					Peers:      numPeers,
+					IsActive:   true,
+					IsLsp:      false,
+				}
+
+				createdNode, err := m.NodeRepository.CreateNode(ctx, createNodeParams)
+
+				if err != nil {
+					metrics.RecordError("LNM076", "Error creating node", err)
+					log.Printf("LNM076: Params=%#v", createNodeParams)
+				}
+
+				m.nodeID = createdNode.ID
+			}
+
+			m.LightningNode.SetRegistered(m.nodeID, getInfoResponse.IdentityPubkey)
+			log.Printf("Registered node %v", m.LightningNode.Name)
+			break
+		}
+
+		waitingForSync = true
+		log.Printf("BlockHeight: %v", getInfoResponse.BlockHeight)
+		log.Printf("BestHeaderTimestamp: %v", getInfoResponse.BestHeaderTimestamp)
+
+		select {
+		case <-m.shutdownCtx.Done():
+			return errors.New("shutdown while waiting for sync")
+		case <-time.After(6 * time.Second):
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package notificationoutbox
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+	dbUtil "github.com/satimoto/go-datastore/pkg/util"
+	"github.com/satimoto/go-lnm/internal/health"
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+	"github.com/satimoto/go-lnm/internal/notificationoutbox"
+	"github.com/satimoto/go-lnm/internal/service"
+)
+
+type NotificationOutboxMonitor struct {
+	NotificationOutboxResolver *notificationoutbox.NotificationOutboxResolver
+	Heartbeat                  *health.Heartbeat
+	shutdownCtx                context.Context
+	waitGroup                  *sync.WaitGroup
+	maxAttempts                int32
+	batchSize                  int32
+	claimTimeout               time.Duration
+	pollInterval               time.Duration
+}
+
+func NewNotificationOutboxMonitor(repositoryService *db.RepositoryService, services *service.ServiceResolver) *NotificationOutboxMonitor {
+	pollInterval := time.Duration(dbUtil.GetEnvInt32("NOTIFICATION_POLL_INTERVAL", 2)) * time.Second
+
+	return &NotificationOutboxMonitor{
+		NotificationOutboxResolver: notificationoutbox.NewResolver(repositoryService, services),
+		Heartbeat:                  health.RegisterHeartbeat("monitor_notification_outbox", false, 5*time.Minute+pollInterval),
+		maxAttempts:                dbUtil.GetEnvInt32("NOTIFICATION_MAX_ATTEMPTS", 10),
+		batchSize:                  dbUtil.GetEnvInt32("NOTIFICATION_OUTBOX_BATCH_SIZE", 100),
+		claimTimeout:               time.Duration(dbUtil.GetEnvInt32("NOTIFICATION_CLAIM_TIMEOUT", 300)) * time.Second,
+		pollInterval:               pollInterval,
+	}
+}
+
+func (m *NotificationOutboxMonitor) StartMonitor(shutdownCtx context.Context, waitGroup *sync.WaitGroup) {
+	log.Printf("Starting up Notification Outbox")
+	m.shutdownCtx = shutdownCtx
+	m.waitGroup = waitGroup
+
+	go m.startNotificationOutboxLoop()
+}
+
+func (m *NotificationOutboxMonitor) startNotificationOutboxLoop() {
+	m.waitGroup.Add(1)
+	defer m.waitGroup.Done()
+
+	for {
+		m.dispatchPendingNotifications()
+		m.Heartbeat.Beat()
+
+		select {
+		case <-m.shutdownCtx.Done():
+			log.Printf("Shutting down Notification Outbox")
+			return
+		case <-time.After(m.pollInterval):
+			continue
+		}
+	}
+}
+
+func (m *NotificationOutboxMonitor) dispatchPendingNotifications() {
+	ctx := context.Background()
+	notificationOutboxes, err := m.NotificationOutboxResolver.ClaimPendingNotifications(ctx, m.batchSize, m.claimTimeout)
+
+	if err != nil {
+		metrics.RecordError("LNM186", "Error claiming pending notifications", err)
+		return
+	}
+
+	for _, notificationOutbox := range notificationOutboxes {
+		if m.shutdownCtx.Err() != nil {
+			return
+		}
+
+		m.NotificationOutboxResolver.DispatchNotification(ctx, notificationOutbox, m.maxAttempts)
+	}
+}
@@ -0,0 +1,88 @@
+package notificationoutbox
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+	dbMocks "github.com/satimoto/go-datastore/pkg/db/mocks"
+	dbUtil "github.com/satimoto/go-datastore/pkg/util"
+	ferpMocks "github.com/satimoto/go-lnm/internal/ferp/mocks"
+	lightningnetworkMocks "github.com/satimoto/go-lnm/internal/lightningnetwork/mocks"
+	"github.com/satimoto/go-lnm/internal/notification"
+	notificationMocks "github.com/satimoto/go-lnm/internal/notification/mocks"
+	notificationoutboxMocks "github.com/satimoto/go-lnm/internal/notificationoutbox/mocks"
+	serviceMocks "github.com/satimoto/go-lnm/internal/service/mocks"
+	ocpiMocks "github.com/satimoto/go-ocpi/pkg/ocpi/mocks"
+)
+
+func TestDispatchPendingNotifications(t *testing.T) {
+	notificationOutboxes := []db.NotificationOutbox{{
+		ID:               1,
+		UserID:           1,
+		Channel:          db.NotificationOutboxChannelTypePUSH,
+		NotificationType: notification.SESSION_UPDATE,
+		Platform:         dbUtil.SqlNullString(notification.PLATFORM_ANDROID),
+		DeviceToken:      dbUtil.SqlNullString("TOKEN0001"),
+		Payload:          `{"type":"SESSION_UPDATE"}`,
+		Status:           db.NotificationOutboxStatusTypePENDING,
+	}}
+
+	t.Run("Dispatches claimed notifications", func(t *testing.T) {
+		mockRepository := dbMocks.NewMockRepositoryService()
+		mockNotificationService := notificationMocks.NewService()
+		mockServices := serviceMocks.NewService(ferpMocks.NewService(), lightningnetworkMocks.NewService(), mockNotificationService, ocpiMocks.NewService())
+
+		notificationOutboxMonitor := &NotificationOutboxMonitor{
+			NotificationOutboxResolver: notificationoutboxMocks.NewResolver(mockRepository, mockServices),
+			shutdownCtx:                context.Background(),
+			maxAttempts:                10,
+			batchSize:                  100,
+			claimTimeout:               5 * time.Minute,
+		}
+
+		mockRepository.SetClaimPendingNotificationOutboxesMockData(dbMocks.NotificationOutboxesMockData{NotificationOutboxes: notificationOutboxes})
+		mockNotificationService.SetSendNotificationMockData(&notification.Response{
+			Results: []notification.Result{{Token: "TOKEN0001", MessageID: "MESSAGE0001"}},
+		})
+
+		notificationOutboxMonitor.dispatchPendingNotifications()
+
+		updateNotificationOutboxParams, err := mockRepository.GetUpdateNotificationOutboxMockData()
+
+		if err != nil {
+			t.Fatalf("Expected notification outbox update: %v", err)
+		}
+
+		if updateNotificationOutboxParams.Status != db.NotificationOutboxStatusTypeSENT {
+			t.Errorf("Status mismatch: %v expecting %v", updateNotificationOutboxParams.Status, db.NotificationOutboxStatusTypeSENT)
+		}
+	})
+
+	t.Run("Stops dispatching on shutdown", func(t *testing.T) {
+		shutdownCtx, cancelFunc := context.WithCancel(context.Background())
+		cancelFunc()
+
+		mockRepository := dbMocks.NewMockRepositoryService()
+		mockNotificationService := notificationMocks.NewService()
+		mockServices := serviceMocks.NewService(ferpMocks.NewService(), lightningnetworkMocks.NewService(), mockNotificationService, ocpiMocks.NewService())
+
+		notificationOutboxMonitor := &NotificationOutboxMonitor{
+			NotificationOutboxResolver: notificationoutboxMocks.NewResolver(mockRepository, mockServices),
+			shutdownCtx:                shutdownCtx,
+			maxAttempts:                10,
+			batchSize:                  100,
+			claimTimeout:               5 * time.Minute,
+		}
+
+		mockRepository.SetClaimPendingNotificationOutboxesMockData(dbMocks.NotificationOutboxesMockData{NotificationOutboxes: notificationOutboxes})
+
+		notificationOutboxMonitor.dispatchPendingNotifications()
+
+		// The claim expires so the notification is dispatched by the next poll
+		if _, err := mockRepository.GetUpdateNotificationOutboxMockData(); err == nil {
+			t.Errorf("Unexpected notification outbox update")
+		}
+	})
+}
@@ -0,0 +1,21 @@
+package peerevent
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricPeerEventsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lsp_peer_events_total",
+		Help: "The total number of peer online and offline events",
+	}, []string{"pubkey", "event_type"})
+	metricPeerFlapsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lsp_peer_flaps_total",
+		Help: "The total number of times a user or important peer connection flapped",
+	}, []string{"pubkey", "peer_type"})
+	metricPeerReconnectsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lsp_peer_reconnects_total",
+		Help: "The total number of reconnects to important peers",
+	}, []string{"pubkey", "result"})
+)
@@ -0,0 +1,18 @@
+package mocks
+
+import (
+	mocks "github.com/satimoto/go-datastore/pkg/db/mocks"
+	user "github.com/satimoto/go-datastore/pkg/user/mocks"
+	"github.com/satimoto/go-lnm/internal/monitor/peerevent"
+	peer "github.com/satimoto/go-lnm/internal/peer/mocks"
+	"github.com/satimoto/go-lnm/internal/service"
+)
+
+func NewPeerEventMonitor(repositoryService *mocks.MockRepositoryService, services *service.ServiceResolver) *peerevent.PeerEventMonitor {
+	return &peerevent.PeerEventMonitor{
+		LeaseService:     services.LeaseService,
+		LightningService: services.LightningService,
+		PeerResolver:     peer.NewResolver(repositoryService),
+		UserRepository:   user.NewRepository(repositoryService),
+	}
+}
@@ -0,0 +1,345 @@
+package peerevent
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/satimoto/go-datastore/pkg/db"
+	"github.com/satimoto/go-datastore/pkg/param"
+	"github.com/satimoto/go-datastore/pkg/user"
+	dbUtil "github.com/satimoto/go-datastore/pkg/util"
+	"github.com/satimoto/go-lnm/internal/health"
+	"github.com/satimoto/go-lnm/internal/lease"
+	"github.com/satimoto/go-lnm/internal/lightningnetwork"
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+	"github.com/satimoto/go-lnm/internal/peer"
+	"github.com/satimoto/go-lnm/internal/service"
+	"github.com/satimoto/go-lnm/internal/subscription"
+)
+
+const (
+	PEER_TYPE_IMPORTANT = "important"
+	PEER_TYPE_USER      = "user"
+)
+
+type PeerEventMonitor struct {
+	LeaseService     lease.Lease
+	LightningService lightningnetwork.LightningNetwork
+	Heartbeat        *health.Heartbeat
+	PeerResolver     *peer.PeerResolver
+	UserRepository   user.UserRepository
+	flapDetector     *peer.FlapDetector
+	importantPeers   map[string]bool
+	reconnectChan    chan string
+	mutex            sync.Mutex
+	lightningNode    *lightningnetwork.LightningNode
+	nodeID           int64
+}
+
+func NewPeerEventMonitor(repositoryService *db.RepositoryService, services *service.ServiceResolver) *PeerEventMonitor {
+	return &PeerEventMonitor{
+		LeaseService:     services.LeaseService,
+		LightningService: services.LightningService,
+		lightningNode:    services.LightningNode,
+		PeerResolver:     peer.NewResolver(repositoryService),
+		UserRepository:   user.NewRepository(repositoryService),
+		Heartbeat:        health.RegisterNodeHeartbeat(services.LightningNode, "monitor_peer_event", true, 0),
+	}
+}
+
+func (m *PeerEventMonitor) StartMonitor(nodeID int64, shutdownCtx context.Context, waitGroup *sync.WaitGroup) {
+	log.Printf("Starting up Peer Events")
+
+	flapWindow := time.Duration(dbUtil.GetEnvInt32("PEER_FLAP_WINDOW", 600)) * time.Second
+	flapThreshold := int(dbUtil.GetEnvInt32("PEER_FLAP_THRESHOLD", 3))
+	reconnectInterval := time.Duration(dbUtil.GetEnvInt32("PEER_RECONNECT_INTERVAL", 60)) * time.Second
+
+	m.nodeID = nodeID
+	m.flapDetector = peer.NewFlapDetector(flapWindow, flapThreshold)
+	m.importantPeers = make(map[string]bool)
+	m.reconnectChan = make(chan string, 1)
+
+	for _, pubkey := range strings.Split(dbUtil.GetEnv("PEER_IMPORTANT_PUBKEYS", ""), ",") {
+		if pubkey = strings.TrimSpace(pubkey); len(pubkey) > 0 {
+			m.importantPeers[pubkey] = true
+		}
+	}
+
+	subscription.NewRunner(subscription.Config[lnrpc.PeerEvent]{
+		Name:      "peer_event",
+		Pubkey:    m.lightningNode.GetPubkey(),
+		Subscribe: m.subscribePeerEvents,
+		Handle:    m.handlePeerEvent,
+		Heartbeat: m.Heartbeat,
+		// Only one instance handles the events of a node
+		LeaseService: m.LeaseService,
+		LeaseType:    lease.LEASE_PEER_EVENT,
+		LeaseID:      nodeID,
+	}).Start(shutdownCtx, waitGroup)
+
+	waitGroup.Add(1)
+	go m.startReconnectLoop(reconnectInterval, shutdownCtx, waitGroup)
+}
+
+func (m *PeerEventMonitor) handlePeerEvent(peerEvent *lnrpc.PeerEvent) {
+	/** Peer Event received.
+	 *  Find the peer by its pubkey or create it.
+	 *  Update the online time of the peer and alert
+	 *  if a user or important peer is flapping.
+	 *  Reconnect to important peers that go offline.
+	 */
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	ctx := context.Background()
+	now := time.Now()
+	isOnline := peerEvent.Type == lnrpc.PeerEvent_PEER_ONLINE
+
+	p, err := m.getOrCreatePeer(ctx, peerEvent.PubKey)
+
+	if err != nil {
+		return
+	}
+
+	updatePeerParams := param.NewUpdatePeerParams(p)
+	updatePeerParams.LastUpdated = now
+	peer.SetOnline(&updatePeerParams, isOnline, now)
+
+	if !isOnline && m.flapDetector.RecordOffline(p.Pubkey, now) {
+		updatePeerParams.FlapCount++
+		m.alertFlapping(ctx, p)
+	}
+
+	m.updatePeer(ctx, updatePeerParams)
+	metricPeerEventsTotal.WithLabelValues(m.lightningNode.GetPubkey(), peerEvent.Type.String()).Inc()
+
+	if !isOnline && m.importantPeers[p.Pubkey] {
+		select {
+		case m.reconnectChan <- p.Pubkey:
+		default:
+		}
+	}
+}
+
+// alertFlapping alerts when the connection of a user or important peer flaps
+func (m *PeerEventMonitor) alertFlapping(ctx context.Context, p db.Peer) {
+	peerType := ""
+
+	if m.importantPeers[p.Pubkey] {
+		peerType = PEER_TYPE_IMPORTANT
+	} else if _, err := m.UserRepository.GetUserByPubkey(ctx, p.Pubkey); err == nil {
+		peerType = PEER_TYPE_USER
+	} else {
+		return
+	}
+
+	metricPeerFlapsTotal.WithLabelValues(m.lightningNode.GetPubkey(), peerType).Inc()
+	metrics.RecordError("LNM262", "Peer connection flapping", fmt.Errorf("%v peer %v is flapping", peerType, p.Pubkey))
+	log.Printf("LNM262: Node=%v, Pubkey=%v, Uptime=%#v", m.lightningNode.GetName(), p.Pubkey, peer.GetUptime(p, time.Now()))
+}
+
+func (m *PeerEventMonitor) getOrCreatePeer(ctx context.Context, pubkey string) (db.Peer, error) {
+	getPeerByPubkeyParams := db.GetPeerByPubkeyParams{
+		NodeID: m.nodeID,
+		Pubkey: pubkey,
+	}
+
+	if p, err := m.PeerResolver.Repository.GetPeerByPubkey(ctx, getPeerByPubkeyParams); err == nil {
+		return p, nil
+	}
+
+	now := time.Now()
+	createPeerParams := db.CreatePeerParams{
+		NodeID:      m.nodeID,
+		Pubkey:      pubkey,
+		CreatedDate: now,
+		LastUpdated: now,
+	}
+
+	p, err := m.PeerResolver.Repository.CreatePeer(ctx, createPeerParams)
+
+	if err != nil {
+		metrics.RecordError("LNM263", "Error creating peer", err)
+		log.Printf("LNM263: Params=%#v", createPeerParams)
+	}
+
+	return p, err
+}
+
+func (m *PeerEventMonitor) subscribePeerEvents() (subscription.Stream[lnrpc.PeerEvent], error) {
+	peerEventsClient, err := m.LightningService.SubscribePeerEvents(&lnrpc.PeerEventSubscription{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	m.syncPeers()
+
+	return peerEventsClient, nil
+}
+
+// syncPeers updates the online state and address of peers from LND, catching
+// up on events missed while not subscribed. Returns the connected peers.
+func (m *PeerEventMonitor) syncPeers() map[string]bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	ctx := context.Background()
+	now := time.Now()
+	connectedPeers := make(map[string]bool)
+
+	listPeersResponse, err := m.LightningService.ListPeers(&lnrpc.ListPeersRequest{})
+
+	if err != nil {
+		metrics.RecordError("LNM264", "Error listing peers", err)
+		log.Printf("LNM264: Node=%v", m.lightningNode.GetName())
+		return nil
+	}
+
+	for _, connectedPeer := range listPeersResponse.Peers {
+		connectedPeers[connectedPeer.PubKey] = true
+		p, err := m.getOrCreatePeer(ctx, connectedPeer.PubKey)
+
+		if err != nil {
+			continue
+		}
+
+		// The address of an inbound connection is not the listening address of the peer
+		if p.IsOnline && (connectedPeer.Inbound || p.Address == connectedPeer.Address) {
+			continue
+		}
+
+		updatePeerParams := param.NewUpdatePeerParams(p)
+		updatePeerParams.LastUpdated = now
+		peer.SetOnline(&updatePeerParams, true, now)
+
+		if !connectedPeer.Inbound {
+			updatePeerParams.Address = connectedPeer.Address
+		} else if len(updatePeerParams.Address) == 0 {
+			// Without an outbound address use the address the peer announces
+			updatePeerParams.Address = m.getAnnouncedAddress(connectedPeer.PubKey)
+		}
+
+		m.updatePeer(ctx, updatePeerParams)
+	}
+
+	if peers, err := m.PeerResolver.Repository.ListPeersByNodeID(ctx, m.nodeID); err == nil {
+		for _, p := range peers {
+			if p.IsOnline && !connectedPeers[p.Pubkey] {
+				updatePeerParams := param.NewUpdatePeerParams(p)
+				updatePeerParams.LastUpdated = now
+				peer.SetOnline(&updatePeerParams, false, now)
+
+				m.updatePeer(ctx, updatePeerParams)
+			}
+		}
+	}
+
+	return connectedPeers
+}
+
+// getAnnouncedAddress returns the first address in the node announcement of
+// a peer, or an empty address if the peer is not announced
+func (m *PeerEventMonitor) getAnnouncedAddress(pubkey string) string {
+	nodeInfo, err := m.LightningService.GetNodeInfo(&lnrpc.NodeInfoRequest{
+		PubKey: pubkey,
+	})
+
+	if err != nil || nodeInfo.Node == nil {
+		return ""
+	}
+
+	for _, nodeAddress := range nodeInfo.Node.Addresses {
+		if len(nodeAddress.Addr) > 0 {
+			return nodeAddress.Addr
+		}
+	}
+
+	return ""
+}
+
+func (m *PeerEventMonitor) updatePeer(ctx context.Context, updatePeerParams db.UpdatePeerParams) {
+	if _, err := m.PeerResolver.Repository.UpdatePeer(ctx, updatePeerParams); err != nil {
+		metrics.RecordError("LNM261", "Error updating peer", err)
+		log.Printf("LNM261: Params=%#v", updatePeerParams)
+	}
+}
+
+func (m *PeerEventMonitor) startReconnectLoop(reconnectInterval time.Duration, shutdownCtx context.Context, waitGroup *sync.WaitGroup) {
+	defer waitGroup.Done()
+
+	ticker := time.NewTicker(reconnectInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-shutdownCtx.Done():
+			log.Printf("Shutting down Peer reconnects")
+			return
+		case <-ticker.C:
+		case <-m.reconnectChan:
+		}
+
+		if len(m.importantPeers) > 0 {
+			m.reconnectPeers()
+		}
+	}
+}
+
+// reconnectPeers connects to important peers that are not connected using
+// their last known address, or the address in their node announcement
+func (m *PeerEventMonitor) reconnectPeers() {
+	ctx := context.Background()
+	connectedPeers := m.syncPeers()
+
+	if connectedPeers == nil {
+		return
+	}
+
+	for pubkey := range m.importantPeers {
+		if connectedPeers[pubkey] {
+			continue
+		}
+
+		p, err := m.PeerResolver.Repository.GetPeerByPubkey(ctx, db.GetPeerByPubkeyParams{
+			NodeID: m.nodeID,
+			Pubkey: pubkey,
+		})
+
+		address := p.Address
+
+		if err != nil || len(address) == 0 {
+			address = m.getAnnouncedAddress(pubkey)
+		}
+
+		if len(address) == 0 {
+			log.Printf("No known address to reconnect to peer %v on %v", pubkey, m.lightningNode.GetName())
+			continue
+		}
+
+		log.Printf("Reconnecting to peer %v at %v on %v", pubkey, address, m.lightningNode.GetName())
+
+		_, err = m.LightningService.ConnectPeer(&lnrpc.ConnectPeerRequest{
+			Addr: &lnrpc.LightningAddress{
+				Pubkey: pubkey,
+				Host:   address,
+			},
+			Timeout: 30,
+		})
+
+		if err != nil {
+			metrics.RecordError("LNM265", "Error reconnecting to peer", err)
+			log.Printf("LNM265: Node=%v, Pubkey=%v, Address=%v", m.lightningNode.GetName(), pubkey, address)
+			metricPeerReconnectsTotal.WithLabelValues(m.lightningNode.GetPubkey(), "error").Inc()
+			continue
+		}
+
+		metricPeerReconnectsTotal.WithLabelValues(m.lightningNode.GetPubkey(), "success").Inc()
+	}
+}
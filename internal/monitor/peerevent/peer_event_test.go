@@ -0,0 +1,137 @@
+package peerevent_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/satimoto/go-datastore/pkg/db"
+	dbMocks "github.com/satimoto/go-datastore/pkg/db/mocks"
+	ferpMocks "github.com/satimoto/go-lnm/internal/ferp/mocks"
+	lightningnetworkMocks "github.com/satimoto/go-lnm/internal/lightningnetwork/mocks"
+	peereventMocks "github.com/satimoto/go-lnm/internal/monitor/peerevent/mocks"
+	notificationMocks "github.com/satimoto/go-lnm/internal/notification/mocks"
+	serviceMocks "github.com/satimoto/go-lnm/internal/service/mocks"
+	ocpiMocks "github.com/satimoto/go-ocpi/pkg/ocpi/mocks"
+)
+
+func TestPeerEvent(t *testing.T) {
+	t.Run("Important peer reconnected", func(t *testing.T) {
+		t.Setenv("PEER_IMPORTANT_PUBKEYS", "0216fc")
+
+		shutdownCtx, cancelFunc := context.WithCancel(context.Background())
+		waitGroup := &sync.WaitGroup{}
+
+		mockRepository := dbMocks.NewMockRepositoryService()
+		mockFerpService := ferpMocks.NewService()
+		mockLightningService := lightningnetworkMocks.NewService()
+		mockNotificationService := notificationMocks.NewService()
+		mockOcpiService := ocpiMocks.NewService()
+		mockServices := serviceMocks.NewService(mockFerpService, mockLightningService, mockNotificationService, mockOcpiService)
+
+		peerEventMonitor := peereventMocks.NewPeerEventMonitor(mockRepository, mockServices)
+		recvChan := mockLightningService.NewSubscribePeerEventsMockData()
+		mockLightningService.SetListPeersMockData(&lnrpc.ListPeersResponse{})
+		mockLightningService.SetListPeersMockData(&lnrpc.ListPeersResponse{})
+
+		for i := 0; i < 2; i++ {
+			mockRepository.SetGetPeerByPubkeyMockData(dbMocks.PeerMockData{
+				Peer: db.Peer{
+					ID:          1,
+					Pubkey:      "0216fc",
+					Address:     "127.0.0.1:9735",
+					IsOnline:    true,
+					CreatedDate: time.Now(),
+				},
+			})
+		}
+
+		peerEventMonitor.StartMonitor(1, shutdownCtx, waitGroup)
+
+		recvChan <- &lnrpc.PeerEvent{
+			PubKey: "0216fc",
+			Type:   lnrpc.PeerEvent_PEER_OFFLINE,
+		}
+
+		time.Sleep(time.Second * 2)
+
+		peer, err := mockRepository.GetUpdatePeerMockData()
+
+		if err != nil {
+			t.Error(err)
+		}
+
+		if peer.IsOnline {
+			t.Errorf("Peer still online: %#v", peer)
+		}
+
+		connectPeerRequest, err := mockLightningService.GetConnectPeerMockData()
+
+		if err != nil {
+			t.Error(err)
+		}
+
+		if connectPeerRequest.Addr.Pubkey != "0216fc" || connectPeerRequest.Addr.Host != "127.0.0.1:9735" {
+			t.Errorf("Connect peer mismatch: %#v", connectPeerRequest)
+		}
+
+		cancelFunc()
+		waitGroup.Wait()
+	})
+	t.Run("Inbound peer address from node announcement", func(t *testing.T) {
+		shutdownCtx, cancelFunc := context.WithCancel(context.Background())
+		waitGroup := &sync.WaitGroup{}
+
+		mockRepository := dbMocks.NewMockRepositoryService()
+		mockFerpService := ferpMocks.NewService()
+		mockLightningService := lightningnetworkMocks.NewService()
+		mockNotificationService := notificationMocks.NewService()
+		mockOcpiService := ocpiMocks.NewService()
+		mockServices := serviceMocks.NewService(mockFerpService, mockLightningService, mockNotificationService, mockOcpiService)
+
+		peerEventMonitor := peereventMocks.NewPeerEventMonitor(mockRepository, mockServices)
+		mockLightningService.NewSubscribePeerEventsMockData()
+		mockLightningService.SetListPeersMockData(&lnrpc.ListPeersResponse{
+			Peers: []*lnrpc.Peer{{
+				PubKey:  "0216fc",
+				Address: "10.0.0.2:53124",
+				Inbound: true,
+			}},
+		})
+		mockLightningService.SetGetNodeInfoMockData(&lnrpc.NodeInfo{
+			Node: &lnrpc.LightningNode{
+				PubKey: "0216fc",
+				Addresses: []*lnrpc.NodeAddress{{
+					Network: "tcp",
+					Addr:    "10.0.0.2:9735",
+				}},
+			},
+		})
+		mockRepository.SetGetPeerByPubkeyMockData(dbMocks.PeerMockData{
+			Peer: db.Peer{
+				ID:          1,
+				Pubkey:      "0216fc",
+				CreatedDate: time.Now(),
+			},
+		})
+
+		peerEventMonitor.StartMonitor(1, shutdownCtx, waitGroup)
+
+		time.Sleep(time.Second)
+
+		peer, err := mockRepository.GetUpdatePeerMockData()
+
+		if err != nil {
+			t.Error(err)
+		}
+
+		if !peer.IsOnline || peer.Address != "10.0.0.2:9735" {
+			t.Errorf("Peer mismatch: %#v", peer)
+		}
+
+		cancelFunc()
+		waitGroup.Wait()
+	})
+}
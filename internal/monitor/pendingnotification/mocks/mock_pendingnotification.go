@@ -3,16 +3,22 @@ package mocks
 import (
 	channelrequestMocks "github.com/satimoto/go-datastore/pkg/channelrequest/mocks"
 	"github.com/satimoto/go-datastore/pkg/db/mocks"
+	invoicerequestMocks "github.com/satimoto/go-datastore/pkg/invoicerequest/mocks"
 	pendingnotificationMocks "github.com/satimoto/go-datastore/pkg/pendingnotification/mocks"
+	userMocks "github.com/satimoto/go-datastore/pkg/user/mocks"
 	"github.com/satimoto/go-lnm/internal/monitor/pendingnotification"
+	notificationoutboxMocks "github.com/satimoto/go-lnm/internal/notificationoutbox/mocks"
 	"github.com/satimoto/go-lnm/internal/service"
 )
 
 func NewPendingNotificationMonitor(repositoryService *mocks.MockRepositoryService, services *service.ServiceResolver) *pendingnotification.PendingNotificationMonitor {
 	return &pendingnotification.PendingNotificationMonitor{
+		LeaseService:                  services.LeaseService,
 		LightningService:              services.LightningService,
-		NotificationService:           services.NotificationService,
+		NotificationOutboxResolver:    notificationoutboxMocks.NewResolver(repositoryService, services),
 		ChannelRequestRepository:      channelrequestMocks.NewRepository(repositoryService),
+		InvoiceRequestRepository:      invoicerequestMocks.NewRepository(repositoryService),
 		PendingNotificationRepository: pendingnotificationMocks.NewRepository(repositoryService),
+		UserRepository:                userMocks.NewRepository(repositoryService),
 	}
 }
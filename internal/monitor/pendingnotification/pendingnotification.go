@@ -6,21 +6,30 @@ import (
 	"sync"
 	"time"
 
-	"github.com/appleboy/go-fcm"
 	"github.com/satimoto/go-datastore/pkg/channelrequest"
 	"github.com/satimoto/go-datastore/pkg/db"
+	"github.com/satimoto/go-datastore/pkg/invoicerequest"
+	"github.com/satimoto/go-datastore/pkg/param"
 	"github.com/satimoto/go-datastore/pkg/pendingnotification"
+	"github.com/satimoto/go-datastore/pkg/user"
+	"github.com/satimoto/go-lnm/internal/health"
+	"github.com/satimoto/go-lnm/internal/lease"
 	"github.com/satimoto/go-lnm/internal/lightningnetwork"
 	metrics "github.com/satimoto/go-lnm/internal/metric"
 	"github.com/satimoto/go-lnm/internal/notification"
+	"github.com/satimoto/go-lnm/internal/notificationoutbox"
 	"github.com/satimoto/go-lnm/internal/service"
 )
 
 type PendingNotificationMonitor struct {
+	LeaseService                  lease.Lease
 	LightningService              lightningnetwork.LightningNetwork
-	NotificationService           notification.Notification
+	NotificationOutboxResolver    *notificationoutbox.NotificationOutboxResolver
 	ChannelRequestRepository      channelrequest.ChannelRequestRepository
+	InvoiceRequestRepository      invoicerequest.InvoiceRequestRepository
 	PendingNotificationRepository pendingnotification.PendingNotificationRepository
+	UserRepository                user.UserRepository
+	Heartbeat                     *health.Heartbeat
 	shutdownCtx                   context.Context
 	waitGroup                     *sync.WaitGroup
 	nodeID                        int64
@@ -28,10 +37,14 @@ type PendingNotificationMonitor struct {
 
 func NewPendingNotificationMonitor(repositoryService *db.RepositoryService, services *service.ServiceResolver) *PendingNotificationMonitor {
 	return &PendingNotificationMonitor{
+		LeaseService:                  services.LeaseService,
 		LightningService:              services.LightningService,
-		NotificationService:           services.NotificationService,
+		NotificationOutboxResolver:    notificationoutbox.NewResolver(repositoryService, services),
 		ChannelRequestRepository:      channelrequest.NewRepository(repositoryService),
+		InvoiceRequestRepository:      invoicerequest.NewRepository(repositoryService),
 		PendingNotificationRepository: pendingnotification.NewRepository(repositoryService),
+		UserRepository:                user.NewRepository(repositoryService),
+		Heartbeat:                     health.RegisterNodeHeartbeat(services.LightningNode, "monitor_pending_notification", false, 3*time.Hour),
 	}
 }
 
@@ -48,51 +61,17 @@ func (s *PendingNotificationMonitor) startPendingNotificationLoop() {
 	for {
 		ctx := context.Background()
 
-		if pendingNotifications, err := s.PendingNotificationRepository.ListPendingNotifications(ctx, s.nodeID); err == nil {
-			if len(pendingNotifications) > 0 {
-				ids := []int64{}
-				registrationIDs := []string{}
-
-				for _, pendingNotification := range pendingNotifications {
-					if pendingNotification.DeviceToken.Valid {
-						ids = append(ids, pendingNotification.ID)
-						registrationIDs = append(registrationIDs, pendingNotification.DeviceToken.String)
-					}
-				}
-
-				message := &fcm.Message{
-					RegistrationIDs: registrationIDs,
-					CollapseKey:     notification.INVOICE_REQUEST,
-					Notification: &fcm.Notification{
-						Title: "Ohms! Collect your satoshis!",
-						Body:  "You've received some satoshis from others charging their vehicles! Open the app to collect them.",
-					},
-				}
-
-				_, err := s.NotificationService.SendNotificationWithRetry(message, 10)
-				// TODO go-lsp#22: Handle application uninstall
-
-				if err != nil {
-					metrics.RecordError("LNM131", "Error sending notification", err)
-					log.Printf("LNM131: Message=%#v", message)
-				} else {
-					updatePendingNotificationsParams := db.UpdatePendingNotificationsParams{
-						SendDate: time.Now().Add(time.Hour * 24),
-						Ids:      ids,
-					}
-
-					err = s.PendingNotificationRepository.UpdatePendingNotifications(ctx, updatePendingNotificationsParams)
-
-					if err != nil {
-						metrics.RecordError("LNM132", "Error updating pending notifications", err)
-						log.Printf("LNM132: Params=%#v", updatePendingNotificationsParams)
-					}
-
-					notification.RecordNotificationSent(notification.INVOICE_REQUEST, len(registrationIDs))
-				}
+		// Only one instance sends the pending notifications of a node
+		if heldLease, err := s.LeaseService.Acquire(ctx, lease.LEASE_PENDING_NOTIFICATION, s.nodeID, nil); err == nil {
+			if pendingNotifications, err := s.PendingNotificationRepository.ListPendingNotifications(ctx, s.nodeID); err == nil {
+				s.sendPendingNotifications(ctx, pendingNotifications)
 			}
+
+			s.LeaseService.Release(ctx, heldLease)
 		}
 
+		s.Heartbeat.Beat()
+
 		select {
 		case <-s.shutdownCtx.Done():
 			log.Printf("Shutting down Pending Notifications")
@@ -102,3 +81,100 @@ func (s *PendingNotificationMonitor) startPendingNotificationLoop() {
 		}
 	}
 }
+
+func (s *PendingNotificationMonitor) sendPendingNotifications(ctx context.Context, pendingNotifications []db.PendingNotification) {
+	userIDs := []int64{}
+	pendingNotificationsByUser := make(map[int64][]db.PendingNotification)
+
+	for _, pendingNotification := range pendingNotifications {
+		if pendingNotification.DeviceToken.Valid {
+			if _, ok := pendingNotificationsByUser[pendingNotification.UserID]; !ok {
+				userIDs = append(userIDs, pendingNotification.UserID)
+			}
+
+			pendingNotificationsByUser[pendingNotification.UserID] = append(pendingNotificationsByUser[pendingNotification.UserID], pendingNotification)
+		}
+	}
+
+	ids := []int64{}
+
+	for _, userID := range userIDs {
+		userPendingNotifications := pendingNotificationsByUser[userID]
+		u, err := s.UserRepository.GetUser(ctx, userID)
+
+		if err != nil {
+			metrics.RecordError("LNM197", "Error retrieving user", err)
+			log.Printf("LNM197: UserID=%v", userID)
+			continue
+		}
+
+		if !u.DeviceToken.Valid {
+			// The outbox removed the token after the provider rejected it
+			s.handleInvalidToken(ctx, userPendingNotifications)
+			continue
+		}
+
+		// A single reminder is queued to the current device of the user,
+		// the outbox selects the provider and retries failed deliveries
+		if _, err := s.NotificationOutboxResolver.QueueUserNotification(ctx, u, nil, notification.INVOICE_REQUEST_REMINDER); err != nil {
+			continue
+		}
+
+		for _, pendingNotification := range userPendingNotifications {
+			ids = append(ids, pendingNotification.ID)
+		}
+	}
+
+	if len(ids) > 0 {
+		updatePendingNotificationsParams := db.UpdatePendingNotificationsParams{
+			SendDate: time.Now().Add(time.Hour * 24),
+			Ids:      ids,
+		}
+
+		err := s.PendingNotificationRepository.UpdatePendingNotifications(ctx, updatePendingNotificationsParams)
+
+		if err != nil {
+			metrics.RecordError("LNM132", "Error updating pending notifications", err)
+			log.Printf("LNM132: Params=%#v", updatePendingNotificationsParams)
+		}
+	}
+}
+
+func (s *PendingNotificationMonitor) handleInvalidToken(ctx context.Context, pendingNotifications []db.PendingNotification) {
+	ids := []int64{}
+
+	for _, pendingNotification := range pendingNotifications {
+		ids = append(ids, pendingNotification.ID)
+
+		if pendingNotification.InvoiceRequestID.Valid {
+			s.markInvoiceRequest(ctx, pendingNotification.InvoiceRequestID.Int64)
+		}
+	}
+
+	// Stop scheduling notifications for the user
+	if err := s.PendingNotificationRepository.DeletePendingNotifications(ctx, ids); err != nil {
+		metrics.RecordError("LNM188", "Error deleting pending notifications", err)
+		log.Printf("LNM188: Ids=%v", ids)
+	}
+}
+
+func (s *PendingNotificationMonitor) markInvoiceRequest(ctx context.Context, invoiceRequestID int64) {
+	invoiceRequest, err := s.InvoiceRequestRepository.GetInvoiceRequest(ctx, invoiceRequestID)
+
+	if err != nil {
+		metrics.RecordError("LNM191", "Error retrieving invoice request", err)
+		log.Printf("LNM191: InvoiceRequestID=%v", invoiceRequestID)
+		return
+	}
+
+	if !invoiceRequest.IsSettled && !invoiceRequest.AltNotificationRequired {
+		// Flag the invoice request to be notified through an alternative channel
+		updateInvoiceRequestParams := param.NewUpdateInvoiceRequestParams(invoiceRequest)
+		updateInvoiceRequestParams.AltNotificationRequired = true
+
+		if _, err := s.InvoiceRequestRepository.UpdateInvoiceRequest(ctx, updateInvoiceRequestParams); err != nil {
+			metrics.RecordError("LNM192", "Error updating invoice request", err)
+			log.Printf("LNM192: Params=%#v", updateInvoiceRequestParams)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package pendingnotification_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+	dbMocks "github.com/satimoto/go-datastore/pkg/db/mocks"
+	dbUtil "github.com/satimoto/go-datastore/pkg/util"
+	ferpMocks "github.com/satimoto/go-lnm/internal/ferp/mocks"
+	lightningnetworkMocks "github.com/satimoto/go-lnm/internal/lightningnetwork/mocks"
+	pendingnotificationMocks "github.com/satimoto/go-lnm/internal/monitor/pendingnotification/mocks"
+	"github.com/satimoto/go-lnm/internal/notification"
+	notificationMocks "github.com/satimoto/go-lnm/internal/notification/mocks"
+	serviceMocks "github.com/satimoto/go-lnm/internal/service/mocks"
+	ocpiMocks "github.com/satimoto/go-ocpi/pkg/ocpi/mocks"
+)
+
+func TestPendingNotificationOutbox(t *testing.T) {
+	users := []db.User{{
+		ID:             1,
+		DeviceToken:    dbUtil.SqlNullString("TOKEN0001"),
+		DevicePlatform: dbUtil.SqlNullString(notification.PLATFORM_IOS),
+	}, {
+		ID:             2,
+		DeviceToken:    dbUtil.SqlNullString("TOKEN0002"),
+		DevicePlatform: dbUtil.SqlNullString(notification.PLATFORM_ANDROID),
+	}, {
+		// The outbox removed the token of the 3rd user after it was rejected
+		ID:             3,
+		DevicePlatform: dbUtil.SqlNullString(notification.PLATFORM_IOS),
+	}}
+
+	pendingNotifications := []db.PendingNotification{{
+		ID:               1,
+		UserID:           1,
+		DeviceToken:      dbUtil.SqlNullString("TOKEN0001"),
+		InvoiceRequestID: dbUtil.SqlNullInt64(1),
+	}, {
+		ID:               2,
+		UserID:           1,
+		DeviceToken:      dbUtil.SqlNullString("TOKEN0001"),
+		InvoiceRequestID: dbUtil.SqlNullInt64(2),
+	}, {
+		ID:               3,
+		UserID:           2,
+		DeviceToken:      dbUtil.SqlNullString("TOKEN0002"),
+		InvoiceRequestID: dbUtil.SqlNullInt64(3),
+	}, {
+		ID:               4,
+		UserID:           3,
+		DeviceToken:      dbUtil.SqlNullString("TOKEN0003"),
+		InvoiceRequestID: dbUtil.SqlNullInt64(4),
+	}}
+
+	shutdownCtx, cancelFunc := context.WithCancel(context.Background())
+	waitGroup := &sync.WaitGroup{}
+
+	mockRepository := dbMocks.NewMockRepositoryService()
+	mockServices := serviceMocks.NewService(ferpMocks.NewService(), lightningnetworkMocks.NewService(), notificationMocks.NewService(), ocpiMocks.NewService())
+	pendingNotificationMonitor := pendingnotificationMocks.NewPendingNotificationMonitor(mockRepository, mockServices)
+
+	mockRepository.SetListPendingNotificationsMockData(dbMocks.PendingNotificationsMockData{PendingNotifications: pendingNotifications})
+
+	for _, user := range users {
+		mockRepository.SetGetUserMockData(dbMocks.UserMockData{User: user})
+	}
+
+	mockRepository.SetGetInvoiceRequestMockData(dbMocks.InvoiceRequestMockData{InvoiceRequest: db.InvoiceRequest{
+		ID: 4,
+	}})
+
+	pendingNotificationMonitor.StartMonitor(1, shutdownCtx, waitGroup)
+	time.Sleep(time.Second)
+
+	cancelFunc()
+	waitGroup.Wait()
+
+	// A single reminder is queued per user with a device token
+	for _, userID := range []int64{1, 2} {
+		createNotificationOutboxParams, err := mockRepository.GetCreateNotificationOutboxMockData()
+
+		if err != nil {
+			t.Fatalf("Expected notification outbox for user %v: %v", userID, err)
+		}
+
+		if createNotificationOutboxParams.UserID != userID || createNotificationOutboxParams.NotificationType != notification.INVOICE_REQUEST_REMINDER {
+			t.Errorf("Notification outbox mismatch: %v, %v expecting %v, %v", createNotificationOutboxParams.UserID, createNotificationOutboxParams.NotificationType, userID, notification.INVOICE_REQUEST_REMINDER)
+		}
+	}
+
+	if _, err := mockRepository.GetCreateNotificationOutboxMockData(); err == nil {
+		t.Errorf("Unexpected notification outbox")
+	}
+
+	updatePendingNotificationsParams, err := mockRepository.GetUpdatePendingNotificationsMockData()
+
+	if err != nil {
+		t.Fatalf("Expected pending notifications update: %v", err)
+	}
+
+	if len(updatePendingNotificationsParams.Ids) != 3 {
+		t.Errorf("Queued mismatch: %v expecting %v", updatePendingNotificationsParams.Ids, []int64{1, 2, 3})
+	}
+
+	deletePendingNotificationsIds, err := mockRepository.GetDeletePendingNotificationsMockData()
+
+	if err != nil || len(deletePendingNotificationsIds) != 1 || deletePendingNotificationsIds[0] != 4 {
+		t.Errorf("Deleted mismatch: %v, %v expecting %v", deletePendingNotificationsIds, err, []int64{4})
+	}
+
+	updateInvoiceRequestParams, err := mockRepository.GetUpdateInvoiceRequestMockData()
+
+	if err != nil || updateInvoiceRequestParams.ID != 4 || !updateInvoiceRequestParams.AltNotificationRequired {
+		t.Errorf("Expected alternative notification for invoice request 4: %#v, %v", updateInvoiceRequestParams, err)
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"log"
 	"sync"
+	"time"
 
 	"github.com/satimoto/go-datastore/pkg/db"
+	dbUtil "github.com/satimoto/go-datastore/pkg/util"
 	"github.com/satimoto/go-lnm/internal/cdr"
 	"github.com/satimoto/go-lnm/internal/service"
 	"github.com/satimoto/go-lnm/internal/session"
@@ -42,4 +44,26 @@ func (s *StartupService) Start(nodeID int64, shutdownCtx context.Context, waitGr
 func (s *StartupService) handleStartup() {
 	s.CdrResolver.Startup(s.nodeID)
 	s.SessionResolver.Startup(s.nodeID)
+
+	s.waitGroup.Add(1)
+	go s.takeoverSessions()
+}
+
+// takeoverSessions periodically starts monitoring sessions whose lease
+// has expired, such as when another instance has stopped
+func (s *StartupService) takeoverSessions() {
+	defer s.waitGroup.Done()
+
+	ticker := time.NewTicker(time.Duration(dbUtil.GetEnvInt32("LEASE_TAKEOVER_INTERVAL", 60)) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.shutdownCtx.Done():
+			log.Printf("Shutting down session takeover")
+			return
+		case <-ticker.C:
+			s.SessionResolver.TakeoverSessions(s.nodeID)
+		}
+	}
 }
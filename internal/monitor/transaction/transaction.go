@@ -4,41 +4,48 @@ import (
 	"context"
 	"log"
 	"sync"
-	"time"
 
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/satimoto/go-datastore/pkg/db"
-	"github.com/satimoto/go-datastore/pkg/util"
+	"github.com/satimoto/go-lnm/internal/health"
 	"github.com/satimoto/go-lnm/internal/lightningnetwork"
 	metrics "github.com/satimoto/go-lnm/internal/metric"
 	"github.com/satimoto/go-lnm/internal/service"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"github.com/satimoto/go-lnm/internal/subscription"
 )
 
 type TransactionMonitor struct {
-	LightningService   lightningnetwork.LightningNetwork
-	TransactionsClient lnrpc.Lightning_SubscribeTransactionsClient
-	nodeID             int64
+	LightningService lightningnetwork.LightningNetwork
+	Heartbeat        *health.Heartbeat
+	lightningNode    *lightningnetwork.LightningNode
+	nodeID           int64
 }
 
 func NewTransactionMonitor(repositoryService *db.RepositoryService, services *service.ServiceResolver) *TransactionMonitor {
 	return &TransactionMonitor{
 		LightningService: services.LightningService,
+		lightningNode:    services.LightningNode,
+		Heartbeat:        health.RegisterNodeHeartbeat(services.LightningNode, "monitor_transaction", true, 0),
 	}
 }
 
 func (m *TransactionMonitor) StartMonitor(nodeID int64, shutdownCtx context.Context, waitGroup *sync.WaitGroup) {
 	log.Printf("Starting up Transactions")
-	transactionChan := make(chan lnrpc.Transaction)
 
 	m.nodeID = nodeID
-	go m.waitForTransactions(shutdownCtx, waitGroup, transactionChan)
-	go m.subscribeTransactionInterceptions(transactionChan)
+
+	subscription.NewRunner(subscription.Config[lnrpc.Transaction]{
+		Name:      "transaction",
+		Pubkey:    m.lightningNode.GetPubkey(),
+		Subscribe: m.subscribeTransactions,
+		Handle:    m.handleTransaction,
+		Heartbeat: m.Heartbeat,
+	}).Start(shutdownCtx, waitGroup)
+
 	go m.updateWalletBalance()
 }
 
-func (m *TransactionMonitor) handleTransaction(transaction lnrpc.Transaction) {
+func (m *TransactionMonitor) handleTransaction(transaction *lnrpc.Transaction) {
 	/** Transaction received.
 	 *
 	 */
@@ -53,23 +60,6 @@ func (m *TransactionMonitor) handleTransaction(transaction lnrpc.Transaction) {
 	go m.updateWalletBalance()
 }
 
-func (m *TransactionMonitor) subscribeTransactionInterceptions(transactionChan chan<- lnrpc.Transaction) {
-	transactionsClient, err := m.waitForSubscribeTransactionsClient(0, 1000)
-	util.PanicOnError("LNM022", "Error creating Transactions client", err)
-	m.TransactionsClient = transactionsClient
-
-	for {
-		transaction, err := m.TransactionsClient.Recv()
-
-		if err == nil {
-			transactionChan <- *transaction
-		} else {
-			m.TransactionsClient, err = m.waitForSubscribeTransactionsClient(100, 1000)
-			util.PanicOnError("LNM023", "Error creating Transactions client", err)
-		}
-	}
-}
-
 func (m *TransactionMonitor) updateWalletBalance() {
 	walletBalance, err := m.LightningService.WalletBalance(&lnrpc.WalletBalanceRequest{})
 
@@ -89,37 +79,6 @@ func (m *TransactionMonitor) updateWalletBalance() {
 	metricWalletReservedBalanceSatoshis.Set(float64(walletBalance.ReservedBalanceAnchorChan))
 }
 
-func (m *TransactionMonitor) waitForTransactions(shutdownCtx context.Context, waitGroup *sync.WaitGroup, transactionChan chan lnrpc.Transaction) {
-	waitGroup.Add(1)
-	defer close(transactionChan)
-	defer waitGroup.Done()
-
-	for {
-		select {
-		case <-shutdownCtx.Done():
-			log.Printf("Shutting down Transactions")
-			return
-		case transaction := <-transactionChan:
-			m.handleTransaction(transaction)
-		}
-	}
-}
-
-func (m *TransactionMonitor) waitForSubscribeTransactionsClient(initialDelay, retryDelay time.Duration) (lnrpc.Lightning_SubscribeTransactionsClient, error) {
-	for {
-		if initialDelay > 0 {
-			time.Sleep(retryDelay * time.Millisecond)
-		}
-
-		subscribeTransactionsClient, err := m.LightningService.SubscribeTransactions(&lnrpc.GetTransactionsRequest{})
-
-		if err == nil {
-			return subscribeTransactionsClient, nil
-		} else if status.Code(err) != codes.Unavailable {
-			return nil, err
-		}
-
-		log.Print("Waiting for Transactions client")
-		time.Sleep(retryDelay * time.Millisecond)
-	}
+func (m *TransactionMonitor) subscribeTransactions() (subscription.Stream[lnrpc.Transaction], error) {
+	return m.LightningService.SubscribeTransactions(&lnrpc.GetTransactionsRequest{})
 }
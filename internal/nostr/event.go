@@ -0,0 +1,115 @@
+package nostr
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+)
+
+const (
+	KIND_ENCRYPTED_DIRECT_MESSAGE = 4
+	KIND_SEAL                     = 13
+	KIND_PRIVATE_DIRECT_MESSAGE   = 14
+	KIND_GIFT_WRAP                = 1059
+)
+
+var ErrInvalidEvent = errors.New("invalid nostr event")
+
+type Tags [][]string
+
+type Event struct {
+	ID        string `json:"id"`
+	PubKey    string `json:"pubkey"`
+	CreatedAt int64  `json:"created_at"`
+	Kind      int    `json:"kind"`
+	Tags      Tags   `json:"tags"`
+	Content   string `json:"content"`
+	Sig       string `json:"sig,omitempty"`
+}
+
+// GetID returns the NIP-01 event ID, the sha256 of the serialized event
+func (e *Event) GetID() (string, error) {
+	tags := e.Tags
+
+	if tags == nil {
+		tags = Tags{}
+	}
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode([]interface{}{0, e.PubKey, e.CreatedAt, e.Kind, tags, e.Content}); err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(bytes.TrimSuffix(buffer.Bytes(), []byte("\n")))
+
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// Sign sets the public key, ID and schnorr signature of the event
+func (e *Event) Sign(privateKey *btcec.PrivateKey) error {
+	if e.Tags == nil {
+		e.Tags = Tags{}
+	}
+
+	e.PubKey = GetPublicKeyHex(privateKey.PubKey())
+	id, err := e.GetID()
+
+	if err != nil {
+		return err
+	}
+
+	idBytes, _ := hex.DecodeString(id)
+	signature, err := schnorr.Sign(privateKey, idBytes)
+
+	if err != nil {
+		return err
+	}
+
+	e.ID = id
+	e.Sig = hex.EncodeToString(signature.Serialize())
+
+	return nil
+}
+
+// Verify checks the event ID and signature
+func (e *Event) Verify() error {
+	id, err := e.GetID()
+
+	if err != nil || id != e.ID {
+		return ErrInvalidEvent
+	}
+
+	publicKey, err := DecodePublicKey(e.PubKey)
+
+	if err != nil {
+		return ErrInvalidEvent
+	}
+
+	sigBytes, err := hex.DecodeString(e.Sig)
+
+	if err != nil {
+		return ErrInvalidEvent
+	}
+
+	signature, err := schnorr.ParseSignature(sigBytes)
+
+	if err != nil {
+		return ErrInvalidEvent
+	}
+
+	idBytes, _ := hex.DecodeString(id)
+
+	if !signature.Verify(idBytes, publicKey) {
+		return ErrInvalidEvent
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package nostr
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil/bech32"
+)
+
+const (
+	HRP_NPUB = "npub"
+	HRP_NSEC = "nsec"
+)
+
+var (
+	ErrInvalidPrivateKey = errors.New("invalid nostr private key")
+	ErrInvalidPublicKey  = errors.New("invalid nostr public key")
+)
+
+// DecodePrivateKey decodes a bech32 nsec or hex private key
+func DecodePrivateKey(key string) (*btcec.PrivateKey, error) {
+	keyBytes, err := decodeKey(key, HRP_NSEC)
+
+	if err != nil || len(keyBytes) != 32 {
+		return nil, ErrInvalidPrivateKey
+	}
+
+	privateKey, _ := btcec.PrivKeyFromBytes(keyBytes)
+
+	return privateKey, nil
+}
+
+// DecodePublicKey decodes a bech32 npub or hex x-only public key
+func DecodePublicKey(key string) (*btcec.PublicKey, error) {
+	keyBytes, err := decodeKey(key, HRP_NPUB)
+
+	if err != nil || len(keyBytes) != 32 {
+		return nil, ErrInvalidPublicKey
+	}
+
+	publicKey, err := schnorr.ParsePubKey(keyBytes)
+
+	if err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+
+	return publicKey, nil
+}
+
+func EncodeNpub(publicKey *btcec.PublicKey) string {
+	return encodeKey(HRP_NPUB, schnorr.SerializePubKey(publicKey))
+}
+
+func EncodeNsec(privateKey *btcec.PrivateKey) string {
+	return encodeKey(HRP_NSEC, privateKey.Serialize())
+}
+
+func GetPublicKeyHex(publicKey *btcec.PublicKey) string {
+	return hex.EncodeToString(schnorr.SerializePubKey(publicKey))
+}
+
+func decodeKey(key, hrp string) ([]byte, error) {
+	key = strings.TrimSpace(key)
+
+	if strings.HasPrefix(strings.ToLower(key), hrp+"1") {
+		decodedHrp, data, err := bech32.Decode(key)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if decodedHrp != hrp {
+			return nil, errors.New("unexpected bech32 prefix")
+		}
+
+		return bech32.ConvertBits(data, 5, 8, false)
+	}
+
+	return hex.DecodeString(key)
+}
+
+func encodeKey(hrp string, keyBytes []byte) string {
+	data, _ := bech32.ConvertBits(keyBytes, 8, 5, true)
+	encoded, _ := bech32.Encode(hrp, data)
+
+	return encoded
+}
@@ -0,0 +1,99 @@
+package mocks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/satimoto/go-lnm/internal/nostr"
+)
+
+// MockRelay is a local stand-in for a nostr relay. It verifies and
+// stores published events and acknowledges them with an OK message.
+type MockRelay struct {
+	Url      string
+	server   *httptest.Server
+	upgrader websocket.Upgrader
+	events   []*nostr.Event
+	reject   string
+	mutex    sync.Mutex
+}
+
+func NewRelay() *MockRelay {
+	mockRelay := &MockRelay{}
+	mockRelay.server = httptest.NewServer(http.HandlerFunc(mockRelay.handle))
+	mockRelay.Url = "ws" + strings.TrimPrefix(mockRelay.server.URL, "http")
+
+	return mockRelay
+}
+
+func (r *MockRelay) Close() {
+	r.server.Close()
+}
+
+func (r *MockRelay) GetEvents() []*nostr.Event {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return append([]*nostr.Event{}, r.events...)
+}
+
+// SetReject makes the relay reject events with the reason, an empty
+// reason accepts events
+func (r *MockRelay) SetReject(reason string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.reject = reason
+}
+
+func (r *MockRelay) handle(w http.ResponseWriter, req *http.Request) {
+	conn, err := r.upgrader.Upgrade(w, req, nil)
+
+	if err != nil {
+		return
+	}
+
+	defer conn.Close()
+
+	for {
+		var message []json.RawMessage
+
+		if err := conn.ReadJSON(&message); err != nil {
+			return
+		}
+
+		var messageType string
+
+		if len(message) < 2 || json.Unmarshal(message[0], &messageType) != nil || messageType != "EVENT" {
+			conn.WriteJSON([]interface{}{"NOTICE", "unsupported message"})
+			continue
+		}
+
+		event := &nostr.Event{}
+
+		if err := json.Unmarshal(message[1], event); err != nil {
+			conn.WriteJSON([]interface{}{"NOTICE", "invalid event"})
+			continue
+		}
+
+		if err := event.Verify(); err != nil {
+			conn.WriteJSON([]interface{}{"OK", event.ID, false, "invalid: " + err.Error()})
+			continue
+		}
+
+		r.mutex.Lock()
+		reject := r.reject
+
+		if len(reject) == 0 {
+			r.events = append(r.events, event)
+		}
+
+		r.mutex.Unlock()
+
+		conn.WriteJSON([]interface{}{"OK", event.ID, len(reject) == 0, reject})
+	}
+}
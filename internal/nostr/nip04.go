@@ -0,0 +1,74 @@
+package nostr
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+var ErrInvalidCiphertext = errors.New("invalid nostr ciphertext")
+
+// EncryptNip04 encrypts a direct message with AES-256-CBC using the
+// unhashed ECDH shared secret as described in NIP-04
+func EncryptNip04(privateKey *btcec.PrivateKey, publicKey *btcec.PublicKey, plaintext string) (string, error) {
+	block, err := aes.NewCipher(btcec.GenerateSharedSecret(privateKey, publicKey))
+
+	if err != nil {
+		return "", err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+
+	if _, err := rand.Read(iv); err != nil {
+		return "", err
+	}
+
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	padded := append([]byte(plaintext), bytes.Repeat([]byte{byte(padding)}, padding)...)
+	ciphertext := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, padded)
+
+	return base64.StdEncoding.EncodeToString(ciphertext) + "?iv=" + base64.StdEncoding.EncodeToString(iv), nil
+}
+
+func DecryptNip04(privateKey *btcec.PrivateKey, publicKey *btcec.PublicKey, content string) (string, error) {
+	parts := strings.Split(content, "?iv=")
+
+	if len(parts) != 2 {
+		return "", ErrInvalidCiphertext
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(parts[0])
+
+	if err != nil || len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return "", ErrInvalidCiphertext
+	}
+
+	iv, err := base64.StdEncoding.DecodeString(parts[1])
+
+	if err != nil || len(iv) != aes.BlockSize {
+		return "", ErrInvalidCiphertext
+	}
+
+	block, err := aes.NewCipher(btcec.GenerateSharedSecret(privateKey, publicKey))
+
+	if err != nil {
+		return "", err
+	}
+
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+	padding := int(plaintext[len(plaintext)-1])
+
+	if padding == 0 || padding > aes.BlockSize {
+		return "", ErrInvalidCiphertext
+	}
+
+	return string(plaintext[:len(plaintext)-padding]), nil
+}
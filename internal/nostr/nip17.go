@@ -0,0 +1,132 @@
+package nostr
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"math/big"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// twoDays is the window NIP-59 timestamps are randomized over to
+// hide the time a message was sent
+const twoDays = 2 * 24 * 60 * 60
+
+// CreateGiftWrap creates a NIP-17 private direct message. The unsigned
+// message is sealed by the sender and gift wrapped with a random key.
+func CreateGiftWrap(privateKey *btcec.PrivateKey, publicKey *btcec.PublicKey, content string) (*Event, error) {
+	recipient := GetPublicKeyHex(publicKey)
+	rumor := &Event{
+		PubKey:    GetPublicKeyHex(privateKey.PubKey()),
+		CreatedAt: time.Now().Unix(),
+		Kind:      KIND_PRIVATE_DIRECT_MESSAGE,
+		Tags:      Tags{{"p", recipient}},
+		Content:   content,
+	}
+
+	id, err := rumor.GetID()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rumor.ID = id
+	seal, err := createEncryptedEvent(privateKey, publicKey, KIND_SEAL, Tags{}, rumor)
+
+	if err != nil {
+		return nil, err
+	}
+
+	wrapKey, err := btcec.NewPrivateKey()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return createEncryptedEvent(wrapKey, publicKey, KIND_GIFT_WRAP, Tags{{"p", recipient}}, seal)
+}
+
+// OpenGiftWrap decrypts a NIP-17 gift wrap and returns the sealed message
+func OpenGiftWrap(privateKey *btcec.PrivateKey, giftWrap *Event) (*Event, error) {
+	seal, err := openEncryptedEvent(privateKey, giftWrap)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := seal.Verify(); err != nil {
+		return nil, err
+	}
+
+	rumor, err := openEncryptedEvent(privateKey, seal)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if rumor.PubKey != seal.PubKey {
+		return nil, ErrInvalidEvent
+	}
+
+	return rumor, nil
+}
+
+func createEncryptedEvent(privateKey *btcec.PrivateKey, publicKey *btcec.PublicKey, kind int, tags Tags, inner *Event) (*Event, error) {
+	innerJson, err := json.Marshal(inner)
+
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := EncryptNip44(GetConversationKey(privateKey, publicKey), string(innerJson))
+
+	if err != nil {
+		return nil, err
+	}
+
+	event := &Event{
+		CreatedAt: randomTimestamp(),
+		Kind:      kind,
+		Tags:      tags,
+		Content:   content,
+	}
+
+	if err := event.Sign(privateKey); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+func openEncryptedEvent(privateKey *btcec.PrivateKey, event *Event) (*Event, error) {
+	publicKey, err := DecodePublicKey(event.PubKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := DecryptNip44(GetConversationKey(privateKey, publicKey), event.Content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	inner := &Event{}
+
+	if err := json.Unmarshal([]byte(content), inner); err != nil {
+		return nil, ErrInvalidEvent
+	}
+
+	return inner, nil
+}
+
+func randomTimestamp() int64 {
+	offset, err := rand.Int(rand.Reader, big.NewInt(twoDays))
+
+	if err != nil {
+		return time.Now().Unix()
+	}
+
+	return time.Now().Unix() - offset.Int64()
+}
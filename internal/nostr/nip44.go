@@ -0,0 +1,158 @@
+package nostr
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/bits"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"golang.org/x/crypto/chacha20"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	NIP44_VERSION        = 2
+	NIP44_MIN_PLAINTEXT  = 1
+	NIP44_MAX_PLAINTEXT  = 65535
+	nip44Salt            = "nip44-v2"
+	nip44NonceSize       = 32
+	nip44MacSize         = 32
+	nip44MessageKeysSize = 76
+)
+
+var ErrInvalidPlaintext = errors.New("invalid nostr plaintext length")
+
+// GetConversationKey derives the NIP-44 v2 conversation key between two keys
+func GetConversationKey(privateKey *btcec.PrivateKey, publicKey *btcec.PublicKey) []byte {
+	return hkdf.Extract(sha256.New, btcec.GenerateSharedSecret(privateKey, publicKey), []byte(nip44Salt))
+}
+
+// EncryptNip44 encrypts a message with ChaCha20 and HMAC-SHA256 as described in NIP-44 v2
+func EncryptNip44(conversationKey []byte, plaintext string) (string, error) {
+	nonce := make([]byte, nip44NonceSize)
+
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return encryptNip44WithNonce(conversationKey, plaintext, nonce)
+}
+
+func DecryptNip44(conversationKey []byte, payload string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(payload)
+
+	if err != nil || len(data) < 1+nip44NonceSize+2+32+nip44MacSize || data[0] != NIP44_VERSION {
+		return "", ErrInvalidCiphertext
+	}
+
+	nonce := data[1 : 1+nip44NonceSize]
+	ciphertext := data[1+nip44NonceSize : len(data)-nip44MacSize]
+	mac := data[len(data)-nip44MacSize:]
+
+	chachaKey, chachaNonce, hmacKey, err := getMessageKeys(conversationKey, nonce)
+
+	if err != nil {
+		return "", err
+	}
+
+	if !hmac.Equal(mac, computeMac(hmacKey, nonce, ciphertext)) {
+		return "", ErrInvalidCiphertext
+	}
+
+	padded, err := xorChaCha20(chachaKey, chachaNonce, ciphertext)
+
+	if err != nil {
+		return "", err
+	}
+
+	length := int(binary.BigEndian.Uint16(padded[:2]))
+
+	if length < NIP44_MIN_PLAINTEXT || 2+length > len(padded) || len(padded) != 2+calcPaddedLen(length) {
+		return "", ErrInvalidCiphertext
+	}
+
+	return string(padded[2 : 2+length]), nil
+}
+
+func encryptNip44WithNonce(conversationKey []byte, plaintext string, nonce []byte) (string, error) {
+	length := len(plaintext)
+
+	if length < NIP44_MIN_PLAINTEXT || length > NIP44_MAX_PLAINTEXT {
+		return "", ErrInvalidPlaintext
+	}
+
+	chachaKey, chachaNonce, hmacKey, err := getMessageKeys(conversationKey, nonce)
+
+	if err != nil {
+		return "", err
+	}
+
+	padded := make([]byte, 2+calcPaddedLen(length))
+	binary.BigEndian.PutUint16(padded, uint16(length))
+	copy(padded[2:], plaintext)
+
+	ciphertext, err := xorChaCha20(chachaKey, chachaNonce, padded)
+
+	if err != nil {
+		return "", err
+	}
+
+	data := make([]byte, 0, 1+len(nonce)+len(ciphertext)+nip44MacSize)
+	data = append(data, NIP44_VERSION)
+	data = append(data, nonce...)
+	data = append(data, ciphertext...)
+	data = append(data, computeMac(hmacKey, nonce, ciphertext)...)
+
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+func getMessageKeys(conversationKey, nonce []byte) (chachaKey, chachaNonce, hmacKey []byte, err error) {
+	keys := make([]byte, nip44MessageKeysSize)
+
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, conversationKey, nonce), keys); err != nil {
+		return nil, nil, nil, err
+	}
+
+	return keys[0:32], keys[32:44], keys[44:76], nil
+}
+
+func computeMac(hmacKey, nonce, ciphertext []byte) []byte {
+	mac := hmac.New(sha256.New, hmacKey)
+	mac.Write(nonce)
+	mac.Write(ciphertext)
+
+	return mac.Sum(nil)
+}
+
+func xorChaCha20(key, nonce, input []byte) ([]byte, error) {
+	stream, err := chacha20.NewUnauthenticatedCipher(key, nonce)
+
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]byte, len(input))
+	stream.XORKeyStream(output, input)
+
+	return output, nil
+}
+
+func calcPaddedLen(length int) int {
+	if length <= 32 {
+		return 32
+	}
+
+	nextPower := 1 << bits.Len(uint(length-1))
+	chunk := 32
+
+	if nextPower > 256 {
+		chunk = nextPower / 8
+	}
+
+	return chunk * ((length-1)/chunk + 1)
+}
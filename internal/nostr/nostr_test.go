@@ -0,0 +1,147 @@
+package nostr_test
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/satimoto/go-lnm/internal/nostr"
+	"github.com/satimoto/go-lnm/internal/nostr/mocks"
+)
+
+const (
+	testPrivateKey1 = "0000000000000000000000000000000000000000000000000000000000000001"
+	testPrivateKey2 = "0000000000000000000000000000000000000000000000000000000000000002"
+)
+
+func TestKeys(t *testing.T) {
+	privateKey, _ := nostr.DecodePrivateKey(testPrivateKey1)
+	npub := nostr.EncodeNpub(privateKey.PubKey())
+	publicKey, err := nostr.DecodePublicKey(npub)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if nostr.GetPublicKeyHex(publicKey) != "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798" {
+		t.Errorf("Public key mismatch: %v", nostr.GetPublicKeyHex(publicKey))
+	}
+
+	nsecKey, err := nostr.DecodePrivateKey(nostr.EncodeNsec(privateKey))
+
+	if err != nil || !nsecKey.Key.Equals(&privateKey.Key) {
+		t.Errorf("Private key mismatch: %v", err)
+	}
+
+	if _, err := nostr.DecodePublicKey("npub1invalid"); err != nostr.ErrInvalidPublicKey {
+		t.Errorf("Error mismatch: %v expecting %v", err, nostr.ErrInvalidPublicKey)
+	}
+}
+
+func TestNip44(t *testing.T) {
+	privateKey1, _ := nostr.DecodePrivateKey(testPrivateKey1)
+	privateKey2, _ := nostr.DecodePrivateKey(testPrivateKey2)
+
+	conversationKey := nostr.GetConversationKey(privateKey1, privateKey2.PubKey())
+
+	if hex.EncodeToString(conversationKey) != "c41c775356fd92eadc63ff5a0dc1da211b268cbea22316767095b2871ea1412d" {
+		t.Errorf("Conversation key mismatch: %x", conversationKey)
+	}
+
+	if reverseKey := nostr.GetConversationKey(privateKey2, privateKey1.PubKey()); hex.EncodeToString(reverseKey) != hex.EncodeToString(conversationKey) {
+		t.Errorf("Conversation key not symmetric: %x", reverseKey)
+	}
+
+	for _, plaintext := range []string{"a", "🍕🫃", string(make([]byte, 1000))} {
+		payload, err := nostr.EncryptNip44(conversationKey, plaintext)
+
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		decrypted, err := nostr.DecryptNip44(conversationKey, payload)
+
+		if err != nil || decrypted != plaintext {
+			t.Errorf("Plaintext mismatch: %v, %v", decrypted, err)
+		}
+	}
+
+	if _, err := nostr.EncryptNip44(conversationKey, ""); err != nostr.ErrInvalidPlaintext {
+		t.Errorf("Error mismatch: %v expecting %v", err, nostr.ErrInvalidPlaintext)
+	}
+}
+
+func TestNip04(t *testing.T) {
+	privateKey1, _ := nostr.DecodePrivateKey(testPrivateKey1)
+	privateKey2, _ := nostr.DecodePrivateKey(testPrivateKey2)
+
+	content, err := nostr.EncryptNip04(privateKey1, privateKey2.PubKey(), "Hello, nostr!")
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	plaintext, err := nostr.DecryptNip04(privateKey2, privateKey1.PubKey(), content)
+
+	if err != nil || plaintext != "Hello, nostr!" {
+		t.Errorf("Plaintext mismatch: %v, %v", plaintext, err)
+	}
+}
+
+func TestGiftWrap(t *testing.T) {
+	privateKey1, _ := nostr.DecodePrivateKey(testPrivateKey1)
+	privateKey2, _ := nostr.DecodePrivateKey(testPrivateKey2)
+
+	giftWrap, err := nostr.CreateGiftWrap(privateKey1, privateKey2.PubKey(), `{"type":"SESSION_INVOICE"}`)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if giftWrap.Kind != nostr.KIND_GIFT_WRAP || giftWrap.PubKey == nostr.GetPublicKeyHex(privateKey1.PubKey()) {
+		t.Errorf("Gift wrap mismatch: %#v", giftWrap)
+	}
+
+	if err := giftWrap.Verify(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	rumor, err := nostr.OpenGiftWrap(privateKey2, giftWrap)
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if rumor.Kind != nostr.KIND_PRIVATE_DIRECT_MESSAGE || rumor.Content != `{"type":"SESSION_INVOICE"}` || rumor.PubKey != nostr.GetPublicKeyHex(privateKey1.PubKey()) {
+		t.Errorf("Rumor mismatch: %#v", rumor)
+	}
+}
+
+func TestPublish(t *testing.T) {
+	mockRelay := mocks.NewRelay()
+	defer mockRelay.Close()
+
+	privateKey, _ := nostr.DecodePrivateKey(testPrivateKey1)
+	event := &nostr.Event{
+		CreatedAt: time.Now().Unix(),
+		Kind:      nostr.KIND_ENCRYPTED_DIRECT_MESSAGE,
+		Content:   "<content & more>",
+	}
+
+	event.Sign(privateKey)
+
+	if err := nostr.PublishTimeout(mockRelay.Url, event, time.Second); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if events := mockRelay.GetEvents(); len(events) != 1 || events[0].ID != event.ID {
+		t.Errorf("Events mismatch: %#v", events)
+	}
+
+	mockRelay.SetReject("blocked: spam")
+
+	if err := nostr.PublishTimeout(mockRelay.Url, event, time.Second); !errors.Is(err, nostr.ErrRelayRejected) {
+		t.Errorf("Error mismatch: %v expecting %v", err, nostr.ErrRelayRejected)
+	}
+}
@@ -0,0 +1,91 @@
+package nostr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+var (
+	ErrRelayRejected = errors.New("event rejected by relay")
+	ErrRelayTimeout  = errors.New("relay did not acknowledge event")
+)
+
+// Publish sends an event to a relay and waits for the NIP-20 OK
+// acknowledgement of the event
+func Publish(ctx context.Context, relayUrl string, event *Event) error {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, relayUrl, nil)
+
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetWriteDeadline(deadline)
+		conn.SetReadDeadline(deadline)
+	}
+
+	if err := conn.WriteJSON([]interface{}{"EVENT", event}); err != nil {
+		return err
+	}
+
+	for {
+		_, data, err := conn.ReadMessage()
+
+		if err != nil {
+			if ctx.Err() != nil || isTimeout(err) {
+				return ErrRelayTimeout
+			}
+
+			return err
+		}
+
+		var message []json.RawMessage
+
+		if err := json.Unmarshal(data, &message); err != nil || len(message) < 3 {
+			continue
+		}
+
+		var messageType, eventID string
+		json.Unmarshal(message[0], &messageType)
+		json.Unmarshal(message[1], &eventID)
+
+		if messageType != "OK" || eventID != event.ID {
+			continue
+		}
+
+		var accepted bool
+		var reason string
+		json.Unmarshal(message[2], &accepted)
+
+		if len(message) > 3 {
+			json.Unmarshal(message[3], &reason)
+		}
+
+		if !accepted {
+			return fmt.Errorf("%w: %s", ErrRelayRejected, reason)
+		}
+
+		return nil
+	}
+}
+
+func isTimeout(err error) bool {
+	var netErr interface{ Timeout() bool }
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// PublishTimeout publishes an event with a timeout
+func PublishTimeout(relayUrl string, event *Event, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return Publish(ctx, relayUrl, event)
+}
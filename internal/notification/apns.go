@@ -0,0 +1,195 @@
+package notification
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	APNS_ENDPOINT_PRODUCTION = "https://api.push.apple.com"
+	APNS_ENDPOINT_SANDBOX    = "https://api.sandbox.push.apple.com"
+)
+
+type ApnsSender struct {
+	endpoint   string
+	httpClient *http.Client
+	keyID      string
+	teamID     string
+	topic      string
+	signer     crypto.Signer
+	authToken  string
+	issuedAt   time.Time
+	mutex      sync.Mutex
+}
+
+type apnsResponse struct {
+	Reason string `json:"reason"`
+}
+
+func NewApnsSender(endpoint string, keyBytes []byte, keyID, teamID, topic string) (*ApnsSender, error) {
+	signer, err := parsePrivateKey(keyBytes)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(endpoint) == 0 {
+		endpoint = APNS_ENDPOINT_PRODUCTION
+	}
+
+	return &ApnsSender{
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		keyID:      keyID,
+		teamID:     teamID,
+		topic:      topic,
+		signer:     signer,
+	}, nil
+}
+
+func (s *ApnsSender) Send(message *Message) (*Response, error) {
+	authToken, err := s.getAuthToken()
+
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(s.createPayload(message))
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &Response{}
+
+	for _, token := range message.Tokens() {
+		messageID, err := s.send(authToken, token, message, body)
+
+		response.addResult(Result{
+			Token:     token,
+			MessageID: messageID,
+			Error:     err,
+		})
+	}
+
+	return response, response.err()
+}
+
+func (s *ApnsSender) createPayload(message *Message) map[string]interface{} {
+	aps := map[string]interface{}{}
+
+	if message.ContentAvailable {
+		aps["content-available"] = 1
+	}
+
+	if message.Notification != nil {
+		aps["alert"] = map[string]string{
+			"title": message.Notification.Title,
+			"body":  message.Notification.Body,
+		}
+	}
+
+	payload := map[string]interface{}{}
+
+	for key, value := range message.Data {
+		payload[key] = value
+	}
+
+	payload["aps"] = aps
+
+	return payload
+}
+
+func (s *ApnsSender) send(authToken, token string, message *Message, body []byte) (string, error) {
+	request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/3/device/%s", s.endpoint, token), bytes.NewReader(body))
+
+	if err != nil {
+		return "", err
+	}
+
+	pushType, priority := "alert", "10"
+
+	if message.Notification == nil {
+		// Background notifications must be sent with a low priority
+		pushType, priority = "background", "5"
+	}
+
+	request.Header.Set("Authorization", "bearer "+authToken)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("apns-topic", s.topic)
+	request.Header.Set("apns-push-type", pushType)
+	request.Header.Set("apns-priority", priority)
+
+	if len(message.CollapseKey) > 0 {
+		request.Header.Set("apns-collapse-id", message.CollapseKey)
+	}
+
+	response, err := s.httpClient.Do(request)
+
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusOK {
+		return response.Header.Get("apns-id"), nil
+	}
+
+	apnsResponse := apnsResponse{}
+	json.NewDecoder(response.Body).Decode(&apnsResponse)
+
+	switch apnsResponse.Reason {
+	case "ExpiredProviderToken", "InvalidProviderToken":
+		// Force the provider token to be refreshed
+		s.mutex.Lock()
+		s.authToken = ""
+		s.mutex.Unlock()
+	}
+
+	switch {
+	case response.StatusCode == http.StatusGone || apnsResponse.Reason == "Unregistered":
+		return "", fmt.Errorf("%w: %v", ErrUnregistered, apnsResponse.Reason)
+	case apnsResponse.Reason == "BadDeviceToken" || apnsResponse.Reason == "DeviceTokenNotForTopic":
+		return "", fmt.Errorf("%w: %v", ErrInvalidToken, apnsResponse.Reason)
+	case response.StatusCode >= http.StatusInternalServerError || response.StatusCode == http.StatusTooManyRequests:
+		return "", fmt.Errorf("%w: %v", ErrUnavailable, apnsResponse.Reason)
+	}
+
+	return "", fmt.Errorf("notification failed with status %v: %v", response.StatusCode, apnsResponse.Reason)
+}
+
+func (s *ApnsSender) getAuthToken() (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// Provider tokens are valid for an hour but must not be refreshed
+	// more than once every 20 minutes
+	if len(s.authToken) > 0 && time.Since(s.issuedAt) < 50*time.Minute {
+		return s.authToken, nil
+	}
+
+	now := time.Now()
+	authToken, err := signJwt(s.signer, map[string]interface{}{
+		"alg": "ES256",
+		"kid": s.keyID,
+	}, map[string]interface{}{
+		"iss": s.teamID,
+		"iat": now.Unix(),
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	s.authToken = authToken
+	s.issuedAt = now
+
+	return s.authToken, nil
+}
@@ -1,49 +1,103 @@
 package notification
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/satimoto/go-datastore/pkg/db"
 )
 
-type NotificationDto map[string]interface{}
+type NotificationDto interface {
+	GetType() string
+}
 
-func CreateInvoiceRequestNotificationDto(invoiceRequest db.InvoiceRequest) NotificationDto {
-	response := map[string]interface{}{
-		"type": INVOICE_REQUEST,
-	}
+type InvoiceRequestNotificationDto struct {
+	Type string `json:"type"`
+}
 
-	return response
+func (d *InvoiceRequestNotificationDto) GetType() string {
+	return d.Type
+}
+
+type SessionInvoiceNotificationDto struct {
+	Type             string               `json:"type"`
+	EstimatedEnergy  float64              `json:"estimatedEnergy"`
+	EstimatedTime    float64              `json:"estimatedTime"`
+	MeteredEnergy    float64              `json:"meteredEnergy"`
+	MeteredTime      float64              `json:"meteredTime"`
+	PaymentRequest   string               `json:"paymentRequest"`
+	Signature        string               `json:"signature"`
+	SessionUid       string               `json:"sessionUid"`
+	SessionInvoiceID int64                `json:"sessionInvoiceId"`
+	Status           db.SessionStatusType `json:"status"`
+	StartDatetime    string               `json:"startDatetime"`
+	EndDatetime      *string              `json:"endDatetime,omitempty"`
+}
+
+func (d *SessionInvoiceNotificationDto) GetType() string {
+	return d.Type
+}
+
+type SessionUpdateNotificationDto struct {
+	Type       string               `json:"type"`
+	SessionUid string               `json:"sessionUid"`
+	Status     db.SessionStatusType `json:"status"`
+}
+
+func (d *SessionUpdateNotificationDto) GetType() string {
+	return d.Type
 }
 
-func CreateSessionInvoiceNotificationDto(session db.Session, sessionInvoice db.SessionInvoice) NotificationDto {
-	response := map[string]interface{}{
-		"type":             SESSION_INVOICE,
-		"estimatedEnergy":  sessionInvoice.EstimatedEnergy,
-		"estimatedTime":    sessionInvoice.EstimatedTime,
-		"meteredEnergy":    sessionInvoice.MeteredEnergy,
-		"meteredTime":      sessionInvoice.MeteredTime,
-		"paymentRequest":   sessionInvoice.PaymentRequest,
-		"signature":        sessionInvoice.Signature,
-		"sessionUid":       session.Uid,
-		"sessionInvoiceId": sessionInvoice.ID,
-		"status":           session.Status,
-		"startDatetime":    session.StartDatetime.Format(time.RFC3339),
+func CreateInvoiceRequestNotificationDto(invoiceRequest db.InvoiceRequest) *InvoiceRequestNotificationDto {
+	return &InvoiceRequestNotificationDto{
+		Type: INVOICE_REQUEST,
+	}
+}
+
+func CreateSessionInvoiceNotificationDto(session db.Session, sessionInvoice db.SessionInvoice) *SessionInvoiceNotificationDto {
+	response := &SessionInvoiceNotificationDto{
+		Type:             SESSION_INVOICE,
+		EstimatedEnergy:  sessionInvoice.EstimatedEnergy,
+		EstimatedTime:    sessionInvoice.EstimatedTime,
+		MeteredEnergy:    sessionInvoice.MeteredEnergy,
+		MeteredTime:      sessionInvoice.MeteredTime,
+		PaymentRequest:   sessionInvoice.PaymentRequest,
+		Signature:        sessionInvoice.Signature,
+		SessionUid:       session.Uid,
+		SessionInvoiceID: sessionInvoice.ID,
+		Status:           session.Status,
+		StartDatetime:    session.StartDatetime.Format(time.RFC3339),
 	}
 
 	if session.EndDatetime.Valid {
-		response["endDatetime"] = session.EndDatetime.Time.Format(time.RFC3339)
+		endDatetime := session.EndDatetime.Time.Format(time.RFC3339)
+		response.EndDatetime = &endDatetime
 	}
 
 	return response
 }
 
-func CreateSessionUpdateNotificationDto(session db.Session) NotificationDto {
-	response := map[string]interface{}{
-		"type":       SESSION_UPDATE,
-		"sessionUid": session.Uid,
-		"status":     session.Status,
+func CreateSessionUpdateNotificationDto(session db.Session) *SessionUpdateNotificationDto {
+	return &SessionUpdateNotificationDto{
+		Type:       SESSION_UPDATE,
+		SessionUid: session.Uid,
+		Status:     session.Status,
 	}
+}
 
-	return response
+// EncodeData converts a notification dto to the data payload of a message
+func EncodeData(dto NotificationDto) (map[string]interface{}, error) {
+	bytes, err := json.Marshal(dto)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data := make(map[string]interface{})
+
+	if err := json.Unmarshal(bytes, &data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
 }
@@ -0,0 +1,120 @@
+package notification_test
+
+import (
+	"database/sql"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+	"github.com/satimoto/go-lnm/internal/notification"
+)
+
+func getSchema(data map[string]interface{}) map[string]string {
+	schema := make(map[string]string)
+
+	for key, value := range data {
+		schema[key] = reflect.TypeOf(value).Kind().String()
+	}
+
+	return schema
+}
+
+func TestNotificationDtoSchema(t *testing.T) {
+	startDatetime := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		desc   string
+		dto    notification.NotificationDto
+		schema map[string]string
+	}{{
+		desc: "Invoice request",
+		dto:  notification.CreateInvoiceRequestNotificationDto(db.InvoiceRequest{ID: 1}),
+		schema: map[string]string{
+			"type": "string",
+		},
+	}, {
+		desc: "Session invoice",
+		dto: notification.CreateSessionInvoiceNotificationDto(db.Session{
+			Uid:           "SESSION0001",
+			Status:        "ACTIVE",
+			StartDatetime: startDatetime,
+		}, db.SessionInvoice{
+			ID:             1,
+			MeteredEnergy:  1.5,
+			PaymentRequest: "lnbc1",
+			Signature:      "signature",
+		}),
+		schema: map[string]string{
+			"type":             "string",
+			"estimatedEnergy":  "float64",
+			"estimatedTime":    "float64",
+			"meteredEnergy":    "float64",
+			"meteredTime":      "float64",
+			"paymentRequest":   "string",
+			"signature":        "string",
+			"sessionUid":       "string",
+			"sessionInvoiceId": "float64",
+			"status":           "string",
+			"startDatetime":    "string",
+		},
+	}, {
+		desc: "Session invoice ended",
+		dto: notification.CreateSessionInvoiceNotificationDto(db.Session{
+			Uid:           "SESSION0001",
+			Status:        "COMPLETED",
+			StartDatetime: startDatetime,
+			EndDatetime:   sql.NullTime{Time: startDatetime.Add(time.Hour), Valid: true},
+		}, db.SessionInvoice{ID: 1}),
+		schema: map[string]string{
+			"type":             "string",
+			"estimatedEnergy":  "float64",
+			"estimatedTime":    "float64",
+			"meteredEnergy":    "float64",
+			"meteredTime":      "float64",
+			"paymentRequest":   "string",
+			"signature":        "string",
+			"sessionUid":       "string",
+			"sessionInvoiceId": "float64",
+			"status":           "string",
+			"startDatetime":    "string",
+			"endDatetime":      "string",
+		},
+	}, {
+		desc: "Session update",
+		dto:  notification.CreateSessionUpdateNotificationDto(db.Session{Uid: "SESSION0001", Status: "ACTIVE"}),
+		schema: map[string]string{
+			"type":       "string",
+			"sessionUid": "string",
+			"status":     "string",
+		},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			data, err := notification.EncodeData(tc.dto)
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			schema := getSchema(data)
+
+			if !reflect.DeepEqual(schema, tc.schema) {
+				keys := []string{}
+
+				for key := range schema {
+					keys = append(keys, key)
+				}
+
+				sort.Strings(keys)
+				t.Errorf("Schema mismatch: %v expecting %v", keys, tc.schema)
+			}
+
+			if data["type"] != tc.dto.GetType() {
+				t.Errorf("Type mismatch: %v expecting %v", data["type"], tc.dto.GetType())
+			}
+		})
+	}
+}
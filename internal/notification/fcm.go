@@ -0,0 +1,348 @@
+package notification
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	FCM_ENDPOINT = "https://fcm.googleapis.com"
+	FCM_SCOPE    = "https://www.googleapis.com/auth/firebase.messaging"
+)
+
+type ServiceAccount struct {
+	Type         string `json:"type"`
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenUri     string `json:"token_uri"`
+}
+
+type FcmSender struct {
+	endpoint       string
+	httpClient     *http.Client
+	serviceAccount ServiceAccount
+	signer         crypto.Signer
+	accessToken    string
+	expiry         time.Time
+	mutex          sync.Mutex
+}
+
+type fcmRequest struct {
+	Message fcmMessage `json:"message"`
+}
+
+type fcmMessage struct {
+	Token        string            `json:"token"`
+	Data         map[string]string `json:"data,omitempty"`
+	Notification *fcmNotification  `json:"notification,omitempty"`
+	Android      *fcmAndroidConfig `json:"android,omitempty"`
+	Apns         *fcmApnsConfig    `json:"apns,omitempty"`
+}
+
+type fcmNotification struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+type fcmAndroidConfig struct {
+	CollapseKey string `json:"collapse_key,omitempty"`
+	Priority    string `json:"priority,omitempty"`
+}
+
+type fcmApnsConfig struct {
+	Headers map[string]string      `json:"headers,omitempty"`
+	Payload map[string]interface{} `json:"payload,omitempty"`
+}
+
+type fcmResponse struct {
+	Name  string    `json:"name"`
+	Error *fcmError `json:"error"`
+}
+
+type fcmError struct {
+	Code    int              `json:"code"`
+	Message string           `json:"message"`
+	Status  string           `json:"status"`
+	Details []fcmErrorDetail `json:"details"`
+}
+
+type fcmErrorDetail struct {
+	Type            string              `json:"@type"`
+	ErrorCode       string              `json:"errorCode"`
+	FieldViolations []fcmFieldViolation `json:"fieldViolations"`
+}
+
+type fcmFieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+type oauthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+	TokenType   string `json:"token_type"`
+}
+
+func NewFcmSender(endpoint string, serviceAccountJson []byte) (*FcmSender, error) {
+	serviceAccount := ServiceAccount{}
+
+	if err := json.Unmarshal(serviceAccountJson, &serviceAccount); err != nil {
+		return nil, err
+	}
+
+	if len(serviceAccount.ProjectID) == 0 || len(serviceAccount.ClientEmail) == 0 || len(serviceAccount.TokenUri) == 0 {
+		return nil, errors.New("invalid service account")
+	}
+
+	signer, err := parsePrivateKey([]byte(serviceAccount.PrivateKey))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(endpoint) == 0 {
+		endpoint = FCM_ENDPOINT
+	}
+
+	return &FcmSender{
+		endpoint:       strings.TrimSuffix(endpoint, "/"),
+		httpClient:     &http.Client{Timeout: 30 * time.Second},
+		serviceAccount: serviceAccount,
+		signer:         signer,
+	}, nil
+}
+
+func (s *FcmSender) Send(message *Message) (*Response, error) {
+	accessToken, err := s.getAccessToken()
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &Response{}
+
+	// The HTTP v1 API accepts a single token per request
+	for _, token := range message.Tokens() {
+		messageID, err := s.send(accessToken, s.createMessage(token, message))
+
+		response.addResult(Result{
+			Token:     token,
+			MessageID: messageID,
+			Error:     err,
+		})
+	}
+
+	return response, response.err()
+}
+
+func (s *FcmSender) createMessage(token string, message *Message) fcmMessage {
+	fcmMessage := fcmMessage{
+		Token: token,
+		Data:  stringifyData(message.Data),
+		Android: &fcmAndroidConfig{
+			CollapseKey: message.CollapseKey,
+			Priority:    strings.ToUpper(message.Priority),
+		},
+	}
+
+	if fcmMessage.Android.Priority != "HIGH" {
+		fcmMessage.Android.Priority = "NORMAL"
+	}
+
+	if message.Notification != nil {
+		fcmMessage.Notification = &fcmNotification{
+			Title: message.Notification.Title,
+			Body:  message.Notification.Body,
+		}
+	}
+
+	if message.ContentAvailable {
+		fcmMessage.Apns = &fcmApnsConfig{
+			Headers: map[string]string{
+				"apns-priority": "5",
+			},
+			Payload: map[string]interface{}{
+				"aps": map[string]interface{}{
+					"content-available": 1,
+				},
+			},
+		}
+	}
+
+	return fcmMessage
+}
+
+func (s *FcmSender) send(accessToken string, message fcmMessage) (string, error) {
+	body, err := json.Marshal(fcmRequest{Message: message})
+
+	if err != nil {
+		return "", err
+	}
+
+	requestUrl := fmt.Sprintf("%s/v1/projects/%s/messages:send", s.endpoint, s.serviceAccount.ProjectID)
+	request, err := http.NewRequest(http.MethodPost, requestUrl, bytes.NewReader(body))
+
+	if err != nil {
+		return "", err
+	}
+
+	request.Header.Set("Authorization", "Bearer "+accessToken)
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := s.httpClient.Do(request)
+
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+
+	defer response.Body.Close()
+	responseBody, err := ioutil.ReadAll(response.Body)
+
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+
+	fcmResponse := fcmResponse{}
+	json.Unmarshal(responseBody, &fcmResponse)
+
+	if response.StatusCode == http.StatusOK {
+		return fcmResponse.Name, nil
+	}
+
+	if response.StatusCode == http.StatusUnauthorized {
+		// Force the access token to be refreshed
+		s.mutex.Lock()
+		s.accessToken = ""
+		s.mutex.Unlock()
+	}
+
+	return "", fcmResponse.Error.toError(response.StatusCode)
+}
+
+func (s *FcmSender) getAccessToken() (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.accessToken) > 0 && time.Now().Add(time.Minute).Before(s.expiry) {
+		return s.accessToken, nil
+	}
+
+	now := time.Now()
+	assertion, err := signJwt(s.signer, map[string]interface{}{
+		"alg": "RS256",
+		"typ": "JWT",
+		"kid": s.serviceAccount.PrivateKeyID,
+	}, map[string]interface{}{
+		"iss":   s.serviceAccount.ClientEmail,
+		"scope": FCM_SCOPE,
+		"aud":   s.serviceAccount.TokenUri,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	response, err := s.httpClient.PostForm(s.serviceAccount.TokenUri, url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error requesting access token: %v", response.Status)
+	}
+
+	tokenResponse := oauthTokenResponse{}
+
+	if err := json.NewDecoder(response.Body).Decode(&tokenResponse); err != nil {
+		return "", err
+	}
+
+	s.accessToken = tokenResponse.AccessToken
+	s.expiry = now.Add(time.Duration(tokenResponse.ExpiresIn) * time.Second)
+
+	return s.accessToken, nil
+}
+
+func (e *fcmError) toError(statusCode int) error {
+	if e == nil {
+		if statusCode >= http.StatusInternalServerError || statusCode == http.StatusTooManyRequests {
+			return fmt.Errorf("%w: status %v", ErrUnavailable, statusCode)
+		}
+
+		return fmt.Errorf("notification failed with status %v", statusCode)
+	}
+
+	errorCode := e.Status
+
+	for _, detail := range e.Details {
+		if len(detail.ErrorCode) > 0 {
+			errorCode = detail.ErrorCode
+		}
+	}
+
+	switch errorCode {
+	case "UNREGISTERED":
+		return fmt.Errorf("%w: %v", ErrUnregistered, e.Message)
+	case "INVALID_ARGUMENT":
+		// Only an invalid token invalidates the token, other invalid
+		// arguments are errors in the message
+		if e.isTokenViolation() {
+			return fmt.Errorf("%w: %v", ErrInvalidToken, e.Message)
+		}
+
+		return fmt.Errorf("%w: %v", ErrInvalidMessage, e.Message)
+	case "UNAVAILABLE", "INTERNAL", "QUOTA_EXCEEDED", "RESOURCE_EXHAUSTED":
+		return fmt.Errorf("%w: %v", ErrUnavailable, e.Message)
+	}
+
+	return fmt.Errorf("%v: %v", errorCode, e.Message)
+}
+
+func (e *fcmError) isTokenViolation() bool {
+	for _, detail := range e.Details {
+		for _, fieldViolation := range detail.FieldViolations {
+			if fieldViolation.Field == "message.token" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func stringifyData(data map[string]interface{}) map[string]string {
+	// The HTTP v1 API only accepts string data values
+	stringData := make(map[string]string)
+
+	for key, value := range data {
+		switch v := value.(type) {
+		case string:
+			stringData[key] = v
+		default:
+			if bytes, err := json.Marshal(v); err == nil {
+				stringData[key] = string(bytes)
+			}
+		}
+	}
+
+	return stringData
+}
@@ -0,0 +1,83 @@
+package notification
+
+import (
+	"errors"
+)
+
+var (
+	ErrInvalidToken   = errors.New("invalid device token")
+	ErrInvalidMessage = errors.New("invalid notification message")
+	ErrUnregistered   = errors.New("device token not registered")
+	ErrUnavailable    = errors.New("notification service unavailable")
+	ErrNotConfigured  = errors.New("notification sender not configured")
+)
+
+type Message struct {
+	Platform         string
+	To               string
+	RegistrationIDs  []string
+	CollapseKey      string
+	Priority         string
+	ContentAvailable bool
+	Notification     *MessageNotification
+	Data             map[string]interface{}
+}
+
+type MessageNotification struct {
+	Title string
+	Body  string
+}
+
+type Response struct {
+	Success int
+	Failure int
+	Results []Result
+}
+
+type Result struct {
+	Token     string
+	MessageID string
+	Error     error
+}
+
+func (m *Message) Tokens() []string {
+	tokens := []string{}
+
+	if len(m.To) > 0 {
+		tokens = append(tokens, m.To)
+	}
+
+	return append(tokens, m.RegistrationIDs...)
+}
+
+func (m *Message) withTokens(tokens []string) *Message {
+	message := *m
+	message.To = ""
+	message.RegistrationIDs = tokens
+
+	return &message
+}
+
+func (r *Response) addResult(result Result) {
+	if result.Error == nil {
+		r.Success++
+	} else {
+		r.Failure++
+	}
+
+	r.Results = append(r.Results, result)
+}
+
+func (r *Response) err() error {
+	// Only error when nothing was delivered, individual
+	// failures are available in the results
+	if r.Success == 0 && r.Failure > 0 {
+		return r.Results[0].Error
+	}
+
+	return nil
+}
+
+func isRetryable(err error) bool {
+	return errors.Is(err, ErrUnavailable)
+}
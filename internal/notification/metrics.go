@@ -10,8 +10,16 @@ var (
 		Name: "lsp_notifications_sent_total",
 		Help: "The total number of notifications sent",
 	}, []string{"type"})
+	metricNotificationsFailedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lsp_notifications_failed_total",
+		Help: "The total number of notifications failed",
+	}, []string{"platform"})
 )
 
 func RecordNotificationSent(notifcationType string, count int) {
 	metricNotificationsSentTotal.WithLabelValues(notifcationType).Add(float64(count))
 }
+
+func RecordNotificationFailed(platform string) {
+	metricNotificationsFailedTotal.WithLabelValues(platform).Inc()
+}
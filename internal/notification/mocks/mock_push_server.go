@@ -0,0 +1,271 @@
+package mocks
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+const (
+	PLATFORM_APNS = "APNS"
+	PLATFORM_FCM  = "FCM"
+)
+
+type MockPushRequest struct {
+	Platform string
+	Token    string
+	Header   http.Header
+	Body     map[string]interface{}
+}
+
+// MockPushServer is a local stand-in for the OAuth token, FCM HTTP v1
+// and APNs endpoints.
+type MockPushServer struct {
+	*httptest.Server
+	ServiceAccountJson []byte
+	ApnsKey            []byte
+	rsaKey             *rsa.PrivateKey
+	ecdsaKey           *ecdsa.PrivateKey
+	requests           []MockPushRequest
+	tokenErrors        map[string]string
+	tokenUnavailable   map[string]int
+	messageCount       int
+	mutex              sync.Mutex
+}
+
+func NewPushServer() *MockPushServer {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecdsaKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	s := &MockPushServer{
+		rsaKey:           rsaKey,
+		ecdsaKey:         ecdsaKey,
+		tokenErrors:      make(map[string]string),
+		tokenUnavailable: make(map[string]int),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", s.handleToken)
+	mux.HandleFunc("/v1/projects/", s.handleFcm)
+	mux.HandleFunc("/3/device/", s.handleApns)
+	s.Server = httptest.NewServer(mux)
+
+	rsaKeyBytes, _ := x509.MarshalPKCS8PrivateKey(rsaKey)
+	s.ServiceAccountJson, _ = json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     "mock-project",
+		"private_key_id": "mock-key-id",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: rsaKeyBytes})),
+		"client_email":   "mock@mock-project.iam.gserviceaccount.com",
+		"token_uri":      s.URL + "/token",
+	})
+
+	ecdsaKeyBytes, _ := x509.MarshalPKCS8PrivateKey(ecdsaKey)
+	s.ApnsKey = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: ecdsaKeyBytes})
+
+	return s
+}
+
+// SetTokenError responds to the token with an FCM error code or APNs reason
+func (s *MockPushServer) SetTokenError(token, code string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.tokenErrors[token] = code
+}
+
+// SetTokenUnavailable responds to the token as unavailable count times
+func (s *MockPushServer) SetTokenUnavailable(token string, count int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.tokenUnavailable[token] = count
+}
+
+func (s *MockPushServer) GetRequests() []MockPushRequest {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]MockPushRequest{}, s.requests...)
+}
+
+func (s *MockPushServer) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.Form.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+		http.Error(w, "invalid_grant", http.StatusBadRequest)
+		return
+	}
+
+	if !verifyJwt(r.Form.Get("assertion"), &s.rsaKey.PublicKey) {
+		http.Error(w, "invalid_grant", http.StatusBadRequest)
+		return
+	}
+
+	writeJson(w, http.StatusOK, map[string]interface{}{
+		"access_token": "mock-access-token",
+		"expires_in":   3600,
+		"token_type":   "Bearer",
+	})
+}
+
+func (s *MockPushServer) handleFcm(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer mock-access-token" {
+		writeFcmError(w, http.StatusUnauthorized, "UNAUTHENTICATED", "")
+		return
+	}
+
+	body := map[string]interface{}{}
+	json.NewDecoder(r.Body).Decode(&body)
+	message, _ := body["message"].(map[string]interface{})
+	token, _ := message["token"].(string)
+
+	code, messageID := s.recordRequest(PLATFORM_FCM, token, r.Header, body)
+
+	switch code {
+	case "":
+		writeJson(w, http.StatusOK, map[string]interface{}{
+			"name": fmt.Sprintf("projects/mock-project/messages/%s", messageID),
+		})
+	case "UNREGISTERED":
+		writeFcmError(w, http.StatusNotFound, code, "")
+	case "INVALID_ARGUMENT":
+		writeFcmError(w, http.StatusBadRequest, code, "message.token")
+	case "INVALID_PAYLOAD":
+		writeFcmError(w, http.StatusBadRequest, "INVALID_ARGUMENT", "message.data")
+	default:
+		writeFcmError(w, http.StatusServiceUnavailable, code, "")
+	}
+}
+
+func (s *MockPushServer) handleApns(w http.ResponseWriter, r *http.Request) {
+	authorization := strings.TrimPrefix(r.Header.Get("Authorization"), "bearer ")
+
+	if !verifyJwt(authorization, &s.ecdsaKey.PublicKey) {
+		writeJson(w, http.StatusForbidden, map[string]string{"reason": "InvalidProviderToken"})
+		return
+	}
+
+	body := map[string]interface{}{}
+	json.NewDecoder(r.Body).Decode(&body)
+	token := strings.TrimPrefix(r.URL.Path, "/3/device/")
+
+	code, messageID := s.recordRequest(PLATFORM_APNS, token, r.Header, body)
+
+	switch code {
+	case "":
+		w.Header().Set("apns-id", messageID)
+		w.WriteHeader(http.StatusOK)
+	case "Unregistered":
+		writeJson(w, http.StatusGone, map[string]string{"reason": code})
+	case "BadDeviceToken":
+		writeJson(w, http.StatusBadRequest, map[string]string{"reason": code})
+	default:
+		writeJson(w, http.StatusServiceUnavailable, map[string]string{"reason": code})
+	}
+}
+
+func (s *MockPushServer) recordRequest(platform, token string, header http.Header, body map[string]interface{}) (string, string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.requests = append(s.requests, MockPushRequest{
+		Platform: platform,
+		Token:    token,
+		Header:   header,
+		Body:     body,
+	})
+
+	if count := s.tokenUnavailable[token]; count > 0 {
+		s.tokenUnavailable[token] = count - 1
+
+		if platform == PLATFORM_APNS {
+			return "ServiceUnavailable", ""
+		}
+
+		return "UNAVAILABLE", ""
+	}
+
+	if code, ok := s.tokenErrors[token]; ok {
+		return code, ""
+	}
+
+	s.messageCount++
+
+	return "", fmt.Sprintf("MESSAGE%04d", s.messageCount)
+}
+
+func verifyJwt(token string, publicKey crypto.PublicKey) bool {
+	parts := strings.Split(token, ".")
+
+	if len(parts) != 3 {
+		return false
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+
+	if err != nil {
+		return false
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil
+	case *ecdsa.PublicKey:
+		if len(signature) != 64 {
+			return false
+		}
+
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+
+		return ecdsa.Verify(key, digest[:], r, s)
+	}
+
+	return false
+}
+
+func writeFcmError(w http.ResponseWriter, statusCode int, errorCode, field string) {
+	details := []map[string]interface{}{{
+		"@type":     "type.googleapis.com/google.firebase.fcm.v1.FcmError",
+		"errorCode": errorCode,
+	}}
+
+	if len(field) > 0 {
+		details = append(details, map[string]interface{}{
+			"@type": "type.googleapis.com/google.rpc.BadRequest",
+			"fieldViolations": []map[string]string{{
+				"field":       field,
+				"description": fmt.Sprintf("Invalid %s", field),
+			}},
+		})
+	}
+
+	writeJson(w, statusCode, map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    statusCode,
+			"message": errorCode,
+			"status":  errorCode,
+			"details": details,
+		},
+	})
+}
+
+func writeJson(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(body)
+}
@@ -3,25 +3,23 @@ package mocks
 import (
 	"errors"
 
-	"github.com/appleboy/go-fcm"
-	"github.com/satimoto/go-datastore/pkg/db"
 	"github.com/satimoto/go-lnm/internal/notification"
 )
 
 type MockNotificationService struct {
-	sendNotificationMessageMockData  []*fcm.Message
-	sendNotificationResponseMockData []*fcm.Response
+	sendNotificationMessageMockData  []*notification.Message
+	sendNotificationResponseMockData []*notification.Response
 }
 
 func NewService() *MockNotificationService {
 	return &MockNotificationService{}
 }
 
-func (s *MockNotificationService) SendNotification(message *fcm.Message) (*fcm.Response, error) {
+func (s *MockNotificationService) SendNotification(message *notification.Message) (*notification.Response, error) {
 	s.sendNotificationMessageMockData = append(s.sendNotificationMessageMockData, message)
 
 	if len(s.sendNotificationResponseMockData) == 0 {
-		return &fcm.Response{}, errors.New("NotFound")
+		return &notification.Response{}, errors.New("NotFound")
 	}
 
 	response := s.sendNotificationResponseMockData[0]
@@ -29,11 +27,11 @@ func (s *MockNotificationService) SendNotification(message *fcm.Message) (*fcm.R
 	return response, nil
 }
 
-func (s *MockNotificationService) SendNotificationWithRetry(message *fcm.Message, retries int) (*fcm.Response, error) {
+func (s *MockNotificationService) SendNotificationWithRetry(message *notification.Message, retries int) (*notification.Response, error) {
 	s.sendNotificationMessageMockData = append(s.sendNotificationMessageMockData, message)
 
 	if len(s.sendNotificationResponseMockData) == 0 {
-		return &fcm.Response{}, errors.New("NotFound")
+		return &notification.Response{}, errors.New("NotFound")
 	}
 
 	response := s.sendNotificationResponseMockData[0]
@@ -41,9 +39,6 @@ func (s *MockNotificationService) SendNotificationWithRetry(message *fcm.Message
 	return response, nil
 }
 
-func (s *MockNotificationService) SetSendNotificationMockData(message *fcm.Response) {
+func (s *MockNotificationService) SetSendNotificationMockData(message *notification.Response) {
 	s.sendNotificationResponseMockData = append(s.sendNotificationResponseMockData, message)
 }
-
-func (s *MockNotificationService) SendUserNotification(user db.User, data notification.NotificationDto, notificationType string) {
-}
@@ -0,0 +1,168 @@
+package notification
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/satimoto/go-lnm/internal/nostr"
+)
+
+const (
+	NOSTR_ENCRYPTION_NIP04 = "NIP04"
+	NOSTR_ENCRYPTION_NIP44 = "NIP44"
+)
+
+type NostrSender struct {
+	privateKey *btcec.PrivateKey
+	relays     []string
+	encryption string
+	timeout    time.Duration
+}
+
+type nostrContent struct {
+	Title string                 `json:"title,omitempty"`
+	Body  string                 `json:"body,omitempty"`
+	Data  map[string]interface{} `json:"data,omitempty"`
+}
+
+func NewNostrSender(privateKey string, relays []string, encryption string) (*NostrSender, error) {
+	key, err := nostr.DecodePrivateKey(privateKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(relays) == 0 {
+		return nil, errors.New("no nostr relays configured")
+	}
+
+	encryption = strings.ToUpper(encryption)
+
+	if len(encryption) == 0 {
+		encryption = NOSTR_ENCRYPTION_NIP44
+	} else if encryption != NOSTR_ENCRYPTION_NIP04 && encryption != NOSTR_ENCRYPTION_NIP44 {
+		return nil, fmt.Errorf("unsupported nostr encryption: %s", encryption)
+	}
+
+	return &NostrSender{
+		privateKey: key,
+		relays:     relays,
+		encryption: encryption,
+		timeout:    10 * time.Second,
+	}, nil
+}
+
+func (s *NostrSender) Send(message *Message) (*Response, error) {
+	content := nostrContent{
+		Data: message.Data,
+	}
+
+	if message.Notification != nil {
+		content.Title = message.Notification.Title
+		content.Body = message.Notification.Body
+	}
+
+	contentJson, err := json.Marshal(content)
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := &Response{}
+
+	for _, token := range message.Tokens() {
+		messageID, err := s.send(token, string(contentJson))
+
+		response.addResult(Result{
+			Token:     token,
+			MessageID: messageID,
+			Error:     err,
+		})
+	}
+
+	return response, response.err()
+}
+
+func (s *NostrSender) send(npub, content string) (string, error) {
+	publicKey, err := nostr.DecodePublicKey(npub)
+
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+
+	event, err := s.createEvent(publicKey, content)
+
+	if err != nil {
+		return "", err
+	}
+
+	if !s.publish(event) {
+		return "", ErrUnavailable
+	}
+
+	return event.ID, nil
+}
+
+func (s *NostrSender) createEvent(publicKey *btcec.PublicKey, content string) (*nostr.Event, error) {
+	if s.encryption == NOSTR_ENCRYPTION_NIP04 {
+		encryptedContent, err := nostr.EncryptNip04(s.privateKey, publicKey, content)
+
+		if err != nil {
+			return nil, err
+		}
+
+		event := &nostr.Event{
+			CreatedAt: time.Now().Unix(),
+			Kind:      nostr.KIND_ENCRYPTED_DIRECT_MESSAGE,
+			Tags:      nostr.Tags{{"p", nostr.GetPublicKeyHex(publicKey)}},
+			Content:   encryptedContent,
+		}
+
+		if err := event.Sign(s.privateKey); err != nil {
+			return nil, err
+		}
+
+		return event, nil
+	}
+
+	return nostr.CreateGiftWrap(s.privateKey, publicKey, content)
+}
+
+// publish sends the event to all relays, returning true if any relay accepted it
+func (s *NostrSender) publish(event *nostr.Event) bool {
+	var waitGroup sync.WaitGroup
+	accepted := make(chan bool, len(s.relays))
+
+	for _, relay := range s.relays {
+		waitGroup.Add(1)
+
+		go func(relay string) {
+			defer waitGroup.Done()
+
+			if err := nostr.PublishTimeout(relay, event, s.timeout); err != nil {
+				log.Printf("Error publishing nostr event to %v: %v", relay, err)
+				accepted <- false
+				return
+			}
+
+			accepted <- true
+		}(relay)
+	}
+
+	waitGroup.Wait()
+	close(accepted)
+
+	for ok := range accepted {
+		if ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,112 @@
+package notification_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/satimoto/go-lnm/internal/nostr"
+	nostrMocks "github.com/satimoto/go-lnm/internal/nostr/mocks"
+	"github.com/satimoto/go-lnm/internal/notification"
+)
+
+func TestNostrSender(t *testing.T) {
+	senderKey, _ := btcec.NewPrivateKey()
+	recipientKey, _ := btcec.NewPrivateKey()
+	npub := nostr.EncodeNpub(recipientKey.PubKey())
+
+	cases := []struct {
+		desc       string
+		encryption string
+		token      string
+		reject     string
+		events     int
+		err        error
+	}{{
+		desc:   "NIP-44 gift wrapped message",
+		token:  npub,
+		events: 1,
+	}, {
+		desc:       "NIP-04 encrypted message",
+		encryption: notification.NOSTR_ENCRYPTION_NIP04,
+		token:      npub,
+		events:     1,
+	}, {
+		desc:  "Invalid npub",
+		token: "TOKEN0001",
+		err:   notification.ErrInvalidToken,
+	}, {
+		desc:   "Relay rejects message",
+		token:  npub,
+		reject: "blocked: rate limited",
+		err:    notification.ErrUnavailable,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			mockRelay := nostrMocks.NewRelay()
+			defer mockRelay.Close()
+
+			mockRelay.SetReject(tc.reject)
+
+			nostrSender, err := notification.NewNostrSender(nostr.EncodeNsec(senderKey), []string{mockRelay.Url}, tc.encryption)
+
+			if err != nil {
+				t.Fatalf("Error creating sender: %v", err)
+			}
+
+			notificationService := notification.NewServiceWithSenders(nil, nil, nostrSender)
+			message := &notification.Message{
+				Platform: notification.PLATFORM_NOSTR,
+				To:       tc.token,
+				Data:     map[string]interface{}{"type": notification.SESSION_INVOICE, "sessionInvoiceId": float64(1)},
+			}
+
+			_, err = notificationService.SendNotification(message)
+
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Error mismatch: %v expecting %v", err, tc.err)
+			}
+
+			events := mockRelay.GetEvents()
+
+			if len(events) != tc.events {
+				t.Fatalf("Events mismatch: %v expecting %v", len(events), tc.events)
+			}
+
+			if tc.events == 0 {
+				return
+			}
+
+			var content string
+
+			if tc.encryption == notification.NOSTR_ENCRYPTION_NIP04 {
+				content, err = nostr.DecryptNip04(recipientKey, senderKey.PubKey(), events[0].Content)
+			} else {
+				rumor, openErr := nostr.OpenGiftWrap(recipientKey, events[0])
+				err = openErr
+
+				if rumor != nil {
+					content = rumor.Content
+				}
+			}
+
+			if err != nil {
+				t.Fatalf("Error decrypting message: %v", err)
+			}
+
+			data := struct {
+				Data map[string]interface{} `json:"data"`
+			}{}
+
+			if err := json.Unmarshal([]byte(content), &data); err != nil {
+				t.Fatalf("Error decoding message: %v", err)
+			}
+
+			if data.Data["type"] != notification.SESSION_INVOICE || data.Data["sessionInvoiceId"] != float64(1) {
+				t.Errorf("Data mismatch: %v", data.Data)
+			}
+		})
+	}
+}
@@ -1,59 +1,278 @@
 package notification
 
 import (
+	"encoding/hex"
+	"io/ioutil"
 	"log"
+	"os"
+	"time"
 
-	"github.com/appleboy/go-fcm"
-	"github.com/satimoto/go-datastore/pkg/db"
 	"github.com/satimoto/go-datastore/pkg/util"
-	metrics "github.com/satimoto/go-lnm/internal/metric"
+	lnmUtil "github.com/satimoto/go-lnm/pkg/util"
 )
 
 type Notification interface {
-	SendNotification(*fcm.Message) (*fcm.Response, error)
-	SendNotificationWithRetry(message *fcm.Message, retries int) (*fcm.Response, error)
-	SendUserNotification(user db.User, data NotificationDto, notificationType string)
+	SendNotification(message *Message) (*Response, error)
+	SendNotificationWithRetry(message *Message, retries int) (*Response, error)
+}
+
+type Sender interface {
+	Send(message *Message) (*Response, error)
 }
 
 type NotificationService struct {
-	client *fcm.Client
+	FcmSender   Sender
+	ApnsSender  Sender
+	NostrSender Sender
+	RetryDelay  time.Duration
 }
 
-func NewService(apiKey string) Notification {
-	client, err := fcm.NewClient(apiKey)
-	util.PanicOnError("LNM034", "Invalid FCM API key", err)
+func NewService() Notification {
+	return NewServiceWithSenders(newFcmSender(), newApnsSender(), newNostrSender())
+}
 
+func NewServiceWithSenders(fcmSender, apnsSender, nostrSender Sender) Notification {
 	return &NotificationService{
-		client: client,
+		FcmSender:   fcmSender,
+		ApnsSender:  apnsSender,
+		NostrSender: nostrSender,
+		RetryDelay:  time.Second,
 	}
 }
 
-func (s *NotificationService) SendNotification(message *fcm.Message) (*fcm.Response, error) {
-	log.Printf("Sending notification: %v", message.To)
+func (s *NotificationService) SendNotification(message *Message) (*Response, error) {
+	log.Printf("Sending notification: %v %v", message.Platform, message.Tokens())
 	log.Printf("Data=%#v", message.Data)
-	return s.client.Send(message)
+
+	messages := splitMessage(message)
+
+	if len(messages) == 1 {
+		return s.sendMessage(message)
+	}
+
+	response := &Response{}
+	var err error
+
+	for _, splitMessage := range messages {
+		splitResponse, splitErr := s.sendMessage(splitMessage)
+
+		if splitErr != nil {
+			err = splitErr
+		}
+
+		if splitResponse == nil {
+			for _, token := range splitMessage.Tokens() {
+				response.addResult(Result{Token: token, Error: splitErr})
+			}
+
+			continue
+		}
+
+		for _, result := range splitResponse.Results {
+			response.addResult(result)
+		}
+	}
+
+	return response, err
 }
 
-func (s *NotificationService) SendNotificationWithRetry(message *fcm.Message, retries int) (*fcm.Response, error) {
-	log.Printf("Sending notification with retry: %v", message.To)
-	log.Printf("Data=%#v", message.Data)
-	return s.client.SendWithRetry(message, retries)
+func (s *NotificationService) sendMessage(message *Message) (*Response, error) {
+	sender := s.getSender(message)
+
+	if sender == nil {
+		return nil, ErrNotConfigured
+	}
+
+	response, err := sender.Send(message)
+
+	if response != nil {
+		for _, result := range response.Results {
+			if result.Error != nil {
+				RecordNotificationFailed(message.Platform)
+			}
+		}
+	}
+
+	return response, err
+}
+
+func (s *NotificationService) SendNotificationWithRetry(message *Message, retries int) (*Response, error) {
+	log.Printf("Sending notification with retry: %v %v", message.Platform, message.Tokens())
+	response, err := s.SendNotification(message)
+
+	if response == nil {
+		return response, err
+	}
+
+	retryDelay := s.RetryDelay
+
+	for attempt := 0; attempt < retries; attempt++ {
+		retryTokens := []string{}
+
+		for _, result := range response.Results {
+			if isRetryable(result.Error) {
+				retryTokens = append(retryTokens, result.Token)
+			}
+		}
+
+		if len(retryTokens) == 0 {
+			break
+		}
+
+		time.Sleep(retryDelay)
+		retryDelay *= 2
+
+		retryResponse, _ := s.SendNotification(message.withTokens(retryTokens))
+
+		if retryResponse == nil {
+			continue
+		}
+
+		response = mergeResponse(response, retryResponse)
+	}
+
+	return response, response.err()
 }
 
-func (s *NotificationService) SendUserNotification(user db.User, data NotificationDto, notificationType string) {
-	message := &fcm.Message{
-		To:               user.DeviceToken.String,
-		ContentAvailable: true,
-		Data:             data,
+func (s *NotificationService) getSender(message *Message) Sender {
+	if message.Platform == PLATFORM_NOSTR {
+		// The device token of a nostr user is their npub
+		return s.NostrSender
 	}
 
-	_, err := s.SendNotificationWithRetry(message, 10)
+	if message.Platform == PLATFORM_IOS && isApnsMessage(message) {
+		// FCM cannot deliver to APNs device tokens
+		return s.ApnsSender
+	}
 
-	if err != nil {
-		// TODO: Cancel session?
-		metrics.RecordError("LNM059", "Error sending notification", err)
-		log.Printf("LNM059: Message=%v", message)
+	// FCM can also deliver to iOS devices registered with Firebase
+	if s.FcmSender != nil {
+		return s.FcmSender
 	}
 
-	RecordNotificationSent(notificationType, 1)
+	return nil
+}
+
+// splitMessage splits an iOS message into a message to APNs device tokens and
+// a message to FCM registration tokens, iOS devices registered before APNs was
+// configured still hold FCM registration tokens
+func splitMessage(message *Message) []*Message {
+	if message.Platform != PLATFORM_IOS {
+		return []*Message{message}
+	}
+
+	apnsTokens := []string{}
+	fcmTokens := []string{}
+
+	for _, token := range message.Tokens() {
+		if isApnsToken(token) {
+			apnsTokens = append(apnsTokens, token)
+		} else {
+			fcmTokens = append(fcmTokens, token)
+		}
+	}
+
+	if len(apnsTokens) == 0 || len(fcmTokens) == 0 {
+		return []*Message{message}
+	}
+
+	return []*Message{message.withTokens(apnsTokens), message.withTokens(fcmTokens)}
+}
+
+func isApnsMessage(message *Message) bool {
+	tokens := message.Tokens()
+
+	return len(tokens) > 0 && isApnsToken(tokens[0])
+}
+
+// isApnsToken returns true if the token is an APNs device token, a 32 byte
+// hex string. FCM registration tokens are longer and not hex encoded
+func isApnsToken(token string) bool {
+	if len(token) != 64 {
+		return false
+	}
+
+	_, err := hex.DecodeString(token)
+
+	return err == nil
+}
+
+func mergeResponse(response, retryResponse *Response) *Response {
+	retryResults := make(map[string]Result)
+
+	for _, result := range retryResponse.Results {
+		retryResults[result.Token] = result
+	}
+
+	mergedResponse := &Response{}
+
+	for _, result := range response.Results {
+		if retryResult, ok := retryResults[result.Token]; ok {
+			result = retryResult
+		}
+
+		mergedResponse.addResult(result)
+	}
+
+	return mergedResponse
+}
+
+func newFcmSender() Sender {
+	serviceAccountJson := []byte(os.Getenv("FCM_SERVICE_ACCOUNT_JSON"))
+
+	if serviceAccountFile := os.Getenv("FCM_SERVICE_ACCOUNT_FILE"); len(serviceAccountFile) > 0 {
+		fileBytes, err := ioutil.ReadFile(serviceAccountFile)
+		util.PanicOnError("LNM034", "Error reading FCM service account", err)
+		serviceAccountJson = fileBytes
+	}
+
+	if len(serviceAccountJson) == 0 {
+		log.Printf("FCM service account not configured")
+		return nil
+	}
+
+	fcmSender, err := NewFcmSender(os.Getenv("FCM_ENDPOINT"), serviceAccountJson)
+	util.PanicOnError("LNM177", "Invalid FCM service account", err)
+
+	return fcmSender
+}
+
+func newApnsSender() Sender {
+	keyBytes := []byte(os.Getenv("APNS_KEY"))
+
+	if keyFile := os.Getenv("APNS_KEY_FILE"); len(keyFile) > 0 {
+		fileBytes, err := ioutil.ReadFile(keyFile)
+		util.PanicOnError("LNM178", "Error reading APNs key", err)
+		keyBytes = fileBytes
+	}
+
+	if len(keyBytes) == 0 {
+		log.Printf("APNs key not configured")
+		return nil
+	}
+
+	endpoint := os.Getenv("APNS_ENDPOINT")
+
+	if len(endpoint) == 0 && !util.GetEnvBool("APNS_PRODUCTION", true) {
+		endpoint = APNS_ENDPOINT_SANDBOX
+	}
+
+	apnsSender, err := NewApnsSender(endpoint, keyBytes, os.Getenv("APNS_KEY_ID"), os.Getenv("APNS_TEAM_ID"), os.Getenv("APNS_TOPIC"))
+	util.PanicOnError("LNM179", "Invalid APNs key", err)
+
+	return apnsSender
+}
+
+func newNostrSender() Sender {
+	privateKey := os.Getenv("NOSTR_PRIVATE_KEY")
+
+	if len(privateKey) == 0 {
+		log.Printf("Nostr private key not configured")
+		return nil
+	}
+
+	nostrSender, err := NewNostrSender(privateKey, lnmUtil.GetEnvStrings("NOSTR_RELAYS"), os.Getenv("NOSTR_ENCRYPTION"))
+	util.PanicOnError("LNM205", "Invalid nostr configuration", err)
+
+	return nostrSender
 }
@@ -0,0 +1,233 @@
+package notification_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/satimoto/go-lnm/internal/notification"
+	notificationMocks "github.com/satimoto/go-lnm/internal/notification/mocks"
+)
+
+const (
+	APNS_TOKEN_0001 = "0f1e2d3c4b5a69780f1e2d3c4b5a69780f1e2d3c4b5a69780f1e2d3c4b5a6901"
+	APNS_TOKEN_0002 = "0f1e2d3c4b5a69780f1e2d3c4b5a69780f1e2d3c4b5a69780f1e2d3c4b5a6902"
+)
+
+func TestSendNotification(t *testing.T) {
+	cases := []struct {
+		desc        string
+		before      func(*notificationMocks.MockPushServer)
+		message     *notification.Message
+		platform    string
+		platforms   map[string]string
+		success     int
+		failure     int
+		requests    int
+		resultError error
+		err         error
+	}{{
+		desc: "Android notification",
+		message: &notification.Message{
+			Platform:         notification.PLATFORM_ANDROID,
+			To:               "TOKEN0001",
+			ContentAvailable: true,
+			Data:             map[string]interface{}{"type": notification.SESSION_UPDATE, "sessionInvoiceId": 1},
+		},
+		platform: notificationMocks.PLATFORM_FCM,
+		success:  1,
+		requests: 1,
+	}, {
+		desc: "iOS notification",
+		message: &notification.Message{
+			Platform: notification.PLATFORM_IOS,
+			To:       APNS_TOKEN_0001,
+			Notification: &notification.MessageNotification{
+				Title: "Title",
+				Body:  "Body",
+			},
+		},
+		platform: notificationMocks.PLATFORM_APNS,
+		success:  1,
+		requests: 1,
+	}, {
+		desc: "iOS notification to FCM token",
+		message: &notification.Message{
+			Platform: notification.PLATFORM_IOS,
+			To:       "TOKEN0001",
+		},
+		platform: notificationMocks.PLATFORM_FCM,
+		success:  1,
+		requests: 1,
+	}, {
+		desc: "iOS notification to APNs and FCM tokens",
+		message: &notification.Message{
+			Platform:        notification.PLATFORM_IOS,
+			RegistrationIDs: []string{APNS_TOKEN_0001, "TOKEN0001", APNS_TOKEN_0002},
+		},
+		platforms: map[string]string{
+			APNS_TOKEN_0001: notificationMocks.PLATFORM_APNS,
+			APNS_TOKEN_0002: notificationMocks.PLATFORM_APNS,
+			"TOKEN0001":     notificationMocks.PLATFORM_FCM,
+		},
+		success:  3,
+		requests: 3,
+	}, {
+		desc: "Unknown platform",
+		message: &notification.Message{
+			RegistrationIDs: []string{"TOKEN0001", "TOKEN0002"},
+		},
+		platform: notificationMocks.PLATFORM_FCM,
+		success:  2,
+		requests: 2,
+	}, {
+		desc: "Unregistered token",
+		before: func(mockPushServer *notificationMocks.MockPushServer) {
+			mockPushServer.SetTokenError("TOKEN0002", "UNREGISTERED")
+		},
+		message: &notification.Message{
+			Platform:        notification.PLATFORM_ANDROID,
+			RegistrationIDs: []string{"TOKEN0001", "TOKEN0002"},
+		},
+		platform:    notificationMocks.PLATFORM_FCM,
+		success:     1,
+		failure:     1,
+		requests:    2,
+		resultError: notification.ErrUnregistered,
+	}, {
+		desc: "Bad device token",
+		before: func(mockPushServer *notificationMocks.MockPushServer) {
+			mockPushServer.SetTokenError(APNS_TOKEN_0001, "BadDeviceToken")
+		},
+		message: &notification.Message{
+			Platform: notification.PLATFORM_IOS,
+			To:       APNS_TOKEN_0001,
+		},
+		platform:    notificationMocks.PLATFORM_APNS,
+		failure:     1,
+		requests:    1,
+		resultError: notification.ErrInvalidToken,
+		err:         notification.ErrInvalidToken,
+	}, {
+		desc: "Invalid registration token",
+		before: func(mockPushServer *notificationMocks.MockPushServer) {
+			mockPushServer.SetTokenError("TOKEN0001", "INVALID_ARGUMENT")
+		},
+		message: &notification.Message{
+			Platform: notification.PLATFORM_ANDROID,
+			To:       "TOKEN0001",
+		},
+		platform:    notificationMocks.PLATFORM_FCM,
+		failure:     1,
+		requests:    1,
+		resultError: notification.ErrInvalidToken,
+		err:         notification.ErrInvalidToken,
+	}, {
+		desc: "Invalid payload",
+		before: func(mockPushServer *notificationMocks.MockPushServer) {
+			mockPushServer.SetTokenError("TOKEN0001", "INVALID_PAYLOAD")
+		},
+		message: &notification.Message{
+			Platform: notification.PLATFORM_ANDROID,
+			To:       "TOKEN0001",
+		},
+		platform:    notificationMocks.PLATFORM_FCM,
+		failure:     1,
+		requests:    1,
+		resultError: notification.ErrInvalidMessage,
+		err:         notification.ErrInvalidMessage,
+	}, {
+		desc: "Retry unavailable",
+		before: func(mockPushServer *notificationMocks.MockPushServer) {
+			mockPushServer.SetTokenUnavailable("TOKEN0002", 2)
+		},
+		message: &notification.Message{
+			Platform:        notification.PLATFORM_ANDROID,
+			RegistrationIDs: []string{"TOKEN0001", "TOKEN0002"},
+		},
+		platform: notificationMocks.PLATFORM_FCM,
+		success:  2,
+		requests: 4,
+	}, {
+		desc: "Retries exhausted",
+		before: func(mockPushServer *notificationMocks.MockPushServer) {
+			mockPushServer.SetTokenUnavailable(APNS_TOKEN_0001, 5)
+		},
+		message: &notification.Message{
+			Platform: notification.PLATFORM_IOS,
+			To:       APNS_TOKEN_0001,
+		},
+		platform:    notificationMocks.PLATFORM_APNS,
+		failure:     1,
+		requests:    4,
+		resultError: notification.ErrUnavailable,
+		err:         notification.ErrUnavailable,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			mockPushServer := notificationMocks.NewPushServer()
+			defer mockPushServer.Close()
+
+			if tc.before != nil {
+				tc.before(mockPushServer)
+			}
+
+			fcmSender, err := notification.NewFcmSender(mockPushServer.URL, mockPushServer.ServiceAccountJson)
+
+			if err != nil {
+				t.Fatalf("Error creating FCM sender: %v", err)
+			}
+
+			apnsSender, err := notification.NewApnsSender(mockPushServer.URL, mockPushServer.ApnsKey, "KEY0001", "TEAM0001", "com.satimoto")
+
+			if err != nil {
+				t.Fatalf("Error creating APNs sender: %v", err)
+			}
+
+			notificationService := &notification.NotificationService{
+				FcmSender:  fcmSender,
+				ApnsSender: apnsSender,
+				RetryDelay: time.Millisecond,
+			}
+
+			response, err := notificationService.SendNotificationWithRetry(tc.message, 3)
+
+			if !errors.Is(err, tc.err) {
+				t.Errorf("Error mismatch: %v expecting %v", err, tc.err)
+			}
+
+			if response.Success != tc.success || response.Failure != tc.failure {
+				t.Errorf("Response mismatch: %v/%v expecting %v/%v", response.Success, response.Failure, tc.success, tc.failure)
+			}
+
+			for _, result := range response.Results {
+				if result.Error == nil && len(result.MessageID) == 0 {
+					t.Errorf("Message ID missing: %v", result.Token)
+				}
+
+				if result.Error != nil && !errors.Is(result.Error, tc.resultError) {
+					t.Errorf("Result error mismatch: %v expecting %v", result.Error, tc.resultError)
+				}
+			}
+
+			requests := mockPushServer.GetRequests()
+
+			if len(requests) != tc.requests {
+				t.Errorf("Request count mismatch: %v expecting %v", len(requests), tc.requests)
+			}
+
+			for _, request := range requests {
+				platform := tc.platform
+
+				if tc.platforms != nil {
+					platform = tc.platforms[request.Token]
+				}
+
+				if request.Platform != platform {
+					t.Errorf("Platform mismatch: %v expecting %v", request.Platform, platform)
+				}
+			}
+		})
+	}
+}
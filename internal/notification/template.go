@@ -0,0 +1,178 @@
+package notification
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+var ErrTemplateNotFound = errors.New("notification template not found")
+
+type Template struct {
+	Title string
+	Body  string
+}
+
+type Locale struct {
+	Templates map[string]Template
+}
+
+type parsedTemplate struct {
+	title *template.Template
+	body  *template.Template
+}
+
+var locales = map[string]Locale{
+	"en": {
+		Templates: map[string]Template{
+			INVOICE_REQUEST: {
+				Title: "You've received satoshis!",
+				Body:  "Open the app to collect the satoshis you've received.",
+			},
+			INVOICE_REQUEST_REMINDER: {
+				Title: "Ohms! Collect your satoshis!",
+				Body:  "You've received some satoshis from others charging their vehicles! Open the app to collect them.",
+			},
+		},
+	},
+	"de": {
+		Templates: map[string]Template{
+			INVOICE_REQUEST: {
+				Title: "Du hast Satoshis erhalten!",
+				Body:  "Öffne die App, um deine erhaltenen Satoshis abzuholen.",
+			},
+			INVOICE_REQUEST_REMINDER: {
+				Title: "Ohms! Hol dir deine Satoshis!",
+				Body:  "Du hast Satoshis von anderen erhalten, die ihre Fahrzeuge laden! Öffne die App, um sie abzuholen.",
+			},
+		},
+	},
+	"es": {
+		Templates: map[string]Template{
+			INVOICE_REQUEST: {
+				Title: "¡Has recibido satoshis!",
+				Body:  "Abre la aplicación para recoger los satoshis que has recibido.",
+			},
+			INVOICE_REQUEST_REMINDER: {
+				Title: "¡Ohms! ¡Recoge tus satoshis!",
+				Body:  "¡Has recibido satoshis de otras personas que cargan sus vehículos! Abre la aplicación para recogerlos.",
+			},
+		},
+	},
+	"fr": {
+		Templates: map[string]Template{
+			INVOICE_REQUEST: {
+				Title: "Vous avez reçu des satoshis !",
+				Body:  "Ouvrez l'application pour récupérer les satoshis reçus.",
+			},
+			INVOICE_REQUEST_REMINDER: {
+				Title: "Ohms ! Récupérez vos satoshis !",
+				Body:  "Vous avez reçu des satoshis d'autres personnes qui rechargent leur véhicule ! Ouvrez l'application pour les récupérer.",
+			},
+		},
+	},
+	"nl": {
+		Templates: map[string]Template{
+			INVOICE_REQUEST: {
+				Title: "Je hebt satoshi's ontvangen!",
+				Body:  "Open de app om de ontvangen satoshi's op te halen.",
+			},
+			INVOICE_REQUEST_REMINDER: {
+				Title: "Ohms! Haal je satoshi's op!",
+				Body:  "Je hebt satoshi's ontvangen van anderen die hun voertuig opladen! Open de app om ze op te halen.",
+			},
+		},
+	},
+}
+
+// dataOnlyTypes are delivered silently to update the app, a visible alert
+// for each session update or invoice would flood the user while charging
+var dataOnlyTypes = map[string]bool{
+	SESSION_INVOICE: true,
+	SESSION_UPDATE:  true,
+}
+
+var parsedTemplates = parseTemplates()
+
+// GetLocale returns the supported locale for a language tag such as
+// "de", "de-AT" or "pt_BR", falling back to the default language
+func GetLocale(language string) string {
+	language = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(language), "_", "-"))
+
+	if _, ok := locales[language]; ok {
+		return language
+	}
+
+	if i := strings.Index(language, "-"); i > 0 {
+		if _, ok := locales[language[:i]]; ok {
+			return language[:i]
+		}
+	}
+
+	return DEFAULT_LANGUAGE
+}
+
+// LocalizeNotification renders the title and body of a notification type
+// in the language of the user, data only types have no notification
+func LocalizeNotification(notificationType, language string, data interface{}) (*MessageNotification, error) {
+	if dataOnlyTypes[notificationType] {
+		return nil, nil
+	}
+
+	locale := GetLocale(language)
+	localeTemplate, ok := parsedTemplates[locale][notificationType]
+
+	if !ok {
+		if localeTemplate, ok = parsedTemplates[DEFAULT_LANGUAGE][notificationType]; !ok {
+			return nil, ErrTemplateNotFound
+		}
+	}
+
+	title, err := executeTemplate(localeTemplate.title, data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := executeTemplate(localeTemplate.body, data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &MessageNotification{
+		Title: title,
+		Body:  body,
+	}, nil
+}
+
+func executeTemplate(t *template.Template, data interface{}) (string, error) {
+	var buffer bytes.Buffer
+
+	if err := t.Execute(&buffer, data); err != nil {
+		return "", err
+	}
+
+	return buffer.String(), nil
+}
+
+func parseTemplates() map[string]map[string]parsedTemplate {
+	parsed := make(map[string]map[string]parsedTemplate)
+
+	for locale, l := range locales {
+		parsed[locale] = make(map[string]parsedTemplate)
+
+		for notificationType, t := range l.Templates {
+			name := fmt.Sprintf("%s.%s", locale, notificationType)
+
+			parsed[locale][notificationType] = parsedTemplate{
+				title: template.Must(template.New(name + ".title").Parse(t.Title)),
+				body:  template.Must(template.New(name + ".body").Parse(t.Body)),
+			}
+		}
+	}
+
+	return parsed
+}
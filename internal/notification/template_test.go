@@ -0,0 +1,106 @@
+package notification_test
+
+import (
+	"testing"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+	"github.com/satimoto/go-lnm/internal/notification"
+)
+
+func TestGetLocale(t *testing.T) {
+	cases := []struct {
+		language string
+		locale   string
+	}{
+		{language: "", locale: "en"},
+		{language: "de", locale: "de"},
+		{language: "de-AT", locale: "de"},
+		{language: "nl_BE", locale: "nl"},
+		{language: "FR", locale: "fr"},
+		{language: "ja", locale: "en"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.language, func(t *testing.T) {
+			if locale := notification.GetLocale(tc.language); locale != tc.locale {
+				t.Errorf("Locale mismatch: %v expecting %v", locale, tc.locale)
+			}
+		})
+	}
+}
+
+func TestLocalizeNotification(t *testing.T) {
+	cases := []struct {
+		desc             string
+		notificationType string
+		language         string
+		data             interface{}
+		title            string
+		body             string
+		dataOnly         bool
+		err              error
+	}{{
+		desc:             "Invoice request reminder",
+		notificationType: notification.INVOICE_REQUEST_REMINDER,
+		language:         "en",
+		title:            "Ohms! Collect your satoshis!",
+		body:             "You've received some satoshis from others charging their vehicles! Open the app to collect them.",
+	}, {
+		desc:             "Invoice request in default language",
+		notificationType: notification.INVOICE_REQUEST,
+		language:         "ja",
+		title:            "You've received satoshis!",
+		body:             "Open the app to collect the satoshis you've received.",
+	}, {
+		desc:             "Invoice request reminder localized",
+		notificationType: notification.INVOICE_REQUEST_REMINDER,
+		language:         "de-DE",
+		title:            "Ohms! Hol dir deine Satoshis!",
+		body:             "Du hast Satoshis von anderen erhalten, die ihre Fahrzeuge laden! Öffne die App, um sie abzuholen.",
+	}, {
+		desc:             "Session invoice is data only",
+		notificationType: notification.SESSION_INVOICE,
+		language:         "de-DE",
+		data:             notification.CreateSessionInvoiceNotificationDto(db.Session{}, db.SessionInvoice{MeteredEnergy: 12.345}),
+		dataOnly:         true,
+	}, {
+		desc:             "Session update is data only",
+		notificationType: notification.SESSION_UPDATE,
+		language:         "es",
+		data:             notification.CreateSessionUpdateNotificationDto(db.Session{Status: "ACTIVE"}),
+		dataOnly:         true,
+	}, {
+		desc:             "Unknown type",
+		notificationType: "UNKNOWN",
+		language:         "en",
+		err:              notification.ErrTemplateNotFound,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			messageNotification, err := notification.LocalizeNotification(tc.notificationType, tc.language, tc.data)
+
+			if err != tc.err {
+				t.Fatalf("Error mismatch: %v expecting %v", err, tc.err)
+			}
+
+			if tc.dataOnly {
+				if messageNotification != nil {
+					t.Errorf("Unexpected notification: %#v", messageNotification)
+				}
+
+				return
+			}
+
+			if err == nil {
+				if messageNotification.Title != tc.title {
+					t.Errorf("Title mismatch: %v expecting %v", messageNotification.Title, tc.title)
+				}
+
+				if messageNotification.Body != tc.body {
+					t.Errorf("Body mismatch: %v expecting %v", messageNotification.Body, tc.body)
+				}
+			}
+		})
+	}
+}
@@ -1,7 +1,18 @@
 package notification
 
 const (
-	INVOICE_REQUEST = "INVOICE_REQUEST"
-	SESSION_INVOICE = "SESSION_INVOICE"
-	SESSION_UPDATE  = "SESSION_UPDATE"
+	INVOICE_REQUEST          = "INVOICE_REQUEST"
+	INVOICE_REQUEST_REMINDER = "INVOICE_REQUEST_REMINDER"
+	SESSION_INVOICE          = "SESSION_INVOICE"
+	SESSION_UPDATE           = "SESSION_UPDATE"
+)
+
+const (
+	PLATFORM_ANDROID = "ANDROID"
+	PLATFORM_IOS     = "IOS"
+	PLATFORM_NOSTR   = "NOSTR"
+)
+
+const (
+	DEFAULT_LANGUAGE = "en"
 )
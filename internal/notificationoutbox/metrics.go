@@ -0,0 +1,25 @@
+package notificationoutbox
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricNotificationOutboxQueuedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lsp_notification_outbox_queued_total",
+		Help: "The total number of notifications queued in the outbox",
+	}, []string{"type"})
+	metricNotificationOutboxDeliveredTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lsp_notification_outbox_delivered_total",
+		Help: "The total number of outbox notifications delivered",
+	}, []string{"type"})
+	metricNotificationOutboxFailedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lsp_notification_outbox_failed_total",
+		Help: "The total number of outbox notifications that permanently failed",
+	}, []string{"type"})
+	metricNotificationOutboxRemovedTokensTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "lsp_notification_outbox_removed_tokens_total",
+		Help: "The total number of invalid device tokens removed",
+	})
+)
@@ -0,0 +1,20 @@
+package mocks
+
+import (
+	mocks "github.com/satimoto/go-datastore/pkg/db/mocks"
+	notificationoutboxMocks "github.com/satimoto/go-datastore/pkg/notificationoutbox/mocks"
+	userMocks "github.com/satimoto/go-datastore/pkg/user/mocks"
+	webhookendpointMocks "github.com/satimoto/go-datastore/pkg/webhookendpoint/mocks"
+	"github.com/satimoto/go-lnm/internal/notificationoutbox"
+	"github.com/satimoto/go-lnm/internal/service"
+)
+
+func NewResolver(repositoryService *mocks.MockRepositoryService, services *service.ServiceResolver) *notificationoutbox.NotificationOutboxResolver {
+	return &notificationoutbox.NotificationOutboxResolver{
+		Repository:          notificationoutboxMocks.NewRepository(repositoryService),
+		NotificationService: services.NotificationService,
+		UserRepository:      userMocks.NewRepository(repositoryService),
+		WebhookRepository:   webhookendpointMocks.NewRepository(repositoryService),
+		WebhookService:      services.WebhookService,
+	}
+}
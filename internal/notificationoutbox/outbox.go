@@ -0,0 +1,220 @@
+package notificationoutbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+	"github.com/satimoto/go-datastore/pkg/param"
+	dbUtil "github.com/satimoto/go-datastore/pkg/util"
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+	"github.com/satimoto/go-lnm/internal/notification"
+)
+
+var ErrNoDeviceToken = errors.New("user has no device token")
+
+func (r *NotificationOutboxResolver) QueueUserNotification(ctx context.Context, user db.User, dto notification.NotificationDto, notificationType string) (*db.NotificationOutbox, error) {
+	payload, err := json.Marshal(dto)
+
+	if err != nil {
+		metrics.RecordError("LNM180", "Error encoding notification payload", err)
+		log.Printf("LNM180: UserID=%v, Dto=%#v", user.ID, dto)
+		return nil, err
+	}
+
+	// Localize when queued so the notification is in the language the user had at the time
+	messageNotification, err := notification.LocalizeNotification(notificationType, user.Language.String, dto)
+
+	if err != nil {
+		metrics.RecordError("LNM195", "Error localizing notification", err)
+		log.Printf("LNM195: UserID=%v, NotificationType=%v", user.ID, notificationType)
+		return nil, err
+	}
+
+	createNotificationOutboxParams := db.CreateNotificationOutboxParams{
+		UserID:           user.ID,
+		Channel:          db.NotificationOutboxChannelTypePUSH,
+		NotificationType: notificationType,
+		Platform:         user.DevicePlatform,
+		DeviceToken:      user.DeviceToken,
+		Payload:          string(payload),
+		Status:           db.NotificationOutboxStatusTypePENDING,
+		NextAttemptDate:  time.Now(),
+	}
+
+	if messageNotification != nil {
+		createNotificationOutboxParams.Title = dbUtil.SqlNullString(messageNotification.Title)
+		createNotificationOutboxParams.Body = dbUtil.SqlNullString(messageNotification.Body)
+	}
+
+	if !user.DeviceToken.Valid {
+		// Record the notification so it is visible as undelivered
+		createNotificationOutboxParams.Status = db.NotificationOutboxStatusTypeFAILED
+		createNotificationOutboxParams.LastError = dbUtil.SqlNullString(ErrNoDeviceToken.Error())
+	}
+
+	notificationOutbox, err := r.Repository.CreateNotificationOutbox(ctx, createNotificationOutboxParams)
+
+	if err != nil {
+		metrics.RecordError("LNM181", "Error creating notification outbox", err)
+		log.Printf("LNM181: Params=%#v", createNotificationOutboxParams)
+		return nil, err
+	}
+
+	metricNotificationOutboxQueuedTotal.WithLabelValues(notificationType).Inc()
+
+	return &notificationOutbox, nil
+}
+
+func (r *NotificationOutboxResolver) ListUndeliveredNotifications(ctx context.Context, userID int64) ([]db.NotificationOutbox, error) {
+	return r.Repository.ListUndeliveredNotificationOutboxesByUserID(ctx, userID)
+}
+
+// ClaimPendingNotifications claims a batch of due notifications by moving their
+// next attempt past the claim timeout. Rows locked by another instance are
+// skipped, so each notification is dispatched by one instance at a time and is
+// due again if the claiming instance stops before dispatching it
+func (r *NotificationOutboxResolver) ClaimPendingNotifications(ctx context.Context, limit int32, claimTimeout time.Duration) ([]db.NotificationOutbox, error) {
+	now := time.Now()
+	claimPendingNotificationOutboxesParams := db.ClaimPendingNotificationOutboxesParams{
+		NextAttemptDate: now,
+		ClaimedUntil:    now.Add(claimTimeout),
+		Limit:           limit,
+	}
+
+	return r.Repository.ClaimPendingNotificationOutboxes(ctx, claimPendingNotificationOutboxesParams)
+}
+
+func (r *NotificationOutboxResolver) DispatchNotification(ctx context.Context, notificationOutbox db.NotificationOutbox, maxAttempts int32) {
+	if notificationOutbox.Channel == db.NotificationOutboxChannelTypeWEBHOOK {
+		r.dispatchWebhook(ctx, notificationOutbox, maxAttempts)
+		return
+	}
+
+	data := make(map[string]interface{})
+
+	if err := json.Unmarshal([]byte(notificationOutbox.Payload), &data); err != nil {
+		metrics.RecordError("LNM182", "Error decoding notification payload", err)
+		log.Printf("LNM182: NotificationOutboxID=%v", notificationOutbox.ID)
+		r.updateNotificationOutbox(ctx, notificationOutbox, "", err, true)
+		return
+	}
+
+	message := &notification.Message{
+		Platform:         notificationOutbox.Platform.String,
+		To:               notificationOutbox.DeviceToken.String,
+		ContentAvailable: true,
+		Priority:         "high",
+		Data:             data,
+	}
+
+	if notificationOutbox.Title.Valid {
+		message.Notification = &notification.MessageNotification{
+			Title: notificationOutbox.Title.String,
+			Body:  notificationOutbox.Body.String,
+		}
+	}
+
+	response, err := r.NotificationService.SendNotification(message)
+
+	if err == nil && response != nil && len(response.Results) > 0 {
+		// A single token is sent, use its result
+		err = response.Results[0].Error
+	}
+
+	if err == nil {
+		messageID := ""
+
+		if response != nil && len(response.Results) > 0 {
+			messageID = response.Results[0].MessageID
+		}
+
+		r.updateNotificationOutbox(ctx, notificationOutbox, messageID, nil, false)
+		notification.RecordNotificationSent(notificationOutbox.NotificationType, 1)
+		metricNotificationOutboxDeliveredTotal.WithLabelValues(notificationOutbox.NotificationType).Inc()
+		return
+	}
+
+	invalidToken := errors.Is(err, notification.ErrUnregistered) || errors.Is(err, notification.ErrInvalidToken)
+
+	if invalidToken {
+		r.removeDeviceToken(ctx, notificationOutbox)
+	}
+
+	isFinal := invalidToken || notificationOutbox.Attempts+1 >= maxAttempts
+	r.updateNotificationOutbox(ctx, notificationOutbox, "", err, isFinal)
+
+	if isFinal {
+		metrics.RecordError("LNM059", "Error sending notification", err)
+		log.Printf("LNM059: NotificationOutboxID=%v, Attempts=%v", notificationOutbox.ID, notificationOutbox.Attempts+1)
+		metricNotificationOutboxFailedTotal.WithLabelValues(notificationOutbox.NotificationType).Inc()
+	}
+}
+
+func (r *NotificationOutboxResolver) removeDeviceToken(ctx context.Context, notificationOutbox db.NotificationOutbox) {
+	user, err := r.UserRepository.GetUser(ctx, notificationOutbox.UserID)
+
+	if err != nil {
+		metrics.RecordError("LNM183", "Error retrieving user", err)
+		log.Printf("LNM183: UserID=%v", notificationOutbox.UserID)
+		return
+	}
+
+	// Only remove the token if the user has not since registered a new one
+	if user.DeviceToken.Valid && user.DeviceToken.String == notificationOutbox.DeviceToken.String {
+		updateUserParams := param.NewUpdateUserParams(user)
+		updateUserParams.DeviceToken = sql.NullString{}
+
+		if _, err := r.UserRepository.UpdateUser(ctx, updateUserParams); err != nil {
+			metrics.RecordError("LNM184", "Error updating user", err)
+			log.Printf("LNM184: Params=%#v", updateUserParams)
+			return
+		}
+
+		log.Printf("Removed invalid device token for user %v", user.ID)
+		metricNotificationOutboxRemovedTokensTotal.Inc()
+	}
+}
+
+func (r *NotificationOutboxResolver) updateNotificationOutbox(ctx context.Context, notificationOutbox db.NotificationOutbox, messageID string, sendErr error, isFinal bool) {
+	updateNotificationOutboxParams := param.NewUpdateNotificationOutboxParams(notificationOutbox)
+	updateNotificationOutboxParams.Attempts = notificationOutbox.Attempts + 1
+
+	switch {
+	case sendErr == nil:
+		updateNotificationOutboxParams.Status = db.NotificationOutboxStatusTypeSENT
+		updateNotificationOutboxParams.MessageID = dbUtil.SqlNullString(messageID)
+		updateNotificationOutboxParams.LastError = sql.NullString{}
+		updateNotificationOutboxParams.SentDate = dbUtil.SqlNullTime(time.Now())
+	case isFinal:
+		updateNotificationOutboxParams.Status = db.NotificationOutboxStatusTypeFAILED
+		updateNotificationOutboxParams.LastError = dbUtil.SqlNullString(sendErr.Error())
+	default:
+		updateNotificationOutboxParams.Status = db.NotificationOutboxStatusTypePENDING
+		updateNotificationOutboxParams.LastError = dbUtil.SqlNullString(sendErr.Error())
+		updateNotificationOutboxParams.NextAttemptDate = time.Now().Add(getRetryDelay(updateNotificationOutboxParams.Attempts))
+	}
+
+	if _, err := r.Repository.UpdateNotificationOutbox(ctx, updateNotificationOutboxParams); err != nil {
+		metrics.RecordError("LNM185", "Error updating notification outbox", err)
+		log.Printf("LNM185: Params=%#v", updateNotificationOutboxParams)
+	}
+}
+
+func getRetryDelay(attempts int32) time.Duration {
+	delay := 30 * time.Second
+
+	for i := int32(1); i < attempts && delay < time.Hour; i++ {
+		delay *= 2
+	}
+
+	if delay > time.Hour {
+		return time.Hour
+	}
+
+	return delay
+}
@@ -0,0 +1,158 @@
+package notificationoutbox_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+	dbMocks "github.com/satimoto/go-datastore/pkg/db/mocks"
+	dbUtil "github.com/satimoto/go-datastore/pkg/util"
+	ferpMocks "github.com/satimoto/go-lnm/internal/ferp/mocks"
+	lightningnetworkMocks "github.com/satimoto/go-lnm/internal/lightningnetwork/mocks"
+	"github.com/satimoto/go-lnm/internal/notification"
+	notificationMocks "github.com/satimoto/go-lnm/internal/notification/mocks"
+	notificationoutboxMocks "github.com/satimoto/go-lnm/internal/notificationoutbox/mocks"
+	serviceMocks "github.com/satimoto/go-lnm/internal/service/mocks"
+	ocpiMocks "github.com/satimoto/go-ocpi/pkg/ocpi/mocks"
+)
+
+func TestDispatchNotification(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		desc        string
+		attempts    int32
+		result      notification.Result
+		status      db.NotificationOutboxStatusType
+		retryDelay  time.Duration
+		removeToken bool
+	}{{
+		desc:   "Delivered",
+		result: notification.Result{Token: "TOKEN0001", MessageID: "MESSAGE0001"},
+		status: db.NotificationOutboxStatusTypeSENT,
+	}, {
+		desc:       "Retry with backoff",
+		attempts:   2,
+		result:     notification.Result{Token: "TOKEN0001", Error: notification.ErrUnavailable},
+		status:     db.NotificationOutboxStatusTypePENDING,
+		retryDelay: 2 * time.Minute,
+	}, {
+		desc:       "Retry delay capped",
+		attempts:   8,
+		result:     notification.Result{Token: "TOKEN0001", Error: notification.ErrUnavailable},
+		status:     db.NotificationOutboxStatusTypePENDING,
+		retryDelay: time.Hour,
+	}, {
+		desc:     "Dead lettered after max attempts",
+		attempts: 9,
+		result:   notification.Result{Token: "TOKEN0001", Error: notification.ErrUnavailable},
+		status:   db.NotificationOutboxStatusTypeFAILED,
+	}, {
+		desc:        "Invalid token",
+		result:      notification.Result{Token: "TOKEN0001", Error: notification.ErrInvalidToken},
+		status:      db.NotificationOutboxStatusTypeFAILED,
+		removeToken: true,
+	}, {
+		desc:        "Unregistered token",
+		attempts:    3,
+		result:      notification.Result{Token: "TOKEN0001", Error: notification.ErrUnregistered},
+		status:      db.NotificationOutboxStatusTypeFAILED,
+		removeToken: true,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			mockRepository := dbMocks.NewMockRepositoryService()
+			mockNotificationService := notificationMocks.NewService()
+			mockServices := serviceMocks.NewService(ferpMocks.NewService(), lightningnetworkMocks.NewService(), mockNotificationService, ocpiMocks.NewService())
+			notificationOutboxResolver := notificationoutboxMocks.NewResolver(mockRepository, mockServices)
+
+			mockNotificationService.SetSendNotificationMockData(&notification.Response{
+				Results: []notification.Result{tc.result},
+			})
+
+			mockRepository.SetGetUserMockData(dbMocks.UserMockData{User: db.User{
+				ID:             1,
+				DeviceToken:    dbUtil.SqlNullString("TOKEN0001"),
+				DevicePlatform: dbUtil.SqlNullString(notification.PLATFORM_ANDROID),
+			}})
+
+			before := time.Now()
+			notificationOutboxResolver.DispatchNotification(ctx, db.NotificationOutbox{
+				ID:               1,
+				UserID:           1,
+				Channel:          db.NotificationOutboxChannelTypePUSH,
+				NotificationType: notification.SESSION_UPDATE,
+				Platform:         dbUtil.SqlNullString(notification.PLATFORM_ANDROID),
+				DeviceToken:      dbUtil.SqlNullString("TOKEN0001"),
+				Payload:          `{"type":"SESSION_UPDATE"}`,
+				Status:           db.NotificationOutboxStatusTypePENDING,
+				Attempts:         tc.attempts,
+			}, 10)
+
+			updateNotificationOutboxParams, err := mockRepository.GetUpdateNotificationOutboxMockData()
+
+			if err != nil {
+				t.Fatalf("Expected notification outbox update: %v", err)
+			}
+
+			if updateNotificationOutboxParams.Status != tc.status {
+				t.Errorf("Status mismatch: %v expecting %v", updateNotificationOutboxParams.Status, tc.status)
+			}
+
+			if updateNotificationOutboxParams.Attempts != tc.attempts+1 {
+				t.Errorf("Attempts mismatch: %v expecting %v", updateNotificationOutboxParams.Attempts, tc.attempts+1)
+			}
+
+			if tc.retryDelay > 0 {
+				retryDelay := updateNotificationOutboxParams.NextAttemptDate.Sub(before)
+
+				if retryDelay < tc.retryDelay || retryDelay > tc.retryDelay+time.Minute {
+					t.Errorf("Retry delay mismatch: %v expecting %v", retryDelay, tc.retryDelay)
+				}
+			}
+
+			updateUserParams, err := mockRepository.GetUpdateUserMockData()
+
+			if tc.removeToken && (err != nil || updateUserParams.DeviceToken.Valid) {
+				t.Errorf("Expected device token removed: %v, %v", updateUserParams.DeviceToken, err)
+			}
+
+			if !tc.removeToken && err == nil {
+				t.Errorf("Unexpected user update: %#v", updateUserParams)
+			}
+		})
+	}
+}
+
+func TestClaimPendingNotifications(t *testing.T) {
+	ctx := context.Background()
+	mockRepository := dbMocks.NewMockRepositoryService()
+	mockServices := serviceMocks.NewService(ferpMocks.NewService(), lightningnetworkMocks.NewService(), notificationMocks.NewService(), ocpiMocks.NewService())
+	notificationOutboxResolver := notificationoutboxMocks.NewResolver(mockRepository, mockServices)
+
+	mockRepository.SetClaimPendingNotificationOutboxesMockData(dbMocks.NotificationOutboxesMockData{NotificationOutboxes: []db.NotificationOutbox{{
+		ID: 1,
+	}}})
+
+	before := time.Now()
+	notificationOutboxes, err := notificationOutboxResolver.ClaimPendingNotifications(ctx, 100, 5*time.Minute)
+
+	if err != nil || len(notificationOutboxes) != 1 {
+		t.Fatalf("Unexpected claim: %v, %v", notificationOutboxes, err)
+	}
+
+	claimPendingNotificationOutboxesParams, err := mockRepository.GetClaimPendingNotificationOutboxesMockData()
+
+	if err != nil {
+		t.Fatalf("Expected claim params: %v", err)
+	}
+
+	if claimPendingNotificationOutboxesParams.Limit != 100 {
+		t.Errorf("Limit mismatch: %v expecting %v", claimPendingNotificationOutboxesParams.Limit, 100)
+	}
+
+	if claimPendingNotificationOutboxesParams.ClaimedUntil.Sub(before) < 5*time.Minute {
+		t.Errorf("Claim mismatch: %v expecting after %v", claimPendingNotificationOutboxesParams.ClaimedUntil, before.Add(5*time.Minute))
+	}
+}
@@ -0,0 +1,29 @@
+package notificationoutbox
+
+import (
+	"github.com/satimoto/go-datastore/pkg/db"
+	"github.com/satimoto/go-datastore/pkg/notificationoutbox"
+	"github.com/satimoto/go-datastore/pkg/user"
+	"github.com/satimoto/go-datastore/pkg/webhookendpoint"
+	"github.com/satimoto/go-lnm/internal/notification"
+	"github.com/satimoto/go-lnm/internal/service"
+	"github.com/satimoto/go-lnm/internal/webhook"
+)
+
+type NotificationOutboxResolver struct {
+	Repository          notificationoutbox.NotificationOutboxRepository
+	NotificationService notification.Notification
+	UserRepository      user.UserRepository
+	WebhookRepository   webhookendpoint.WebhookEndpointRepository
+	WebhookService      webhook.Webhook
+}
+
+func NewResolver(repositoryService *db.RepositoryService, services *service.ServiceResolver) *NotificationOutboxResolver {
+	return &NotificationOutboxResolver{
+		Repository:          notificationoutbox.NewRepository(repositoryService),
+		NotificationService: services.NotificationService,
+		UserRepository:      user.NewRepository(repositoryService),
+		WebhookRepository:   webhookendpoint.NewRepository(repositoryService),
+		WebhookService:      services.WebhookService,
+	}
+}
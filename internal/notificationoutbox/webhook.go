@@ -0,0 +1,169 @@
+package notificationoutbox
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+	dbUtil "github.com/satimoto/go-datastore/pkg/util"
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+	"github.com/satimoto/go-lnm/internal/webhook"
+)
+
+var (
+	ErrInvalidWebhookUrl       = errors.New("webhook url must be a public https url")
+	ErrInvalidWebhookEventType = errors.New("invalid webhook event type")
+)
+
+// QueueWebhookEvent queues an event for delivery to each active endpoint
+// of the user subscribed to the event type, including operator endpoints
+func (r *NotificationOutboxResolver) QueueWebhookEvent(ctx context.Context, userID int64, eventType string, data interface{}) {
+	webhookEndpoints, err := r.WebhookRepository.ListWebhookEndpointsByEventType(ctx, db.ListWebhookEndpointsByEventTypeParams{
+		UserID:    userID,
+		EventType: eventType,
+	})
+
+	if err != nil {
+		metrics.RecordError("LNM198", "Error listing webhook endpoints", err)
+		log.Printf("LNM198: UserID=%v, EventType=%v", userID, eventType)
+		return
+	}
+
+	if len(webhookEndpoints) == 0 {
+		return
+	}
+
+	// The same event is sent to every endpoint so consumers can deduplicate by ID
+	payload, err := json.Marshal(webhook.NewEventDto(eventType, data))
+
+	if err != nil {
+		metrics.RecordError("LNM199", "Error encoding webhook payload", err)
+		log.Printf("LNM199: UserID=%v, EventType=%v", userID, eventType)
+		return
+	}
+
+	for _, webhookEndpoint := range webhookEndpoints {
+		createNotificationOutboxParams := db.CreateNotificationOutboxParams{
+			UserID:            userID,
+			Channel:           db.NotificationOutboxChannelTypeWEBHOOK,
+			WebhookEndpointID: dbUtil.SqlNullInt64(webhookEndpoint.ID),
+			NotificationType:  eventType,
+			Payload:           string(payload),
+			Status:            db.NotificationOutboxStatusTypePENDING,
+			NextAttemptDate:   time.Now(),
+		}
+
+		if _, err := r.Repository.CreateNotificationOutbox(ctx, createNotificationOutboxParams); err != nil {
+			metrics.RecordError("LNM181", "Error creating notification outbox", err)
+			log.Printf("LNM181: Params=%#v", createNotificationOutboxParams)
+			continue
+		}
+
+		metricNotificationOutboxQueuedTotal.WithLabelValues(eventType).Inc()
+	}
+}
+
+// CreateWebhookEndpoint creates an endpoint for the events of the user, or an
+// operator endpoint for the events of all users if the user ID is 0
+func (r *NotificationOutboxResolver) CreateWebhookEndpoint(ctx context.Context, userID int64, webhookUrl string, eventTypes []string) (*db.WebhookEndpoint, error) {
+	if !isValidWebhookUrl(webhookUrl) {
+		return nil, ErrInvalidWebhookUrl
+	}
+
+	if len(eventTypes) == 0 {
+		eventTypes = webhook.EventTypes
+	}
+
+	for _, eventType := range eventTypes {
+		if !webhook.IsEventType(eventType) {
+			return nil, ErrInvalidWebhookEventType
+		}
+	}
+
+	createWebhookEndpointParams := db.CreateWebhookEndpointParams{
+		Url:         webhookUrl,
+		Secret:      newWebhookSecret(),
+		EventTypes:  eventTypes,
+		IsActive:    true,
+		CreatedDate: time.Now(),
+	}
+
+	if userID > 0 {
+		createWebhookEndpointParams.UserID = dbUtil.SqlNullInt64(userID)
+	}
+
+	webhookEndpoint, err := r.WebhookRepository.CreateWebhookEndpoint(ctx, createWebhookEndpointParams)
+
+	if err != nil {
+		metrics.RecordError("LNM200", "Error creating webhook endpoint", err)
+		log.Printf("LNM200: UserID=%v, Url=%v", userID, webhookUrl)
+		return nil, err
+	}
+
+	return &webhookEndpoint, nil
+}
+
+func (r *NotificationOutboxResolver) dispatchWebhook(ctx context.Context, notificationOutbox db.NotificationOutbox, maxAttempts int32) {
+	webhookEndpoint, err := r.WebhookRepository.GetWebhookEndpoint(ctx, notificationOutbox.WebhookEndpointID.Int64)
+
+	if err != nil || !webhookEndpoint.IsActive {
+		// The endpoint has been removed or disabled since the event was queued
+		r.updateNotificationOutbox(ctx, notificationOutbox, "", webhook.ErrRejected, true)
+		metricNotificationOutboxFailedTotal.WithLabelValues(notificationOutbox.NotificationType).Inc()
+		return
+	}
+
+	err = r.WebhookService.Send(webhookEndpoint.Url, webhookEndpoint.Secret, notificationOutbox.NotificationType, []byte(notificationOutbox.Payload))
+
+	if err == nil {
+		r.updateNotificationOutbox(ctx, notificationOutbox, "", nil, false)
+		metricNotificationOutboxDeliveredTotal.WithLabelValues(notificationOutbox.NotificationType).Inc()
+		return
+	}
+
+	isFinal := errors.Is(err, webhook.ErrRejected) || notificationOutbox.Attempts+1 >= maxAttempts
+	r.updateNotificationOutbox(ctx, notificationOutbox, "", err, isFinal)
+
+	if isFinal {
+		metrics.RecordError("LNM201", "Error delivering webhook", err)
+		log.Printf("LNM201: NotificationOutboxID=%v, WebhookEndpointID=%v, Attempts=%v", notificationOutbox.ID, webhookEndpoint.ID, notificationOutbox.Attempts+1)
+		metricNotificationOutboxFailedTotal.WithLabelValues(notificationOutbox.NotificationType).Inc()
+	}
+}
+
+// isValidWebhookUrl checks the url is https and not obviously internal, the
+// resolved address is checked again when delivering
+func isValidWebhookUrl(webhookUrl string) bool {
+	parsedUrl, err := url.Parse(webhookUrl)
+
+	if err != nil || parsedUrl.Scheme != "https" || parsedUrl.Hostname() == "" {
+		return false
+	}
+
+	hostname := strings.ToLower(strings.TrimSuffix(parsedUrl.Hostname(), "."))
+
+	if hostname == "localhost" || strings.HasSuffix(hostname, ".localhost") {
+		return false
+	}
+
+	if ip := net.ParseIP(hostname); ip != nil && !webhook.IsPublicIP(ip) {
+		return false
+	}
+
+	return true
+}
+
+func newWebhookSecret() string {
+	bytes := make([]byte, 32)
+	rand.Read(bytes)
+
+	return "whsec_" + hex.EncodeToString(bytes)
+}
@@ -0,0 +1,25 @@
+package payment
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricPaymentAttemptsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "lsp_payment_attempts_total",
+		Help: "The total number of payment attempts",
+	})
+	metricPaymentsFailedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "lsp_payments_failed_total",
+		Help: "The total number of payments failed after all retries",
+	})
+	metricPaymentsSucceededTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "lsp_payments_succeeded_total",
+		Help: "The total number of payments succeeded",
+	})
+	metricPaymentsFeeSatoshis = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "lsp_payments_fee_satoshis",
+		Help: "The total routing fees paid in satoshis",
+	})
+)
@@ -0,0 +1,357 @@
+package payment
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	dbUtil "github.com/satimoto/go-datastore/pkg/util"
+	"github.com/satimoto/go-lnm/internal/lightningnetwork"
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+	"github.com/satimoto/go-lnm/pkg/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrPaymentNotFound = errors.New("payment not found")
+	ErrPaymentFailed   = errors.New("payment failed")
+)
+
+type Payment interface {
+	// SendPayment sends a payment, retrying until it succeeds, fails
+	// permanently or the context is done. The status of the payment can be
+	// queried by its reference.
+	SendPayment(ctx context.Context, reference, paymentRequest string, amountMsat int64) (*PaymentStatus, error)
+	// ResumePayment tracks a payment that may have been sent before a
+	// restart, and sends it if LND has no record of it
+	ResumePayment(ctx context.Context, reference, paymentRequest string, amountMsat int64) (*PaymentStatus, error)
+	GetPaymentStatus(reference string) (*PaymentStatus, error)
+}
+
+type PaymentConfig struct {
+	FeeLimitSat     int64
+	FeeLimitPpm     int64
+	MaxParts        uint32
+	Timeout         time.Duration
+	OutgoingChanIds []uint64
+	RetrySchedule   []time.Duration
+	StatusRetention time.Duration
+}
+
+type PaymentService struct {
+	LightningService lightningnetwork.LightningNetwork
+	Config           PaymentConfig
+	paymentStatuses  map[string]*PaymentStatus
+	mutex            sync.RWMutex
+}
+
+func NewService(lightningService lightningnetwork.LightningNetwork) Payment {
+	return NewServiceWithConfig(lightningService, NewConfig())
+}
+
+func NewServiceWithConfig(lightningService lightningnetwork.LightningNetwork, config PaymentConfig) Payment {
+	return &PaymentService{
+		LightningService: lightningService,
+		Config:           config,
+		paymentStatuses:  make(map[string]*PaymentStatus),
+	}
+}
+
+func NewConfig() PaymentConfig {
+	defaultRetrySchedule := []time.Duration{
+		30 * time.Second,
+		2 * time.Minute,
+		10 * time.Minute,
+		30 * time.Minute,
+	}
+
+	return PaymentConfig{
+		FeeLimitSat:     int64(dbUtil.GetEnvInt32("PAYMENT_FEE_LIMIT_SAT", 10)),
+		FeeLimitPpm:     int64(dbUtil.GetEnvInt32("PAYMENT_FEE_LIMIT_PPM", 5000)),
+		MaxParts:        uint32(dbUtil.GetEnvInt32("PAYMENT_MAX_PARTS", 16)),
+		Timeout:         time.Duration(dbUtil.GetEnvInt32("PAYMENT_TIMEOUT", 120)) * time.Second,
+		OutgoingChanIds: util.GetEnvUint64s("PAYMENT_OUTGOING_CHAN_IDS"),
+		RetrySchedule:   util.GetEnvDurations("PAYMENT_RETRY_SCHEDULE", defaultRetrySchedule),
+		StatusRetention: time.Duration(dbUtil.GetEnvInt32("PAYMENT_STATUS_RETENTION", 86400)) * time.Second,
+	}
+}
+
+func (s *PaymentService) SendPayment(ctx context.Context, reference, paymentRequest string, amountMsat int64) (*PaymentStatus, error) {
+	return s.sendPayment(ctx, reference, paymentRequest, amountMsat, false)
+}
+
+func (s *PaymentService) ResumePayment(ctx context.Context, reference, paymentRequest string, amountMsat int64) (*PaymentStatus, error) {
+	return s.sendPayment(ctx, reference, paymentRequest, amountMsat, true)
+}
+
+func (s *PaymentService) sendPayment(ctx context.Context, reference, paymentRequest string, amountMsat int64, inDoubt bool) (*PaymentStatus, error) {
+	/** Payment requested.
+	 *  Send the payment within the configured fee limits.
+	 *  Record the failure reasons of each attempt.
+	 *  Retry failed attempts over the retry schedule
+	 *  until the payment succeeds or fails permanently.
+	 *  After a stream error or a restart the payment may still
+	 *  be in flight, so track it before sending it again.
+	 */
+
+	paymentStatus := s.startPaymentStatus(reference, paymentRequest)
+	feeLimitMsat := s.calculateFeeLimitMsat(amountMsat)
+
+	for attempt := 0; ; attempt++ {
+		var payment *lnrpc.Payment
+		var err error
+
+		if inDoubt {
+			payment, err = s.trackPayment(paymentRequest)
+		}
+
+		if !inDoubt || (err == nil && payment == nil) {
+			payment, err = s.sendPaymentAttempt(paymentRequest, feeLimitMsat)
+		}
+
+		inDoubt = err != nil
+		retryable := s.updatePaymentStatus(paymentStatus, payment, err)
+
+		if payment != nil && payment.Status == lnrpc.Payment_SUCCEEDED {
+			metricPaymentsSucceededTotal.Inc()
+			metricPaymentsFeeSatoshis.Add(float64(payment.FeeMsat / 1000))
+
+			return s.copyPaymentStatus(paymentStatus), nil
+		}
+
+		if !retryable || attempt >= len(s.Config.RetrySchedule) {
+			break
+		}
+
+		retryDelay := s.Config.RetrySchedule[attempt]
+		log.Printf("Retrying payment %v in %v", paymentRequest, retryDelay)
+
+		s.mutex.Lock()
+		paymentStatus.NextAttempt = dbUtil.SqlNullTime(time.Now().Add(retryDelay))
+		s.mutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			// The payment may still be in flight, so leave it unresolved
+			log.Printf("Stopped retrying payment %v", paymentRequest)
+
+			s.mutex.Lock()
+			paymentStatus.NextAttempt.Valid = false
+			s.mutex.Unlock()
+
+			return s.copyPaymentStatus(paymentStatus), ctx.Err()
+		case <-time.After(retryDelay):
+		}
+	}
+
+	s.mutex.Lock()
+	paymentStatus.Status = lnrpc.Payment_FAILED
+	paymentStatus.IsFinal = true
+	paymentStatus.NextAttempt.Valid = false
+	s.mutex.Unlock()
+
+	metricPaymentsFailedTotal.Inc()
+
+	return s.copyPaymentStatus(paymentStatus), ErrPaymentFailed
+}
+
+func (s *PaymentService) GetPaymentStatus(reference string) (*PaymentStatus, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if paymentStatus, ok := s.paymentStatuses[reference]; ok {
+		return paymentStatus.copy(), nil
+	}
+
+	return nil, ErrPaymentNotFound
+}
+
+func (s *PaymentService) calculateFeeLimitMsat(amountMsat int64) int64 {
+	// The fee limit is the greater of the absolute and proportional limits,
+	// so that small payments are not starved of routes.
+	feeLimitMsat := s.Config.FeeLimitSat * 1000
+	feeLimitPpmMsat := (amountMsat * s.Config.FeeLimitPpm) / 1000000
+
+	if feeLimitPpmMsat > feeLimitMsat {
+		return feeLimitPpmMsat
+	}
+
+	return feeLimitMsat
+}
+
+func (s *PaymentService) copyPaymentStatus(paymentStatus *PaymentStatus) *PaymentStatus {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return paymentStatus.copy()
+}
+
+func (s *PaymentService) sendPaymentAttempt(paymentRequest string, feeLimitMsat int64) (*lnrpc.Payment, error) {
+	metricPaymentAttemptsTotal.Inc()
+
+	client, err := s.LightningService.SendPaymentV2(&routerrpc.SendPaymentRequest{
+		PaymentRequest:  paymentRequest,
+		TimeoutSeconds:  int32(s.Config.Timeout.Seconds()),
+		FeeLimitMsat:    feeLimitMsat,
+		MaxParts:        s.Config.MaxParts,
+		OutgoingChanIds: s.Config.OutgoingChanIds,
+	})
+
+	if err != nil {
+		metrics.RecordError("LNM124", "Error sending payment", err)
+		log.Printf("LNM124: PaymentRequest=%v", paymentRequest)
+		return nil, err
+	}
+
+	return waitForPayment(paymentRequest, client)
+}
+
+// trackPayment returns the payment once it is no longer in flight, or nil if
+// LND has no record of the payment
+func (s *PaymentService) trackPayment(paymentRequest string) (*lnrpc.Payment, error) {
+	payReq, err := s.LightningService.DecodePayReq(&lnrpc.PayReqString{PayReq: paymentRequest})
+
+	if err != nil {
+		metrics.RecordError("LNM270", "Error decoding payment request", err)
+		log.Printf("LNM270: PaymentRequest=%v", paymentRequest)
+		return nil, err
+	}
+
+	paymentHash, err := hex.DecodeString(payReq.PaymentHash)
+
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := s.LightningService.TrackPaymentV2(&routerrpc.TrackPaymentRequest{
+		PaymentHash: paymentHash,
+	})
+
+	if err == nil {
+		var payment *lnrpc.Payment
+
+		if payment, err = waitForPayment(paymentRequest, client); err == nil {
+			return payment, nil
+		}
+	}
+
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+
+	metrics.RecordError("LNM271", "Error tracking payment", err)
+	log.Printf("LNM271: PaymentRequest=%v", paymentRequest)
+
+	return nil, err
+}
+
+type paymentClient interface {
+	Recv() (*lnrpc.Payment, error)
+}
+
+// waitForPayment returns the payment once it succeeds or fails
+func waitForPayment(paymentRequest string, client paymentClient) (*lnrpc.Payment, error) {
+	for {
+		payment, err := client.Recv()
+
+		if err != nil {
+			if status.Code(err) != codes.NotFound {
+				metrics.RecordError("LNM125", "Error waiting for payment", err)
+				log.Printf("LNM125: PaymentRequest=%v", paymentRequest)
+			}
+
+			return nil, err
+		}
+
+		switch payment.Status {
+		case lnrpc.Payment_FAILED:
+			log.Printf("LNM154: PaymentRequest=%v failed: %v", paymentRequest, payment.FailureReason)
+			return payment, nil
+		case lnrpc.Payment_SUCCEEDED:
+			log.Printf("LNM155: PaymentRequest=%v succeeded", paymentRequest)
+			return payment, nil
+		}
+	}
+}
+
+func (s *PaymentService) startPaymentStatus(reference, paymentRequest string) *PaymentStatus {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.pruneStatuses()
+
+	paymentStatus := &PaymentStatus{
+		Reference:      reference,
+		PaymentRequest: paymentRequest,
+		Status:         lnrpc.Payment_IN_FLIGHT,
+		FailureReasons: []string{},
+		LastUpdated:    time.Now(),
+	}
+
+	s.paymentStatuses[reference] = paymentStatus
+
+	return paymentStatus
+}
+
+// pruneStatuses removes final statuses older than the retention period.
+// The caller must hold the mutex.
+func (s *PaymentService) pruneStatuses() {
+	expiry := time.Now().Add(-s.Config.StatusRetention)
+
+	for reference, paymentStatus := range s.paymentStatuses {
+		if paymentStatus.IsFinal && paymentStatus.LastUpdated.Before(expiry) {
+			delete(s.paymentStatuses, reference)
+		}
+	}
+}
+
+func (s *PaymentService) updatePaymentStatus(paymentStatus *PaymentStatus, payment *lnrpc.Payment, err error) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	paymentStatus.Attempts++
+	paymentStatus.LastUpdated = time.Now()
+
+	if err != nil {
+		paymentStatus.FailureReasons = append(paymentStatus.FailureReasons, err.Error())
+		return true
+	}
+
+	paymentStatus.PaymentHash = payment.PaymentHash
+	paymentStatus.Status = payment.Status
+	paymentStatus.FeeMsat = payment.FeeMsat
+
+	if payment.Status == lnrpc.Payment_SUCCEEDED {
+		paymentStatus.IsFinal = true
+		paymentStatus.NextAttempt.Valid = false
+		return false
+	}
+
+	for _, htlc := range payment.Htlcs {
+		if htlc.Status == lnrpc.HTLCAttempt_FAILED && htlc.Failure != nil {
+			failureReason := fmt.Sprintf("%v at hop %v", htlc.Failure.Code, htlc.Failure.FailureSourceIndex)
+			paymentStatus.FailureReasons = append(paymentStatus.FailureReasons, failureReason)
+		}
+	}
+
+	paymentStatus.FailureReasons = append(paymentStatus.FailureReasons, payment.FailureReason.String())
+
+	return isRetryableFailure(payment.FailureReason)
+}
+
+func isRetryableFailure(failureReason lnrpc.PaymentFailureReason) bool {
+	switch failureReason {
+	case lnrpc.PaymentFailureReason_FAILURE_REASON_INCORRECT_PAYMENT_DETAILS:
+		// The recipient rejected the payment, retrying will not help
+		return false
+	}
+
+	return true
+}
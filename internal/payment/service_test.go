@@ -0,0 +1,337 @@
+package payment_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	lightningnetworkMocks "github.com/satimoto/go-lnm/internal/lightningnetwork/mocks"
+	"github.com/satimoto/go-lnm/internal/payment"
+)
+
+type sendPaymentResult struct {
+	paymentStatus *payment.PaymentStatus
+	err           error
+}
+
+func TestSendPayment(t *testing.T) {
+	cases := []struct {
+		desc           string
+		payments       []*lnrpc.Payment
+		retrySchedule  []time.Duration
+		status         lnrpc.Payment_PaymentStatus
+		attempts       int
+		failureReasons int
+		err            error
+	}{{
+		desc: "Payment succeeded",
+		payments: []*lnrpc.Payment{{
+			PaymentHash: "TestPaymentHash",
+			Status:      lnrpc.Payment_SUCCEEDED,
+		}},
+		retrySchedule: []time.Duration{},
+		status:        lnrpc.Payment_SUCCEEDED,
+		attempts:      1,
+		err:           nil,
+	}, {
+		desc: "Payment succeeded after retry",
+		payments: []*lnrpc.Payment{{
+			PaymentHash:   "TestPaymentHash",
+			Status:        lnrpc.Payment_FAILED,
+			FailureReason: lnrpc.PaymentFailureReason_FAILURE_REASON_NO_ROUTE,
+		}, {
+			PaymentHash: "TestPaymentHash",
+			Status:      lnrpc.Payment_SUCCEEDED,
+		}},
+		retrySchedule:  []time.Duration{time.Millisecond},
+		status:         lnrpc.Payment_SUCCEEDED,
+		attempts:       2,
+		failureReasons: 1,
+		err:            nil,
+	}, {
+		desc: "Payment failed after retries",
+		payments: []*lnrpc.Payment{{
+			PaymentHash:   "TestPaymentHash",
+			Status:        lnrpc.Payment_FAILED,
+			FailureReason: lnrpc.PaymentFailureReason_FAILURE_REASON_TIMEOUT,
+		}, {
+			PaymentHash:   "TestPaymentHash",
+			Status:        lnrpc.Payment_FAILED,
+			FailureReason: lnrpc.PaymentFailureReason_FAILURE_REASON_NO_ROUTE,
+			Htlcs: []*lnrpc.HTLCAttempt{{
+				Status: lnrpc.HTLCAttempt_FAILED,
+				Failure: &lnrpc.Failure{
+					Code:               lnrpc.Failure_TEMPORARY_CHANNEL_FAILURE,
+					FailureSourceIndex: 1,
+				},
+			}},
+		}},
+		retrySchedule:  []time.Duration{time.Millisecond},
+		status:         lnrpc.Payment_FAILED,
+		attempts:       2,
+		failureReasons: 3,
+		err:            payment.ErrPaymentFailed,
+	}, {
+		desc: "Payment failed without retry",
+		payments: []*lnrpc.Payment{{
+			PaymentHash:   "TestPaymentHash",
+			Status:        lnrpc.Payment_FAILED,
+			FailureReason: lnrpc.PaymentFailureReason_FAILURE_REASON_INCORRECT_PAYMENT_DETAILS,
+		}},
+		retrySchedule:  []time.Duration{time.Millisecond},
+		status:         lnrpc.Payment_FAILED,
+		attempts:       1,
+		failureReasons: 1,
+		err:            payment.ErrPaymentFailed,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			mockLightningService := lightningnetworkMocks.NewService()
+			paymentService := payment.NewServiceWithConfig(mockLightningService, payment.PaymentConfig{
+				FeeLimitSat:   10,
+				FeeLimitPpm:   5000,
+				MaxParts:      16,
+				Timeout:       time.Minute,
+				RetrySchedule: tc.retrySchedule,
+			})
+
+			recvChans := []chan<- *lnrpc.Payment{}
+
+			for range tc.payments {
+				recvChans = append(recvChans, mockLightningService.NewSendPaymentV2MockData())
+			}
+
+			resultChan := make(chan sendPaymentResult)
+
+			go func() {
+				paymentStatus, err := paymentService.SendPayment(context.Background(), "TestReference", "TestPaymentRequest", 1000000)
+				resultChan <- sendPaymentResult{paymentStatus: paymentStatus, err: err}
+			}()
+
+			for i, p := range tc.payments {
+				recvChans[i] <- p
+			}
+
+			result := <-resultChan
+
+			if result.err != tc.err {
+				t.Errorf("Error mismatch: %v expecting %v", result.err, tc.err)
+			}
+
+			if result.paymentStatus.Status != tc.status {
+				t.Errorf("Status mismatch: %v expecting %v", result.paymentStatus.Status, tc.status)
+			}
+
+			if result.paymentStatus.Attempts != tc.attempts {
+				t.Errorf("Attempts mismatch: %v expecting %v", result.paymentStatus.Attempts, tc.attempts)
+			}
+
+			if len(result.paymentStatus.FailureReasons) != tc.failureReasons {
+				t.Errorf("Failure reasons mismatch: %v expecting %v", result.paymentStatus.FailureReasons, tc.failureReasons)
+			}
+
+			paymentStatus, err := paymentService.GetPaymentStatus("TestReference")
+
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+
+			if !paymentStatus.IsFinal {
+				t.Error("Payment status not final")
+			}
+		})
+	}
+}
+
+func TestSendPaymentInDoubt(t *testing.T) {
+	t.Run("Payment tracked after stream error", func(t *testing.T) {
+		mockLightningService := lightningnetworkMocks.NewService()
+		paymentService := payment.NewServiceWithConfig(mockLightningService, payment.PaymentConfig{
+			RetrySchedule: []time.Duration{time.Millisecond},
+		})
+
+		sendRecvChan := mockLightningService.NewSendPaymentV2MockData()
+		trackRecvChan := mockLightningService.NewTrackPaymentV2MockData()
+		mockLightningService.SetDecodePayReqMockData(&lnrpc.PayReq{
+			PaymentHash: "0102",
+		})
+
+		resultChan := make(chan sendPaymentResult)
+
+		go func() {
+			paymentStatus, err := paymentService.SendPayment(context.Background(), "TestReference", "TestPaymentRequest", 1000000)
+			resultChan <- sendPaymentResult{paymentStatus: paymentStatus, err: err}
+		}()
+
+		close(sendRecvChan)
+		trackRecvChan <- &lnrpc.Payment{
+			PaymentHash: "0102",
+			Status:      lnrpc.Payment_SUCCEEDED,
+		}
+
+		result := <-resultChan
+
+		if result.err != nil || result.paymentStatus.Status != lnrpc.Payment_SUCCEEDED || result.paymentStatus.Attempts != 2 {
+			t.Errorf("Payment status mismatch: %#v, %v", result.paymentStatus, result.err)
+		}
+	})
+
+	t.Run("Payment sent again when not initiated", func(t *testing.T) {
+		mockLightningService := lightningnetworkMocks.NewService()
+		paymentService := payment.NewServiceWithConfig(mockLightningService, payment.PaymentConfig{
+			RetrySchedule: []time.Duration{time.Millisecond},
+		})
+
+		firstRecvChan := mockLightningService.NewSendPaymentV2MockData()
+		secondRecvChan := mockLightningService.NewSendPaymentV2MockData()
+		mockLightningService.SetDecodePayReqMockData(&lnrpc.PayReq{
+			PaymentHash: "0102",
+		})
+
+		resultChan := make(chan sendPaymentResult)
+
+		go func() {
+			paymentStatus, err := paymentService.SendPayment(context.Background(), "TestReference", "TestPaymentRequest", 1000000)
+			resultChan <- sendPaymentResult{paymentStatus: paymentStatus, err: err}
+		}()
+
+		close(firstRecvChan)
+		secondRecvChan <- &lnrpc.Payment{
+			PaymentHash: "0102",
+			Status:      lnrpc.Payment_SUCCEEDED,
+		}
+
+		result := <-resultChan
+
+		if result.err != nil || result.paymentStatus.Status != lnrpc.Payment_SUCCEEDED || result.paymentStatus.Attempts != 2 {
+			t.Errorf("Payment status mismatch: %#v, %v", result.paymentStatus, result.err)
+		}
+	})
+}
+
+func TestResumePayment(t *testing.T) {
+	t.Run("Payment tracked without sending", func(t *testing.T) {
+		mockLightningService := lightningnetworkMocks.NewService()
+		paymentService := payment.NewServiceWithConfig(mockLightningService, payment.PaymentConfig{
+			RetrySchedule: []time.Duration{time.Millisecond},
+		})
+
+		trackRecvChan := mockLightningService.NewTrackPaymentV2MockData()
+		mockLightningService.SetDecodePayReqMockData(&lnrpc.PayReq{
+			PaymentHash: "0102",
+		})
+
+		resultChan := make(chan sendPaymentResult)
+
+		go func() {
+			paymentStatus, err := paymentService.ResumePayment(context.Background(), "TestReference", "TestPaymentRequest", 1000000)
+			resultChan <- sendPaymentResult{paymentStatus: paymentStatus, err: err}
+		}()
+
+		trackRecvChan <- &lnrpc.Payment{
+			PaymentHash: "0102",
+			Status:      lnrpc.Payment_SUCCEEDED,
+		}
+
+		result := <-resultChan
+
+		if result.err != nil || result.paymentStatus.Status != lnrpc.Payment_SUCCEEDED || result.paymentStatus.Attempts != 1 {
+			t.Errorf("Payment status mismatch: %#v, %v", result.paymentStatus, result.err)
+		}
+
+		if paymentStatus, err := paymentService.GetPaymentStatus("TestReference"); err != nil || !paymentStatus.IsFinal {
+			t.Errorf("Payment status mismatch: %#v, %v", paymentStatus, err)
+		}
+	})
+
+	t.Run("Payment sent when unknown to LND", func(t *testing.T) {
+		mockLightningService := lightningnetworkMocks.NewService()
+		paymentService := payment.NewServiceWithConfig(mockLightningService, payment.PaymentConfig{
+			RetrySchedule: []time.Duration{time.Millisecond},
+		})
+
+		sendRecvChan := mockLightningService.NewSendPaymentV2MockData()
+		mockLightningService.SetDecodePayReqMockData(&lnrpc.PayReq{
+			PaymentHash: "0102",
+		})
+
+		resultChan := make(chan sendPaymentResult)
+
+		go func() {
+			paymentStatus, err := paymentService.ResumePayment(context.Background(), "TestReference", "TestPaymentRequest", 1000000)
+			resultChan <- sendPaymentResult{paymentStatus: paymentStatus, err: err}
+		}()
+
+		sendRecvChan <- &lnrpc.Payment{
+			PaymentHash: "0102",
+			Status:      lnrpc.Payment_SUCCEEDED,
+		}
+
+		result := <-resultChan
+
+		if result.err != nil || result.paymentStatus.Status != lnrpc.Payment_SUCCEEDED {
+			t.Errorf("Payment status mismatch: %#v, %v", result.paymentStatus, result.err)
+		}
+	})
+}
+
+func TestSendPaymentShutdown(t *testing.T) {
+	mockLightningService := lightningnetworkMocks.NewService()
+	paymentService := payment.NewServiceWithConfig(mockLightningService, payment.PaymentConfig{
+		RetrySchedule: []time.Duration{time.Hour},
+	})
+
+	recvChan := mockLightningService.NewSendPaymentV2MockData()
+	ctx, cancel := context.WithCancel(context.Background())
+	resultChan := make(chan sendPaymentResult)
+
+	go func() {
+		paymentStatus, err := paymentService.SendPayment(ctx, "TestReference", "TestPaymentRequest", 1000000)
+		resultChan <- sendPaymentResult{paymentStatus: paymentStatus, err: err}
+	}()
+
+	recvChan <- &lnrpc.Payment{
+		PaymentHash:   "0102",
+		Status:        lnrpc.Payment_FAILED,
+		FailureReason: lnrpc.PaymentFailureReason_FAILURE_REASON_NO_ROUTE,
+	}
+
+	cancel()
+
+	select {
+	case result := <-resultChan:
+		if result.err != context.Canceled || result.paymentStatus.IsFinal {
+			t.Errorf("Payment status mismatch: %#v, %v", result.paymentStatus, result.err)
+		}
+	case <-time.After(time.Second):
+		t.Error("Payment retries not stopped on shutdown")
+	}
+}
+
+func TestPaymentStatusRetention(t *testing.T) {
+	mockLightningService := lightningnetworkMocks.NewService()
+	paymentService := payment.NewServiceWithConfig(mockLightningService, payment.PaymentConfig{
+		StatusRetention: time.Millisecond,
+	})
+
+	for _, reference := range []string{"FirstReference", "SecondReference"} {
+		recvChan := mockLightningService.NewSendPaymentV2MockData()
+
+		go func() {
+			recvChan <- &lnrpc.Payment{Status: lnrpc.Payment_SUCCEEDED}
+		}()
+
+		paymentService.SendPayment(context.Background(), reference, "TestPaymentRequest", 1000000)
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	if _, err := paymentService.GetPaymentStatus("FirstReference"); err != payment.ErrPaymentNotFound {
+		t.Errorf("Error mismatch: %v expecting %v", err, payment.ErrPaymentNotFound)
+	}
+
+	if _, err := paymentService.GetPaymentStatus("SecondReference"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
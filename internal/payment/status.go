@@ -0,0 +1,28 @@
+package payment
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+type PaymentStatus struct {
+	Reference      string
+	PaymentRequest string
+	PaymentHash    string
+	Status         lnrpc.Payment_PaymentStatus
+	Attempts       int
+	FeeMsat        int64
+	FailureReasons []string
+	IsFinal        bool
+	NextAttempt    sql.NullTime
+	LastUpdated    time.Time
+}
+
+func (s *PaymentStatus) copy() *PaymentStatus {
+	paymentStatus := *s
+	paymentStatus.FailureReasons = append([]string{}, s.FailureReasons...)
+
+	return &paymentStatus
+}
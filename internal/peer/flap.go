@@ -0,0 +1,56 @@
+package peer
+
+import (
+	"sync"
+	"time"
+)
+
+// FlapDetector counts the times each peer goes offline within a window. A
+// peer is flapping when the count reaches the threshold, and is reported once
+// per window.
+type FlapDetector struct {
+	window     time.Duration
+	threshold  int
+	offlineAt  map[string][]time.Time
+	reportedAt map[string]time.Time
+	mutex      sync.Mutex
+}
+
+func NewFlapDetector(window time.Duration, threshold int) *FlapDetector {
+	return &FlapDetector{
+		window:     window,
+		threshold:  threshold,
+		offlineAt:  make(map[string][]time.Time),
+		reportedAt: make(map[string]time.Time),
+	}
+}
+
+// RecordOffline records a peer going offline and returns true if the peer
+// has started flapping
+func (d *FlapDetector) RecordOffline(pubkey string, now time.Time) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	offlineAt := []time.Time{}
+
+	for _, t := range d.offlineAt[pubkey] {
+		if now.Sub(t) < d.window {
+			offlineAt = append(offlineAt, t)
+		}
+	}
+
+	offlineAt = append(offlineAt, now)
+	d.offlineAt[pubkey] = offlineAt
+
+	if len(offlineAt) < d.threshold {
+		return false
+	}
+
+	if reportedAt, ok := d.reportedAt[pubkey]; ok && now.Sub(reportedAt) < d.window {
+		return false
+	}
+
+	d.reportedAt[pubkey] = now
+
+	return true
+}
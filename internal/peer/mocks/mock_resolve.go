@@ -0,0 +1,13 @@
+package mocks
+
+import (
+	mocks "github.com/satimoto/go-datastore/pkg/db/mocks"
+	peerMocks "github.com/satimoto/go-datastore/pkg/peer/mocks"
+	"github.com/satimoto/go-lnm/internal/peer"
+)
+
+func NewResolver(repositoryService *mocks.MockRepositoryService) *peer.PeerResolver {
+	return &peer.PeerResolver{
+		Repository: peerMocks.NewRepository(repositoryService),
+	}
+}
@@ -0,0 +1,60 @@
+package peer_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+	"github.com/satimoto/go-lnm/internal/peer"
+)
+
+func TestUptime(t *testing.T) {
+	createdDate := time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	updatePeerParams := &db.UpdatePeerParams{}
+
+	peer.SetOnline(updatePeerParams, true, createdDate)
+	peer.SetOnline(updatePeerParams, false, createdDate.Add(60*time.Second))
+	peer.SetOnline(updatePeerParams, false, createdDate.Add(70*time.Second))
+	peer.SetOnline(updatePeerParams, true, createdDate.Add(80*time.Second))
+
+	if updatePeerParams.OnlineSeconds != 60 || !updatePeerParams.IsOnline {
+		t.Errorf("Online time mismatch: %#v", updatePeerParams)
+	}
+
+	uptime := peer.GetUptime(db.Peer{
+		OnlineSeconds: updatePeerParams.OnlineSeconds,
+		OnlineSince:   updatePeerParams.OnlineSince,
+		CreatedDate:   createdDate,
+	}, createdDate.Add(100*time.Second))
+
+	if uptime.OnlineSeconds != 80 || uptime.LifetimeSeconds != 100 || uptime.Ratio != 0.8 {
+		t.Errorf("Uptime mismatch: %#v", uptime)
+	}
+}
+
+func TestFlapDetector(t *testing.T) {
+	now := time.Date(2022, 7, 1, 0, 0, 0, 0, time.UTC)
+	flapDetector := peer.NewFlapDetector(10*time.Minute, 3)
+
+	cases := []struct {
+		desc     string
+		pubkey   string
+		offset   time.Duration
+		flapping bool
+	}{
+		{"First offline", "a", 0, false},
+		{"Other peer offline", "b", time.Minute, false},
+		{"Second offline", "a", 2 * time.Minute, false},
+		{"Third offline", "a", 4 * time.Minute, true},
+		{"Already reported", "a", 6 * time.Minute, false},
+		{"Window passed", "a", 11 * time.Minute, false},
+		{"Flapping again", "a", 15 * time.Minute, true},
+	}
+
+	for _, tc := range cases {
+		if flapping := flapDetector.RecordOffline(tc.pubkey, now.Add(tc.offset)); flapping != tc.flapping {
+			t.Errorf("%v: flapping %v expecting %v", tc.desc, flapping, tc.flapping)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package peer
+
+import (
+	"github.com/satimoto/go-datastore/pkg/db"
+	"github.com/satimoto/go-datastore/pkg/peer"
+)
+
+type PeerResolver struct {
+	Repository peer.PeerRepository
+}
+
+func NewResolver(repositoryService *db.RepositoryService) *PeerResolver {
+	return &PeerResolver{
+		Repository: peer.NewRepository(repositoryService),
+	}
+}
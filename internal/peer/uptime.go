@@ -0,0 +1,45 @@
+package peer
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+)
+
+type Uptime struct {
+	OnlineSeconds   int64
+	LifetimeSeconds int64
+	Ratio           float64
+}
+
+// SetOnline sets whether a peer is online and accumulates its online time
+// when it goes offline
+func SetOnline(updatePeerParams *db.UpdatePeerParams, isOnline bool, now time.Time) {
+	if isOnline && !updatePeerParams.OnlineSince.Valid {
+		updatePeerParams.OnlineSince = sql.NullTime{Time: now, Valid: true}
+	} else if !isOnline && updatePeerParams.OnlineSince.Valid {
+		updatePeerParams.OnlineSeconds += int64(now.Sub(updatePeerParams.OnlineSince.Time).Seconds())
+		updatePeerParams.OnlineSince = sql.NullTime{}
+	}
+
+	updatePeerParams.IsOnline = isOnline
+}
+
+// GetUptime returns the time a peer has been online since it was first seen
+func GetUptime(peer db.Peer, now time.Time) *Uptime {
+	uptime := &Uptime{
+		OnlineSeconds:   peer.OnlineSeconds,
+		LifetimeSeconds: int64(now.Sub(peer.CreatedDate).Seconds()),
+	}
+
+	if peer.OnlineSince.Valid {
+		uptime.OnlineSeconds += int64(now.Sub(peer.OnlineSince.Time).Seconds())
+	}
+
+	if uptime.LifetimeSeconds > 0 {
+		uptime.Ratio = float64(uptime.OnlineSeconds) / float64(uptime.LifetimeSeconds)
+	}
+
+	return uptime
+}
@@ -0,0 +1,68 @@
+package rest
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/satimoto/go-datastore/pkg/db"
+	"github.com/satimoto/go-lnm/internal/cdr"
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+)
+
+func (rs *RestService) listFlaggedCdrs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	filter, err := parseAdminFilter(r)
+
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	cdrs, err := rs.CdrResolver.Repository.ListFlaggedCdrs(ctx, db.ListFlaggedCdrsParams{
+		UserID:   filter.UserID,
+		FromDate: filter.FromDate,
+		ToDate:   filter.ToDate,
+		Limit:    filter.Limit,
+		Offset:   filter.Offset,
+	})
+
+	if err != nil {
+		metrics.RecordError("LNM217", "Error listing flagged cdrs", err)
+		log.Printf("LNM217: Filter=%#v", filter)
+		render.Render(w, r, ErrInternalServer(errors.New("error listing flagged cdrs")))
+		return
+	}
+
+	render.JSON(w, r, NewAdminCdrListDto(cdrs))
+}
+
+func (rs *RestService) processCdr(w http.ResponseWriter, r *http.Request) {
+	cdrUid := chi.URLParam(r, "cdr_uid")
+	storedCdr, err := rs.CdrResolver.Repository.GetCdrByUid(r.Context(), cdrUid)
+
+	if err != nil {
+		render.Render(w, r, ErrNotFound(errors.New("cdr not found")))
+		return
+	}
+
+	log.Printf("Admin processing cdr %v", storedCdr.Uid)
+
+	if err := rs.CdrResolver.ProcessCdr(storedCdr); errors.Is(err, cdr.ErrCdrAlreadyProcessed) {
+		render.Render(w, r, ErrConflict(err))
+		return
+	} else if err != nil {
+		metrics.RecordError("LNM218", "Error processing cdr", err)
+		log.Printf("LNM218: CdrUid=%v", storedCdr.Uid)
+		render.Render(w, r, ErrConflict(err))
+		return
+	}
+
+	if updatedCdr, err := rs.CdrResolver.Repository.GetCdrByUid(r.Context(), cdrUid); err == nil {
+		storedCdr = updatedCdr
+	}
+
+	render.JSON(w, r, NewAdminCdrDto(storedCdr))
+}
@@ -0,0 +1,80 @@
+package rest
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/satimoto/go-datastore/pkg/db"
+	"github.com/satimoto/go-lnm/internal/channel"
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+)
+
+func (rs *RestService) listChannels(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	nodeID, err := strconv.ParseInt(r.URL.Query().Get("node_id"), 10, 64)
+
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(errors.New("invalid node_id")))
+		return
+	}
+
+	channels, err := rs.ChannelResolver.Repository.ListChannelsByNodeID(ctx, nodeID)
+
+	if err != nil {
+		metrics.RecordError("LNM259", "Error listing channels", err)
+		log.Printf("LNM259: NodeID=%v", nodeID)
+		render.Render(w, r, ErrInternalServer(errors.New("error listing channels")))
+		return
+	}
+
+	list := []*AdminChannelDto{}
+
+	for _, channel := range channels {
+		dto, err := rs.newAdminChannelDto(r, channel)
+
+		if err != nil {
+			render.Render(w, r, ErrInternalServer(errors.New("error calculating channel pnl")))
+			return
+		}
+
+		list = append(list, dto)
+	}
+
+	render.JSON(w, r, list)
+}
+
+func (rs *RestService) getChannel(w http.ResponseWriter, r *http.Request) {
+	channelPoint := chi.URLParam(r, "channel_point")
+	channel, err := rs.ChannelResolver.Repository.GetChannelByChannelPoint(r.Context(), channelPoint)
+
+	if err != nil {
+		render.Render(w, r, ErrNotFound(errors.New("channel not found")))
+		return
+	}
+
+	dto, err := rs.newAdminChannelDto(r, channel)
+
+	if err != nil {
+		render.Render(w, r, ErrInternalServer(errors.New("error calculating channel pnl")))
+		return
+	}
+
+	render.JSON(w, r, dto)
+}
+
+func (rs *RestService) newAdminChannelDto(r *http.Request, c db.Channel) (*AdminChannelDto, error) {
+	pnl, err := rs.ChannelResolver.GetPnl(r.Context(), c)
+
+	if err != nil {
+		metrics.RecordError("LNM260", "Error calculating channel pnl", err)
+		log.Printf("LNM260: ChannelID=%v", c.ID)
+		return nil, err
+	}
+
+	return NewAdminChannelDto(c, channel.GetUptime(c, time.Now()), pnl), nil
+}
@@ -0,0 +1,353 @@
+package rest
+
+import (
+	"time"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+	"github.com/satimoto/go-lnm/internal/channel"
+	"github.com/satimoto/go-lnm/internal/graph"
+	"github.com/satimoto/go-lnm/internal/session"
+)
+
+type AdminSessionDto struct {
+	ID              int64                `json:"id"`
+	Uid             string               `json:"uid"`
+	UserID          int64                `json:"userId"`
+	AuthorizationID *string              `json:"authorizationId,omitempty"`
+	Status          db.SessionStatusType `json:"status"`
+	Currency        string               `json:"currency"`
+	TotalEnergy     float64              `json:"totalEnergy"`
+	TotalCost       *float64             `json:"totalCost,omitempty"`
+	IsFlagged       bool                 `json:"isFlagged"`
+	StartDatetime   string               `json:"startDatetime"`
+	EndDatetime     *string              `json:"endDatetime,omitempty"`
+	LastUpdated     string               `json:"lastUpdated"`
+}
+
+type AdminSessionDetailDto struct {
+	*AdminSessionDto
+	SessionInvoices    []*AdminSessionInvoiceDto `json:"sessionInvoices"`
+	InvoicedTotalFiat  float64                   `json:"invoicedTotalFiat"`
+	InvoicedTotalMsat  int64                     `json:"invoicedTotalMsat"`
+	TariffBreakdown    *AdminTariffBreakdownDto  `json:"tariffBreakdown,omitempty"`
+	TariffBreakdownErr *string                   `json:"tariffBreakdownError,omitempty"`
+}
+
+type AdminSessionInvoiceDto struct {
+	ID              int64   `json:"id"`
+	SessionID       int64   `json:"sessionId"`
+	UserID          int64   `json:"userId"`
+	Currency        string  `json:"currency"`
+	PriceFiat       float64 `json:"priceFiat"`
+	PriceMsat       int64   `json:"priceMsat"`
+	CommissionFiat  float64 `json:"commissionFiat"`
+	CommissionMsat  int64   `json:"commissionMsat"`
+	TaxFiat         float64 `json:"taxFiat"`
+	TaxMsat         int64   `json:"taxMsat"`
+	TotalFiat       float64 `json:"totalFiat"`
+	TotalMsat       int64   `json:"totalMsat"`
+	EstimatedEnergy float64 `json:"estimatedEnergy"`
+	EstimatedTime   float64 `json:"estimatedTime"`
+	MeteredEnergy   float64 `json:"meteredEnergy"`
+	MeteredTime     float64 `json:"meteredTime"`
+	PaymentRequest  string  `json:"paymentRequest"`
+	IsSettled       bool    `json:"isSettled"`
+	IsExpired       bool    `json:"isExpired"`
+}
+
+type AdminReissueResultDto struct {
+	SessionInvoiceID int64                   `json:"sessionInvoiceId"`
+	SessionInvoice   *AdminSessionInvoiceDto `json:"sessionInvoice,omitempty"`
+	Error            *string                 `json:"error,omitempty"`
+}
+
+type AdminTariffBreakdownDto struct {
+	Currency        string  `json:"currency"`
+	IsTotalCost     bool    `json:"isTotalCost"`
+	FlatCost        float64 `json:"flatCost"`
+	EnergyCost      float64 `json:"energyCost"`
+	TimeCost        float64 `json:"timeCost"`
+	ParkingTimeCost float64 `json:"parkingTimeCost"`
+	SessionTimeCost float64 `json:"sessionTimeCost"`
+	TotalCost       float64 `json:"totalCost"`
+	TotalEnergy     float64 `json:"totalEnergy"`
+	TotalTime       float64 `json:"totalTime"`
+	CommissionFiat  float64 `json:"commissionFiat"`
+	TaxFiat         float64 `json:"taxFiat"`
+	TotalFiat       float64 `json:"totalFiat"`
+}
+
+type AdminCdrDto struct {
+	ID              int64   `json:"id"`
+	Uid             string  `json:"uid"`
+	UserID          int64   `json:"userId"`
+	AuthorizationID *string `json:"authorizationId,omitempty"`
+	Currency        string  `json:"currency"`
+	TotalCost       float64 `json:"totalCost"`
+	TotalEnergy     float64 `json:"totalEnergy"`
+	IsFlagged       bool    `json:"isFlagged"`
+	StartDatetime   string  `json:"startDatetime"`
+	EndDatetime     *string `json:"endDatetime,omitempty"`
+	LastUpdated     string  `json:"lastUpdated"`
+}
+
+type AdminChannelDto struct {
+	ID                int64   `json:"id"`
+	NodeID            int64   `json:"nodeId"`
+	ChannelPoint      string  `json:"channelPoint"`
+	ChanID            int64   `json:"chanId"`
+	RemotePubkey      string  `json:"remotePubkey"`
+	Alias             string  `json:"alias"`
+	Capacity          int64   `json:"capacity"`
+	Status            string  `json:"status"`
+	IsActive          bool    `json:"isActive"`
+	CloseType         *string `json:"closeType,omitempty"`
+	SettledBalance    int64   `json:"settledBalance"`
+	TimeLockedBalance int64   `json:"timeLockedBalance"`
+	ActiveSeconds     int64   `json:"activeSeconds"`
+	LifetimeSeconds   int64   `json:"lifetimeSeconds"`
+	Uptime            float64 `json:"uptime"`
+	FeesEarnedMsat    int64   `json:"feesEarnedMsat"`
+	OpeningFeeMsat    int64   `json:"openingFeeMsat"`
+	CommitFeeMsat     int64   `json:"commitFeeMsat"`
+	RebalanceMsat     int64   `json:"rebalanceMsat"`
+	PnlMsat           int64   `json:"pnlMsat"`
+	OpenedAt          *string `json:"openedAt,omitempty"`
+	ClosedAt          *string `json:"closedAt,omitempty"`
+	LastUpdated       string  `json:"lastUpdated"`
+}
+
+type AdminGraphPeerDto struct {
+	Pubkey             string                `json:"pubkey"`
+	LocalFeeRatePpm    int64                 `json:"localFeeRatePpm"`
+	Competitors        int                   `json:"competitors"`
+	MinFeeRatePpm      int64                 `json:"minFeeRatePpm"`
+	MedianFeeRatePpm   int64                 `json:"medianFeeRatePpm"`
+	MaxFeeRatePpm      int64                 `json:"maxFeeRatePpm"`
+	MedianFeeBaseMsat  int64                 `json:"medianFeeBaseMsat"`
+	CompetitorPolicies []*AdminEdgePolicyDto `json:"competitorPolicies,omitempty"`
+}
+
+type AdminEdgePolicyDto struct {
+	ChanID        uint64 `json:"chanId"`
+	Capacity      int64  `json:"capacity"`
+	Pubkey        string `json:"pubkey"`
+	FeeBaseMsat   int64  `json:"feeBaseMsat"`
+	FeeRatePpm    int64  `json:"feeRatePpm"`
+	TimeLockDelta uint32 `json:"timeLockDelta"`
+	MinHtlcMsat   int64  `json:"minHtlcMsat"`
+	MaxHtlcMsat   uint64 `json:"maxHtlcMsat"`
+	Disabled      bool   `json:"disabled"`
+	LastUpdate    string `json:"lastUpdate"`
+}
+
+func NewAdminSessionDto(session db.Session) *AdminSessionDto {
+	response := &AdminSessionDto{
+		ID:            session.ID,
+		Uid:           session.Uid,
+		UserID:        session.UserID,
+		Status:        session.Status,
+		Currency:      session.Currency,
+		TotalEnergy:   session.Kwh,
+		IsFlagged:     session.IsFlagged,
+		StartDatetime: session.StartDatetime.Format(time.RFC3339),
+		LastUpdated:   session.LastUpdated.Format(time.RFC3339),
+	}
+
+	if session.AuthorizationID.Valid {
+		response.AuthorizationID = &session.AuthorizationID.String
+	}
+
+	if session.TotalCost.Valid {
+		response.TotalCost = &session.TotalCost.Float64
+	}
+
+	if session.EndDatetime.Valid {
+		endDatetime := session.EndDatetime.Time.Format(time.RFC3339)
+		response.EndDatetime = &endDatetime
+	}
+
+	return response
+}
+
+func NewAdminSessionListDto(sessions []db.Session) []*AdminSessionDto {
+	list := []*AdminSessionDto{}
+	for _, session := range sessions {
+		list = append(list, NewAdminSessionDto(session))
+	}
+	return list
+}
+
+func NewAdminSessionInvoiceDto(sessionInvoice db.SessionInvoice) *AdminSessionInvoiceDto {
+	return &AdminSessionInvoiceDto{
+		ID:              sessionInvoice.ID,
+		SessionID:       sessionInvoice.SessionID,
+		UserID:          sessionInvoice.UserID,
+		Currency:        sessionInvoice.Currency,
+		PriceFiat:       sessionInvoice.PriceFiat,
+		PriceMsat:       sessionInvoice.PriceMsat,
+		CommissionFiat:  sessionInvoice.CommissionFiat,
+		CommissionMsat:  sessionInvoice.CommissionMsat,
+		TaxFiat:         sessionInvoice.TaxFiat,
+		TaxMsat:         sessionInvoice.TaxMsat,
+		TotalFiat:       sessionInvoice.TotalFiat,
+		TotalMsat:       sessionInvoice.TotalMsat,
+		EstimatedEnergy: sessionInvoice.EstimatedEnergy,
+		EstimatedTime:   sessionInvoice.EstimatedTime,
+		MeteredEnergy:   sessionInvoice.MeteredEnergy,
+		MeteredTime:     sessionInvoice.MeteredTime,
+		PaymentRequest:  sessionInvoice.PaymentRequest,
+		IsSettled:       sessionInvoice.IsSettled,
+		IsExpired:       sessionInvoice.IsExpired,
+	}
+}
+
+func NewAdminSessionInvoiceListDto(sessionInvoices []db.SessionInvoice) []*AdminSessionInvoiceDto {
+	list := []*AdminSessionInvoiceDto{}
+	for _, sessionInvoice := range sessionInvoices {
+		list = append(list, NewAdminSessionInvoiceDto(sessionInvoice))
+	}
+	return list
+}
+
+func NewAdminReissueResultDto(sessionInvoiceID int64, sessionInvoice *db.SessionInvoice, err error) *AdminReissueResultDto {
+	response := &AdminReissueResultDto{
+		SessionInvoiceID: sessionInvoiceID,
+	}
+
+	if sessionInvoice != nil {
+		response.SessionInvoice = NewAdminSessionInvoiceDto(*sessionInvoice)
+	}
+
+	if err != nil {
+		errText := err.Error()
+		response.Error = &errText
+	}
+
+	return response
+}
+
+func NewAdminTariffBreakdownDto(tariffBreakdown *session.TariffBreakdown) *AdminTariffBreakdownDto {
+	return &AdminTariffBreakdownDto{
+		Currency:        tariffBreakdown.Currency,
+		IsTotalCost:     tariffBreakdown.IsTotalCost,
+		FlatCost:        tariffBreakdown.FlatCost,
+		EnergyCost:      tariffBreakdown.EnergyCost,
+		TimeCost:        tariffBreakdown.TimeCost,
+		ParkingTimeCost: tariffBreakdown.ParkingTimeCost,
+		SessionTimeCost: tariffBreakdown.SessionTimeCost,
+		TotalCost:       tariffBreakdown.TotalCost,
+		TotalEnergy:     tariffBreakdown.TotalEnergy,
+		TotalTime:       tariffBreakdown.TotalTime,
+		CommissionFiat:  tariffBreakdown.CommissionFiat,
+		TaxFiat:         tariffBreakdown.TaxFiat,
+		TotalFiat:       tariffBreakdown.TotalFiat,
+	}
+}
+
+func NewAdminCdrDto(cdr db.Cdr) *AdminCdrDto {
+	response := &AdminCdrDto{
+		ID:            cdr.ID,
+		Uid:           cdr.Uid,
+		UserID:        cdr.UserID,
+		Currency:      cdr.Currency,
+		TotalCost:     cdr.TotalCost,
+		TotalEnergy:   cdr.TotalEnergy,
+		IsFlagged:     cdr.IsFlagged,
+		StartDatetime: cdr.StartDateTime.Format(time.RFC3339),
+		LastUpdated:   cdr.LastUpdated.Format(time.RFC3339),
+	}
+
+	if cdr.AuthorizationID.Valid {
+		response.AuthorizationID = &cdr.AuthorizationID.String
+	}
+
+	if cdr.StopDateTime.Valid {
+		endDatetime := cdr.StopDateTime.Time.Format(time.RFC3339)
+		response.EndDatetime = &endDatetime
+	}
+
+	return response
+}
+
+func NewAdminCdrListDto(cdrs []db.Cdr) []*AdminCdrDto {
+	list := []*AdminCdrDto{}
+	for _, cdr := range cdrs {
+		list = append(list, NewAdminCdrDto(cdr))
+	}
+	return list
+}
+
+func NewAdminChannelDto(c db.Channel, uptime *channel.Uptime, pnl *channel.Pnl) *AdminChannelDto {
+	response := &AdminChannelDto{
+		ID:                c.ID,
+		NodeID:            c.NodeID,
+		ChannelPoint:      c.ChannelPoint,
+		ChanID:            c.ChanID,
+		RemotePubkey:      c.RemotePubkey,
+		Alias:             c.Alias,
+		Capacity:          c.Capacity,
+		Status:            string(c.Status),
+		IsActive:          c.IsActive,
+		SettledBalance:    c.SettledBalance,
+		TimeLockedBalance: c.TimeLockedBalance,
+		ActiveSeconds:     uptime.ActiveSeconds,
+		LifetimeSeconds:   uptime.LifetimeSeconds,
+		Uptime:            uptime.Ratio,
+		FeesEarnedMsat:    pnl.FeesEarnedMsat,
+		OpeningFeeMsat:    pnl.OpeningFeeMsat,
+		CommitFeeMsat:     pnl.CommitFeeMsat,
+		RebalanceMsat:     pnl.RebalanceMsat,
+		PnlMsat:           pnl.TotalMsat,
+		LastUpdated:       c.LastUpdated.Format(time.RFC3339),
+	}
+
+	if c.CloseType.Valid {
+		response.CloseType = &c.CloseType.String
+	}
+
+	if c.OpenedAt.Valid {
+		openedAt := c.OpenedAt.Time.Format(time.RFC3339)
+		response.OpenedAt = &openedAt
+	}
+
+	if c.ClosedAt.Valid {
+		closedAt := c.ClosedAt.Time.Format(time.RFC3339)
+		response.ClosedAt = &closedAt
+	}
+
+	return response
+}
+
+func NewAdminGraphPeerDto(feeSummary *graph.FeeSummary, competitorPolicies []*graph.EdgePolicy) *AdminGraphPeerDto {
+	response := &AdminGraphPeerDto{
+		Pubkey:            feeSummary.PeerPubkey,
+		LocalFeeRatePpm:   feeSummary.LocalFeeRatePpm,
+		Competitors:       feeSummary.Competitors,
+		MinFeeRatePpm:     feeSummary.MinFeeRatePpm,
+		MedianFeeRatePpm:  feeSummary.MedianFeeRatePpm,
+		MaxFeeRatePpm:     feeSummary.MaxFeeRatePpm,
+		MedianFeeBaseMsat: feeSummary.MedianFeeBaseMsat,
+	}
+
+	for _, edgePolicy := range competitorPolicies {
+		response.CompetitorPolicies = append(response.CompetitorPolicies, NewAdminEdgePolicyDto(edgePolicy))
+	}
+
+	return response
+}
+
+func NewAdminEdgePolicyDto(edgePolicy *graph.EdgePolicy) *AdminEdgePolicyDto {
+	return &AdminEdgePolicyDto{
+		ChanID:        edgePolicy.ChanID,
+		Capacity:      edgePolicy.Capacity,
+		Pubkey:        edgePolicy.Pubkey,
+		FeeBaseMsat:   edgePolicy.Policy.FeeBaseMsat,
+		FeeRatePpm:    edgePolicy.Policy.FeeRatePpm,
+		TimeLockDelta: edgePolicy.Policy.TimeLockDelta,
+		MinHtlcMsat:   edgePolicy.Policy.MinHtlcMsat,
+		MaxHtlcMsat:   edgePolicy.Policy.MaxHtlcMsat,
+		Disabled:      edgePolicy.Policy.Disabled,
+		LastUpdate:    edgePolicy.Policy.LastUpdate.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,82 @@
+package rest
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	DEFAULT_FILTER_LIMIT = 50
+	MAX_FILTER_LIMIT     = 500
+)
+
+type adminFilter struct {
+	UserID   sql.NullInt64
+	FromDate sql.NullTime
+	ToDate   sql.NullTime
+	Limit    int32
+	Offset   int32
+}
+
+// parseAdminFilter parses the user_id, from, to, limit and offset query parameters
+func parseAdminFilter(r *http.Request) (*adminFilter, error) {
+	query := r.URL.Query()
+	filter := &adminFilter{
+		Limit: DEFAULT_FILTER_LIMIT,
+	}
+
+	if userID := query.Get("user_id"); len(userID) > 0 {
+		value, err := strconv.ParseInt(userID, 10, 64)
+
+		if err != nil {
+			return nil, errors.New("invalid user_id")
+		}
+
+		filter.UserID = sql.NullInt64{Int64: value, Valid: true}
+	}
+
+	if fromDate := query.Get("from"); len(fromDate) > 0 {
+		value, err := time.Parse(time.RFC3339, fromDate)
+
+		if err != nil {
+			return nil, errors.New("invalid from date")
+		}
+
+		filter.FromDate = sql.NullTime{Time: value, Valid: true}
+	}
+
+	if toDate := query.Get("to"); len(toDate) > 0 {
+		value, err := time.Parse(time.RFC3339, toDate)
+
+		if err != nil {
+			return nil, errors.New("invalid to date")
+		}
+
+		filter.ToDate = sql.NullTime{Time: value, Valid: true}
+	}
+
+	if limit := query.Get("limit"); len(limit) > 0 {
+		value, err := strconv.ParseInt(limit, 10, 32)
+
+		if err != nil || value < 1 || value > MAX_FILTER_LIMIT {
+			return nil, errors.New("invalid limit")
+		}
+
+		filter.Limit = int32(value)
+	}
+
+	if offset := query.Get("offset"); len(offset) > 0 {
+		value, err := strconv.ParseInt(offset, 10, 32)
+
+		if err != nil || value < 0 {
+			return nil, errors.New("invalid offset")
+		}
+
+		filter.Offset = int32(value)
+	}
+
+	return filter, nil
+}
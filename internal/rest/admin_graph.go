@@ -0,0 +1,65 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/satimoto/go-lnm/internal/graph"
+)
+
+func (rs *RestService) listGraphPeers(w http.ResponseWriter, r *http.Request) {
+	g, ok := rs.getGraph(w, r)
+
+	if !ok {
+		return
+	}
+
+	list := []*AdminGraphPeerDto{}
+
+	for _, pubkey := range g.ListPeers() {
+		list = append(list, NewAdminGraphPeerDto(g.GetFeeSummary(pubkey), nil))
+	}
+
+	render.JSON(w, r, list)
+}
+
+func (rs *RestService) getGraphPeer(w http.ResponseWriter, r *http.Request) {
+	g, ok := rs.getGraph(w, r)
+
+	if !ok {
+		return
+	}
+
+	pubkey := chi.URLParam(r, "pubkey")
+
+	for _, peer := range g.ListPeers() {
+		if peer == pubkey {
+			render.JSON(w, r, NewAdminGraphPeerDto(g.GetFeeSummary(pubkey), g.ListCompetitorPolicies(pubkey)))
+			return
+		}
+	}
+
+	render.Render(w, r, ErrNotFound(errors.New("peer not found")))
+}
+
+// getGraph returns the channel graph of the node managed by this process
+func (rs *RestService) getGraph(w http.ResponseWriter, r *http.Request) (graph.Graph, bool) {
+	nodeID, err := strconv.ParseInt(r.URL.Query().Get("node_id"), 10, 64)
+
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(errors.New("invalid node_id")))
+		return nil, false
+	}
+
+	lightningNode, ok := rs.LightningNodes.GetNode(nodeID)
+
+	if !ok {
+		render.Render(w, r, ErrNotFound(errors.New("node not found")))
+		return nil, false
+	}
+
+	return lightningNode.Graph, true
+}
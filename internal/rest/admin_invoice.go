@@ -0,0 +1,44 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/satimoto/go-lnm/internal/session"
+)
+
+func (rs *RestService) reissueSessionInvoice(w http.ResponseWriter, r *http.Request) {
+	sessionInvoiceID, err := strconv.ParseInt(chi.URLParam(r, "session_invoice_id"), 10, 64)
+
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(errors.New("invalid session invoice id")))
+		return
+	}
+
+	sessionInvoice, err := rs.SessionResolver.Repository.GetSessionInvoice(r.Context(), sessionInvoiceID)
+
+	if err != nil {
+		render.Render(w, r, ErrNotFound(errors.New("session invoice not found")))
+		return
+	}
+
+	// Use a background context so the invoice expiry is not bound to the request
+	reissuedSessionInvoice, err := rs.SessionResolver.ReissueSessionInvoice(context.Background(), sessionInvoice)
+
+	if errors.Is(err, session.ErrSessionInvoiceNotExpired) {
+		render.Render(w, r, ErrConflict(err))
+		return
+	} else if err != nil {
+		render.Render(w, r, ErrInternalServer(err))
+		return
+	}
+
+	log.Printf("Admin reissued session invoice %v", sessionInvoice.ID)
+
+	render.JSON(w, r, NewAdminSessionInvoiceDto(*reissuedSessionInvoice))
+}
@@ -0,0 +1,173 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/satimoto/go-datastore/pkg/db"
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+	"github.com/satimoto/go-lnm/internal/session"
+)
+
+func (rs *RestService) listFlaggedSessions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	filter, err := parseAdminFilter(r)
+
+	if err != nil {
+		render.Render(w, r, ErrInvalidRequest(err))
+		return
+	}
+
+	sessions, err := rs.SessionResolver.Repository.ListFlaggedSessions(ctx, db.ListFlaggedSessionsParams{
+		UserID:   filter.UserID,
+		FromDate: filter.FromDate,
+		ToDate:   filter.ToDate,
+		Limit:    filter.Limit,
+		Offset:   filter.Offset,
+	})
+
+	if err != nil {
+		metrics.RecordError("LNM212", "Error listing flagged sessions", err)
+		log.Printf("LNM212: Filter=%#v", filter)
+		render.Render(w, r, ErrInternalServer(errors.New("error listing flagged sessions")))
+		return
+	}
+
+	render.JSON(w, r, NewAdminSessionListDto(sessions))
+}
+
+func (rs *RestService) getSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	s, ok := rs.getSessionByUid(w, r)
+
+	if !ok {
+		return
+	}
+
+	sessionInvoices, err := rs.SessionResolver.Repository.ListSessionInvoicesBySessionID(ctx, s.ID)
+
+	if err != nil {
+		metrics.RecordError("LNM213", "Error listing session invoices", err)
+		log.Printf("LNM213: SessionUid=%v", s.Uid)
+		render.Render(w, r, ErrInternalServer(errors.New("error listing session invoices")))
+		return
+	}
+
+	response := &AdminSessionDetailDto{
+		AdminSessionDto: NewAdminSessionDto(s),
+		SessionInvoices: NewAdminSessionInvoiceListDto(sessionInvoices),
+	}
+
+	response.InvoicedTotalFiat, response.InvoicedTotalMsat = session.CalculateTotalInvoiced(sessionInvoices)
+
+	if tariffBreakdown, err := rs.SessionResolver.GetTariffBreakdown(ctx, s); err == nil {
+		response.TariffBreakdown = NewAdminTariffBreakdownDto(tariffBreakdown)
+	} else {
+		// The session is still shown when the breakdown cannot be calculated
+		tariffBreakdownErr := err.Error()
+		response.TariffBreakdownErr = &tariffBreakdownErr
+	}
+
+	render.JSON(w, r, response)
+}
+
+func (rs *RestService) unflagSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	s, ok := rs.getSessionByUid(w, r)
+
+	if !ok {
+		return
+	}
+
+	if err := rs.SessionResolver.UnflagSession(ctx, s); err != nil {
+		metrics.RecordError("LNM214", "Error unflagging session", err)
+		log.Printf("LNM214: SessionUid=%v", s.Uid)
+		render.Render(w, r, ErrInternalServer(errors.New("error unflagging session")))
+		return
+	}
+
+	log.Printf("Admin unflagged session %v", s.Uid)
+	s.IsFlagged = false
+
+	render.JSON(w, r, NewAdminSessionDto(s))
+}
+
+func (rs *RestService) stopSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	s, ok := rs.getSessionByUid(w, r)
+
+	if !ok {
+		return
+	}
+
+	log.Printf("Admin stopping session %v", s.Uid)
+	_, err := rs.SessionResolver.StopSession(ctx, s)
+
+	if err != nil {
+		metrics.RecordError("LNM215", "Error stopping session", err)
+		log.Printf("LNM215: SessionUid=%v", s.Uid)
+		render.Render(w, r, ErrConflict(err))
+		return
+	}
+
+	// Stopping the session also flags it
+	s.IsFlagged = true
+
+	render.JSON(w, r, NewAdminSessionDto(s))
+}
+
+func (rs *RestService) reissueSessionInvoices(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	s, ok := rs.getSessionByUid(w, r)
+
+	if !ok {
+		return
+	}
+
+	sessionInvoices, err := rs.SessionResolver.Repository.ListSessionInvoicesBySessionID(ctx, s.ID)
+
+	if err != nil {
+		metrics.RecordError("LNM216", "Error listing session invoices", err)
+		log.Printf("LNM216: SessionUid=%v", s.Uid)
+		render.Render(w, r, ErrInternalServer(errors.New("error listing session invoices")))
+		return
+	}
+
+	// Each invoice is reissued independently, a failure does not stop the others
+	results := []*AdminReissueResultDto{}
+	reissuedCount := 0
+
+	for _, sessionInvoice := range sessionInvoices {
+		if sessionInvoice.IsSettled || !sessionInvoice.IsExpired {
+			continue
+		}
+
+		reissuedSessionInvoice, err := rs.SessionResolver.ReissueSessionInvoice(context.Background(), sessionInvoice)
+
+		if err == nil {
+			reissuedCount++
+		}
+
+		results = append(results, NewAdminReissueResultDto(sessionInvoice.ID, reissuedSessionInvoice, err))
+	}
+
+	log.Printf("Admin reissued %v of %v session invoices for session %v", reissuedCount, len(results), s.Uid)
+
+	render.JSON(w, r, results)
+}
+
+func (rs *RestService) getSessionByUid(w http.ResponseWriter, r *http.Request) (db.Session, bool) {
+	sessionUid := chi.URLParam(r, "session_uid")
+	s, err := rs.SessionResolver.Repository.GetSessionByUid(r.Context(), sessionUid)
+
+	if err != nil {
+		render.Render(w, r, ErrNotFound(errors.New("session not found")))
+		return s, false
+	}
+
+	return s, true
+}
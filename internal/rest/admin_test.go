@@ -0,0 +1,418 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/satimoto/go-datastore/pkg/db"
+	dbMocks "github.com/satimoto/go-datastore/pkg/db/mocks"
+	dbUtil "github.com/satimoto/go-datastore/pkg/util"
+	"github.com/satimoto/go-ferp/pkg/rate"
+	cdrMocks "github.com/satimoto/go-lnm/internal/cdr/mocks"
+	ferpMocks "github.com/satimoto/go-lnm/internal/ferp/mocks"
+	lightningnetworkMocks "github.com/satimoto/go-lnm/internal/lightningnetwork/mocks"
+	notificationMocks "github.com/satimoto/go-lnm/internal/notification/mocks"
+	serviceMocks "github.com/satimoto/go-lnm/internal/service/mocks"
+	sessionMocks "github.com/satimoto/go-lnm/internal/session/mocks"
+	"github.com/satimoto/go-ocpi/ocpirpc"
+	ocpiMocks "github.com/satimoto/go-ocpi/pkg/ocpi/mocks"
+)
+
+const ADMIN_API_TOKEN = "ADMINTOKEN0001"
+
+type adminTestServices struct {
+	mockRepository       *dbMocks.MockRepositoryService
+	mockFerpService      *ferpMocks.MockFerpService
+	mockLightningService *lightningnetworkMocks.MockLightningNetworkService
+	mockOcpiService      *ocpiMocks.MockOcpiService
+	restService          *RestService
+}
+
+func newAdminTestServices() *adminTestServices {
+	mockRepository := dbMocks.NewMockRepositoryService()
+	mockFerpService := ferpMocks.NewService()
+	mockLightningService := lightningnetworkMocks.NewService()
+	mockOcpiService := ocpiMocks.NewService()
+	mockServices := serviceMocks.NewService(mockFerpService, mockLightningService, notificationMocks.NewService(), mockOcpiService)
+
+	return &adminTestServices{
+		mockRepository:       mockRepository,
+		mockFerpService:      mockFerpService,
+		mockLightningService: mockLightningService,
+		mockOcpiService:      mockOcpiService,
+		restService: &RestService{
+			CdrResolver:     cdrMocks.NewResolver(mockRepository, mockServices),
+			SessionResolver: sessionMocks.NewResolver(mockRepository, mockServices),
+			adminApiToken:   ADMIN_API_TOKEN,
+		},
+	}
+}
+
+func serveAdmin(restService *RestService, method, target, token string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, nil)
+
+	if len(token) > 0 {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	recorder := httptest.NewRecorder()
+	restService.mountAdmin().ServeHTTP(recorder, request)
+
+	return recorder
+}
+
+func TestAdminAuthenticator(t *testing.T) {
+	cases := []struct {
+		desc   string
+		token  string
+		status int
+	}{{
+		desc:   "Missing token",
+		status: http.StatusUnauthorized,
+	}, {
+		desc:   "Invalid token",
+		token:  "ADMINTOKEN0002",
+		status: http.StatusUnauthorized,
+	}, {
+		desc:   "Valid token",
+		token:  ADMIN_API_TOKEN,
+		status: http.StatusOK,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			services := newAdminTestServices()
+			services.mockRepository.SetListFlaggedSessionsMockData(dbMocks.SessionsMockData{Sessions: []db.Session{}})
+
+			recorder := serveAdmin(services.restService, http.MethodGet, "/sessions/flagged", tc.token)
+
+			if recorder.Code != tc.status {
+				t.Errorf("Status mismatch: %v expecting %v", recorder.Code, tc.status)
+			}
+		})
+	}
+}
+
+func TestParseAdminFilter(t *testing.T) {
+	cases := []struct {
+		desc   string
+		query  string
+		filter *adminFilter
+	}{{
+		desc:   "Defaults",
+		query:  "",
+		filter: &adminFilter{Limit: DEFAULT_FILTER_LIMIT},
+	}, {
+		desc:  "All parameters",
+		query: "user_id=3&from=2023-01-01T00:00:00Z&to=2023-02-01T00:00:00Z&limit=10&offset=20",
+		filter: &adminFilter{
+			UserID:   dbUtil.SqlNullInt64(3),
+			FromDate: dbUtil.SqlNullTime(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)),
+			ToDate:   dbUtil.SqlNullTime(time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)),
+			Limit:    10,
+			Offset:   20,
+		},
+	}, {
+		desc:  "Invalid user id",
+		query: "user_id=abc",
+	}, {
+		desc:  "Invalid date",
+		query: "from=2023-01-01",
+	}, {
+		desc:  "Limit too high",
+		query: "limit=501",
+	}, {
+		desc:  "Limit too low",
+		query: "limit=0",
+	}, {
+		desc:  "Negative offset",
+		query: "offset=-1",
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/sessions/flagged?"+tc.query, nil)
+			filter, err := parseAdminFilter(request)
+
+			if tc.filter == nil {
+				if err == nil {
+					t.Errorf("Expected error: %#v", filter)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if filter.UserID != tc.filter.UserID {
+				t.Errorf("UserID mismatch: %v expecting %v", filter.UserID, tc.filter.UserID)
+			}
+
+			if filter.FromDate.Valid != tc.filter.FromDate.Valid || !filter.FromDate.Time.Equal(tc.filter.FromDate.Time) {
+				t.Errorf("FromDate mismatch: %v expecting %v", filter.FromDate, tc.filter.FromDate)
+			}
+
+			if filter.ToDate.Valid != tc.filter.ToDate.Valid || !filter.ToDate.Time.Equal(tc.filter.ToDate.Time) {
+				t.Errorf("ToDate mismatch: %v expecting %v", filter.ToDate, tc.filter.ToDate)
+			}
+
+			if filter.Limit != tc.filter.Limit || filter.Offset != tc.filter.Offset {
+				t.Errorf("Paging mismatch: %v/%v expecting %v/%v", filter.Limit, filter.Offset, tc.filter.Limit, tc.filter.Offset)
+			}
+		})
+	}
+}
+
+func TestUnflagSession(t *testing.T) {
+	services := newAdminTestServices()
+	services.mockRepository.SetGetSessionByUidMockData(dbMocks.SessionMockData{Session: db.Session{
+		ID:        1,
+		Uid:       "SESSION0001",
+		IsFlagged: true,
+	}})
+
+	recorder := serveAdmin(services.restService, http.MethodPost, "/sessions/SESSION0001/unflag", ADMIN_API_TOKEN)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Status mismatch: %v expecting %v", recorder.Code, http.StatusOK)
+	}
+
+	updateSessionIsFlaggedByUidParams, err := services.mockRepository.GetUpdateSessionIsFlaggedByUidMockData()
+
+	if err != nil || updateSessionIsFlaggedByUidParams.Uid != "SESSION0001" || updateSessionIsFlaggedByUidParams.IsFlagged {
+		t.Errorf("Unflag mismatch: %#v, %v", updateSessionIsFlaggedByUidParams, err)
+	}
+}
+
+func TestStopSession(t *testing.T) {
+	t.Run("Session stopped", func(t *testing.T) {
+		services := newAdminTestServices()
+		services.mockRepository.SetGetSessionByUidMockData(dbMocks.SessionMockData{Session: db.Session{
+			ID:              1,
+			Uid:             "SESSION0001",
+			TokenID:         1,
+			AuthorizationID: dbUtil.SqlNullString("AUTH0001"),
+		}})
+		services.mockRepository.SetGetTokenMockData(dbMocks.TokenMockData{Token: db.Token{ID: 1, Type: db.TokenTypeOTHER}})
+		services.mockOcpiService.SetStopSessionMockData(&ocpirpc.StopSessionResponse{})
+
+		recorder := serveAdmin(services.restService, http.MethodPost, "/sessions/SESSION0001/stop", ADMIN_API_TOKEN)
+
+		if recorder.Code != http.StatusOK {
+			t.Errorf("Status mismatch: %v expecting %v", recorder.Code, http.StatusOK)
+		}
+	})
+
+	t.Run("Session cannot be stopped remotely", func(t *testing.T) {
+		services := newAdminTestServices()
+		services.mockRepository.SetGetSessionByUidMockData(dbMocks.SessionMockData{Session: db.Session{
+			ID:      1,
+			Uid:     "SESSION0001",
+			TokenID: 1,
+		}})
+		services.mockRepository.SetGetTokenMockData(dbMocks.TokenMockData{Token: db.Token{ID: 1, Type: db.TokenTypeRFID}})
+
+		recorder := serveAdmin(services.restService, http.MethodPost, "/sessions/SESSION0001/stop", ADMIN_API_TOKEN)
+
+		if recorder.Code != http.StatusConflict {
+			t.Errorf("Status mismatch: %v expecting %v", recorder.Code, http.StatusConflict)
+		}
+	})
+
+	t.Run("Session not found", func(t *testing.T) {
+		services := newAdminTestServices()
+
+		recorder := serveAdmin(services.restService, http.MethodPost, "/sessions/SESSION0001/stop", ADMIN_API_TOKEN)
+
+		if recorder.Code != http.StatusNotFound {
+			t.Errorf("Status mismatch: %v expecting %v", recorder.Code, http.StatusNotFound)
+		}
+	})
+}
+
+func TestProcessCdr(t *testing.T) {
+	t.Run("Cdr already processed", func(t *testing.T) {
+		cdr := db.Cdr{
+			ID:              1,
+			Uid:             "CDR0001",
+			AuthorizationID: dbUtil.SqlNullString("AUTH0001"),
+		}
+
+		services := newAdminTestServices()
+		services.mockRepository.SetGetCdrByUidMockData(dbMocks.CdrMockData{Cdr: cdr})
+		services.mockRepository.SetGetCdrByUidMockData(dbMocks.CdrMockData{Cdr: cdr})
+		services.mockRepository.SetGetSessionByAuthorizationIDMockData(dbMocks.SessionMockData{Session: db.Session{
+			ID:              1,
+			Uid:             "SESSION0001",
+			AuthorizationID: dbUtil.SqlNullString("AUTH0001"),
+			Status:          db.SessionStatusTypeINVOICED,
+		}})
+
+		recorder := serveAdmin(services.restService, http.MethodPost, "/cdrs/CDR0001/process", ADMIN_API_TOKEN)
+
+		if recorder.Code != http.StatusConflict {
+			t.Errorf("Status mismatch: %v expecting %v", recorder.Code, http.StatusConflict)
+		}
+	})
+
+	t.Run("Cdr not found", func(t *testing.T) {
+		services := newAdminTestServices()
+
+		recorder := serveAdmin(services.restService, http.MethodPost, "/cdrs/CDR0001/process", ADMIN_API_TOKEN)
+
+		if recorder.Code != http.StatusNotFound {
+			t.Errorf("Status mismatch: %v expecting %v", recorder.Code, http.StatusNotFound)
+		}
+	})
+}
+
+func TestReissueSessionInvoice(t *testing.T) {
+	session := db.Session{
+		ID:       1,
+		Uid:      "SESSION0001",
+		UserID:   1,
+		Currency: "EUR",
+	}
+
+	expiredSessionInvoice := db.SessionInvoice{
+		ID:             1,
+		SessionID:      1,
+		UserID:         1,
+		Currency:       "EUR",
+		PriceFiat:      1.25,
+		PriceMsat:      5000000,
+		TaxFiat:        0.25,
+		TaxMsat:        1000000,
+		TotalFiat:      1.5,
+		TotalMsat:      6000000,
+		PaymentRequest: "PAYMENTREQUEST0001",
+		IsExpired:      true,
+	}
+
+	t.Run("Reissued at session rate", func(t *testing.T) {
+		services := newAdminTestServices()
+		services.mockRepository.SetGetSessionInvoiceMockData(dbMocks.SessionInvoiceMockData{SessionInvoice: expiredSessionInvoice})
+		services.mockRepository.SetGetSessionMockData(dbMocks.SessionMockData{Session: session})
+		services.mockRepository.SetGetUserMockData(dbMocks.UserMockData{User: db.User{ID: 1}})
+		services.mockFerpService.SetGetRateMockData(&rate.CurrencyRate{Rate: 5000, RateMsat: 5000000, LastUpdated: time.Now()})
+		services.mockLightningService.SetSignMessageMockData(&lnrpc.SignMessageResponse{Signature: "SIGNATURE0001"})
+
+		recorder := serveAdmin(services.restService, http.MethodPost, "/invoices/1/reissue", ADMIN_API_TOKEN)
+
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("Status mismatch: %v expecting %v", recorder.Code, http.StatusOK)
+		}
+
+		updateSessionInvoiceParams, err := services.mockRepository.GetUpdateSessionInvoiceMockData()
+
+		if err != nil {
+			t.Fatalf("Expected session invoice update: %v", err)
+		}
+
+		// The session has no locked rate, the fiat total is converted again at the current rate
+		if updateSessionInvoiceParams.TotalMsat != 7500000 {
+			t.Errorf("TotalMsat mismatch: %v expecting %v", updateSessionInvoiceParams.TotalMsat, 7500000)
+		}
+
+		if updateSessionInvoiceParams.CurrencyRateMsat != 5000000 {
+			t.Errorf("CurrencyRateMsat mismatch: %v expecting %v", updateSessionInvoiceParams.CurrencyRateMsat, 5000000)
+		}
+
+		if updateSessionInvoiceParams.IsExpired {
+			t.Errorf("Expected session invoice not expired")
+		}
+	})
+
+	t.Run("Invoice not expired", func(t *testing.T) {
+		sessionInvoice := expiredSessionInvoice
+		sessionInvoice.IsExpired = false
+
+		services := newAdminTestServices()
+		services.mockRepository.SetGetSessionInvoiceMockData(dbMocks.SessionInvoiceMockData{SessionInvoice: sessionInvoice})
+
+		recorder := serveAdmin(services.restService, http.MethodPost, "/invoices/1/reissue", ADMIN_API_TOKEN)
+
+		if recorder.Code != http.StatusConflict {
+			t.Errorf("Status mismatch: %v expecting %v", recorder.Code, http.StatusConflict)
+		}
+	})
+
+	t.Run("Invalid invoice id", func(t *testing.T) {
+		services := newAdminTestServices()
+
+		recorder := serveAdmin(services.restService, http.MethodPost, "/invoices/abc/reissue", ADMIN_API_TOKEN)
+
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("Status mismatch: %v expecting %v", recorder.Code, http.StatusBadRequest)
+		}
+	})
+}
+
+func TestReissueSessionInvoices(t *testing.T) {
+	services := newAdminTestServices()
+	services.mockRepository.SetGetSessionByUidMockData(dbMocks.SessionMockData{Session: db.Session{
+		ID:       1,
+		Uid:      "SESSION0001",
+		UserID:   1,
+		Currency: "EUR",
+	}})
+	services.mockRepository.SetListSessionInvoicesBySessionIDMockData(dbMocks.SessionInvoicesMockData{SessionInvoices: []db.SessionInvoice{{
+		ID:        1,
+		SessionID: 1,
+		Currency:  "EUR",
+		TotalFiat: 1.5,
+		IsSettled: true,
+	}, {
+		ID:        2,
+		SessionID: 1,
+		Currency:  "EUR",
+		TotalFiat: 1.5,
+		IsExpired: true,
+	}, {
+		ID:        3,
+		SessionID: 1,
+		Currency:  "EUR",
+		TotalFiat: 1.5,
+		IsExpired: true,
+	}}})
+
+	// Only one exchange rate is available, so reissuing the last
+	// expired invoice fails without failing the whole request
+	for i := 0; i < 2; i++ {
+		services.mockRepository.SetGetSessionMockData(dbMocks.SessionMockData{Session: db.Session{ID: 1, Uid: "SESSION0001", UserID: 1, Currency: "EUR"}})
+		services.mockRepository.SetGetUserMockData(dbMocks.UserMockData{User: db.User{ID: 1}})
+	}
+
+	services.mockFerpService.SetGetRateMockData(&rate.CurrencyRate{Rate: 5000, RateMsat: 5000000, LastUpdated: time.Now()})
+	services.mockLightningService.SetSignMessageMockData(&lnrpc.SignMessageResponse{Signature: "SIGNATURE0001"})
+
+	recorder := serveAdmin(services.restService, http.MethodPost, "/sessions/SESSION0001/reissue", ADMIN_API_TOKEN)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Status mismatch: %v expecting %v", recorder.Code, http.StatusOK)
+	}
+
+	results := []AdminReissueResultDto{}
+
+	if err := json.Unmarshal(recorder.Body.Bytes(), &results); err != nil {
+		t.Fatalf("Error decoding response: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Result count mismatch: %v expecting %v", len(results), 2)
+	}
+
+	if results[0].SessionInvoiceID != 2 || results[0].Error != nil || results[0].SessionInvoice == nil {
+		t.Errorf("Expected session invoice 2 reissued: %#v", results[0])
+	}
+
+	if results[1].SessionInvoiceID != 3 || results[1].Error == nil {
+		t.Errorf("Expected error for session invoice 3: %#v", results[1])
+	}
+}
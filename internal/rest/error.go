@@ -0,0 +1,51 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+)
+
+type ErrResponse struct {
+	HTTPStatusCode int    `json:"-"`
+	StatusText     string `json:"status"`
+	ErrorText      string `json:"error,omitempty"`
+}
+
+func (e *ErrResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, e.HTTPStatusCode)
+	return nil
+}
+
+func ErrInvalidRequest(err error) render.Renderer {
+	return newErrResponse(http.StatusBadRequest, "Invalid request", err)
+}
+
+func ErrUnauthorized() render.Renderer {
+	return newErrResponse(http.StatusUnauthorized, "Unauthorized", nil)
+}
+
+func ErrNotFound(err error) render.Renderer {
+	return newErrResponse(http.StatusNotFound, "Not found", err)
+}
+
+func ErrConflict(err error) render.Renderer {
+	return newErrResponse(http.StatusConflict, "Conflict", err)
+}
+
+func ErrInternalServer(err error) render.Renderer {
+	return newErrResponse(http.StatusInternalServerError, "Internal server error", err)
+}
+
+func newErrResponse(statusCode int, statusText string, err error) render.Renderer {
+	response := &ErrResponse{
+		HTTPStatusCode: statusCode,
+		StatusText:     statusText,
+	}
+
+	if err != nil {
+		response.ErrorText = err.Error()
+	}
+
+	return response
+}
@@ -17,6 +17,12 @@ import (
 
 	"github.com/satimoto/go-datastore/pkg/db"
 	"github.com/satimoto/go-datastore/pkg/util"
+	"github.com/satimoto/go-lnm/internal/cdr"
+	"github.com/satimoto/go-lnm/internal/channel"
+	"github.com/satimoto/go-lnm/internal/health"
+	"github.com/satimoto/go-lnm/internal/lightningnetwork"
+	"github.com/satimoto/go-lnm/internal/service"
+	"github.com/satimoto/go-lnm/internal/session"
 )
 
 type Rest interface {
@@ -27,11 +33,25 @@ type Rest interface {
 type RestService struct {
 	*db.RepositoryService
 	*http.Server
+	HealthService   health.Health
+	CdrResolver     *cdr.CdrResolver
+	ChannelResolver *channel.ChannelResolver
+	SessionResolver *session.SessionResolver
+	LightningNodes  lightningnetwork.LightningNodes
+	adminApiToken   string
 }
 
-func NewRest(d *sql.DB) Rest {
+func NewRest(d *sql.DB, services *service.ServiceResolver) Rest {
+	repositoryService := db.NewRepositoryService(d)
+
 	return &RestService{
-		RepositoryService: db.NewRepositoryService(d),
+		RepositoryService: repositoryService,
+		HealthService:     health.NewService(d, services.FerpService, services.LightningNodes, services.OcpiService),
+		CdrResolver:       cdr.NewResolver(repositoryService, services),
+		ChannelResolver:   channel.NewResolver(repositoryService),
+		SessionResolver:   session.NewResolver(repositoryService, services),
+		LightningNodes:    services.LightningNodes,
+		adminApiToken:     os.Getenv("ADMIN_API_TOKEN"),
 	}
 }
 
@@ -45,6 +65,12 @@ func (rs *RestService) Handler() *chi.Mux {
 
 	router.Mount("/health", rs.mountHealth())
 
+	if len(rs.adminApiToken) > 0 {
+		router.Mount("/admin", rs.mountAdmin())
+	} else {
+		log.Printf("Admin API token not configured")
+	}
+
 	return router
 }
 
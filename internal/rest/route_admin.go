@@ -0,0 +1,61 @@
+package rest
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+func (rs *RestService) mountAdmin() *chi.Mux {
+	router := chi.NewRouter()
+	router.Use(rs.adminAuthenticator)
+
+	router.Route("/sessions", func(sessionRouter chi.Router) {
+		sessionRouter.Get("/flagged", rs.listFlaggedSessions)
+
+		sessionRouter.Route("/{session_uid}", func(sessionUidRouter chi.Router) {
+			sessionUidRouter.Get("/", rs.getSession)
+			sessionUidRouter.Post("/unflag", rs.unflagSession)
+			sessionUidRouter.Post("/stop", rs.stopSession)
+			sessionUidRouter.Post("/reissue", rs.reissueSessionInvoices)
+		})
+	})
+
+	router.Route("/cdrs", func(cdrRouter chi.Router) {
+		cdrRouter.Get("/flagged", rs.listFlaggedCdrs)
+		cdrRouter.Post("/{cdr_uid}/process", rs.processCdr)
+	})
+
+	router.Route("/channels", func(channelRouter chi.Router) {
+		channelRouter.Get("/", rs.listChannels)
+		channelRouter.Get("/{channel_point}", rs.getChannel)
+	})
+
+	router.Route("/graph", func(graphRouter chi.Router) {
+		graphRouter.Get("/peers", rs.listGraphPeers)
+		graphRouter.Get("/peers/{pubkey}", rs.getGraphPeer)
+	})
+
+	router.Route("/invoices", func(invoiceRouter chi.Router) {
+		invoiceRouter.Post("/{session_invoice_id}/reissue", rs.reissueSessionInvoice)
+	})
+
+	return router
+}
+
+// adminAuthenticator requires requests to have the admin API token as a bearer token
+func (rs *RestService) adminAuthenticator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+		if subtle.ConstantTimeCompare([]byte(token), []byte(rs.adminApiToken)) != 1 {
+			render.Render(w, r, ErrUnauthorized())
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
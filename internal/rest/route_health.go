@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/satimoto/go-lnm/internal/health"
 )
 
 func (rs *RestService) mountHealth() *chi.Mux {
@@ -12,5 +14,21 @@ func (rs *RestService) mountHealth() *chi.Mux {
 		w.Write([]byte("ok"))
 	})
 
+	router.Get("/live", func(w http.ResponseWriter, r *http.Request) {
+		renderHealth(w, r, rs.HealthService.Live(r.Context()))
+	})
+
+	router.Get("/ready", func(w http.ResponseWriter, r *http.Request) {
+		renderHealth(w, r, rs.HealthService.Ready(r.Context()))
+	})
+
 	return router
 }
+
+func renderHealth(w http.ResponseWriter, r *http.Request, healthDto *health.HealthDto) {
+	if healthDto.Status != health.STATUS_UP {
+		render.Status(r, http.StatusServiceUnavailable)
+	}
+
+	render.JSON(w, r, healthDto)
+}
@@ -0,0 +1,174 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/satimoto/go-lnm/internal/rpc/auth"
+	"github.com/satimoto/go-lnm/lsprpc"
+	"github.com/satimoto/go-lnm/pkg/lsp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestPolicy(t *testing.T) {
+	cases := []struct {
+		desc       string
+		fullMethod string
+		identity   string
+		allowed    bool
+	}{{
+		desc:       "Api calls invoice service",
+		fullMethod: "/invoice.InvoiceService/UpdateInvoiceRequest",
+		identity:   auth.IDENTITY_API,
+		allowed:    true,
+	}, {
+		desc:       "Ocpi calls invoice service",
+		fullMethod: "/invoice.InvoiceService/UpdateInvoiceRequest",
+		identity:   auth.IDENTITY_OCPI,
+	}, {
+		desc:       "Ocpi calls session service",
+		fullMethod: "/session.SessionService/SessionCreated",
+		identity:   auth.IDENTITY_OCPI,
+		allowed:    true,
+	}, {
+		desc:       "Api calls session service",
+		fullMethod: "/session.SessionService/SessionCreated",
+		identity:   auth.IDENTITY_API,
+	}, {
+		desc:       "Admin calls session service",
+		fullMethod: "/session.SessionService/SessionUpdated",
+		identity:   auth.IDENTITY_ADMIN,
+		allowed:    true,
+	}, {
+		desc:       "Api watches session",
+		fullMethod: "/lspsession.SessionService/WatchSession",
+		identity:   auth.IDENTITY_API,
+		allowed:    true,
+	}, {
+		desc:       "Ocpi watches session",
+		fullMethod: "/lspsession.SessionService/WatchSession",
+		identity:   auth.IDENTITY_OCPI,
+	}, {
+		desc:       "Ocpi checks health",
+		fullMethod: "/grpc.health.v1.Health/Check",
+		identity:   auth.IDENTITY_OCPI,
+		allowed:    true,
+	}, {
+		desc:       "Api calls reflection",
+		fullMethod: "/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo",
+		identity:   auth.IDENTITY_API,
+	}, {
+		desc:       "Unknown service",
+		fullMethod: "/unknown.UnknownService/Call",
+		identity:   auth.IDENTITY_ADMIN,
+	}}
+
+	policy := auth.NewPolicy()
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if allowed := policy.IsAllowed(tc.fullMethod, tc.identity); allowed != tc.allowed {
+				t.Errorf("Allowed mismatch: %v expecting %v", allowed, tc.allowed)
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	cases := []struct {
+		desc           string
+		commonName     string
+		assertedUserID string
+		fullMethod     string
+		request        interface{}
+		code           codes.Code
+	}{{
+		desc:           "Api with matching user",
+		commonName:     auth.IDENTITY_API,
+		assertedUserID: "1",
+		fullMethod:     "/invoice.InvoiceService/UpdateSessionInvoice",
+		request:        &lsprpc.UpdateSessionInvoiceRequest{Id: 1, UserId: 1},
+		code:           codes.OK,
+	}, {
+		desc:           "Api with other user",
+		commonName:     auth.IDENTITY_API,
+		assertedUserID: "2",
+		fullMethod:     "/invoice.InvoiceService/UpdateSessionInvoice",
+		request:        &lsprpc.UpdateSessionInvoiceRequest{Id: 1, UserId: 1},
+		code:           codes.PermissionDenied,
+	}, {
+		desc:       "Api without asserted user",
+		commonName: auth.IDENTITY_API,
+		fullMethod: "/invoice.InvoiceService/UpdateSessionInvoice",
+		request:    &lsprpc.UpdateSessionInvoiceRequest{Id: 1, UserId: 1},
+		code:       codes.PermissionDenied,
+	}, {
+		desc:       "Admin without asserted user",
+		commonName: auth.IDENTITY_ADMIN,
+		fullMethod: "/invoice.InvoiceService/UpdateSessionInvoice",
+		request:    &lsprpc.UpdateSessionInvoiceRequest{Id: 1, UserId: 1},
+		code:       codes.OK,
+	}, {
+		desc:       "Ocpi calls invoice service",
+		commonName: auth.IDENTITY_OCPI,
+		fullMethod: "/invoice.InvoiceService/UpdateSessionInvoice",
+		request:    &lsprpc.UpdateSessionInvoiceRequest{Id: 1, UserId: 1},
+		code:       codes.PermissionDenied,
+	}, {
+		desc:       "Unknown identity",
+		commonName: "unknown",
+		fullMethod: "/invoice.InvoiceService/UpdateSessionInvoice",
+		request:    &lsprpc.UpdateSessionInvoiceRequest{Id: 1, UserId: 1},
+		code:       codes.Unauthenticated,
+	}, {
+		desc:       "Missing certificate",
+		fullMethod: "/invoice.InvoiceService/UpdateSessionInvoice",
+		request:    &lsprpc.UpdateSessionInvoiceRequest{Id: 1, UserId: 1},
+		code:       codes.Unauthenticated,
+	}}
+
+	interceptor := auth.NewAuthorizer(auth.NewPolicy()).UnaryServerInterceptor()
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := context.Background()
+
+			if len(tc.commonName) > 0 {
+				ctx = peer.NewContext(ctx, &peer.Peer{
+					AuthInfo: credentials.TLSInfo{
+						State: tls.ConnectionState{
+							VerifiedChains: [][]*x509.Certificate{{{
+								Subject: pkix.Name{CommonName: tc.commonName},
+							}}},
+						},
+					},
+				})
+			}
+
+			if len(tc.assertedUserID) > 0 {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(lsp.METADATA_USER_ID, tc.assertedUserID))
+			}
+
+			info := &grpc.UnaryServerInfo{FullMethod: tc.fullMethod}
+			_, err := interceptor(ctx, tc.request, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				if identity, ok := auth.GetIdentity(ctx); !ok || identity != tc.commonName {
+					t.Errorf("Identity mismatch: %v expecting %v", identity, tc.commonName)
+				}
+
+				return nil, nil
+			})
+
+			if code := status.Code(err); code != tc.code {
+				t.Errorf("Code mismatch: %v expecting %v", code, tc.code)
+			}
+		})
+	}
+}
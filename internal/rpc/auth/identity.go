@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+const (
+	IDENTITY_ADMIN = "admin"
+	IDENTITY_API   = "api"
+	IDENTITY_OCPI  = "ocpi"
+)
+
+var (
+	ErrMissingCertificate = errors.New("missing client certificate")
+	ErrUnknownIdentity    = errors.New("unknown client identity")
+)
+
+type identityKey struct{}
+
+// GetIdentity returns the service identity of the caller
+func GetIdentity(ctx context.Context) (string, bool) {
+	identity, ok := ctx.Value(identityKey{}).(string)
+
+	return identity, ok
+}
+
+func withIdentity(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// getPeerIdentity returns the identity from the common name of the
+// verified client certificate
+func getPeerIdentity(ctx context.Context) (string, error) {
+	p, ok := peer.FromContext(ctx)
+
+	if !ok {
+		return "", ErrMissingCertificate
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return "", ErrMissingCertificate
+	}
+
+	return getCertificateIdentity(tlsInfo.State.VerifiedChains[0][0])
+}
+
+func getCertificateIdentity(certificate *x509.Certificate) (string, error) {
+	switch identity := certificate.Subject.CommonName; identity {
+	case IDENTITY_ADMIN, IDENTITY_API, IDENTITY_OCPI:
+		return identity, nil
+	}
+
+	return "", ErrUnknownIdentity
+}
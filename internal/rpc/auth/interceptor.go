@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"context"
+	"log"
+	"strconv"
+
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+	"github.com/satimoto/go-lnm/pkg/lsp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type userRequest interface {
+	GetUserId() int64
+}
+
+type Authorizer struct {
+	Policy Policy
+}
+
+func NewAuthorizer(policy Policy) *Authorizer {
+	return &Authorizer{
+		Policy: policy,
+	}
+}
+
+func (a *Authorizer) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := a.authorize(ctx, info.FullMethod)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err := checkUser(ctx, info.FullMethod, req); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func (a *Authorizer) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authorize(stream.Context(), info.FullMethod)
+
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &authorizedStream{
+			ServerStream: stream,
+			ctx:          ctx,
+			fullMethod:   info.FullMethod,
+		})
+	}
+}
+
+// authorize checks the caller identity is allowed to call the method
+// and adds the identity to the context
+func (a *Authorizer) authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	identity, err := getPeerIdentity(ctx)
+
+	if err != nil {
+		metrics.RecordError("LNM219", "Error authenticating rpc caller", err)
+		log.Printf("LNM219: Method=%v", fullMethod)
+		RecordUnauthorized(fullMethod, codes.Unauthenticated)
+		return ctx, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if !a.Policy.IsAllowed(fullMethod, identity) {
+		log.Printf("LNM220: Method=%v, Identity=%v", fullMethod, identity)
+		RecordUnauthorized(fullMethod, codes.PermissionDenied)
+		return ctx, status.Errorf(codes.PermissionDenied, "%s is not allowed to call %s", identity, fullMethod)
+	}
+
+	return withIdentity(ctx, identity), nil
+}
+
+// checkUser checks that requests from the api identity containing a user ID
+// match the user asserted in the request metadata
+func checkUser(ctx context.Context, fullMethod string, req interface{}) error {
+	request, ok := req.(userRequest)
+
+	if !ok {
+		return nil
+	}
+
+	if identity, _ := GetIdentity(ctx); identity != IDENTITY_API {
+		return nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(lsp.METADATA_USER_ID)
+
+	if len(values) != 1 {
+		log.Printf("LNM221: Method=%v, UserID=%v", fullMethod, request.GetUserId())
+		RecordUnauthorized(fullMethod, codes.PermissionDenied)
+		return status.Error(codes.PermissionDenied, "missing asserted user")
+	}
+
+	if userID, err := strconv.ParseInt(values[0], 10, 64); err != nil || userID != request.GetUserId() {
+		log.Printf("LNM222: Method=%v, UserID=%v, AssertedUserID=%v", fullMethod, request.GetUserId(), values[0])
+		RecordUnauthorized(fullMethod, codes.PermissionDenied)
+		return status.Error(codes.PermissionDenied, "user does not match asserted user")
+	}
+
+	return nil
+}
+
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx        context.Context
+	fullMethod string
+}
+
+func (s *authorizedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *authorizedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	return checkUser(s.ctx, s.fullMethod, m)
+}
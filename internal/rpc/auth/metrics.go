@@ -0,0 +1,18 @@
+package auth
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	metricRpcUnauthorizedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lsp_rpc_unauthorized_total",
+		Help: "The total number of unauthorized rpc calls",
+	}, []string{"method", "code"})
+)
+
+func RecordUnauthorized(fullMethod string, code codes.Code) {
+	metricRpcUnauthorizedTotal.WithLabelValues(fullMethod, code.String()).Inc()
+}
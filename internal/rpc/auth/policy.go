@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"strings"
+)
+
+// Policy maps a service name, or a service and method name, to the
+// identities allowed to call it. Method entries take precedence.
+type Policy map[string][]string
+
+func NewPolicy() Policy {
+	return Policy{
+		"CdrService":          {IDENTITY_OCPI, IDENTITY_ADMIN},
+		"RpcService":          {IDENTITY_OCPI, IDENTITY_ADMIN},
+		"SessionService":      {IDENTITY_OCPI, IDENTITY_ADMIN},
+		"ChannelService":      {IDENTITY_API, IDENTITY_ADMIN},
+		"InvoiceService":      {IDENTITY_API, IDENTITY_ADMIN},
+		"NotificationService": {IDENTITY_API, IDENTITY_ADMIN},
+		"Health":              {IDENTITY_API, IDENTITY_OCPI, IDENTITY_ADMIN},
+		"ServerReflection":    {IDENTITY_ADMIN},
+
+		// The lsprpc SessionService shares its short name with the ocpirpc SessionService
+		"SessionService/WatchSession": {IDENTITY_API, IDENTITY_ADMIN},
+	}
+}
+
+// IsAllowed checks if the identity can call the full method name,
+// methods not in the policy are denied
+func (p Policy) IsAllowed(fullMethod, identity string) bool {
+	service, method := splitMethodName(fullMethod)
+	identities, ok := p[service+"/"+method]
+
+	if !ok {
+		identities, ok = p[service]
+	}
+
+	if ok {
+		for _, allowedIdentity := range identities {
+			if allowedIdentity == identity {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// splitMethodName splits "/package.Service/Method" into the service name
+// without its package and the method name
+func splitMethodName(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	service, method := fullMethod, ""
+
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		service, method = fullMethod[:i], fullMethod[i+1:]
+	}
+
+	if i := strings.LastIndex(service, "."); i >= 0 {
+		service = service[i+1:]
+	}
+
+	return service, method
+}
@@ -0,0 +1,162 @@
+package interceptor
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"time"
+
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// METADATA_REQUEST_ID is the request and response metadata key of the request ID
+const METADATA_REQUEST_ID = "x-request-id"
+
+type requestIDKey struct{}
+
+// GetRequestID returns the ID of the rpc request
+func GetRequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+
+	return requestID
+}
+
+// UnaryServerInterceptors returns the request ID, metrics and
+// panic recovery interceptors in the order they should be chained
+func UnaryServerInterceptors() []grpc.UnaryServerInterceptor {
+	return []grpc.UnaryServerInterceptor{
+		unaryRequestIDInterceptor,
+		unaryMetricsInterceptor,
+		unaryRecoveryInterceptor,
+	}
+}
+
+// StreamServerInterceptors returns the request ID, metrics and
+// panic recovery interceptors in the order they should be chained
+func StreamServerInterceptors() []grpc.StreamServerInterceptor {
+	return []grpc.StreamServerInterceptor{
+		streamRequestIDInterceptor,
+		streamMetricsInterceptor,
+		streamRecoveryInterceptor,
+	}
+}
+
+func unaryRequestIDInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx = withRequestID(ctx)
+	timerStart := time.Now()
+	log.Printf("Rpc %v started: RequestID=%v", info.FullMethod, GetRequestID(ctx))
+
+	response, err := handler(ctx, req)
+	log.Printf("Rpc %v finished in %f seconds: RequestID=%v, Code=%v", info.FullMethod, time.Since(timerStart).Seconds(), GetRequestID(ctx), status.Code(err))
+
+	return response, err
+}
+
+func streamRequestIDInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx := withRequestID(stream.Context())
+	timerStart := time.Now()
+	log.Printf("Rpc stream %v started: RequestID=%v", info.FullMethod, GetRequestID(ctx))
+
+	err := handler(srv, &contextStream{ServerStream: stream, ctx: ctx})
+	log.Printf("Rpc stream %v finished in %f seconds: RequestID=%v, Code=%v", info.FullMethod, time.Since(timerStart).Seconds(), GetRequestID(ctx), status.Code(err))
+
+	return err
+}
+
+func unaryMetricsInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	done := recordRequest(info.FullMethod)
+	response, err := handler(ctx, req)
+	done(err)
+
+	return response, err
+}
+
+func streamMetricsInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	done := recordRequest(info.FullMethod)
+	err := handler(srv, stream)
+	done(err)
+
+	return err
+}
+
+func unaryRecoveryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (response interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recoverPanic(ctx, info.FullMethod, r)
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+func streamRecoveryInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recoverPanic(stream.Context(), info.FullMethod, r)
+		}
+	}()
+
+	return handler(srv, stream)
+}
+
+func recordRequest(fullMethod string) func(error) {
+	timerStart := time.Now()
+	metricRpcRequestsInFlight.WithLabelValues(fullMethod).Inc()
+
+	return func(err error) {
+		metricRpcRequestsInFlight.WithLabelValues(fullMethod).Dec()
+		metricRpcRequestDurationSeconds.WithLabelValues(fullMethod).Observe(time.Since(timerStart).Seconds())
+		metricRpcRequestsTotal.WithLabelValues(fullMethod, status.Code(err).String()).Inc()
+	}
+}
+
+func recoverPanic(ctx context.Context, fullMethod string, r interface{}) error {
+	metricRpcPanicsTotal.WithLabelValues(fullMethod).Inc()
+	metrics.RecordError("LNM225", "Panic in rpc handler", fmt.Errorf("%v", r))
+	log.Printf("LNM225: Method=%v, RequestID=%v, Panic=%v\n%s", fullMethod, GetRequestID(ctx), r, debug.Stack())
+
+	return status.Error(codes.Internal, "internal error")
+}
+
+// withRequestID adds the request ID from the incoming metadata, or a new
+// request ID, to the context and returns it in the response header
+func withRequestID(ctx context.Context) context.Context {
+	requestID := ""
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(METADATA_REQUEST_ID); len(values) > 0 && len(values[0]) > 0 && len(values[0]) <= 64 {
+			requestID = values[0]
+		}
+	}
+
+	if len(requestID) == 0 {
+		requestID = newRequestID()
+	}
+
+	grpc.SetHeader(ctx, metadata.Pairs(METADATA_REQUEST_ID, requestID))
+
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+func newRequestID() string {
+	bytes := make([]byte, 8)
+	rand.Read(bytes)
+
+	return hex.EncodeToString(bytes)
+}
+
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
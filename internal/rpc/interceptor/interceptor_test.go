@@ -0,0 +1,77 @@
+package interceptor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/satimoto/go-lnm/internal/rpc/interceptor"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptors(t *testing.T) {
+	cases := []struct {
+		desc      string
+		requestID string
+		panic     bool
+		code      codes.Code
+	}{{
+		desc:      "Request ID from metadata",
+		requestID: "abc123",
+		code:      codes.OK,
+	}, {
+		desc: "Generated request ID",
+		code: codes.OK,
+	}, {
+		desc:  "Recovered panic",
+		panic: true,
+		code:  codes.Internal,
+	}}
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.TestService/Call"}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := context.Background()
+
+			if len(tc.requestID) > 0 {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(interceptor.METADATA_REQUEST_ID, tc.requestID))
+			}
+
+			requestID := ""
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				requestID = interceptor.GetRequestID(ctx)
+
+				if tc.panic {
+					panic("handler panic")
+				}
+
+				return req, nil
+			}
+
+			_, err := chainUnary(interceptor.UnaryServerInterceptors(), info, handler)(ctx, "request")
+
+			if code := status.Code(err); code != tc.code {
+				t.Errorf("Code mismatch: %v expecting %v", code, tc.code)
+			}
+
+			if len(requestID) == 0 || (len(tc.requestID) > 0 && requestID != tc.requestID) {
+				t.Errorf("Request ID mismatch: %v expecting %v", requestID, tc.requestID)
+			}
+		})
+	}
+}
+
+func chainUnary(interceptors []grpc.UnaryServerInterceptor, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) grpc.UnaryHandler {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		next, unaryInterceptor := handler, interceptors[i]
+
+		handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+			return unaryInterceptor(ctx, req, info, next)
+		}
+	}
+
+	return handler
+}
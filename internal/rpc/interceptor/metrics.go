@@ -0,0 +1,26 @@
+package interceptor
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricRpcRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lsp_rpc_requests_total",
+		Help: "The total number of rpc requests",
+	}, []string{"method", "code"})
+	metricRpcRequestDurationSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "lsp_rpc_request_duration_seconds",
+		Help:    "The duration of rpc requests in seconds",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method"})
+	metricRpcRequestsInFlight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "lsp_rpc_requests_in_flight",
+		Help: "The number of rpc requests in flight",
+	}, []string{"method"})
+	metricRpcPanicsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lsp_rpc_panics_total",
+		Help: "The total number of recovered rpc panics",
+	}, []string{"method"})
+)
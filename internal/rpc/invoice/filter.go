@@ -0,0 +1,66 @@
+package invoice
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/satimoto/go-lnm/lsprpc"
+)
+
+const (
+	DEFAULT_LIST_LIMIT = 50
+	MAX_LIST_LIMIT     = 500
+)
+
+func parseBoolFilter(boolFilter lsprpc.BoolFilter) sql.NullBool {
+	switch boolFilter {
+	case lsprpc.BoolFilter_BOOL_FILTER_TRUE:
+		return sql.NullBool{Bool: true, Valid: true}
+	case lsprpc.BoolFilter_BOOL_FILTER_FALSE:
+		return sql.NullBool{Bool: false, Valid: true}
+	}
+
+	return sql.NullBool{}
+}
+
+func parseDateFilter(date string) (sql.NullTime, error) {
+	if len(date) == 0 {
+		return sql.NullTime{}, nil
+	}
+
+	value, err := time.Parse(time.RFC3339, date)
+
+	if err != nil {
+		return sql.NullTime{}, err
+	}
+
+	return sql.NullTime{Time: value, Valid: true}, nil
+}
+
+func parseFilterDates(from, to string, fromDate, toDate *sql.NullTime) (err error) {
+	if *fromDate, err = parseDateFilter(from); err != nil {
+		return errors.New("invalid from date")
+	}
+
+	if *toDate, err = parseDateFilter(to); err != nil {
+		return errors.New("invalid to date")
+	}
+
+	return nil
+}
+
+// parsePagination defaults an unset limit and caps it at MAX_LIST_LIMIT
+func parsePagination(limit, offset int32) (int32, int32, error) {
+	if limit < 0 || offset < 0 {
+		return 0, 0, errors.New("invalid pagination")
+	}
+
+	if limit == 0 {
+		limit = DEFAULT_LIST_LIMIT
+	} else if limit > MAX_LIST_LIMIT {
+		limit = MAX_LIST_LIMIT
+	}
+
+	return limit, offset, nil
+}
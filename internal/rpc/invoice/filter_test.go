@@ -0,0 +1,131 @@
+package invoice
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/satimoto/go-lnm/lsprpc"
+)
+
+func TestParseBoolFilter(t *testing.T) {
+	cases := []struct {
+		desc       string
+		boolFilter lsprpc.BoolFilter
+		value      sql.NullBool
+	}{{
+		desc:       "Any",
+		boolFilter: lsprpc.BoolFilter_BOOL_FILTER_ANY,
+		value:      sql.NullBool{},
+	}, {
+		desc:       "True",
+		boolFilter: lsprpc.BoolFilter_BOOL_FILTER_TRUE,
+		value:      sql.NullBool{Bool: true, Valid: true},
+	}, {
+		desc:       "False",
+		boolFilter: lsprpc.BoolFilter_BOOL_FILTER_FALSE,
+		value:      sql.NullBool{Bool: false, Valid: true},
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if value := parseBoolFilter(tc.boolFilter); value != tc.value {
+				t.Errorf("Value mismatch: %v expecting %v", value, tc.value)
+			}
+		})
+	}
+}
+
+func TestParseFilterDates(t *testing.T) {
+	cases := []struct {
+		desc     string
+		from     string
+		to       string
+		fromDate sql.NullTime
+		toDate   sql.NullTime
+		err      bool
+	}{{
+		desc: "No dates",
+	}, {
+		desc:     "Both dates",
+		from:     "2023-01-01T00:00:00Z",
+		to:       "2023-02-01T12:30:00+01:00",
+		fromDate: sql.NullTime{Time: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+		toDate:   sql.NullTime{Time: time.Date(2023, 2, 1, 11, 30, 0, 0, time.UTC), Valid: true},
+	}, {
+		desc: "Invalid from date",
+		from: "2023-01-01",
+		err:  true,
+	}, {
+		desc: "Invalid to date",
+		to:   "yesterday",
+		err:  true,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var fromDate, toDate sql.NullTime
+			err := parseFilterDates(tc.from, tc.to, &fromDate, &toDate)
+
+			if (err != nil) != tc.err {
+				t.Fatalf("Error mismatch: %v expecting error %v", err, tc.err)
+			}
+
+			if tc.err {
+				return
+			}
+
+			if fromDate.Valid != tc.fromDate.Valid || !fromDate.Time.Equal(tc.fromDate.Time) {
+				t.Errorf("From date mismatch: %v expecting %v", fromDate, tc.fromDate)
+			}
+
+			if toDate.Valid != tc.toDate.Valid || !toDate.Time.Equal(tc.toDate.Time) {
+				t.Errorf("To date mismatch: %v expecting %v", toDate, tc.toDate)
+			}
+		})
+	}
+}
+
+func TestParsePagination(t *testing.T) {
+	cases := []struct {
+		desc   string
+		limit  int32
+		offset int32
+		expect [2]int32
+		err    bool
+	}{{
+		desc:   "Default limit",
+		expect: [2]int32{DEFAULT_LIST_LIMIT, 0},
+	}, {
+		desc:   "Limit and offset",
+		limit:  10,
+		offset: 20,
+		expect: [2]int32{10, 20},
+	}, {
+		desc:   "Limit capped",
+		limit:  MAX_LIST_LIMIT + 1,
+		expect: [2]int32{MAX_LIST_LIMIT, 0},
+	}, {
+		desc:  "Negative limit",
+		limit: -1,
+		err:   true,
+	}, {
+		desc:   "Negative offset",
+		offset: -1,
+		err:    true,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			limit, offset, err := parsePagination(tc.limit, tc.offset)
+
+			if (err != nil) != tc.err {
+				t.Fatalf("Error mismatch: %v expecting error %v", err, tc.err)
+			}
+
+			if !tc.err && (limit != tc.expect[0] || offset != tc.expect[1]) {
+				t.Errorf("Pagination mismatch: %v/%v expecting %v/%v", limit, offset, tc.expect[0], tc.expect[1])
+			}
+		})
+	}
+}
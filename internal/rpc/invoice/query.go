@@ -0,0 +1,256 @@
+package invoice
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+	"github.com/satimoto/go-lnm/internal/session"
+	"github.com/satimoto/go-lnm/lsprpc"
+)
+
+func (r *RpcInvoiceResolver) ListSessionInvoices(reqCtx context.Context, input *lsprpc.ListSessionInvoicesRequest) (*lsprpc.ListSessionInvoicesResponse, error) {
+	if input != nil {
+		ctx := context.Background()
+		filterSessionInvoicesParams := db.FilterSessionInvoicesParams{
+			UserID:    input.UserId,
+			IsSettled: parseBoolFilter(input.IsSettled),
+			IsExpired: parseBoolFilter(input.IsExpired),
+		}
+
+		if len(input.SessionUid) > 0 {
+			sess, err := r.getUserSession(ctx, input.UserId, input.SessionUid)
+
+			if err != nil {
+				return nil, err
+			}
+
+			filterSessionInvoicesParams.SessionID = sql.NullInt64{Int64: sess.ID, Valid: true}
+		}
+
+		if err := parseFilterDates(input.FromDate, input.ToDate, &filterSessionInvoicesParams.FromDate, &filterSessionInvoicesParams.ToDate); err != nil {
+			return nil, err
+		}
+
+		limit, offset, err := parsePagination(input.Limit, input.Offset)
+
+		if err != nil {
+			return nil, err
+		}
+
+		filterSessionInvoicesParams.Limit = limit
+		filterSessionInvoicesParams.Offset = offset
+
+		sessionInvoices, err := r.SessionRepository.FilterSessionInvoices(ctx, filterSessionInvoicesParams)
+
+		if err != nil {
+			metrics.RecordError("LNM230", "Error listing session invoices", err)
+			log.Printf("LNM230: Params=%#v", filterSessionInvoicesParams)
+			return nil, errors.New("error listing session invoices")
+		}
+
+		response := &lsprpc.ListSessionInvoicesResponse{
+			SessionInvoices: []*lsprpc.SessionInvoice{},
+		}
+
+		for _, sessionInvoice := range sessionInvoices {
+			response.SessionInvoices = append(response.SessionInvoices, createSessionInvoiceResponse(sessionInvoice))
+		}
+
+		return response, nil
+	}
+
+	return nil, errors.New("missing request")
+}
+
+func (r *RpcInvoiceResolver) GetSessionInvoice(reqCtx context.Context, input *lsprpc.GetSessionInvoiceRequest) (*lsprpc.SessionInvoice, error) {
+	if input != nil {
+		ctx := context.Background()
+		sessionInvoice, err := r.SessionRepository.GetSessionInvoice(ctx, input.Id)
+
+		if err != nil {
+			metrics.RecordError("LNM231", "Error retrieving session invoice", err)
+			log.Printf("LNM231: Input=%#v", input)
+			return nil, errors.New("error retrieving session invoice")
+		}
+
+		if sessionInvoice.UserID != input.UserId {
+			metrics.RecordError("LNM232", "Error invalid user for session invoice", errors.New("user mismatch"))
+			log.Printf("LNM232: Input=%#v", input)
+			return nil, errors.New("error invalid user for session invoice")
+		}
+
+		return createSessionInvoiceResponse(sessionInvoice), nil
+	}
+
+	return nil, errors.New("missing request")
+}
+
+func (r *RpcInvoiceResolver) ListInvoiceRequests(reqCtx context.Context, input *lsprpc.ListInvoiceRequestsRequest) (*lsprpc.ListInvoiceRequestsResponse, error) {
+	if input != nil {
+		ctx := context.Background()
+		filterInvoiceRequestsParams := db.FilterInvoiceRequestsParams{
+			UserID:    input.UserId,
+			IsSettled: parseBoolFilter(input.IsSettled),
+		}
+
+		if err := parseFilterDates(input.FromDate, input.ToDate, &filterInvoiceRequestsParams.FromDate, &filterInvoiceRequestsParams.ToDate); err != nil {
+			return nil, err
+		}
+
+		limit, offset, err := parsePagination(input.Limit, input.Offset)
+
+		if err != nil {
+			return nil, err
+		}
+
+		filterInvoiceRequestsParams.Limit = limit
+		filterInvoiceRequestsParams.Offset = offset
+
+		invoiceRequests, err := r.InvoiceRequestRepository.FilterInvoiceRequests(ctx, filterInvoiceRequestsParams)
+
+		if err != nil {
+			metrics.RecordError("LNM233", "Error listing invoice requests", err)
+			log.Printf("LNM233: Params=%#v", filterInvoiceRequestsParams)
+			return nil, errors.New("error listing invoice requests")
+		}
+
+		response := &lsprpc.ListInvoiceRequestsResponse{
+			InvoiceRequests: []*lsprpc.InvoiceRequest{},
+		}
+
+		for _, invoiceRequest := range invoiceRequests {
+			response.InvoiceRequests = append(response.InvoiceRequests, createInvoiceRequestResponse(invoiceRequest))
+		}
+
+		return response, nil
+	}
+
+	return nil, errors.New("missing request")
+}
+
+func (r *RpcInvoiceResolver) GetSessionBillingSummary(reqCtx context.Context, input *lsprpc.GetSessionBillingSummaryRequest) (*lsprpc.SessionBillingSummary, error) {
+	if input != nil {
+		ctx := context.Background()
+		sess, err := r.getUserSession(ctx, input.UserId, input.SessionUid)
+
+		if err != nil {
+			return nil, err
+		}
+
+		sessionInvoices, err := r.SessionRepository.ListSessionInvoicesBySessionID(ctx, sess.ID)
+
+		if err != nil {
+			metrics.RecordError("LNM234", "Error retrieving session invoices", err)
+			log.Printf("LNM234: SessionUid=%v", sess.Uid)
+			return nil, errors.New("error retrieving session invoices")
+		}
+
+		priceFiat, priceMsat := session.CalculatePriceInvoiced(sessionInvoices)
+		totalFiat, totalMsat := session.CalculateTotalInvoiced(sessionInvoices)
+		summary := &lsprpc.SessionBillingSummary{
+			SessionUid:    sess.Uid,
+			Status:        string(sess.Status),
+			Currency:      sess.Currency,
+			InvoiceCount:  int32(len(sessionInvoices)),
+			PriceFiat:     priceFiat,
+			PriceMsat:     priceMsat,
+			TotalFiat:     totalFiat,
+			TotalMsat:     totalMsat,
+			UnsettledFiat: totalFiat,
+			UnsettledMsat: totalMsat,
+		}
+
+		for _, sessionInvoice := range sessionInvoices {
+			summary.CommissionFiat += sessionInvoice.CommissionFiat
+			summary.CommissionMsat += sessionInvoice.CommissionMsat
+			summary.TaxFiat += sessionInvoice.TaxFiat
+			summary.TaxMsat += sessionInvoice.TaxMsat
+
+			if sessionInvoice.IsSettled {
+				summary.SettledCount++
+				summary.SettledFiat += sessionInvoice.TotalFiat
+				summary.SettledMsat += sessionInvoice.TotalMsat
+				summary.UnsettledFiat -= sessionInvoice.TotalFiat
+				summary.UnsettledMsat -= sessionInvoice.TotalMsat
+			}
+		}
+
+		return summary, nil
+	}
+
+	return nil, errors.New("missing request")
+}
+
+func (r *RpcInvoiceResolver) getUserSession(ctx context.Context, userID int64, sessionUid string) (*db.Session, error) {
+	sess, err := r.SessionRepository.GetSessionByUid(ctx, sessionUid)
+
+	if err != nil {
+		metrics.RecordError("LNM235", "Error retrieving session", err)
+		log.Printf("LNM235: SessionUid=%v", sessionUid)
+		return nil, errors.New("error retrieving session")
+	}
+
+	if sess.UserID != userID {
+		metrics.RecordError("LNM236", "Error invalid user for session", errors.New("user mismatch"))
+		log.Printf("LNM236: SessionUid=%v, UserID=%v", sessionUid, userID)
+		return nil, errors.New("error invalid user for session")
+	}
+
+	return &sess, nil
+}
+
+func createSessionInvoiceResponse(sessionInvoice db.SessionInvoice) *lsprpc.SessionInvoice {
+	return &lsprpc.SessionInvoice{
+		Id:                    sessionInvoice.ID,
+		UserId:                sessionInvoice.UserID,
+		SessionId:             sessionInvoice.SessionID,
+		Currency:              sessionInvoice.Currency,
+		CurrencyRate:          sessionInvoice.CurrencyRate,
+		CurrencyRateMsat:      sessionInvoice.CurrencyRateMsat,
+		CurrencyRateSpreadPpm: sessionInvoice.CurrencyRateSpreadPpm,
+		PriceFiat:             sessionInvoice.PriceFiat,
+		PriceMsat:             sessionInvoice.PriceMsat,
+		CommissionFiat:        sessionInvoice.CommissionFiat,
+		CommissionMsat:        sessionInvoice.CommissionMsat,
+		TaxFiat:               sessionInvoice.TaxFiat,
+		TaxMsat:               sessionInvoice.TaxMsat,
+		TotalFiat:             sessionInvoice.TotalFiat,
+		TotalMsat:             sessionInvoice.TotalMsat,
+		EstimatedEnergy:       sessionInvoice.EstimatedEnergy,
+		EstimatedTime:         sessionInvoice.EstimatedTime,
+		MeteredEnergy:         sessionInvoice.MeteredEnergy,
+		MeteredTime:           sessionInvoice.MeteredTime,
+		PaymentRequest:        sessionInvoice.PaymentRequest,
+		Signature:             sessionInvoice.Signature,
+		IsSettled:             sessionInvoice.IsSettled,
+		IsExpired:             sessionInvoice.IsExpired,
+		LastUpdated:           sessionInvoice.LastUpdated.Format(time.RFC3339),
+	}
+}
+
+func createInvoiceRequestResponse(invoiceRequest db.InvoiceRequest) *lsprpc.InvoiceRequest {
+	return &lsprpc.InvoiceRequest{
+		Id:                    invoiceRequest.ID,
+		UserId:                invoiceRequest.UserID,
+		Currency:              invoiceRequest.Currency,
+		CurrencyRate:          invoiceRequest.CurrencyRate,
+		CurrencyRateMsat:      invoiceRequest.CurrencyRateMsat,
+		CurrencyRateSpreadPpm: invoiceRequest.CurrencyRateSpreadPpm,
+		Memo:                  invoiceRequest.Memo,
+		PriceFiat:             invoiceRequest.PriceFiat.Float64,
+		PriceMsat:             invoiceRequest.PriceMsat.Int64,
+		CommissionFiat:        invoiceRequest.CommissionFiat.Float64,
+		CommissionMsat:        invoiceRequest.CommissionMsat.Int64,
+		TaxFiat:               invoiceRequest.TaxFiat.Float64,
+		TaxMsat:               invoiceRequest.TaxMsat.Int64,
+		TotalFiat:             invoiceRequest.TotalFiat,
+		TotalMsat:             invoiceRequest.TotalMsat,
+		PaymentRequest:        invoiceRequest.PaymentRequest.String,
+		IsSettled:             invoiceRequest.IsSettled,
+	}
+}
@@ -0,0 +1,259 @@
+package invoice_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+	dbMocks "github.com/satimoto/go-datastore/pkg/db/mocks"
+	invoicerequestMocks "github.com/satimoto/go-datastore/pkg/invoicerequest/mocks"
+	sessionMocks "github.com/satimoto/go-datastore/pkg/session/mocks"
+	dbUtil "github.com/satimoto/go-datastore/pkg/util"
+	"github.com/satimoto/go-lnm/internal/rpc/invoice"
+	"github.com/satimoto/go-lnm/lsprpc"
+)
+
+func newResolver(mockRepository *dbMocks.MockRepositoryService) *invoice.RpcInvoiceResolver {
+	return &invoice.RpcInvoiceResolver{
+		InvoiceRequestRepository: invoicerequestMocks.NewRepository(mockRepository),
+		SessionRepository:        sessionMocks.NewRepository(mockRepository),
+	}
+}
+
+func TestListSessionInvoices(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Lists session invoices with rates", func(t *testing.T) {
+		mockRepository := dbMocks.NewMockRepositoryService()
+		mockRepository.SetGetSessionByUidMockData(dbMocks.SessionMockData{Session: db.Session{ID: 1, Uid: "SESSION0001", UserID: 1}})
+		mockRepository.SetFilterSessionInvoicesMockData(dbMocks.SessionInvoicesMockData{SessionInvoices: []db.SessionInvoice{{
+			ID:               1,
+			UserID:           1,
+			SessionID:        1,
+			Currency:         "EUR",
+			CurrencyRate:     4500,
+			CurrencyRateMsat: 4500000,
+			TotalFiat:        1.5,
+			TotalMsat:        6750000,
+			PaymentRequest:   "PAYMENTREQUEST0001",
+			Signature:        "SIGNATURE0001",
+		}}})
+
+		response, err := newResolver(mockRepository).ListSessionInvoices(ctx, &lsprpc.ListSessionInvoicesRequest{
+			UserId:     1,
+			SessionUid: "SESSION0001",
+			IsSettled:  lsprpc.BoolFilter_BOOL_FILTER_FALSE,
+		})
+
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(response.SessionInvoices) != 1 {
+			t.Fatalf("Session invoice count mismatch: %v expecting %v", len(response.SessionInvoices), 1)
+		}
+
+		sessionInvoice := response.SessionInvoices[0]
+
+		if sessionInvoice.CurrencyRateMsat != 4500000 || sessionInvoice.PaymentRequest != "PAYMENTREQUEST0001" || sessionInvoice.Signature != "SIGNATURE0001" {
+			t.Errorf("Session invoice mismatch: %#v", sessionInvoice)
+		}
+	})
+
+	t.Run("Session of another user", func(t *testing.T) {
+		mockRepository := dbMocks.NewMockRepositoryService()
+		mockRepository.SetGetSessionByUidMockData(dbMocks.SessionMockData{Session: db.Session{ID: 1, Uid: "SESSION0001", UserID: 2}})
+
+		if _, err := newResolver(mockRepository).ListSessionInvoices(ctx, &lsprpc.ListSessionInvoicesRequest{
+			UserId:     1,
+			SessionUid: "SESSION0001",
+		}); err == nil {
+			t.Errorf("Expected error listing session invoices of another user")
+		}
+	})
+
+	t.Run("Invalid date", func(t *testing.T) {
+		mockRepository := dbMocks.NewMockRepositoryService()
+
+		if _, err := newResolver(mockRepository).ListSessionInvoices(ctx, &lsprpc.ListSessionInvoicesRequest{
+			UserId:   1,
+			FromDate: "2023-01-01",
+		}); err == nil {
+			t.Errorf("Expected error for invalid date")
+		}
+	})
+
+	t.Run("Invalid pagination", func(t *testing.T) {
+		mockRepository := dbMocks.NewMockRepositoryService()
+
+		if _, err := newResolver(mockRepository).ListSessionInvoices(ctx, &lsprpc.ListSessionInvoicesRequest{
+			UserId: 1,
+			Offset: -1,
+		}); err == nil {
+			t.Errorf("Expected error for invalid pagination")
+		}
+	})
+}
+
+func TestGetSessionInvoice(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		desc   string
+		userID int64
+		err    bool
+	}{{
+		desc:   "Owner",
+		userID: 1,
+	}, {
+		desc:   "Another user",
+		userID: 2,
+		err:    true,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			mockRepository := dbMocks.NewMockRepositoryService()
+			mockRepository.SetGetSessionInvoiceMockData(dbMocks.SessionInvoiceMockData{SessionInvoice: db.SessionInvoice{
+				ID:     1,
+				UserID: 1,
+			}})
+
+			response, err := newResolver(mockRepository).GetSessionInvoice(ctx, &lsprpc.GetSessionInvoiceRequest{Id: 1, UserId: tc.userID})
+
+			if (err != nil) != tc.err {
+				t.Fatalf("Error mismatch: %v expecting error %v", err, tc.err)
+			}
+
+			if !tc.err && response.Id != 1 {
+				t.Errorf("Session invoice mismatch: %v expecting %v", response.Id, 1)
+			}
+		})
+	}
+}
+
+func TestListInvoiceRequests(t *testing.T) {
+	ctx := context.Background()
+	mockRepository := dbMocks.NewMockRepositoryService()
+	mockRepository.SetFilterInvoiceRequestsMockData(dbMocks.InvoiceRequestsMockData{InvoiceRequests: []db.InvoiceRequest{{
+		ID:                    1,
+		UserID:                1,
+		Currency:              "EUR",
+		CurrencyRate:          4500,
+		CurrencyRateMsat:      4500000,
+		CurrencyRateSpreadPpm: 5000,
+		PriceFiat:             dbUtil.SqlNullFloat64(1.0),
+		PriceMsat:             dbUtil.SqlNullInt64(4500000),
+		TotalFiat:             1.0,
+		TotalMsat:             4500000,
+		PaymentRequest:        dbUtil.SqlNullString("PAYMENTREQUEST0001"),
+	}}})
+
+	response, err := newResolver(mockRepository).ListInvoiceRequests(ctx, &lsprpc.ListInvoiceRequestsRequest{
+		UserId:    1,
+		IsSettled: lsprpc.BoolFilter_BOOL_FILTER_FALSE,
+	})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(response.InvoiceRequests) != 1 {
+		t.Fatalf("Invoice request count mismatch: %v expecting %v", len(response.InvoiceRequests), 1)
+	}
+
+	invoiceRequest := response.InvoiceRequests[0]
+
+	if invoiceRequest.CurrencyRate != 4500 || invoiceRequest.CurrencyRateMsat != 4500000 || invoiceRequest.CurrencyRateSpreadPpm != 5000 {
+		t.Errorf("Currency rate mismatch: %#v", invoiceRequest)
+	}
+
+	if invoiceRequest.PriceMsat != 4500000 || invoiceRequest.PaymentRequest != "PAYMENTREQUEST0001" {
+		t.Errorf("Invoice request mismatch: %#v", invoiceRequest)
+	}
+}
+
+func TestGetSessionBillingSummary(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Summarises settled and unsettled invoices", func(t *testing.T) {
+		mockRepository := dbMocks.NewMockRepositoryService()
+		mockRepository.SetGetSessionByUidMockData(dbMocks.SessionMockData{Session: db.Session{
+			ID:       1,
+			Uid:      "SESSION0001",
+			UserID:   1,
+			Currency: "EUR",
+			Status:   db.SessionStatusTypeACTIVE,
+		}})
+		mockRepository.SetListSessionInvoicesBySessionIDMockData(dbMocks.SessionInvoicesMockData{SessionInvoices: []db.SessionInvoice{{
+			ID:             1,
+			PriceFiat:      1.0,
+			PriceMsat:      4000000,
+			CommissionFiat: 0.25,
+			CommissionMsat: 1000000,
+			TaxFiat:        0.25,
+			TaxMsat:        1000000,
+			TotalFiat:      1.5,
+			TotalMsat:      6000000,
+			IsSettled:      true,
+		}, {
+			ID:             2,
+			PriceFiat:      0.5,
+			PriceMsat:      2000000,
+			CommissionFiat: 0.125,
+			CommissionMsat: 500000,
+			TaxFiat:        0.125,
+			TaxMsat:        500000,
+			TotalFiat:      0.75,
+			TotalMsat:      3000000,
+		}}})
+
+		summary, err := newResolver(mockRepository).GetSessionBillingSummary(ctx, &lsprpc.GetSessionBillingSummaryRequest{
+			UserId:     1,
+			SessionUid: "SESSION0001",
+		})
+
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if summary.InvoiceCount != 2 || summary.SettledCount != 1 {
+			t.Errorf("Count mismatch: %v/%v expecting %v/%v", summary.InvoiceCount, summary.SettledCount, 2, 1)
+		}
+
+		if summary.PriceFiat != 1.5 || summary.PriceMsat != 6000000 {
+			t.Errorf("Price mismatch: %v/%v expecting %v/%v", summary.PriceFiat, summary.PriceMsat, 1.5, 6000000)
+		}
+
+		if summary.CommissionFiat != 0.375 || summary.CommissionMsat != 1500000 {
+			t.Errorf("Commission mismatch: %v/%v expecting %v/%v", summary.CommissionFiat, summary.CommissionMsat, 0.375, 1500000)
+		}
+
+		if summary.TaxFiat != 0.375 || summary.TaxMsat != 1500000 {
+			t.Errorf("Tax mismatch: %v/%v expecting %v/%v", summary.TaxFiat, summary.TaxMsat, 0.375, 1500000)
+		}
+
+		if summary.TotalFiat != 2.25 || summary.TotalMsat != 9000000 {
+			t.Errorf("Total mismatch: %v/%v expecting %v/%v", summary.TotalFiat, summary.TotalMsat, 2.25, 9000000)
+		}
+
+		if summary.SettledFiat != 1.5 || summary.SettledMsat != 6000000 {
+			t.Errorf("Settled mismatch: %v/%v expecting %v/%v", summary.SettledFiat, summary.SettledMsat, 1.5, 6000000)
+		}
+
+		if summary.UnsettledFiat != 0.75 || summary.UnsettledMsat != 3000000 {
+			t.Errorf("Unsettled mismatch: %v/%v expecting %v/%v", summary.UnsettledFiat, summary.UnsettledMsat, 0.75, 3000000)
+		}
+	})
+
+	t.Run("Session of another user", func(t *testing.T) {
+		mockRepository := dbMocks.NewMockRepositoryService()
+		mockRepository.SetGetSessionByUidMockData(dbMocks.SessionMockData{Session: db.Session{ID: 1, Uid: "SESSION0001", UserID: 2}})
+
+		if _, err := newResolver(mockRepository).GetSessionBillingSummary(ctx, &lsprpc.GetSessionBillingSummaryRequest{
+			UserId:     1,
+			SessionUid: "SESSION0001",
+		}); err == nil {
+			t.Errorf("Expected error summarising session of another user")
+		}
+	})
+}
@@ -1,26 +1,41 @@
 package invoice
 
 import (
+	"context"
+
 	"github.com/satimoto/go-datastore/pkg/db"
 	"github.com/satimoto/go-datastore/pkg/invoicerequest"
 	"github.com/satimoto/go-datastore/pkg/session"
 	"github.com/satimoto/go-datastore/pkg/tokenauthorization"
+	"github.com/satimoto/go-datastore/pkg/user"
 	"github.com/satimoto/go-lnm/internal/lightningnetwork"
+	"github.com/satimoto/go-lnm/internal/notificationoutbox"
+	"github.com/satimoto/go-lnm/internal/payment"
 	"github.com/satimoto/go-lnm/internal/service"
 )
 
 type RpcInvoiceResolver struct {
+	LightningNodes               lightningnetwork.LightningNodes
 	LightningService             lightningnetwork.LightningNetwork
+	PaymentService               payment.Payment
 	InvoiceRequestRepository     invoicerequest.InvoiceRequestRepository
 	SessionRepository            session.SessionRepository
 	TokenAuthorizationRepository tokenauthorization.TokenAuthorizationRepository
+	UserRepository               user.UserRepository
+	NotificationOutboxResolver   *notificationoutbox.NotificationOutboxResolver
+	ShutdownCtx                  context.Context
 }
 
-func NewResolver(repositoryService *db.RepositoryService, services *service.ServiceResolver) *RpcInvoiceResolver {
+func NewResolver(shutdownCtx context.Context, repositoryService *db.RepositoryService, services *service.ServiceResolver) *RpcInvoiceResolver {
 	return &RpcInvoiceResolver{
+		LightningNodes:               services.LightningNodes,
 		LightningService:             services.LightningService,
+		PaymentService:               services.PaymentService,
 		InvoiceRequestRepository:     invoicerequest.NewRepository(repositoryService),
 		SessionRepository:            session.NewRepository(repositoryService),
 		TokenAuthorizationRepository: tokenauthorization.NewRepository(repositoryService),
+		UserRepository:               user.NewRepository(repositoryService),
+		NotificationOutboxResolver:   notificationoutbox.NewResolver(repositoryService, services),
+		ShutdownCtx:                  shutdownCtx,
 	}
 }
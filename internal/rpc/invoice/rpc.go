@@ -3,16 +3,17 @@ package invoice
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 	"time"
 
 	"github.com/lightningnetwork/lnd/lnrpc"
-	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
 	"github.com/satimoto/go-datastore/pkg/db"
 	"github.com/satimoto/go-datastore/pkg/param"
 	dbUtil "github.com/satimoto/go-datastore/pkg/util"
 	"github.com/satimoto/go-lnm/internal/lightningnetwork"
 	metrics "github.com/satimoto/go-lnm/internal/metric"
+	"github.com/satimoto/go-lnm/internal/webhook"
 	"github.com/satimoto/go-lnm/lsprpc"
 )
 
@@ -76,7 +77,7 @@ func (r *RpcInvoiceResolver) UpdateInvoiceRequest(reqCtx context.Context, input
 
 		log.Printf("LNM153: Update PaymentId=%v PaymentRequest=%v", invoiceRequest.ID, invoiceRequest.PaymentRequest)
 
-		go r.waitForPayment(invoiceRequest)
+		go r.waitForPayment(invoiceRequest, false)
 
 		return &lsprpc.UpdateInvoiceRequestResponse{}, nil
 	}
@@ -122,6 +123,21 @@ func (r *RpcInvoiceResolver) UpdateSessionInvoice(reqCtx context.Context, input
 			return nil, errors.New("error retrieving session")
 		}
 
+		sessionUser, err := r.UserRepository.GetUser(ctx, sessionInvoice.UserID)
+
+		if err != nil {
+			metrics.RecordError("LNM244", "Error retrieving session user", err)
+			log.Printf("LNM244: UserID=%v", sessionInvoice.UserID)
+			return nil, errors.New("error retrieving session user")
+		}
+
+		// Issue the invoice from the node the user has channels with
+		lightningService, err := r.LightningNodes.GetService(sessionUser.NodeID)
+
+		if err != nil {
+			return nil, err
+		}
+
 		preimage, err := lightningnetwork.RandomPreimage()
 
 		if err != nil {
@@ -130,7 +146,7 @@ func (r *RpcInvoiceResolver) UpdateSessionInvoice(reqCtx context.Context, input
 			return nil, errors.New("error creating preimage")
 		}
 
-		invoice, err := r.LightningService.AddInvoice(&lnrpc.Invoice{
+		invoice, err := lightningService.AddInvoice(&lnrpc.Invoice{
 			Memo:      session.Uid,
 			Expiry:    3600,
 			RPreimage: preimage[:],
@@ -143,7 +159,7 @@ func (r *RpcInvoiceResolver) UpdateSessionInvoice(reqCtx context.Context, input
 			return nil, errors.New("error creating lightning invoice")
 		}
 
-		signMessage, err := r.LightningService.SignMessage(&lnrpc.SignMessageRequest{
+		signMessage, err := lightningService.SignMessage(&lnrpc.SignMessageRequest{
 			Msg: []byte(invoice.PaymentRequest),
 		})
 
@@ -180,6 +196,56 @@ func (r *RpcInvoiceResolver) UpdateSessionInvoice(reqCtx context.Context, input
 	return nil, errors.New("missing request")
 }
 
+func (r *RpcInvoiceResolver) GetInvoiceRequestPaymentStatus(reqCtx context.Context, input *lsprpc.GetInvoiceRequestPaymentStatusRequest) (*lsprpc.InvoiceRequestPaymentStatus, error) {
+	if input != nil {
+		ctx := context.Background()
+		invoiceRequest, err := r.InvoiceRequestRepository.GetInvoiceRequest(ctx, input.Id)
+
+		if err != nil {
+			metrics.RecordError("LNM272", "Error retrieving invoice request", err)
+			log.Printf("LNM272: Input=%#v", input)
+			return nil, errors.New("error retrieving invoice request")
+		}
+
+		if invoiceRequest.UserID != input.UserId {
+			metrics.RecordError("LNM273", "Error invalid user for invoice request", err)
+			log.Printf("LNM273: Input=%#v", input)
+			return nil, errors.New("error invalid user for invoice request")
+		}
+
+		response := &lsprpc.InvoiceRequestPaymentStatus{
+			Id:             invoiceRequest.ID,
+			UserId:         invoiceRequest.UserID,
+			PaymentRequest: invoiceRequest.PaymentRequest.String,
+			Status:         lnrpc.Payment_UNKNOWN.String(),
+			FailureReasons: []string{},
+		}
+
+		if paymentStatus, err := r.PaymentService.GetPaymentStatus(getPaymentReference(invoiceRequest.ID)); err == nil {
+			response.PaymentRequest = paymentStatus.PaymentRequest
+			response.PaymentHash = paymentStatus.PaymentHash
+			response.Status = paymentStatus.Status.String()
+			response.Attempts = int32(paymentStatus.Attempts)
+			response.FeeMsat = paymentStatus.FeeMsat
+			response.FailureReasons = paymentStatus.FailureReasons
+			response.IsFinal = paymentStatus.IsFinal
+			response.LastUpdated = paymentStatus.LastUpdated.Format(time.RFC3339)
+
+			if paymentStatus.NextAttempt.Valid {
+				response.NextAttempt = paymentStatus.NextAttempt.Time.Format(time.RFC3339)
+			}
+		} else if invoiceRequest.IsSettled {
+			// The payment status is no longer retained
+			response.Status = lnrpc.Payment_SUCCEEDED.String()
+			response.IsFinal = true
+		}
+
+		return response, nil
+	}
+
+	return nil, errors.New("missing request")
+}
+
 func (r *RpcInvoiceResolver) waitForInvoiceExpiry(paymentRequest string) {
 	payReqParams := &lnrpc.PayReqString{PayReq: paymentRequest}
 	expiry := int64(3600)
@@ -198,53 +264,48 @@ func (r *RpcInvoiceResolver) waitForInvoiceExpiry(paymentRequest string) {
 			updateSessionInvoiceParams := param.NewUpdateSessionInvoiceParams(sessionInvoice)
 			updateSessionInvoiceParams.IsExpired = true
 
-			_, err = r.SessionRepository.UpdateSessionInvoice(ctx, updateSessionInvoiceParams)
+			updatedSessionInvoice, err := r.SessionRepository.UpdateSessionInvoice(ctx, updateSessionInvoiceParams)
 
 			if err != nil {
 				metrics.RecordError("LNM161", "Error updating session invoice", err)
 				log.Printf("LNM161: Params=%#v", updateSessionInvoiceParams)
+				return
+			}
+
+			if session, err := r.SessionRepository.GetSession(ctx, updatedSessionInvoice.SessionID); err == nil {
+				dto := webhook.NewSessionInvoiceDto(session, updatedSessionInvoice)
+				r.NotificationOutboxResolver.QueueWebhookEvent(ctx, updatedSessionInvoice.UserID, webhook.INVOICE_EXPIRED, dto)
 			}
 		}
 	}
 }
 
-func (r *RpcInvoiceResolver) waitForPayment(invoiceRequest db.InvoiceRequest) {
-	client, err := r.LightningService.SendPaymentV2(&routerrpc.SendPaymentRequest{
-		PaymentRequest: invoiceRequest.PaymentRequest.String,
-		TimeoutSeconds: 120,
-		FeeLimitSat:    10,
-	})
-
-	if err != nil {
-		metrics.RecordError("LNM124", "Error sending payment", err)
-		log.Printf("LNM124: InvoiceRequest=%#v", invoiceRequest)
-		return
-	}
-
+// waitForPayment sends the payment of an invoice request, or resumes it
+// after a restart, and settles the invoice request once paid
+func (r *RpcInvoiceResolver) waitForPayment(invoiceRequest db.InvoiceRequest, resume bool) {
 	ctx := context.Background()
 	updateInvoiceRequestParams := param.NewUpdateInvoiceRequestParams(invoiceRequest)
+	reference := getPaymentReference(invoiceRequest.ID)
+	sendPayment := r.PaymentService.SendPayment
 
-waitLoop:
-	for {
-		payment, err := client.Recv()
+	if resume {
+		sendPayment = r.PaymentService.ResumePayment
+	}
 
-		if err != nil {
-			metrics.RecordError("LNM125", "Error waiting for payment", err)
-			log.Printf("LNM125: PaymentRequest=%v", invoiceRequest.PaymentRequest)
-			updateInvoiceRequestParams.PaymentRequest = dbUtil.SqlNullString(nil)
-			break
-		}
+	paymentStatus, err := sendPayment(r.ShutdownCtx, reference, invoiceRequest.PaymentRequest.String, invoiceRequest.TotalMsat)
 
-		switch payment.Status {
-		case lnrpc.Payment_FAILED:
-			log.Printf("LNM154: PaymentRequest=%v failed: %#v", invoiceRequest.PaymentRequest, payment)
-			updateInvoiceRequestParams.PaymentRequest = dbUtil.SqlNullString(nil)
-			break waitLoop
-		case lnrpc.Payment_SUCCEEDED:
-			log.Printf("LNM155: PaymentRequest=%v succeeded", invoiceRequest.PaymentRequest)
-			updateInvoiceRequestParams.IsSettled = true
-			break waitLoop
-		}
+	if err != nil && r.ShutdownCtx.Err() != nil {
+		// The payment may still be in flight, so keep the payment request
+		// for the payment to be resumed on startup
+		log.Printf("Payment of %v interrupted by shutdown", invoiceRequest.PaymentRequest.String)
+		return
+	} else if err != nil {
+		// Clear the payment request so the user can request a new payment
+		metrics.RecordError("LNM174", "Error sending payment", err)
+		log.Printf("LNM174: PaymentRequest=%v, Attempts=%v, FailureReasons=%v", invoiceRequest.PaymentRequest.String, paymentStatus.Attempts, paymentStatus.FailureReasons)
+		updateInvoiceRequestParams.PaymentRequest = dbUtil.SqlNullString(nil)
+	} else {
+		updateInvoiceRequestParams.IsSettled = true
 	}
 
 	_, err = r.InvoiceRequestRepository.UpdateInvoiceRequest(ctx, updateInvoiceRequestParams)
@@ -254,3 +315,7 @@ waitLoop:
 		log.Printf("LNM126: Params=%#v", updateInvoiceRequestParams)
 	}
 }
+
+func getPaymentReference(invoiceRequestID int64) string {
+	return fmt.Sprintf("invoice_request:%d", invoiceRequestID)
+}
@@ -0,0 +1,27 @@
+package invoice
+
+import (
+	"context"
+	"log"
+
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+)
+
+// ResumePayments resumes the payments of invoice requests that have a
+// payment request but are not settled, such as payments interrupted by a
+// shutdown. Payments still known to LND are tracked rather than sent again.
+func (r *RpcInvoiceResolver) ResumePayments() {
+	ctx := context.Background()
+	invoiceRequests, err := r.InvoiceRequestRepository.ListInFlightInvoiceRequests(ctx)
+
+	if err != nil {
+		metrics.RecordError("LNM279", "Error listing in flight invoice requests", err)
+		log.Printf("LNM279: Error=%v", err)
+		return
+	}
+
+	for _, invoiceRequest := range invoiceRequests {
+		log.Printf("Resuming payment of %v", invoiceRequest.PaymentRequest.String)
+		go r.waitForPayment(invoiceRequest, true)
+	}
+}
@@ -0,0 +1,13 @@
+package lspsession
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricSessionWatchStreams = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "lsp_session_watch_streams",
+		Help: "The number of open session watch streams",
+	})
+)
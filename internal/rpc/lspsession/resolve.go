@@ -0,0 +1,28 @@
+package lspsession
+
+import (
+	"context"
+	"time"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+	dbUtil "github.com/satimoto/go-datastore/pkg/util"
+	"github.com/satimoto/go-lnm/internal/service"
+	"github.com/satimoto/go-lnm/internal/session"
+	"github.com/satimoto/go-lnm/internal/sessionevent"
+)
+
+type RpcSessionResolver struct {
+	SessionEventService sessionevent.SessionEvent
+	SessionResolver     *session.SessionResolver
+	ShutdownCtx         context.Context
+	PollInterval        time.Duration
+}
+
+func NewResolver(shutdownCtx context.Context, repositoryService *db.RepositoryService, services *service.ServiceResolver) *RpcSessionResolver {
+	return &RpcSessionResolver{
+		SessionEventService: services.SessionEventService,
+		SessionResolver:     session.NewResolver(repositoryService, services),
+		ShutdownCtx:         shutdownCtx,
+		PollInterval:        time.Duration(dbUtil.GetEnvInt32("SESSION_WATCH_POLL_INTERVAL", 5)) * time.Second,
+	}
+}
@@ -0,0 +1,220 @@
+package lspsession
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"time"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+	"github.com/satimoto/go-lnm/internal/session"
+	"github.com/satimoto/go-lnm/internal/sessionevent"
+	"github.com/satimoto/go-lnm/lsprpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WatchSession streams the current state of the session followed by its
+// updates until the session is invoiced and its invoices are settled or
+// expired. Events published on the in-process bus are sent as they happen,
+// and the session is polled every PollInterval for updates made by other
+// replicas, such as invoices issued by a session monitor leased elsewhere.
+func (r *RpcSessionResolver) WatchSession(input *lsprpc.WatchSessionRequest, stream lsprpc.SessionService_WatchSessionServer) error {
+	if input != nil {
+		ctx := context.Background()
+		sess, err := r.SessionResolver.Repository.GetSessionByUid(ctx, input.SessionUid)
+
+		if err != nil {
+			metrics.RecordError("LNM227", "Error retrieving session", err)
+			log.Printf("LNM227: Input=%#v", input)
+			return errors.New("session not found")
+		}
+
+		if sess.UserID != input.UserId {
+			metrics.RecordError("LNM228", "Error invalid user for session", errors.New("user mismatch"))
+			log.Printf("LNM228: Input=%#v", input)
+			return errors.New("error invalid user for session")
+		}
+
+		// Subscribe before reading the current state so no event is missed
+		eventChan, unsubscribe := r.SessionEventService.Subscribe(sess.ID)
+		defer unsubscribe()
+
+		metricSessionWatchStreams.Inc()
+		defer metricSessionWatchStreams.Dec()
+
+		pollTicker := time.NewTicker(r.PollInterval)
+		defer pollTicker.Stop()
+
+		event := sessionevent.NewSessionEvent(sessionevent.SESSION_UPDATED, sess)
+		sessionInvoices, err := r.SessionResolver.Repository.ListSessionInvoicesBySessionID(ctx, sess.ID)
+
+		if err == nil && len(sessionInvoices) > 0 {
+			// Use the estimates of the latest session invoice until the session monitor publishes new ones
+			lastSessionInvoice := sessionInvoices[len(sessionInvoices)-1]
+			event.EstimatedEnergy = &lastSessionInvoice.EstimatedEnergy
+			event.EstimatedTime = &lastSessionInvoice.EstimatedTime
+		}
+
+		watcher := &sessionWatcher{}
+		events := []sessionevent.Event{event}
+
+		for {
+			for _, event := range events {
+				if err := stream.Send(watcher.createSessionEvent(event, sessionInvoices)); err != nil {
+					return err
+				}
+
+				sess = event.Session
+			}
+
+			if isSessionEnded(sess, sessionInvoices) {
+				return nil
+			}
+
+			select {
+			case <-stream.Context().Done():
+				return stream.Context().Err()
+			case <-r.ShutdownCtx.Done():
+				return status.Error(codes.Unavailable, "server shutting down")
+			case nextEvent, ok := <-eventChan:
+				if !ok {
+					return nil
+				}
+
+				events = []sessionevent.Event{nextEvent}
+
+				if nextSessionInvoices, err := r.SessionResolver.Repository.ListSessionInvoicesBySessionID(ctx, sess.ID); err == nil {
+					sessionInvoices = nextSessionInvoices
+				} else {
+					metrics.RecordError("LNM229", "Error retrieving session invoices", err)
+					log.Printf("LNM229: SessionUid=%v", sess.Uid)
+				}
+			case <-pollTicker.C:
+				polledSession, err := r.SessionResolver.Repository.GetSessionByUid(ctx, sess.Uid)
+
+				if err != nil {
+					metrics.RecordError("LNM284", "Error polling session", err)
+					log.Printf("LNM284: SessionUid=%v", sess.Uid)
+					events = nil
+					continue
+				}
+
+				polledSessionInvoices, err := r.SessionResolver.Repository.ListSessionInvoicesBySessionID(ctx, sess.ID)
+
+				if err != nil {
+					metrics.RecordError("LNM229", "Error retrieving session invoices", err)
+					log.Printf("LNM229: SessionUid=%v", sess.Uid)
+					events = nil
+					continue
+				}
+
+				events = listChangedEvents(sess, sessionInvoices, polledSession, polledSessionInvoices)
+				sessionInvoices = polledSessionInvoices
+			}
+		}
+	}
+
+	return errors.New("missing request")
+}
+
+// listChangedEvents returns events for the session invoices issued, settled
+// or expired and for the session updated since they were last sent
+func listChangedEvents(sess db.Session, sessionInvoices []db.SessionInvoice, polledSession db.Session, polledSessionInvoices []db.SessionInvoice) []sessionevent.Event {
+	events := []sessionevent.Event{}
+	sessionInvoicesByID := make(map[int64]db.SessionInvoice)
+
+	for _, sessionInvoice := range sessionInvoices {
+		sessionInvoicesByID[sessionInvoice.ID] = sessionInvoice
+	}
+
+	for _, polledSessionInvoice := range polledSessionInvoices {
+		sessionInvoice, ok := sessionInvoicesByID[polledSessionInvoice.ID]
+
+		switch {
+		case !ok:
+			event := sessionevent.NewSessionInvoiceEvent(sessionevent.SESSION_INVOICED, polledSession, polledSessionInvoice)
+			event.EstimatedEnergy = &polledSessionInvoice.EstimatedEnergy
+			event.EstimatedTime = &polledSessionInvoice.EstimatedTime
+			events = append(events, event)
+		case polledSessionInvoice.IsSettled && !sessionInvoice.IsSettled:
+			events = append(events, sessionevent.NewSessionInvoiceEvent(sessionevent.INVOICE_SETTLED, polledSession, polledSessionInvoice))
+		case polledSessionInvoice.IsExpired && !sessionInvoice.IsExpired:
+			events = append(events, sessionevent.NewSessionInvoiceEvent(sessionevent.INVOICE_EXPIRED, polledSession, polledSessionInvoice))
+		}
+	}
+
+	if len(events) == 0 && (polledSession.Status != sess.Status || polledSession.Kwh != sess.Kwh || !polledSession.LastUpdated.Equal(sess.LastUpdated)) {
+		events = append(events, sessionevent.NewSessionEvent(sessionevent.SESSION_UPDATED, polledSession))
+	}
+
+	return events
+}
+
+// sessionWatcher keeps the last estimates so events without estimates
+// still carry the latest known values
+type sessionWatcher struct {
+	estimatedEnergy float64
+	estimatedTime   float64
+}
+
+func (w *sessionWatcher) createSessionEvent(event sessionevent.Event, sessionInvoices []db.SessionInvoice) *lsprpc.SessionEvent {
+	if event.EstimatedEnergy != nil {
+		w.estimatedEnergy = *event.EstimatedEnergy
+	}
+
+	if event.EstimatedTime != nil {
+		w.estimatedTime = *event.EstimatedTime
+	}
+
+	invoicedFiat, invoicedMsat := session.CalculateTotalInvoiced(sessionInvoices)
+	meteredTime := event.Session.LastUpdated.Sub(event.Session.StartDatetime).Hours()
+
+	response := &lsprpc.SessionEvent{
+		EventType:       event.Type,
+		SessionUid:      event.Session.Uid,
+		Status:          string(event.Session.Status),
+		Currency:        event.Session.Currency,
+		MeteredEnergy:   event.Session.Kwh,
+		MeteredTime:     math.Max(0, meteredTime),
+		EstimatedEnergy: w.estimatedEnergy,
+		EstimatedTime:   w.estimatedTime,
+		InvoicedFiat:    invoicedFiat,
+		InvoicedMsat:    invoicedMsat,
+		LastUpdated:     event.Session.LastUpdated.Format(time.RFC3339),
+	}
+
+	if event.SessionInvoice != nil {
+		response.SessionInvoice = &lsprpc.SessionEventInvoice{
+			Id:             event.SessionInvoice.ID,
+			PaymentRequest: event.SessionInvoice.PaymentRequest,
+			TotalFiat:      event.SessionInvoice.TotalFiat,
+			TotalMsat:      event.SessionInvoice.TotalMsat,
+			IsSettled:      event.SessionInvoice.IsSettled,
+			IsExpired:      event.SessionInvoice.IsExpired,
+		}
+	}
+
+	return response
+}
+
+// isSessionEnded reports if no more updates are expected, once the session is
+// invalid or invoiced with each invoice settled or expired
+func isSessionEnded(sess db.Session, sessionInvoices []db.SessionInvoice) bool {
+	switch sess.Status {
+	case db.SessionStatusTypeINVALID:
+		return true
+	case db.SessionStatusTypeINVOICED:
+		for _, sessionInvoice := range sessionInvoices {
+			if !sessionInvoice.IsSettled && !sessionInvoice.IsExpired {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,271 @@
+package lspsession_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+	dbMocks "github.com/satimoto/go-datastore/pkg/db/mocks"
+	ferpMocks "github.com/satimoto/go-lnm/internal/ferp/mocks"
+	lightningnetworkMocks "github.com/satimoto/go-lnm/internal/lightningnetwork/mocks"
+	notificationMocks "github.com/satimoto/go-lnm/internal/notification/mocks"
+	"github.com/satimoto/go-lnm/internal/rpc/lspsession"
+	serviceMocks "github.com/satimoto/go-lnm/internal/service/mocks"
+	sessionMocks "github.com/satimoto/go-lnm/internal/session/mocks"
+	"github.com/satimoto/go-lnm/internal/sessionevent"
+	"github.com/satimoto/go-lnm/lsprpc"
+	ocpiMocks "github.com/satimoto/go-ocpi/pkg/ocpi/mocks"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mockWatchSessionServer struct {
+	grpc.ServerStream
+	ctx       context.Context
+	eventChan chan *lsprpc.SessionEvent
+}
+
+func newMockWatchSessionServer(ctx context.Context) *mockWatchSessionServer {
+	return &mockWatchSessionServer{
+		ctx:       ctx,
+		eventChan: make(chan *lsprpc.SessionEvent, 10),
+	}
+}
+
+func (s *mockWatchSessionServer) Context() context.Context {
+	return s.ctx
+}
+
+func (s *mockWatchSessionServer) Send(event *lsprpc.SessionEvent) error {
+	s.eventChan <- event
+	return nil
+}
+
+func (s *mockWatchSessionServer) receive(t *testing.T) *lsprpc.SessionEvent {
+	select {
+	case event := <-s.eventChan:
+		return event
+	case <-time.After(time.Second):
+		t.Fatalf("Timeout waiting for session event")
+	}
+
+	return nil
+}
+
+func newResolver(shutdownCtx context.Context, mockRepository *dbMocks.MockRepositoryService, pollInterval time.Duration) *lspsession.RpcSessionResolver {
+	mockServices := serviceMocks.NewService(ferpMocks.NewService(), lightningnetworkMocks.NewService(), notificationMocks.NewService(), ocpiMocks.NewService())
+
+	return &lspsession.RpcSessionResolver{
+		SessionEventService: mockServices.SessionEventService,
+		SessionResolver:     sessionMocks.NewResolver(mockRepository, mockServices),
+		ShutdownCtx:         shutdownCtx,
+		PollInterval:        pollInterval,
+	}
+}
+
+func TestWatchSession(t *testing.T) {
+	session := db.Session{
+		ID:            1,
+		Uid:           "SESSION0001",
+		UserID:        1,
+		Currency:      "EUR",
+		Status:        db.SessionStatusTypeACTIVE,
+		StartDatetime: time.Now().Add(-time.Hour),
+		LastUpdated:   time.Now(),
+	}
+
+	t.Run("Sends initial state and events until the final invoice settles", func(t *testing.T) {
+		settledSessionInvoice := db.SessionInvoice{
+			ID:              1,
+			SessionID:       1,
+			TotalFiat:       1.5,
+			TotalMsat:       6000000,
+			EstimatedEnergy: 10,
+			EstimatedTime:   2,
+			IsSettled:       true,
+		}
+		finalSessionInvoice := db.SessionInvoice{
+			ID:        2,
+			SessionID: 1,
+			TotalFiat: 0.5,
+			TotalMsat: 2000000,
+		}
+		settledFinalSessionInvoice := finalSessionInvoice
+		settledFinalSessionInvoice.IsSettled = true
+
+		mockRepository := dbMocks.NewMockRepositoryService()
+		mockRepository.SetGetSessionByUidMockData(dbMocks.SessionMockData{Session: session})
+		mockRepository.SetListSessionInvoicesBySessionIDMockData(dbMocks.SessionInvoicesMockData{SessionInvoices: []db.SessionInvoice{settledSessionInvoice}})
+		mockRepository.SetListSessionInvoicesBySessionIDMockData(dbMocks.SessionInvoicesMockData{SessionInvoices: []db.SessionInvoice{settledSessionInvoice}})
+		mockRepository.SetListSessionInvoicesBySessionIDMockData(dbMocks.SessionInvoicesMockData{SessionInvoices: []db.SessionInvoice{settledSessionInvoice}})
+		mockRepository.SetListSessionInvoicesBySessionIDMockData(dbMocks.SessionInvoicesMockData{SessionInvoices: []db.SessionInvoice{settledSessionInvoice, finalSessionInvoice}})
+		mockRepository.SetListSessionInvoicesBySessionIDMockData(dbMocks.SessionInvoicesMockData{SessionInvoices: []db.SessionInvoice{settledSessionInvoice, settledFinalSessionInvoice}})
+
+		resolver := newResolver(context.Background(), mockRepository, time.Hour)
+		stream := newMockWatchSessionServer(context.Background())
+		errChan := make(chan error, 1)
+
+		go func() {
+			errChan <- resolver.WatchSession(&lsprpc.WatchSessionRequest{SessionUid: "SESSION0001", UserId: 1}, stream)
+		}()
+
+		event := stream.receive(t)
+
+		if event.SessionUid != "SESSION0001" || event.InvoicedMsat != 6000000 || event.EstimatedEnergy != 10 {
+			t.Errorf("Initial state mismatch: %#v", event)
+		}
+
+		updatedSession := session
+		updatedSession.Kwh = 5
+		resolver.SessionEventService.Publish(sessionevent.NewSessionEvent(sessionevent.SESSION_UPDATED, updatedSession))
+
+		if event = stream.receive(t); event.MeteredEnergy != 5 || event.EstimatedEnergy != 10 {
+			t.Errorf("Forwarded event mismatch: %#v", event)
+		}
+
+		completedSession := updatedSession
+		completedSession.Status = db.SessionStatusTypeCOMPLETED
+		resolver.SessionEventService.Publish(sessionevent.NewSessionEvent(sessionevent.SESSION_UPDATED, completedSession))
+
+		if event = stream.receive(t); event.Status != string(db.SessionStatusTypeCOMPLETED) {
+			t.Errorf("Status mismatch: %v expecting %v", event.Status, db.SessionStatusTypeCOMPLETED)
+		}
+
+		invoicedSession := completedSession
+		invoicedSession.Status = db.SessionStatusTypeINVOICED
+		resolver.SessionEventService.Publish(sessionevent.NewSessionInvoiceEvent(sessionevent.SESSION_INVOICED, invoicedSession, finalSessionInvoice))
+
+		if event = stream.receive(t); event.Status != string(db.SessionStatusTypeINVOICED) || event.InvoicedMsat != 8000000 {
+			t.Errorf("Invoiced event mismatch: %#v", event)
+		}
+
+		select {
+		case err := <-errChan:
+			t.Errorf("Expected stream to stay open until the final invoice settles: %v", err)
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		resolver.SessionEventService.Publish(sessionevent.NewSessionInvoiceEvent(sessionevent.INVOICE_SETTLED, invoicedSession, settledFinalSessionInvoice))
+
+		if event = stream.receive(t); event.EventType != sessionevent.INVOICE_SETTLED || !event.SessionInvoice.IsSettled {
+			t.Errorf("Settled event mismatch: %#v", event)
+		}
+
+		select {
+		case err := <-errChan:
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("Expected stream to end when the final invoice settled")
+		}
+	})
+
+	t.Run("Polls updates made by other replicas", func(t *testing.T) {
+		invoicedSession := session
+		invoicedSession.Status = db.SessionStatusTypeINVOICED
+		expiredSessionInvoice := db.SessionInvoice{
+			ID:        1,
+			SessionID: 1,
+			TotalMsat: 6000000,
+			IsExpired: true,
+		}
+
+		mockRepository := dbMocks.NewMockRepositoryService()
+		mockRepository.SetGetSessionByUidMockData(dbMocks.SessionMockData{Session: session})
+		mockRepository.SetGetSessionByUidMockData(dbMocks.SessionMockData{Session: invoicedSession})
+		mockRepository.SetListSessionInvoicesBySessionIDMockData(dbMocks.SessionInvoicesMockData{SessionInvoices: []db.SessionInvoice{}})
+		mockRepository.SetListSessionInvoicesBySessionIDMockData(dbMocks.SessionInvoicesMockData{SessionInvoices: []db.SessionInvoice{expiredSessionInvoice}})
+
+		resolver := newResolver(context.Background(), mockRepository, 10*time.Millisecond)
+		stream := newMockWatchSessionServer(context.Background())
+		errChan := make(chan error, 1)
+
+		go func() {
+			errChan <- resolver.WatchSession(&lsprpc.WatchSessionRequest{SessionUid: "SESSION0001", UserId: 1}, stream)
+		}()
+
+		if event := stream.receive(t); event.Status != string(db.SessionStatusTypeACTIVE) {
+			t.Errorf("Status mismatch: %v expecting %v", event.Status, db.SessionStatusTypeACTIVE)
+		}
+
+		if event := stream.receive(t); event.EventType != sessionevent.SESSION_INVOICED || event.Status != string(db.SessionStatusTypeINVOICED) || !event.SessionInvoice.IsExpired {
+			t.Errorf("Polled event mismatch: %#v", event)
+		}
+
+		select {
+		case err := <-errChan:
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("Expected stream to end when the final invoice expired")
+		}
+	})
+
+	t.Run("Rejects other users", func(t *testing.T) {
+		mockRepository := dbMocks.NewMockRepositoryService()
+		mockRepository.SetGetSessionByUidMockData(dbMocks.SessionMockData{Session: session})
+
+		resolver := newResolver(context.Background(), mockRepository, time.Hour)
+		stream := newMockWatchSessionServer(context.Background())
+
+		if err := resolver.WatchSession(&lsprpc.WatchSessionRequest{SessionUid: "SESSION0001", UserId: 2}, stream); err == nil {
+			t.Errorf("Expected error watching session of another user")
+		}
+	})
+
+	t.Run("Ends on client cancel", func(t *testing.T) {
+		mockRepository := dbMocks.NewMockRepositoryService()
+		mockRepository.SetGetSessionByUidMockData(dbMocks.SessionMockData{Session: session})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		resolver := newResolver(context.Background(), mockRepository, time.Hour)
+		stream := newMockWatchSessionServer(ctx)
+		errChan := make(chan error, 1)
+
+		go func() {
+			errChan <- resolver.WatchSession(&lsprpc.WatchSessionRequest{SessionUid: "SESSION0001", UserId: 1}, stream)
+		}()
+
+		stream.receive(t)
+		cancel()
+
+		select {
+		case err := <-errChan:
+			if err != context.Canceled {
+				t.Errorf("Error mismatch: %v expecting %v", err, context.Canceled)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("Expected stream to end when the client cancelled")
+		}
+	})
+
+	t.Run("Ends on shutdown", func(t *testing.T) {
+		mockRepository := dbMocks.NewMockRepositoryService()
+		mockRepository.SetGetSessionByUidMockData(dbMocks.SessionMockData{Session: session})
+
+		shutdownCtx, cancel := context.WithCancel(context.Background())
+		resolver := newResolver(shutdownCtx, mockRepository, time.Hour)
+		stream := newMockWatchSessionServer(context.Background())
+		errChan := make(chan error, 1)
+
+		go func() {
+			errChan <- resolver.WatchSession(&lsprpc.WatchSessionRequest{SessionUid: "SESSION0001", UserId: 1}, stream)
+		}()
+
+		stream.receive(t)
+		cancel()
+
+		select {
+		case err := <-errChan:
+			if status.Code(err) != codes.Unavailable {
+				t.Errorf("Code mismatch: %v expecting %v", status.Code(err), codes.Unavailable)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("Expected stream to end on shutdown")
+		}
+	})
+}
@@ -0,0 +1,17 @@
+package notification
+
+import (
+	"github.com/satimoto/go-datastore/pkg/db"
+	"github.com/satimoto/go-lnm/internal/notificationoutbox"
+	"github.com/satimoto/go-lnm/internal/service"
+)
+
+type RpcNotificationResolver struct {
+	NotificationOutboxResolver *notificationoutbox.NotificationOutboxResolver
+}
+
+func NewResolver(repositoryService *db.RepositoryService, services *service.ServiceResolver) *RpcNotificationResolver {
+	return &RpcNotificationResolver{
+		NotificationOutboxResolver: notificationoutbox.NewResolver(repositoryService, services),
+	}
+}
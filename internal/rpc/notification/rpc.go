@@ -0,0 +1,44 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+	"github.com/satimoto/go-lnm/lsprpc"
+)
+
+func (r *RpcNotificationResolver) ListUndeliveredNotifications(reqCtx context.Context, input *lsprpc.ListUndeliveredNotificationsRequest) (*lsprpc.ListUndeliveredNotificationsResponse, error) {
+	if input != nil {
+		ctx := context.Background()
+		notificationOutboxes, err := r.NotificationOutboxResolver.ListUndeliveredNotifications(ctx, input.UserId)
+
+		if err != nil {
+			metrics.RecordError("LNM187", "Error listing undelivered notifications", err)
+			log.Printf("LNM187: Input=%#v", input)
+			return nil, errors.New("error listing undelivered notifications")
+		}
+
+		notifications := []*lsprpc.UndeliveredNotification{}
+
+		for _, notificationOutbox := range notificationOutboxes {
+			notifications = append(notifications, &lsprpc.UndeliveredNotification{
+				Id:               notificationOutbox.ID,
+				NotificationType: notificationOutbox.NotificationType,
+				Status:           string(notificationOutbox.Status),
+				Attempts:         notificationOutbox.Attempts,
+				LastError:        notificationOutbox.LastError.String,
+				NextAttemptDate:  notificationOutbox.NextAttemptDate.Format(time.RFC3339),
+				CreatedDate:      notificationOutbox.CreatedDate.Format(time.RFC3339),
+			})
+		}
+
+		return &lsprpc.ListUndeliveredNotificationsResponse{
+			Notifications: notifications,
+		}, nil
+	}
+
+	return nil, errors.New("missing request")
+}
@@ -0,0 +1,131 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+	"github.com/satimoto/go-lnm/internal/notificationoutbox"
+	"github.com/satimoto/go-lnm/internal/rpc/auth"
+	"github.com/satimoto/go-lnm/lsprpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (r *RpcNotificationResolver) CreateWebhook(reqCtx context.Context, input *lsprpc.CreateWebhookRequest) (*lsprpc.Webhook, error) {
+	if input != nil {
+		if err := checkOperator(reqCtx, input.UserId); err != nil {
+			return nil, err
+		}
+
+		ctx := context.Background()
+		webhookEndpoint, err := r.NotificationOutboxResolver.CreateWebhookEndpoint(ctx, input.UserId, input.Url, input.EventTypes)
+
+		if err != nil {
+			if errors.Is(err, notificationoutbox.ErrInvalidWebhookUrl) || errors.Is(err, notificationoutbox.ErrInvalidWebhookEventType) {
+				return nil, err
+			}
+
+			return nil, errors.New("error creating webhook")
+		}
+
+		// The secret is only returned when the webhook is created
+		webhook := createWebhook(*webhookEndpoint)
+		webhook.Secret = webhookEndpoint.Secret
+
+		return webhook, nil
+	}
+
+	return nil, errors.New("missing request")
+}
+
+func (r *RpcNotificationResolver) ListWebhooks(reqCtx context.Context, input *lsprpc.ListWebhooksRequest) (*lsprpc.ListWebhooksResponse, error) {
+	if input != nil {
+		if err := checkOperator(reqCtx, input.UserId); err != nil {
+			return nil, err
+		}
+
+		ctx := context.Background()
+		webhookEndpoints, err := r.listWebhookEndpoints(ctx, input.UserId)
+
+		if err != nil {
+			metrics.RecordError("LNM203", "Error listing webhook endpoints", err)
+			log.Printf("LNM203: Input=%#v", input)
+			return nil, errors.New("error listing webhooks")
+		}
+
+		webhooks := []*lsprpc.Webhook{}
+
+		for _, webhookEndpoint := range webhookEndpoints {
+			webhooks = append(webhooks, createWebhook(webhookEndpoint))
+		}
+
+		return &lsprpc.ListWebhooksResponse{
+			Webhooks: webhooks,
+		}, nil
+	}
+
+	return nil, errors.New("missing request")
+}
+
+func (r *RpcNotificationResolver) DeleteWebhook(reqCtx context.Context, input *lsprpc.DeleteWebhookRequest) (*lsprpc.DeleteWebhookResponse, error) {
+	if input != nil {
+		if err := checkOperator(reqCtx, input.UserId); err != nil {
+			return nil, err
+		}
+
+		ctx := context.Background()
+
+		if err := r.deleteWebhookEndpoint(ctx, input.UserId, input.Id); err != nil {
+			metrics.RecordError("LNM204", "Error deleting webhook endpoint", err)
+			log.Printf("LNM204: Input=%#v", input)
+			return nil, errors.New("error deleting webhook")
+		}
+
+		return &lsprpc.DeleteWebhookResponse{}, nil
+	}
+
+	return nil, errors.New("missing request")
+}
+
+func (r *RpcNotificationResolver) listWebhookEndpoints(ctx context.Context, userID int64) ([]db.WebhookEndpoint, error) {
+	if userID == 0 {
+		return r.NotificationOutboxResolver.WebhookRepository.ListOperatorWebhookEndpoints(ctx)
+	}
+
+	return r.NotificationOutboxResolver.WebhookRepository.ListWebhookEndpointsByUserID(ctx, userID)
+}
+
+func (r *RpcNotificationResolver) deleteWebhookEndpoint(ctx context.Context, userID, id int64) error {
+	if userID == 0 {
+		return r.NotificationOutboxResolver.WebhookRepository.DeleteOperatorWebhookEndpoint(ctx, id)
+	}
+
+	return r.NotificationOutboxResolver.WebhookRepository.DeleteWebhookEndpoint(ctx, db.DeleteWebhookEndpointParams{
+		ID:     id,
+		UserID: userID,
+	})
+}
+
+// checkOperator checks that requests for operator webhooks, which receive
+// the events of all users, are made by the admin
+func checkOperator(ctx context.Context, userID int64) error {
+	if identity, _ := auth.GetIdentity(ctx); userID == 0 && identity != auth.IDENTITY_ADMIN {
+		return status.Error(codes.PermissionDenied, "operator webhooks require the admin identity")
+	}
+
+	return nil
+}
+
+func createWebhook(webhookEndpoint db.WebhookEndpoint) *lsprpc.Webhook {
+	return &lsprpc.Webhook{
+		Id:          webhookEndpoint.ID,
+		Url:         webhookEndpoint.Url,
+		EventTypes:  webhookEndpoint.EventTypes,
+		IsActive:    webhookEndpoint.IsActive,
+		CreatedDate: webhookEndpoint.CreatedDate.Format(time.RFC3339),
+	}
+}
@@ -8,19 +8,29 @@ import (
 	"net"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/satimoto/go-datastore/pkg/db"
 	"github.com/satimoto/go-datastore/pkg/util"
+	lnmHealth "github.com/satimoto/go-lnm/internal/health"
 	metrics "github.com/satimoto/go-lnm/internal/metric"
 	"github.com/satimoto/go-lnm/internal/monitor"
+	"github.com/satimoto/go-lnm/internal/rpc/auth"
 	"github.com/satimoto/go-lnm/internal/rpc/cdr"
+	"github.com/satimoto/go-lnm/internal/rpc/interceptor"
 	"github.com/satimoto/go-lnm/internal/rpc/invoice"
+	"github.com/satimoto/go-lnm/internal/rpc/lspsession"
+	"github.com/satimoto/go-lnm/internal/rpc/notification"
 	"github.com/satimoto/go-lnm/internal/rpc/rpc"
 	"github.com/satimoto/go-lnm/internal/rpc/session"
 	"github.com/satimoto/go-lnm/internal/service"
 	"github.com/satimoto/go-lnm/lsprpc"
+	lnmUtil "github.com/satimoto/go-lnm/pkg/util"
 	"github.com/satimoto/go-ocpi/ocpirpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 type Rpc interface {
@@ -28,26 +38,36 @@ type Rpc interface {
 }
 
 type RpcService struct {
-	RepositoryService  *db.RepositoryService
-	Server             *grpc.Server
-	RpcCdrResolver     *cdr.RpcCdrResolver
-	RpcInvoiceResolver *invoice.RpcInvoiceResolver
-	RpcResolver        *rpc.RpcResolver
-	RpcSessionResolver *session.RpcSessionResolver
-	ShutdownCtx        context.Context
+	RepositoryService       *db.RepositoryService
+	Server                  *grpc.Server
+	HealthServer            *health.Server
+	HealthOnlyServer        *grpc.Server
+	HealthService           lnmHealth.Health
+	RpcCdrResolver          *cdr.RpcCdrResolver
+	RpcInvoiceResolver      *invoice.RpcInvoiceResolver
+	RpcLspSessionResolver   *lspsession.RpcSessionResolver
+	RpcNotificationResolver *notification.RpcNotificationResolver
+	RpcResolver             *rpc.RpcResolver
+	RpcSessionResolver      *session.RpcSessionResolver
+	ShutdownCtx             context.Context
 }
 
 func NewRpc(shutdownCtx context.Context, d *sql.DB, services *service.ServiceResolver, monitorService *monitor.Monitor) Rpc {
 	repositoryService := db.NewRepositoryService(d)
 
 	return &RpcService{
-		RepositoryService:  repositoryService,
-		Server:             grpc.NewServer(),
-		RpcCdrResolver:     cdr.NewResolver(repositoryService, services),
-		RpcInvoiceResolver: invoice.NewResolver(repositoryService, services),
-		RpcResolver:        rpc.NewResolver(repositoryService, services),
-		RpcSessionResolver: session.NewResolver(repositoryService, services),
-		ShutdownCtx:        shutdownCtx,
+		RepositoryService:       repositoryService,
+		Server:                  newServer(),
+		HealthServer:            health.NewServer(),
+		HealthOnlyServer:        grpc.NewServer(),
+		HealthService:           lnmHealth.NewService(d, services.FerpService, services.LightningNodes, services.OcpiService),
+		RpcCdrResolver:          cdr.NewResolver(repositoryService, services),
+		RpcInvoiceResolver:      invoice.NewResolver(shutdownCtx, repositoryService, services),
+		RpcLspSessionResolver:   lspsession.NewResolver(shutdownCtx, repositoryService, services),
+		RpcNotificationResolver: notification.NewResolver(repositoryService, services),
+		RpcResolver:             rpc.NewResolver(repositoryService, services),
+		RpcSessionResolver:      session.NewResolver(repositoryService, services),
+		ShutdownCtx:             shutdownCtx,
 	}
 }
 
@@ -56,6 +76,7 @@ func (rs *RpcService) StartRpc(waitGroup *sync.WaitGroup) {
 	waitGroup.Add(1)
 
 	go rs.listenAndServe()
+	go rs.RpcInvoiceResolver.ResumePayments()
 
 	go func() {
 		<-rs.ShutdownCtx.Done()
@@ -73,14 +94,104 @@ func (rs *RpcService) listenAndServe() {
 	util.PanicOnError("LNM028", "Error creating network address", err)
 
 	lsprpc.RegisterInvoiceServiceServer(rs.Server, rs.RpcInvoiceResolver)
+	lsprpc.RegisterNotificationServiceServer(rs.Server, rs.RpcNotificationResolver)
+	lsprpc.RegisterSessionServiceServer(rs.Server, rs.RpcLspSessionResolver)
 	ocpirpc.RegisterCdrServiceServer(rs.Server, rs.RpcCdrResolver)
 	ocpirpc.RegisterRpcServiceServer(rs.Server, rs.RpcResolver)
 	ocpirpc.RegisterSessionServiceServer(rs.Server, rs.RpcSessionResolver)
+	grpc_health_v1.RegisterHealthServer(rs.Server, rs.HealthServer)
+	reflection.Register(rs.Server)
+
+	// Not serving until the readiness checks have passed
+	rs.setServingStatus(grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+
+	go rs.watchReadiness()
+	go rs.listenAndServeHealth()
 
 	err = rs.Server.Serve(listener)
 	metrics.RecordError("LNM029", "Error in Rpc service", err)
 }
 
+// listenAndServeHealth serves only the health service on a separate port
+// without TLS, so probes can check the service without a client certificate
+func (rs *RpcService) listenAndServeHealth() {
+	healthPort := os.Getenv("RPC_HEALTH_PORT")
+
+	if len(healthPort) == 0 {
+		return
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", healthPort))
+	util.PanicOnError("LNM276", "Error creating health network address", err)
+
+	grpc_health_v1.RegisterHealthServer(rs.HealthOnlyServer, rs.HealthServer)
+
+	err = rs.HealthOnlyServer.Serve(listener)
+	metrics.RecordError("LNM277", "Error in Rpc health service", err)
+}
+
+// watchReadiness updates the serving status from the readiness checks
+// every RPC_HEALTH_INTERVAL seconds
+func (rs *RpcService) watchReadiness() {
+	ticker := time.NewTicker(time.Duration(util.GetEnvInt32("RPC_HEALTH_INTERVAL", 10)) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		rs.updateServingStatus()
+
+		select {
+		case <-rs.ShutdownCtx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (rs *RpcService) updateServingStatus() {
+	servingStatus := grpc_health_v1.HealthCheckResponse_SERVING
+
+	if readyHealth := rs.HealthService.Ready(rs.ShutdownCtx); readyHealth.Status != lnmHealth.STATUS_UP {
+		servingStatus = grpc_health_v1.HealthCheckResponse_NOT_SERVING
+	}
+
+	rs.setServingStatus(servingStatus)
+}
+
+func (rs *RpcService) setServingStatus(servingStatus grpc_health_v1.HealthCheckResponse_ServingStatus) {
+	for serviceName := range rs.Server.GetServiceInfo() {
+		rs.HealthServer.SetServingStatus(serviceName, servingStatus)
+	}
+
+	rs.HealthServer.SetServingStatus("", servingStatus)
+}
+
 func (rs *RpcService) shutdown() {
+	rs.HealthServer.Shutdown()
 	rs.Server.GracefulStop()
+	rs.HealthOnlyServer.GracefulStop()
+}
+
+func newServer() *grpc.Server {
+	unaryInterceptors := interceptor.UnaryServerInterceptors()
+	streamInterceptors := interceptor.StreamServerInterceptors()
+	serverOptions := []grpc.ServerOption{}
+
+	if util.GetEnvBool("RPC_INSECURE", false) {
+		log.Printf("Rpc service running without TLS, callers are not authenticated")
+	} else {
+		serverCredentials, err := lnmUtil.NewServerCredential(os.Getenv("RPC_TLS_CERT"), os.Getenv("RPC_TLS_KEY"), os.Getenv("RPC_TLS_CLIENT_CA"))
+		util.PanicOnError("LNM224", "Error creating Rpc server credentials", err)
+
+		authorizer := auth.NewAuthorizer(auth.NewPolicy())
+		unaryInterceptors = append(unaryInterceptors, authorizer.UnaryServerInterceptor())
+		streamInterceptors = append(streamInterceptors, authorizer.StreamServerInterceptor())
+		serverOptions = append(serverOptions, grpc.Creds(serverCredentials))
+	}
+
+	serverOptions = append(serverOptions,
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+	)
+
+	return grpc.NewServer(serverOptions...)
 }
@@ -0,0 +1,31 @@
+package scheduler
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricSchedulerTasks = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "lsp_scheduler_tasks",
+		Help: "The number of scheduled tasks",
+	}, []string{"scheduler"})
+	metricSchedulerBusyWorkers = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "lsp_scheduler_busy_workers",
+		Help: "The number of workers running a task",
+	}, []string{"scheduler"})
+	metricSchedulerWakesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lsp_scheduler_wakes_total",
+		Help: "The total number of tasks woken before they were due",
+	}, []string{"scheduler"})
+	metricSchedulerLagSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "lsp_scheduler_lag_seconds",
+		Help:    "The time between a task being due and a worker running it",
+		Buckets: []float64{0.01, 0.1, 0.5, 1, 5, 15, 30, 60, 300},
+	}, []string{"scheduler"})
+	metricSchedulerTaskDurationSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "lsp_scheduler_task_duration_seconds",
+		Help:    "The time taken to run a task",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"scheduler"})
+)
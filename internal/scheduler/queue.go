@@ -0,0 +1,49 @@
+package scheduler
+
+import (
+	"time"
+)
+
+type scheduledTask struct {
+	id        int64
+	task      Task
+	dueTime   time.Time
+	index     int
+	running   bool
+	woken     bool
+	cancelled bool
+}
+
+// taskQueue is a container/heap of tasks ordered by due time
+type taskQueue []*scheduledTask
+
+func (q taskQueue) Len() int {
+	return len(q)
+}
+
+func (q taskQueue) Less(i, j int) bool {
+	return q[i].dueTime.Before(q[j].dueTime)
+}
+
+func (q taskQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *taskQueue) Push(x interface{}) {
+	item := x.(*scheduledTask)
+	item.index = len(*q)
+	*q = append(*q, item)
+}
+
+func (q *taskQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.index = -1
+	*q = old[:n-1]
+
+	return item
+}
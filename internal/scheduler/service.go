@@ -0,0 +1,235 @@
+package scheduler
+
+import (
+	"container/heap"
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Task runs when it is due and returns the delay until it should run
+// again, or false to stop it being scheduled
+type Task func() (time.Duration, bool)
+
+type Scheduler interface {
+	Schedule(id int64, delay time.Duration, task Task)
+	Wake(id int64)
+	Cancel(id int64)
+	Start(shutdownCtx context.Context, waitGroup *sync.WaitGroup)
+}
+
+type SchedulerService struct {
+	name     string
+	workers  int
+	mutex    sync.Mutex
+	queue    taskQueue
+	tasks    map[int64]*scheduledTask
+	wakeChan chan struct{}
+	workChan chan *scheduledTask
+}
+
+func NewService(name string, workers int) Scheduler {
+	if workers < 1 {
+		workers = 1
+	}
+
+	return &SchedulerService{
+		name:     name,
+		workers:  workers,
+		queue:    taskQueue{},
+		tasks:    make(map[int64]*scheduledTask),
+		wakeChan: make(chan struct{}, 1),
+		workChan: make(chan *scheduledTask),
+	}
+}
+
+// Schedule adds a task to run after the delay, replacing any task
+// already scheduled with the same ID
+func (s *SchedulerService) Schedule(id int64, delay time.Duration, task Task) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	dueTime := time.Now().Add(delay)
+
+	if scheduledTask, ok := s.tasks[id]; ok {
+		scheduledTask.task = task
+		scheduledTask.cancelled = false
+
+		if !scheduledTask.running {
+			scheduledTask.dueTime = dueTime
+			heap.Fix(&s.queue, scheduledTask.index)
+			s.signal()
+		}
+
+		return
+	}
+
+	scheduledTask := &scheduledTask{
+		id:      id,
+		task:    task,
+		dueTime: dueTime,
+	}
+
+	s.tasks[id] = scheduledTask
+	heap.Push(&s.queue, scheduledTask)
+	metricSchedulerTasks.WithLabelValues(s.name).Inc()
+	s.signal()
+}
+
+// Wake makes a scheduled task due now. A task that is running is run
+// again as soon as it completes.
+func (s *SchedulerService) Wake(id int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if scheduledTask, ok := s.tasks[id]; ok {
+		if scheduledTask.running {
+			scheduledTask.woken = true
+		} else {
+			scheduledTask.dueTime = time.Now()
+			heap.Fix(&s.queue, scheduledTask.index)
+			s.signal()
+		}
+
+		metricSchedulerWakesTotal.WithLabelValues(s.name).Inc()
+	}
+}
+
+// Cancel removes a task, a task that is running is not scheduled again
+func (s *SchedulerService) Cancel(id int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if scheduledTask, ok := s.tasks[id]; ok {
+		if scheduledTask.running {
+			scheduledTask.cancelled = true
+		} else {
+			s.remove(scheduledTask)
+		}
+	}
+}
+
+func (s *SchedulerService) Start(shutdownCtx context.Context, waitGroup *sync.WaitGroup) {
+	log.Printf("Starting up %v scheduler with %v workers", s.name, s.workers)
+
+	for i := 0; i < s.workers; i++ {
+		waitGroup.Add(1)
+		go s.runWorker(waitGroup)
+	}
+
+	go s.runQueue(shutdownCtx)
+}
+
+func (s *SchedulerService) runQueue(shutdownCtx context.Context) {
+	defer close(s.workChan)
+
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		scheduledTask, delay := s.next()
+
+		if scheduledTask != nil {
+			select {
+			case <-shutdownCtx.Done():
+				log.Printf("Shutting down %v scheduler", s.name)
+				return
+			case s.workChan <- scheduledTask:
+			}
+
+			continue
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+
+		timer.Reset(delay)
+
+		select {
+		case <-shutdownCtx.Done():
+			log.Printf("Shutting down %v scheduler", s.name)
+			return
+		case <-timer.C:
+		case <-s.wakeChan:
+		}
+	}
+}
+
+func (s *SchedulerService) runWorker(waitGroup *sync.WaitGroup) {
+	defer waitGroup.Done()
+
+	for scheduledTask := range s.workChan {
+		metricSchedulerLagSeconds.WithLabelValues(s.name).Observe(time.Since(scheduledTask.dueTime).Seconds())
+		metricSchedulerBusyWorkers.WithLabelValues(s.name).Inc()
+
+		start := time.Now()
+		delay, ok := scheduledTask.task()
+
+		metricSchedulerTaskDurationSeconds.WithLabelValues(s.name).Observe(time.Since(start).Seconds())
+		metricSchedulerBusyWorkers.WithLabelValues(s.name).Dec()
+
+		s.complete(scheduledTask, delay, ok)
+	}
+}
+
+// next pops the task that is due, otherwise it returns the delay until
+// the next task is due
+func (s *SchedulerService) next() (*scheduledTask, time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.queue) == 0 {
+		return nil, time.Hour
+	}
+
+	if delay := time.Until(s.queue[0].dueTime); delay > 0 {
+		return nil, delay
+	}
+
+	scheduledTask := heap.Pop(&s.queue).(*scheduledTask)
+	scheduledTask.running = true
+
+	return scheduledTask, 0
+}
+
+func (s *SchedulerService) complete(scheduledTask *scheduledTask, delay time.Duration, ok bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	scheduledTask.running = false
+
+	if !ok || scheduledTask.cancelled {
+		s.remove(scheduledTask)
+		return
+	}
+
+	if scheduledTask.woken {
+		delay = 0
+		scheduledTask.woken = false
+	}
+
+	scheduledTask.dueTime = time.Now().Add(delay)
+	heap.Push(&s.queue, scheduledTask)
+	s.signal()
+}
+
+func (s *SchedulerService) remove(scheduledTask *scheduledTask) {
+	if scheduledTask.index >= 0 {
+		heap.Remove(&s.queue, scheduledTask.index)
+	}
+
+	delete(s.tasks, scheduledTask.id)
+	metricSchedulerTasks.WithLabelValues(s.name).Dec()
+}
+
+func (s *SchedulerService) signal() {
+	select {
+	case s.wakeChan <- struct{}{}:
+	default:
+	}
+}
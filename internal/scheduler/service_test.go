@@ -0,0 +1,121 @@
+package scheduler_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/satimoto/go-lnm/internal/scheduler"
+)
+
+func TestScheduler(t *testing.T) {
+	t.Run("Run tasks in due order", func(t *testing.T) {
+		shutdownCtx, cancelFunc := context.WithCancel(context.Background())
+		waitGroup := &sync.WaitGroup{}
+		schedulerService := scheduler.NewService("test", 1)
+		resultChan := make(chan int64, 3)
+
+		for _, id := range []int64{3, 1, 2} {
+			taskID := id
+			schedulerService.Schedule(taskID, time.Duration(taskID)*10*time.Millisecond, func() (time.Duration, bool) {
+				resultChan <- taskID
+				return 0, false
+			})
+		}
+
+		schedulerService.Start(shutdownCtx, waitGroup)
+
+		for _, expected := range []int64{1, 2, 3} {
+			if id := waitForResult(t, resultChan); id != expected {
+				t.Errorf("Task mismatch: %v expecting %v", id, expected)
+			}
+		}
+
+		cancelFunc()
+		waitGroup.Wait()
+	})
+
+	t.Run("Reschedule task", func(t *testing.T) {
+		shutdownCtx, cancelFunc := context.WithCancel(context.Background())
+		waitGroup := &sync.WaitGroup{}
+		schedulerService := scheduler.NewService("test", 2)
+		resultChan := make(chan int64, 3)
+		runs := 0
+
+		schedulerService.Schedule(1, 0, func() (time.Duration, bool) {
+			runs++
+			resultChan <- int64(runs)
+			return 10 * time.Millisecond, runs < 3
+		})
+
+		schedulerService.Start(shutdownCtx, waitGroup)
+
+		for _, expected := range []int64{1, 2, 3} {
+			if run := waitForResult(t, resultChan); run != expected {
+				t.Errorf("Run mismatch: %v expecting %v", run, expected)
+			}
+		}
+
+		cancelFunc()
+		waitGroup.Wait()
+	})
+
+	t.Run("Wake task early", func(t *testing.T) {
+		shutdownCtx, cancelFunc := context.WithCancel(context.Background())
+		waitGroup := &sync.WaitGroup{}
+		schedulerService := scheduler.NewService("test", 1)
+		resultChan := make(chan int64, 1)
+
+		schedulerService.Schedule(1, time.Hour, func() (time.Duration, bool) {
+			resultChan <- 1
+			return time.Hour, true
+		})
+
+		schedulerService.Start(shutdownCtx, waitGroup)
+		schedulerService.Wake(1)
+
+		waitForResult(t, resultChan)
+
+		cancelFunc()
+		waitGroup.Wait()
+	})
+
+	t.Run("Cancel task", func(t *testing.T) {
+		shutdownCtx, cancelFunc := context.WithCancel(context.Background())
+		waitGroup := &sync.WaitGroup{}
+		schedulerService := scheduler.NewService("test", 1)
+		resultChan := make(chan int64, 2)
+
+		schedulerService.Schedule(1, 10*time.Millisecond, func() (time.Duration, bool) {
+			resultChan <- 1
+			return 0, false
+		})
+
+		schedulerService.Schedule(2, 20*time.Millisecond, func() (time.Duration, bool) {
+			resultChan <- 2
+			return 0, false
+		})
+
+		schedulerService.Cancel(1)
+		schedulerService.Start(shutdownCtx, waitGroup)
+
+		if id := waitForResult(t, resultChan); id != 2 {
+			t.Errorf("Task mismatch: %v expecting %v", id, 2)
+		}
+
+		cancelFunc()
+		waitGroup.Wait()
+	})
+}
+
+func waitForResult(t *testing.T, resultChan <-chan int64) int64 {
+	select {
+	case result := <-resultChan:
+		return result
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for task")
+	}
+
+	return 0
+}
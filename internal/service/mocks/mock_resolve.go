@@ -2,17 +2,28 @@ package mocks
 
 import (
 	ferp "github.com/satimoto/go-lnm/internal/ferp/mocks"
+	lease "github.com/satimoto/go-lnm/internal/lease/mocks"
 	lightningnetwork "github.com/satimoto/go-lnm/internal/lightningnetwork/mocks"
 	notification "github.com/satimoto/go-lnm/internal/notification/mocks"
+	"github.com/satimoto/go-lnm/internal/payment"
+	"github.com/satimoto/go-lnm/internal/scheduler"
 	"github.com/satimoto/go-lnm/internal/service"
+	"github.com/satimoto/go-lnm/internal/sessionevent"
+	"github.com/satimoto/go-lnm/pkg/util"
 	ocpi "github.com/satimoto/go-ocpi/pkg/ocpi/mocks"
 )
 
 func NewService(ferpService *ferp.MockFerpService, lightningService *lightningnetwork.MockLightningNetworkService, notificationService *notification.MockNotificationService, ocpiService *ocpi.MockOcpiService) *service.ServiceResolver {
 	return &service.ServiceResolver{
+		ConversionPolicy:    util.NewConversionPolicy(),
 		FerpService:         ferpService,
+		LeaseService:        lease.NewService(),
+		LightningNodes:      lightningnetwork.NewNodes(lightningService),
 		LightningService:    lightningService,
 		NotificationService: notificationService,
 		OcpiService:         ocpiService,
+		PaymentService:      payment.NewService(lightningService),
+		SessionEventService: sessionevent.NewService(),
+		SessionScheduler:    scheduler.NewService("session", 1),
 	}
 }
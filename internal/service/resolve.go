@@ -3,29 +3,69 @@ package service
 import (
 	"os"
 
+	"github.com/satimoto/go-datastore/pkg/db"
+	dbUtil "github.com/satimoto/go-datastore/pkg/util"
 	"github.com/satimoto/go-lnm/internal/ferp"
+	"github.com/satimoto/go-lnm/internal/lease"
 	"github.com/satimoto/go-lnm/internal/lightningnetwork"
 	"github.com/satimoto/go-lnm/internal/notification"
+	"github.com/satimoto/go-lnm/internal/payment"
+	"github.com/satimoto/go-lnm/internal/scheduler"
+	"github.com/satimoto/go-lnm/internal/sessionevent"
+	"github.com/satimoto/go-lnm/internal/webhook"
+	"github.com/satimoto/go-lnm/pkg/util"
 	"github.com/satimoto/go-ocpi/pkg/ocpi"
 )
 
 type ServiceResolver struct {
+	ConversionPolicy    *util.ConversionPolicy
 	FerpService         ferp.Ferp
+	LeaseService        lease.Lease
+	LightningNode       *lightningnetwork.LightningNode
+	LightningNodes      lightningnetwork.LightningNodes
 	LightningService    lightningnetwork.LightningNetwork
 	NotificationService notification.Notification
 	OcpiService         ocpi.Ocpi
+	PaymentService      payment.Payment
+	SessionEventService sessionevent.SessionEvent
+	SessionScheduler    scheduler.Scheduler
+	WebhookService      webhook.Webhook
 }
 
-func NewService() *ServiceResolver {
-	ferpService := ferp.NewService(os.Getenv("FERP_RPC_ADDRESS"))
-	lightningService := lightningnetwork.NewService()
-	notificationService := notification.NewService(os.Getenv("FCM_API_KEY"))
+func NewService(repositoryService *db.RepositoryService) *ServiceResolver {
+	conversionPolicy := util.NewConversionPolicy()
+	ferpService := ferp.NewService(conversionPolicy, os.Getenv("FERP_RPC_ADDRESS"), util.GetEnvStrings("FERP_FALLBACK_RPC_ADDRESSES")...)
+	leaseService := lease.NewService(repositoryService)
+	lightningNodes := lightningnetwork.NewNodes()
+	lightningService := lightningNodes.ListNodes()[0].LightningService
+	notificationService := notification.NewService()
 	ocpiService := ocpi.NewService(os.Getenv("OCPI_RPC_ADDRESS"))
+	paymentService := payment.NewService(lightningService)
+	sessionEventService := sessionevent.NewService()
+	sessionScheduler := scheduler.NewService("session", int(dbUtil.GetEnvInt32("SESSION_MONITOR_WORKERS", 10)))
+	webhookService := webhook.NewService()
 
 	return &ServiceResolver{
+		ConversionPolicy:    conversionPolicy,
 		FerpService:         ferpService,
+		LeaseService:        leaseService,
+		LightningNodes:      lightningNodes,
 		LightningService:    lightningService,
 		OcpiService:         ocpiService,
 		NotificationService: notificationService,
+		PaymentService:      paymentService,
+		SessionEventService: sessionEventService,
+		SessionScheduler:    sessionScheduler,
+		WebhookService:      webhookService,
 	}
 }
+
+// ForNode returns a copy of the services using the lightning service
+// of the node, used by the monitors of each node
+func (s *ServiceResolver) ForNode(lightningNode *lightningnetwork.LightningNode) *ServiceResolver {
+	services := *s
+	services.LightningNode = lightningNode
+	services.LightningService = lightningNode.LightningService
+
+	return &services
+}
@@ -0,0 +1,85 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+	dbUtil "github.com/satimoto/go-datastore/pkg/util"
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+	"github.com/satimoto/go-lnm/internal/user"
+)
+
+type TariffBreakdown struct {
+	Currency        string
+	IsTotalCost     bool
+	FlatCost        float64
+	EnergyCost      float64
+	TimeCost        float64
+	ParkingTimeCost float64
+	SessionTimeCost float64
+	TotalCost       float64
+	TotalEnergy     float64
+	TotalTime       float64
+	CommissionFiat  float64
+	TaxFiat         float64
+	TotalFiat       float64
+}
+
+// GetTariffBreakdown calculates the current cost of the session
+// using the tariff of the session connector
+func (r *SessionResolver) GetTariffBreakdown(ctx context.Context, session db.Session) (*TariffBreakdown, error) {
+	sessionUser, err := r.UserResolver.Repository.GetUser(ctx, session.UserID)
+
+	if err != nil {
+		metrics.RecordError("LNM206", "Error retrieving session user", err)
+		log.Printf("LNM206: SessionUid=%v, UserID=%v", session.Uid, session.UserID)
+		return nil, errors.New("error retrieving session user")
+	}
+
+	connector, err := r.LocationRepository.GetConnector(ctx, session.ConnectorID)
+
+	if err != nil {
+		metrics.RecordError("LNM207", "Error retrieving session connector", err)
+		log.Printf("LNM207: SessionUid=%v, ConnectorID=%v", session.Uid, session.ConnectorID)
+		return nil, errors.New("error retrieving session connector")
+	}
+
+	if !connector.TariffID.Valid {
+		return nil, errors.New("session connector has no tariff")
+	}
+
+	tariff, err := r.TariffResolver.Repository.GetTariffByUid(ctx, connector.TariffID.String)
+
+	if err != nil {
+		metrics.RecordError("LNM208", "Error retrieving session tariff", err)
+		log.Printf("LNM208: SessionUid=%v, TariffID=%v", session.Uid, connector.TariffID.String)
+		return nil, errors.New("error retrieving session tariff")
+	}
+
+	location, err := r.LocationRepository.GetLocation(ctx, session.LocationID)
+
+	if err != nil {
+		metrics.RecordError("LNM209", "Error retrieving session location", err)
+		log.Printf("LNM209: SessionUid=%v, LocationID=%v", session.Uid, session.LocationID)
+		return nil, errors.New("error retrieving session location")
+	}
+
+	timeLocation, err := time.LoadLocation(location.TimeZone.String)
+
+	if err != nil {
+		timeLocation = time.UTC
+	}
+
+	taxPercent := r.AccountResolver.GetTaxPercentByCountry(ctx, location.Country, dbUtil.GetEnvFloat64("DEFAULT_TAX_PERCENT", 19))
+	estimatedChargePower := user.GetEstimatedChargePower(sessionUser, connector)
+	tariffIto := r.TariffResolver.CreateTariffIto(ctx, tariff)
+	sessionIto := r.CreateSessionIto(ctx, session)
+
+	tariffBreakdown := r.CalculateTariffBreakdown(sessionIto, tariffIto, estimatedChargePower, timeLocation, time.Now().UTC())
+	tariffBreakdown.TotalFiat, tariffBreakdown.CommissionFiat, tariffBreakdown.TaxFiat = CalculateCommission(tariffBreakdown.TotalCost, sessionUser.CommissionPercent, taxPercent)
+
+	return tariffBreakdown, nil
+}
@@ -0,0 +1,34 @@
+package session
+
+import (
+	"context"
+	"log"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+	"github.com/satimoto/go-lnm/internal/sessionevent"
+)
+
+func (r *SessionResolver) PublishSessionEvent(eventType string, session db.Session) {
+	r.SessionEventService.Publish(sessionevent.NewSessionEvent(eventType, session))
+}
+
+func (r *SessionResolver) PublishSessionEstimateEvent(session db.Session, estimatedEnergy, estimatedTime float64) {
+	event := sessionevent.NewSessionEvent(sessionevent.SESSION_UPDATED, session)
+	event.EstimatedEnergy = &estimatedEnergy
+	event.EstimatedTime = &estimatedTime
+
+	r.SessionEventService.Publish(event)
+}
+
+func (r *SessionResolver) PublishSessionInvoiceEvent(ctx context.Context, eventType string, sessionInvoice db.SessionInvoice) {
+	session, err := r.Repository.GetSession(ctx, sessionInvoice.SessionID)
+
+	if err != nil {
+		metrics.RecordError("LNM226", "Error retrieving session", err)
+		log.Printf("LNM226: SessionInvoiceID=%v, SessionID=%v", sessionInvoice.ID, sessionInvoice.SessionID)
+		return
+	}
+
+	r.SessionEventService.Publish(sessionevent.NewSessionInvoiceEvent(eventType, session, sessionInvoice))
+}
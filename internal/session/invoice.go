@@ -2,6 +2,7 @@ package session
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"log"
@@ -12,13 +13,19 @@ import (
 	"github.com/satimoto/go-datastore/pkg/param"
 	dbUtil "github.com/satimoto/go-datastore/pkg/util"
 	"github.com/satimoto/go-ferp/pkg/rate"
+	"github.com/satimoto/go-lnm/internal/lease"
 	"github.com/satimoto/go-lnm/internal/lightningnetwork"
 	metrics "github.com/satimoto/go-lnm/internal/metric"
+	"github.com/satimoto/go-lnm/internal/sessionevent"
+	"github.com/satimoto/go-lnm/internal/webhook"
 	"github.com/satimoto/go-lnm/pkg/util"
 )
 
+var ErrSessionInvoiceNotExpired = errors.New("session invoice is settled or not expired")
+
 func (r *SessionResolver) IssueSessionInvoice(ctx context.Context, user db.User, session db.Session, invoiceParams util.InvoiceParams, chargeParams util.ChargeParams) *db.SessionInvoice {
-	currencyRate, err := r.FerpService.GetRate(invoiceParams.Currency)
+	// Use the rate locked for the session so all invoices within the lock window share a rate
+	currencyRate, err := r.LockRate(ctx, session, invoiceParams.Currency)
 
 	if err != nil {
 		metrics.RecordError("LNM054", "Error retrieving exchange rate", err)
@@ -31,7 +38,7 @@ func (r *SessionResolver) IssueSessionInvoice(ctx context.Context, user db.User,
 	if updateUnsettledInvoices {
 		if sessionInvoice, err := r.Repository.GetUnsettledSessionInvoiceBySession(ctx, session.ID); err == nil {
 			// An unsettled session invoice exists, try to update it
-			if updatedSessionInvoice := r.updateSessionInvoice(ctx, currencyRate, session, sessionInvoice, invoiceParams, chargeParams); updatedSessionInvoice != nil {
+			if updatedSessionInvoice := r.updateSessionInvoice(ctx, currencyRate, user, session, sessionInvoice, invoiceParams, chargeParams); updatedSessionInvoice != nil {
 				return updatedSessionInvoice
 			}
 		}
@@ -56,37 +63,49 @@ func (r *SessionResolver) WaitForInvoiceExpiry(paymentRequest string) {
 	time.Sleep(timeout)
 
 	if sessionInvoice, err := r.Repository.GetSessionInvoiceByPaymentRequest(ctx, paymentRequest); err == nil && !sessionInvoice.IsSettled {
-		if paymentRequest, signature, err := lightningnetwork.CreateLightningInvoice(r.LightningService, payReqResponse.Description, sessionInvoice.TotalMsat); err == nil {
-			// Get the session invoice again to check if it's been settled or updated
-			latestSessionInvoice, err := r.Repository.GetSessionInvoice(ctx, sessionInvoice.ID)
+		lightningService := r.LightningService
+
+		if sessionUser, err := r.UserResolver.Repository.GetUser(ctx, sessionInvoice.UserID); err == nil {
+			// The invoice expires if the node of the user is not available
+			lightningService, _ = r.LightningNodes.GetService(sessionUser.NodeID)
+		}
+
+		if lightningService != nil {
+			if paymentRequest, signature, err := lightningnetwork.CreateLightningInvoice(lightningService, payReqResponse.Description, sessionInvoice.TotalMsat); err == nil {
+				// Get the session invoice again to check if it's been settled or updated
+				latestSessionInvoice, err := r.Repository.GetSessionInvoice(ctx, sessionInvoice.ID)
 
-			if err == nil && !latestSessionInvoice.IsSettled && latestSessionInvoice.PaymentRequest == sessionInvoice.PaymentRequest {
-				sessionInvoiceParams := param.NewUpdateSessionInvoiceParams(sessionInvoice)
-				sessionInvoiceParams.PaymentRequest = paymentRequest
-				sessionInvoiceParams.Signature = signature
-				sessionInvoiceParams.IsExpired = false
+				if err == nil && !latestSessionInvoice.IsSettled && latestSessionInvoice.PaymentRequest == sessionInvoice.PaymentRequest {
+					sessionInvoiceParams := param.NewUpdateSessionInvoiceParams(sessionInvoice)
+					sessionInvoiceParams.PaymentRequest = paymentRequest
+					sessionInvoiceParams.Signature = signature
+					sessionInvoiceParams.IsExpired = false
 
-				_, err := r.Repository.UpdateSessionInvoice(ctx, sessionInvoiceParams)
+					_, err := r.Repository.UpdateSessionInvoice(ctx, sessionInvoiceParams)
 
-				if err != nil {
-					metrics.RecordError("LNM170", "Error updating session invoice", err)
-					log.Printf("LNM170: Params=%#v", sessionInvoiceParams)
+					if err != nil {
+						metrics.RecordError("LNM170", "Error updating session invoice", err)
+						log.Printf("LNM170: Params=%#v", sessionInvoiceParams)
+					}
+
+					go r.WaitForInvoiceExpiry(paymentRequest)
 				}
 
-				go r.WaitForInvoiceExpiry(paymentRequest)
+				return
 			}
-
-			return
 		}
 
 		updateSessionInvoiceParams := param.NewUpdateSessionInvoiceParams(sessionInvoice)
 		updateSessionInvoiceParams.IsExpired = true
 
-		_, err = r.Repository.UpdateSessionInvoice(ctx, updateSessionInvoiceParams)
+		updatedSessionInvoice, err := r.Repository.UpdateSessionInvoice(ctx, updateSessionInvoiceParams)
 
 		if err != nil {
 			metrics.RecordError("LNM036", "Error updating session invoice", err)
 			log.Printf("LNM036: Params=%#v", updateSessionInvoiceParams)
+		} else {
+			r.QueueSessionInvoiceEvent(ctx, webhook.INVOICE_EXPIRED, updatedSessionInvoice)
+			r.PublishSessionInvoiceEvent(ctx, sessionevent.INVOICE_EXPIRED, updatedSessionInvoice)
 		}
 
 		// Metrics: Increment number of expired session invoices
@@ -94,10 +113,96 @@ func (r *SessionResolver) WaitForInvoiceExpiry(paymentRequest string) {
 	}
 }
 
+// ReissueSessionInvoice replaces the payment request of an expired session invoice
+// and sends the session invoice to the user again
+func (r *SessionResolver) ReissueSessionInvoice(ctx context.Context, sessionInvoice db.SessionInvoice) (*db.SessionInvoice, error) {
+	if sessionInvoice.IsSettled || !sessionInvoice.IsExpired {
+		return nil, ErrSessionInvoiceNotExpired
+	}
+
+	session, err := r.Repository.GetSession(ctx, sessionInvoice.SessionID)
+
+	if err != nil {
+		metrics.RecordError("LNM210", "Error retrieving session", err)
+		log.Printf("LNM210: SessionInvoiceID=%v, SessionID=%v", sessionInvoice.ID, sessionInvoice.SessionID)
+		return nil, errors.New("error retrieving session")
+	}
+
+	sessionUser, err := r.UserResolver.Repository.GetUser(ctx, session.UserID)
+
+	if err != nil {
+		metrics.RecordError("LNM243", "Error retrieving session user", err)
+		log.Printf("LNM243: SessionUid=%v, UserID=%v", session.Uid, session.UserID)
+		return nil, errors.New("error retrieving session user")
+	}
+
+	// Convert the fiat amounts again at the session rate. Within the lock window
+	// this is the rate the session was invoiced at, after it the current rate
+	// is locked, as the rate of the expired invoice may be stale
+	currencyRate, err := r.LockRate(ctx, session, sessionInvoice.Currency)
+
+	if err != nil {
+		metrics.RecordError("LNM275", "Error retrieving exchange rate", err)
+		log.Printf("LNM275: SessionInvoiceID=%v, Currency=%v", sessionInvoice.ID, sessionInvoice.Currency)
+		return nil, errors.New("error retrieving exchange rate")
+	}
+
+	invoiceParams := r.ConversionPolicy.FillInvoiceRequestParams(util.InvoiceParams{
+		Currency:       sessionInvoice.Currency,
+		PriceFiat:      dbUtil.SqlNullFloat64(sessionInvoice.PriceFiat),
+		CommissionFiat: dbUtil.SqlNullFloat64(sessionInvoice.CommissionFiat),
+		TaxFiat:        dbUtil.SqlNullFloat64(sessionInvoice.TaxFiat),
+		TotalFiat:      dbUtil.SqlNullFloat64(sessionInvoice.TotalFiat),
+	}, currencyRate.RateMsat)
+
+	lightningService, err := r.LightningNodes.GetService(sessionUser.NodeID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	memo := fmt.Sprintf("Satimoto: %s", session.Uid)
+	paymentRequest, signature, err := lightningnetwork.CreateLightningInvoice(lightningService, memo, invoiceParams.TotalMsat.Int64)
+
+	if err != nil {
+		metrics.RecordError("LNM280", "Error creating lightning invoice", err)
+		log.Printf("LNM280: SessionInvoiceID=%v, TotalMsat=%v", sessionInvoice.ID, invoiceParams.TotalMsat.Int64)
+		return nil, errors.New("error creating lightning invoice")
+	}
+
+	sessionInvoiceParams := param.NewUpdateSessionInvoiceParams(sessionInvoice)
+	sessionInvoiceParams.CurrencyRate = currencyRate.Rate
+	sessionInvoiceParams.CurrencyRateMsat = currencyRate.RateMsat
+	sessionInvoiceParams.CurrencyRateSpreadPpm = r.ConversionPolicy.GetSpreadPpm(sessionInvoice.Currency)
+	sessionInvoiceParams.PriceMsat = invoiceParams.PriceMsat.Int64
+	sessionInvoiceParams.CommissionMsat = invoiceParams.CommissionMsat.Int64
+	sessionInvoiceParams.TaxMsat = invoiceParams.TaxMsat.Int64
+	sessionInvoiceParams.TotalMsat = invoiceParams.TotalMsat.Int64
+	sessionInvoiceParams.PaymentRequest = paymentRequest
+	sessionInvoiceParams.Signature = signature
+	sessionInvoiceParams.IsExpired = false
+
+	updatedSessionInvoice, err := r.Repository.UpdateSessionInvoice(ctx, sessionInvoiceParams)
+
+	if err != nil {
+		metrics.RecordError("LNM211", "Error updating session invoice", err)
+		log.Printf("LNM211: Params=%#v", sessionInvoiceParams)
+		return nil, errors.New("error updating session invoice")
+	}
+
+	r.SendSessionInvoiceNotification(sessionUser, session, updatedSessionInvoice)
+
+	r.QueueSessionInvoiceEvent(ctx, webhook.SESSION_INVOICED, updatedSessionInvoice)
+	r.SessionEventService.Publish(sessionevent.NewSessionInvoiceEvent(sessionevent.SESSION_INVOICED, session, updatedSessionInvoice))
+
+	go r.WaitForInvoiceExpiry(paymentRequest)
+
+	return &updatedSessionInvoice, nil
+}
+
 func (r *SessionResolver) createSessionInvoice(ctx context.Context, currencyRate *rate.CurrencyRate, user db.User, session db.Session, invoiceParams util.InvoiceParams, chargeParams util.ChargeParams) *db.SessionInvoice {
 	memo := fmt.Sprintf("Satimoto: %s", session.Uid)
-	rateMsat := float64(currencyRate.RateMsat)
-	invoiceParams = util.FillInvoiceRequestParams(invoiceParams, rateMsat)
+	invoiceParams = r.ConversionPolicy.FillInvoiceRequestParams(invoiceParams, currencyRate.RateMsat)
 
 	if !invoiceParams.TotalMsat.Valid {
 		metrics.RecordError("LNM116", "Error filling request params", errors.New("invoiceParams TotalMsat not valid"))
@@ -105,11 +210,18 @@ func (r *SessionResolver) createSessionInvoice(ctx context.Context, currencyRate
 		return nil
 	}
 
-	if paymentRequest, signature, err := lightningnetwork.CreateLightningInvoice(r.LightningService, memo, invoiceParams.TotalMsat.Int64); err == nil {
+	lightningService, err := r.LightningNodes.GetService(user.NodeID)
+
+	if err != nil {
+		return nil
+	}
+
+	if paymentRequest, signature, err := lightningnetwork.CreateLightningInvoice(lightningService, memo, invoiceParams.TotalMsat.Int64); err == nil {
 		sessionInvoiceParams := param.NewCreateSessionInvoiceParams(session)
 		sessionInvoiceParams.UserID = user.ID
 		sessionInvoiceParams.CurrencyRate = currencyRate.Rate
 		sessionInvoiceParams.CurrencyRateMsat = currencyRate.RateMsat
+		sessionInvoiceParams.CurrencyRateSpreadPpm = r.ConversionPolicy.GetSpreadPpm(invoiceParams.Currency)
 		sessionInvoiceParams.PriceFiat = invoiceParams.PriceFiat.Float64
 		sessionInvoiceParams.PriceMsat = invoiceParams.PriceMsat.Int64
 		sessionInvoiceParams.CommissionFiat = invoiceParams.CommissionFiat.Float64
@@ -125,8 +237,19 @@ func (r *SessionResolver) createSessionInvoice(ctx context.Context, currencyRate
 		sessionInvoiceParams.MeteredEnergy = chargeParams.MeteredEnergy
 		sessionInvoiceParams.MeteredTime = chargeParams.MeteredTime
 
+		// The invoice is only inserted while the session lease is still held
+		fence := lease.GetFence(ctx)
+		sessionInvoiceParams.LeaseResourceType = fence.ResourceType
+		sessionInvoiceParams.LeaseResourceID = fence.ResourceID
+		sessionInvoiceParams.LeaseFencingToken = fence.FencingToken
+
 		sessionInvoice, err := r.Repository.CreateSessionInvoice(ctx, sessionInvoiceParams)
 
+		if fence.FencingToken.Valid && errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Session %s lease lost before invoicing", session.Uid)
+			return nil
+		}
+
 		if err != nil {
 			metrics.RecordError("LNM003", "Error creating session invoice", err)
 			log.Printf("LNM003: Params=%#v", sessionInvoiceParams)
@@ -144,8 +267,10 @@ func (r *SessionResolver) createSessionInvoice(ctx context.Context, currencyRate
 		metricSessionInvoicesTotalFiat.WithLabelValues(invoiceParams.Currency).Add(sessionInvoice.TotalFiat)
 		metricSessionInvoicesTotalSatoshis.Add(float64(sessionInvoice.TotalMsat / 1000))
 
-		// TODO: handle notification failure
+		// Notification is queued in the outbox and retried on failure
 		r.SendSessionInvoiceNotification(user, session, sessionInvoice)
+		r.QueueSessionInvoiceEvent(ctx, webhook.SESSION_INVOICED, sessionInvoice)
+		r.SessionEventService.Publish(sessionevent.NewSessionInvoiceEvent(sessionevent.SESSION_INVOICED, session, sessionInvoice))
 
 		go r.WaitForInvoiceExpiry(paymentRequest)
 
@@ -155,9 +280,8 @@ func (r *SessionResolver) createSessionInvoice(ctx context.Context, currencyRate
 	return nil
 }
 
-func (r *SessionResolver) updateSessionInvoice(ctx context.Context, currencyRate *rate.CurrencyRate, session db.Session, sessionInvoice db.SessionInvoice, invoiceParams util.InvoiceParams, chargeParams util.ChargeParams) *db.SessionInvoice {
+func (r *SessionResolver) updateSessionInvoice(ctx context.Context, currencyRate *rate.CurrencyRate, user db.User, session db.Session, sessionInvoice db.SessionInvoice, invoiceParams util.InvoiceParams, chargeParams util.ChargeParams) *db.SessionInvoice {
 	memo := fmt.Sprintf("Satimoto: %s", session.Uid)
-	rateMsat := float64(currencyRate.RateMsat)
 	updateInvoiceParams := util.InvoiceParams{
 		Currency:       invoiceParams.Currency,
 		PriceFiat:      util.AddNullFloat64(dbUtil.SqlNullFloat64(sessionInvoice.PriceFiat), invoiceParams.PriceFiat),
@@ -166,7 +290,7 @@ func (r *SessionResolver) updateSessionInvoice(ctx context.Context, currencyRate
 		TotalFiat:      util.AddNullFloat64(dbUtil.SqlNullFloat64(sessionInvoice.TotalFiat), invoiceParams.TotalFiat),
 	}
 
-	updateInvoiceParams = util.FillInvoiceRequestParams(updateInvoiceParams, rateMsat)
+	updateInvoiceParams = r.ConversionPolicy.FillInvoiceRequestParams(updateInvoiceParams, currencyRate.RateMsat)
 
 	if !invoiceParams.TotalMsat.Valid {
 		metrics.RecordError("LNM168", "Error filling request params", errors.New("invoiceParams TotalMsat not valid"))
@@ -174,7 +298,13 @@ func (r *SessionResolver) updateSessionInvoice(ctx context.Context, currencyRate
 		return nil
 	}
 
-	if paymentRequest, signature, err := lightningnetwork.CreateLightningInvoice(r.LightningService, memo, invoiceParams.TotalMsat.Int64); err == nil {
+	lightningService, err := r.LightningNodes.GetService(user.NodeID)
+
+	if err != nil {
+		return nil
+	}
+
+	if paymentRequest, signature, err := lightningnetwork.CreateLightningInvoice(lightningService, memo, invoiceParams.TotalMsat.Int64); err == nil {
 		// Get the session invoice again to check if it's been settled or updated
 		latestSessionInvoice, err := r.Repository.GetSessionInvoice(ctx, sessionInvoice.ID)
 
@@ -182,6 +312,7 @@ func (r *SessionResolver) updateSessionInvoice(ctx context.Context, currencyRate
 			sessionInvoiceParams := param.NewUpdateSessionInvoiceParams(latestSessionInvoice)
 			sessionInvoiceParams.CurrencyRate = currencyRate.Rate
 			sessionInvoiceParams.CurrencyRateMsat = currencyRate.RateMsat
+			sessionInvoiceParams.CurrencyRateSpreadPpm = r.ConversionPolicy.GetSpreadPpm(invoiceParams.Currency)
 			sessionInvoiceParams.PriceFiat = invoiceParams.PriceFiat.Float64
 			sessionInvoiceParams.PriceMsat = invoiceParams.PriceMsat.Int64
 			sessionInvoiceParams.CommissionFiat = invoiceParams.CommissionFiat.Float64
@@ -198,7 +329,7 @@ func (r *SessionResolver) updateSessionInvoice(ctx context.Context, currencyRate
 			sessionInvoiceParams.MeteredEnergy = chargeParams.MeteredEnergy
 			sessionInvoiceParams.MeteredTime = chargeParams.MeteredTime
 
-			_, err := r.Repository.UpdateSessionInvoice(ctx, sessionInvoiceParams)
+			updatedSessionInvoice, err := r.Repository.UpdateSessionInvoice(ctx, sessionInvoiceParams)
 
 			if err != nil {
 				metrics.RecordError("LNM169", "Error updating session invoice", err)
@@ -206,6 +337,9 @@ func (r *SessionResolver) updateSessionInvoice(ctx context.Context, currencyRate
 				return nil
 			}
 
+			r.QueueSessionInvoiceEvent(ctx, webhook.SESSION_INVOICED, updatedSessionInvoice)
+			r.SessionEventService.Publish(sessionevent.NewSessionInvoiceEvent(sessionevent.SESSION_INVOICED, session, updatedSessionInvoice))
+
 			// Metrics
 			metricSessionInvoicesTotal.Inc()
 			metricSessionInvoicesCommissionFiat.WithLabelValues(invoiceParams.Currency).Add(invoiceParams.CommissionFiat.Float64)
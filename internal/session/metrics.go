@@ -10,9 +10,14 @@ var (
 		Name: "lsp_sessions_flagged_total",
 		Help: "The total number of sessions flagged",
 	})
+	metricSessionMonitoringTasks = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "lsp_session_monitoring_tasks",
+		Help: "The total number of sessions scheduled for monitoring",
+	})
+	// Deprecated: replaced by lsp_session_monitoring_tasks, to be removed in the next release
 	metricSessionMonitoringGoroutines = promauto.NewGauge(prometheus.GaugeOpts{
 		Name: "lsp_session_monitoring_goroutines",
-		Help: "The total number of session monitoring goroutines",
+		Help: "Deprecated, use lsp_session_monitoring_tasks",
 	})
 	metricSessionInvoicesExpiredTotal = promauto.NewCounter(prometheus.CounterOpts{
 		Name: "lsp_session_invoices_expired_total",
@@ -58,4 +63,9 @@ var (
 
 func RecordFlaggedSession() {
 	metricSessionsFlaggedTotal.Inc()
+}
+
+func addSessionMonitoringTasks(delta float64) {
+	metricSessionMonitoringTasks.Add(delta)
+	metricSessionMonitoringGoroutines.Add(delta)
 }
\ No newline at end of file
@@ -4,8 +4,10 @@ import (
 	mocks "github.com/satimoto/go-datastore/pkg/db/mocks"
 	location "github.com/satimoto/go-datastore/pkg/location/mocks"
 	sessionMocks "github.com/satimoto/go-datastore/pkg/session/mocks"
+	token "github.com/satimoto/go-datastore/pkg/token/mocks"
 	tokenauthorization "github.com/satimoto/go-datastore/pkg/tokenauthorization/mocks"
 	account "github.com/satimoto/go-lnm/internal/account/mocks"
+	notificationoutbox "github.com/satimoto/go-lnm/internal/notificationoutbox/mocks"
 	"github.com/satimoto/go-lnm/internal/service"
 	"github.com/satimoto/go-lnm/internal/session"
 	tariff "github.com/satimoto/go-lnm/internal/tariff/mocks"
@@ -16,13 +18,20 @@ func NewResolver(repositoryService *mocks.MockRepositoryService, services *servi
 	return &session.SessionResolver{
 		Repository:                   sessionMocks.NewRepository(repositoryService),
 		FerpService:                  services.FerpService,
+		LeaseService:                 services.LeaseService,
+		LightningNodes:               services.LightningNodes,
 		LightningService:             services.LightningService,
 		NotificationService:          services.NotificationService,
 		OcpiService:                  services.OcpiService,
+		SessionEventService:          services.SessionEventService,
+		SessionScheduler:             services.SessionScheduler,
 		AccountResolver:              account.NewResolver(repositoryService),
 		LocationRepository:           location.NewRepository(repositoryService),
+		NotificationOutboxResolver:   notificationoutbox.NewResolver(repositoryService, services),
 		TariffResolver:               tariff.NewResolver(repositoryService),
+		TokenRepository:              token.NewRepository(repositoryService),
 		TokenAuthorizationRepository: tokenauthorization.NewRepository(repositoryService),
 		UserResolver:                 user.NewResolver(repositoryService, services),
+		ConversionPolicy:             services.ConversionPolicy,
 	}
 }
@@ -11,26 +11,62 @@ import (
 	"github.com/satimoto/go-datastore/pkg/param"
 	dbUtil "github.com/satimoto/go-datastore/pkg/util"
 	"github.com/satimoto/go-lnm/internal/ito"
+	"github.com/satimoto/go-lnm/internal/lease"
 	metrics "github.com/satimoto/go-lnm/internal/metric"
+	"github.com/satimoto/go-lnm/internal/scheduler"
+	"github.com/satimoto/go-lnm/internal/sessionevent"
 	"github.com/satimoto/go-lnm/internal/user"
+	"github.com/satimoto/go-lnm/internal/webhook"
 	"github.com/satimoto/go-lnm/pkg/util"
 	"github.com/satimoto/go-ocpi/ocpirpc"
 )
 
+type sessionMonitor struct {
+	sessionID       int64
+	sessionUid      string
+	user            db.User
+	connector       db.Connector
+	tariffIto       *ito.TariffIto
+	timeLocation    *time.Location
+	taxPercent      float64
+	invoiceInterval time.Duration
+	heldLease       *lease.HeldLease
+}
+
 func (r *SessionResolver) StartSessionMonitor(session db.Session) {
 	/** Session has been created.
 	 *  Send SessionUpdate notification to user.
 	 *  Calculate invoiced amount.
 	 *  Define invoice period based on connector wattage
-	 *  Periodically calculate session total, issue an invoice to the user.
+	 *  Schedule the session to periodically calculate the session total
+	 *  and issue an invoice to the user.
 	 *  Monitor issued invoices, if invoices go unpaid, cancel session.
 	 */
 
-	metricSessionMonitoringGoroutines.Inc()
-	defer metricSessionMonitoringGoroutines.Dec()
-
 	ctx := context.Background()
 
+	// Only one instance monitors a session at a time
+	heldLease, err := r.LeaseService.Acquire(ctx, lease.LEASE_SESSION, session.ID, func() {
+		r.SessionScheduler.Wake(session.ID)
+	})
+
+	if err != nil {
+		if errors.Is(err, lease.ErrLeaseHeld) {
+			log.Printf("Session %s is already monitored", session.Uid)
+		}
+
+		return
+	}
+
+	scheduled := false
+
+	defer func() {
+		if !scheduled {
+			// Monitoring ended, the session is not taken over
+			r.LeaseService.End(ctx, heldLease)
+		}
+	}()
+
 	if !session.AuthorizationID.Valid {
 		// There is no AuthorizationID set, flag the session.
 		metrics.RecordError("LNM137", "Error in session", errors.New("authorizationID is nil"))
@@ -93,6 +129,8 @@ func (r *SessionResolver) StartSessionMonitor(session db.Session) {
 	}
 
 	r.SendSessionUpdateNotification(user, session)
+	r.QueueSessionEvent(ctx, webhook.SESSION_STARTED, session)
+	r.PublishSessionEvent(sessionevent.SESSION_UPDATED, session)
 
 	if connector.TariffID.Valid {
 		tariff, err := r.TariffResolver.Repository.GetTariffByUid(ctx, connector.TariffID.String)
@@ -124,34 +162,72 @@ func (r *SessionResolver) StartSessionMonitor(session db.Session) {
 		invoiceInterval := calculateInvoiceInterval(connector.Wattage)
 		log.Printf("Monitor session for %s, running every %f seconds", session.Uid, invoiceInterval.Seconds())
 
-	invoiceLoop:
-		for {
-			// Wait for invoice interval
-			time.Sleep(invoiceInterval)
+		monitor := &sessionMonitor{
+			sessionID:       session.ID,
+			sessionUid:      session.Uid,
+			user:            user,
+			connector:       connector,
+			tariffIto:       tariffIto,
+			timeLocation:    timeLocation,
+			taxPercent:      taxPercent,
+			invoiceInterval: invoiceInterval,
+			heldLease:       heldLease,
+		}
 
-			// Get latest session
-			session, err = r.Repository.GetSession(ctx, session.ID)
+		scheduled = true
+		addSessionMonitoringTasks(1)
+		r.SessionScheduler.Schedule(session.ID, invoiceInterval, r.monitorSession(monitor))
+	}
+}
 
-			if err != nil {
-				metrics.RecordError("LNM032", "Error retrieving session", err)
-				log.Printf("LNM032: SessionUid=%v", session.Uid)
-				continue
+// monitorSession returns the scheduler task that invoices the session every
+// invoice interval, or sooner when the session is updated
+func (r *SessionResolver) monitorSession(monitor *sessionMonitor) scheduler.Task {
+	return func() (time.Duration, bool) {
+		// Invoices are fenced by the session lease
+		ctx := lease.WithLease(context.Background(), monitor.heldLease)
+
+		if err := r.LeaseService.Check(ctx, monitor.heldLease); err != nil {
+			if errors.Is(err, lease.ErrLeaseLost) {
+				// Another instance has taken over the session
+				log.Printf("Ending session monitoring for %s, lease lost", monitor.sessionUid)
+				addSessionMonitoringTasks(-1)
+				return 0, false
 			}
 
-			switch session.Status {
-			case db.SessionStatusTypeCOMPLETED, db.SessionStatusTypeENDING, db.SessionStatusTypeINVALID, db.SessionStatusTypeINVOICED:
-				// End monitoring, let the CDR issue the final invoice
-				log.Printf("Ending session monitoring for %s", session.Uid)
-				r.SendSessionUpdateNotification(user, session)
-				break invoiceLoop
-			case db.SessionStatusTypeACTIVE:
-				// Session is active, calculate new invoice
-				if ok := r.processInvoicePeriod(ctx, user, session, timeLocation, tariffIto, connector, taxPercent); !ok {
-					log.Printf("Ending session monitoring for %s with errors", session.Uid)
-					break invoiceLoop
-				}
+			return monitor.invoiceInterval, true
+		}
+
+		// Get latest session
+		session, err := r.Repository.GetSession(ctx, monitor.sessionID)
+
+		if err != nil {
+			metrics.RecordError("LNM032", "Error retrieving session", err)
+			log.Printf("LNM032: SessionUid=%v", monitor.sessionUid)
+			return monitor.invoiceInterval, true
+		}
+
+		switch session.Status {
+		case db.SessionStatusTypeCOMPLETED, db.SessionStatusTypeENDING, db.SessionStatusTypeINVALID, db.SessionStatusTypeINVOICED:
+			// End monitoring, let the CDR issue the final invoice
+			log.Printf("Ending session monitoring for %s", session.Uid)
+			r.SendSessionUpdateNotification(monitor.user, session)
+			r.QueueSessionEvent(ctx, webhook.SESSION_UPDATED, session)
+			r.PublishSessionEvent(sessionevent.SESSION_UPDATED, session)
+			r.LeaseService.End(ctx, monitor.heldLease)
+			addSessionMonitoringTasks(-1)
+			return 0, false
+		case db.SessionStatusTypeACTIVE:
+			// Session is active, calculate new invoice
+			if ok := r.processInvoicePeriod(ctx, monitor.user, session, monitor.timeLocation, monitor.tariffIto, monitor.connector, monitor.taxPercent); !ok {
+				log.Printf("Ending session monitoring for %s with errors", session.Uid)
+				r.LeaseService.End(ctx, monitor.heldLease)
+				addSessionMonitoringTasks(-1)
+				return 0, false
 			}
 		}
+
+		return monitor.invoiceInterval, true
 	}
 }
 
@@ -164,6 +240,13 @@ func (r *SessionResolver) FlagSession(ctx context.Context, session db.Session) {
 	RecordFlaggedSession()
 }
 
+func (r *SessionResolver) UnflagSession(ctx context.Context, session db.Session) error {
+	return r.Repository.UpdateSessionIsFlaggedByUid(ctx, db.UpdateSessionIsFlaggedByUidParams{
+		Uid:       session.Uid,
+		IsFlagged: false,
+	})
+}
+
 func (r *SessionResolver) StopSession(ctx context.Context, session db.Session) (*ocpirpc.StopSessionResponse, error) {
 	r.FlagSession(ctx, session)
 
@@ -237,6 +320,8 @@ func (r *SessionResolver) processInvoicePeriod(ctx context.Context, sessionUser
 		return false
 	}
 
+	r.PublishSessionEstimateEvent(session, math.Max(0, estimatedEnergy), math.Max(0, estimatedTime))
+
 	if estimatedFiat > invoicedPriceFiat {
 		priceFiat := estimatedFiat - invoicedPriceFiat
 		totalFiat, commissionFiat, taxFiat := CalculateCommission(estimatedFiat-invoicedPriceFiat, sessionUser.CommissionPercent, taxPercent)
@@ -1,6 +1,8 @@
 package session
 
 import (
+	"context"
+
 	"github.com/satimoto/go-datastore/pkg/db"
 	"github.com/satimoto/go-lnm/internal/notification"
 )
@@ -8,11 +10,11 @@ import (
 func (r *SessionResolver) SendSessionInvoiceNotification(user db.User, session db.Session, sessionInvoice db.SessionInvoice) {
 	dto := notification.CreateSessionInvoiceNotificationDto(session, sessionInvoice)
 
-	r.NotificationService.SendUserNotification(user, dto, notification.SESSION_INVOICE)
+	r.NotificationOutboxResolver.QueueUserNotification(context.Background(), user, dto, notification.SESSION_INVOICE)
 }
 
 func (r *SessionResolver) SendSessionUpdateNotification(user db.User, session db.Session) {
 	dto := notification.CreateSessionUpdateNotificationDto(session)
 
-	r.NotificationService.SendUserNotification(user, dto, notification.SESSION_UPDATE)
+	r.NotificationOutboxResolver.QueueUserNotification(context.Background(), user, dto, notification.SESSION_UPDATE)
 }
@@ -9,9 +9,18 @@ import (
 	"github.com/satimoto/go-datastore/pkg/util"
 	"github.com/satimoto/go-lnm/internal/ito"
 	metrics "github.com/satimoto/go-lnm/internal/metric"
+	"github.com/satimoto/go-lnm/internal/sessionevent"
+	"github.com/satimoto/go-lnm/internal/webhook"
 )
 
 func (r *SessionResolver) ProcessChargingPeriods(sessionIto *ito.SessionIto, tariffIto *ito.TariffIto, estimatedChargePower float64, timeLocation *time.Location, processDatetime time.Time) (totalAmount, totalEnergy, totalTime float64) {
+	tariffBreakdown := r.CalculateTariffBreakdown(sessionIto, tariffIto, estimatedChargePower, timeLocation, processDatetime)
+
+	return tariffBreakdown.TotalCost, tariffBreakdown.TotalEnergy, tariffBreakdown.TotalTime
+}
+
+// CalculateTariffBreakdown calculates the session cost per tariff dimension
+func (r *SessionResolver) CalculateTariffBreakdown(sessionIto *ito.SessionIto, tariffIto *ito.TariffIto, estimatedChargePower float64, timeLocation *time.Location, processDatetime time.Time) *TariffBreakdown {
 	lastDatetime := sessionIto.LastUpdated
 	numChargingPeriods := len(sessionIto.ChargingPeriods)
 	startDatetime := sessionIto.StartDatetime
@@ -22,11 +31,15 @@ func (r *SessionResolver) ProcessChargingPeriods(sessionIto *ito.SessionIto, tar
 	}
 
 	isCdr := sessionIto.IsCdr
+	totalAmount := 0.0
 	totalCost := sessionIto.TotalCost
-	totalEnergy = sessionIto.TotalEnergy
-	totalTime = util.DefaultFloat(sessionIto.TotalTime, 0)
+	totalEnergy := sessionIto.TotalEnergy
+	totalTime := util.DefaultFloat(sessionIto.TotalTime, 0)
 	totalParkingTime := sessionIto.TotalParkingTime
 	totalSessionTime := sessionIto.TotalSessionTime
+	tariffBreakdown := &TariffBreakdown{
+		Currency: tariffIto.Currency,
+	}
 
 	// Get the time from ITO, else calculate it from the session time period
 	if sessionIto.TotalTime == nil {
@@ -36,6 +49,7 @@ func (r *SessionResolver) ProcessChargingPeriods(sessionIto *ito.SessionIto, tar
 	if totalCost != nil && *totalCost > 0 {
 		// ITO has total cost defined
 		totalAmount = *totalCost
+		tariffBreakdown.IsTotalCost = true
 
 		if !isCdr && sessionIto.EndDatetime == nil && lastUpdatedTime < totalTime {
 			// Calculate delta
@@ -199,16 +213,27 @@ func (r *SessionResolver) ProcessChargingPeriods(sessionIto *ito.SessionIto, tar
 		}
 
 		totalAmount = chargingPeriodsEnergyCost + chargingPeriodsParkingTimeCost + chargingPeriodsTimeCost + flatCost + sessionTimeCost
+
+		tariffBreakdown.EnergyCost = chargingPeriodsEnergyCost
+		tariffBreakdown.FlatCost = flatCost
+		tariffBreakdown.ParkingTimeCost = chargingPeriodsParkingTimeCost
+		tariffBreakdown.SessionTimeCost = sessionTimeCost
+		tariffBreakdown.TimeCost = chargingPeriodsTimeCost
 	}
 
 	log.Printf("%v: Total cost: %v", sessionIto.Uid, totalAmount)
 
-	return totalAmount, totalEnergy, totalTime
+	tariffBreakdown.TotalCost = totalAmount
+	tariffBreakdown.TotalEnergy = totalEnergy
+	tariffBreakdown.TotalTime = totalTime
+
+	return tariffBreakdown
 }
 
 func (r *SessionResolver) UpdateSession(session db.Session) {
 	/** Session status has changed.
 	 *  Send a SessionUpdate notification to the user
+	 *  Wake the session monitor to invoice the update
 	 */
 
 	ctx := context.Background()
@@ -220,6 +245,9 @@ func (r *SessionResolver) UpdateSession(session db.Session) {
 		return
 	}
 
-	// TODO: handle notification failure
+	// Notification is queued in the outbox and retried on failure
 	r.SendSessionUpdateNotification(user, session)
+	r.QueueSessionEvent(ctx, webhook.SESSION_UPDATED, session)
+	r.PublishSessionEvent(sessionevent.SESSION_UPDATED, session)
+	r.SessionScheduler.Wake(session.ID)
 }
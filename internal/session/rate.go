@@ -0,0 +1,59 @@
+package session
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+	dbUtil "github.com/satimoto/go-datastore/pkg/util"
+	"github.com/satimoto/go-ferp/pkg/rate"
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+)
+
+// LockRate returns the currency rate locked for the session. When the session
+// has no lock or the lock window has passed, the current rate is locked and
+// stored with the session, so every invoice and rebate of the session uses
+// and records the same rate across restarts and instances.
+func (r *SessionResolver) LockRate(ctx context.Context, session db.Session, currency string) (*rate.CurrencyRate, error) {
+	if r.RateLockWindow <= 0 || currency != session.Currency {
+		return r.FerpService.GetRate(currency)
+	}
+
+	// Reload the session, the rate may have been locked since it was read
+	if s, err := r.Repository.GetSession(ctx, session.ID); err == nil {
+		session = s
+	}
+
+	if session.RateLockedAt.Valid && time.Now().Before(session.RateLockedAt.Time.Add(r.RateLockWindow)) {
+		return &rate.CurrencyRate{
+			Rate:        session.CurrencyRate.Int64,
+			RateMsat:    session.CurrencyRateMsat.Int64,
+			LastUpdated: session.RateLockedAt.Time,
+		}, nil
+	}
+
+	currencyRate, err := r.FerpService.GetRate(currency)
+
+	if err != nil {
+		return nil, err
+	}
+
+	updateSessionRateLockParams := db.UpdateSessionRateLockParams{
+		ID:               session.ID,
+		CurrencyRate:     dbUtil.SqlNullInt64(currencyRate.Rate),
+		CurrencyRateMsat: dbUtil.SqlNullInt64(currencyRate.RateMsat),
+		RateLockedAt:     dbUtil.SqlNullTime(time.Now()),
+	}
+
+	if err := r.Repository.UpdateSessionRateLock(ctx, updateSessionRateLockParams); err != nil {
+		// Invoice at the current rate, the lock is retried on the next invoice
+		metrics.RecordError("LNM274", "Error locking session rate", err)
+		log.Printf("LNM274: Params=%#v", updateSessionRateLockParams)
+		return currencyRate, nil
+	}
+
+	log.Printf("Rate locked for %v: Currency=%v, RateMsat=%v", session.Uid, currency, currencyRate.RateMsat)
+
+	return currencyRate, nil
+}
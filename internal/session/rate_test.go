@@ -0,0 +1,85 @@
+package session_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+	dbMocks "github.com/satimoto/go-datastore/pkg/db/mocks"
+	dbUtil "github.com/satimoto/go-datastore/pkg/util"
+	"github.com/satimoto/go-ferp/pkg/rate"
+	ferpMocks "github.com/satimoto/go-lnm/internal/ferp/mocks"
+	lightningnetworkMocks "github.com/satimoto/go-lnm/internal/lightningnetwork/mocks"
+	notificationMocks "github.com/satimoto/go-lnm/internal/notification/mocks"
+	serviceMocks "github.com/satimoto/go-lnm/internal/service/mocks"
+	sessionsMocks "github.com/satimoto/go-lnm/internal/session/mocks"
+	ocpiMocks "github.com/satimoto/go-ocpi/pkg/ocpi/mocks"
+)
+
+func TestLockRate(t *testing.T) {
+	ctx := context.Background()
+	session := db.Session{
+		ID:       1,
+		Uid:      "SESSION0001",
+		Currency: "EUR",
+	}
+
+	t.Run("Rate locked within window", func(t *testing.T) {
+		mockRepository := dbMocks.NewMockRepositoryService()
+		mockFerpService := ferpMocks.NewService()
+		mockServices := serviceMocks.NewService(mockFerpService, lightningnetworkMocks.NewService(), notificationMocks.NewService(), ocpiMocks.NewService())
+		sessionResolver := sessionsMocks.NewResolver(mockRepository, mockServices)
+		sessionResolver.RateLockWindow = time.Hour
+
+		lockedSession := session
+		lockedSession.CurrencyRate = dbUtil.SqlNullInt64(4500)
+		lockedSession.CurrencyRateMsat = dbUtil.SqlNullInt64(4500000)
+		lockedSession.RateLockedAt = dbUtil.SqlNullTime(time.Now().Add(-time.Minute))
+
+		mockRepository.SetGetSessionMockData(dbMocks.SessionMockData{Session: lockedSession})
+		mockFerpService.SetGetRateMockData(&rate.CurrencyRate{Rate: 5000, RateMsat: 5000000, LastUpdated: time.Now()})
+
+		currencyRate, err := sessionResolver.LockRate(ctx, session, "EUR")
+
+		if err != nil || currencyRate.RateMsat != 4500000 {
+			t.Errorf("Rate mismatch: %v, %v expecting %v", currencyRate, err, 4500000)
+		}
+
+		if _, err := mockRepository.GetUpdateSessionRateLockMockData(); err == nil {
+			t.Errorf("Unexpected rate lock update")
+		}
+	})
+
+	t.Run("Rate lock expired", func(t *testing.T) {
+		mockRepository := dbMocks.NewMockRepositoryService()
+		mockFerpService := ferpMocks.NewService()
+		mockServices := serviceMocks.NewService(mockFerpService, lightningnetworkMocks.NewService(), notificationMocks.NewService(), ocpiMocks.NewService())
+		sessionResolver := sessionsMocks.NewResolver(mockRepository, mockServices)
+		sessionResolver.RateLockWindow = time.Hour
+
+		lockedSession := session
+		lockedSession.CurrencyRate = dbUtil.SqlNullInt64(4500)
+		lockedSession.CurrencyRateMsat = dbUtil.SqlNullInt64(4500000)
+		lockedSession.RateLockedAt = dbUtil.SqlNullTime(time.Now().Add(-2 * time.Hour))
+
+		mockRepository.SetGetSessionMockData(dbMocks.SessionMockData{Session: lockedSession})
+		mockFerpService.SetGetRateMockData(&rate.CurrencyRate{Rate: 5000, RateMsat: 5000000, LastUpdated: time.Now()})
+
+		currencyRate, err := sessionResolver.LockRate(ctx, session, "EUR")
+
+		if err != nil || currencyRate.RateMsat != 5000000 {
+			t.Errorf("Rate mismatch: %v, %v expecting %v", currencyRate, err, 5000000)
+		}
+
+		updateSessionRateLockParams, err := mockRepository.GetUpdateSessionRateLockMockData()
+
+		if err != nil {
+			t.Fatalf("Expected rate lock update: %v", err)
+		}
+
+		if updateSessionRateLockParams.CurrencyRateMsat.Int64 != 5000000 {
+			t.Errorf("Locked rate mismatch: %v expecting %v", updateSessionRateLockParams.CurrencyRateMsat.Int64, 5000000)
+		}
+	})
+}
@@ -1,47 +1,69 @@
 package session
 
 import (
+	"time"
+
 	"github.com/satimoto/go-datastore/pkg/db"
 	"github.com/satimoto/go-datastore/pkg/location"
 	"github.com/satimoto/go-datastore/pkg/session"
 	"github.com/satimoto/go-datastore/pkg/token"
 	"github.com/satimoto/go-datastore/pkg/tokenauthorization"
+	dbUtil "github.com/satimoto/go-datastore/pkg/util"
 	"github.com/satimoto/go-lnm/internal/account"
 	"github.com/satimoto/go-lnm/internal/ferp"
+	"github.com/satimoto/go-lnm/internal/lease"
 	"github.com/satimoto/go-lnm/internal/lightningnetwork"
 	"github.com/satimoto/go-lnm/internal/notification"
+	"github.com/satimoto/go-lnm/internal/notificationoutbox"
+	"github.com/satimoto/go-lnm/internal/scheduler"
 	"github.com/satimoto/go-lnm/internal/service"
+	"github.com/satimoto/go-lnm/internal/sessionevent"
 	"github.com/satimoto/go-lnm/internal/tariff"
 	"github.com/satimoto/go-lnm/internal/user"
+	"github.com/satimoto/go-lnm/pkg/util"
 	"github.com/satimoto/go-ocpi/pkg/ocpi"
 )
 
 type SessionResolver struct {
 	Repository                   session.SessionRepository
 	FerpService                  ferp.Ferp
+	LeaseService                 lease.Lease
+	LightningNodes               lightningnetwork.LightningNodes
 	LightningService             lightningnetwork.LightningNetwork
 	NotificationService          notification.Notification
 	OcpiService                  ocpi.Ocpi
+	SessionScheduler             scheduler.Scheduler
+	SessionEventService          sessionevent.SessionEvent
 	AccountResolver              *account.AccountResolver
 	LocationRepository           location.LocationRepository
+	NotificationOutboxResolver   *notificationoutbox.NotificationOutboxResolver
 	TariffResolver               *tariff.TariffResolver
 	TokenRepository              token.TokenRepository
 	TokenAuthorizationRepository tokenauthorization.TokenAuthorizationRepository
 	UserResolver                 *user.UserResolver
+	ConversionPolicy             *util.ConversionPolicy
+	RateLockWindow               time.Duration
 }
 
 func NewResolver(repositoryService *db.RepositoryService, services *service.ServiceResolver) *SessionResolver {
 	return &SessionResolver{
 		Repository:                   session.NewRepository(repositoryService),
 		FerpService:                  services.FerpService,
+		LeaseService:                 services.LeaseService,
+		LightningNodes:               services.LightningNodes,
 		LightningService:             services.LightningService,
 		OcpiService:                  services.OcpiService,
 		NotificationService:          services.NotificationService,
+		SessionEventService:          services.SessionEventService,
+		SessionScheduler:             services.SessionScheduler,
 		AccountResolver:              account.NewResolver(repositoryService),
 		LocationRepository:           location.NewRepository(repositoryService),
+		NotificationOutboxResolver:   notificationoutbox.NewResolver(repositoryService, services),
 		TariffResolver:               tariff.NewResolver(repositoryService),
 		TokenRepository:              token.NewRepository(repositoryService),
 		TokenAuthorizationRepository: tokenauthorization.NewRepository(repositoryService),
 		UserResolver:                 user.NewResolver(repositoryService, services),
+		ConversionPolicy:             services.ConversionPolicy,
+		RateLockWindow:               time.Duration(dbUtil.GetEnvInt32("FERP_RATE_LOCK_WINDOW", 1800)) * time.Second,
 	}
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/satimoto/go-datastore/pkg/db"
 	dbUtil "github.com/satimoto/go-datastore/pkg/util"
+	"github.com/satimoto/go-lnm/internal/lease"
 	metrics "github.com/satimoto/go-lnm/internal/metric"
 )
 
@@ -13,17 +14,7 @@ func (r *SessionResolver) Startup(nodeID int64) {
 	ctx := context.Background()
 
 	// Start monitoring in progress sessions
-	sessions, err := r.Repository.ListInProgressSessionsByNodeID(ctx, dbUtil.SqlNullInt64(nodeID))
-
-	if err != nil {
-		metrics.RecordError("LNM135", "Error listing sessions", err)
-		log.Printf("LNM135: NodeID=%v", nodeID)
-	}
-
-	for _, session := range sessions {
-		log.Printf("Monitoring session %s after restart", session.Uid)
-		go r.StartSessionMonitor(session)
-	}
+	r.TakeoverSessions(nodeID)
 
 	// List session invoices to check expiry
 	listSessionInvoicesParams := db.ListSessionInvoicesByNodeIDParams{
@@ -43,3 +34,23 @@ func (r *SessionResolver) Startup(nodeID int64) {
 		go r.WaitForInvoiceExpiry(sessionInvoice.PaymentRequest)
 	}
 }
+
+// TakeoverSessions starts monitoring in progress sessions not monitored by
+// this instance. Sessions monitored by another instance are skipped until
+// their lease expires, and sessions whose monitoring ended are skipped.
+func (r *SessionResolver) TakeoverSessions(nodeID int64) {
+	ctx := context.Background()
+	sessions, err := r.Repository.ListInProgressSessionsByNodeID(ctx, dbUtil.SqlNullInt64(nodeID))
+
+	if err != nil {
+		metrics.RecordError("LNM135", "Error listing sessions", err)
+		log.Printf("LNM135: NodeID=%v", nodeID)
+		return
+	}
+
+	for _, session := range sessions {
+		if !r.LeaseService.IsHeld(lease.LEASE_SESSION, session.ID) {
+			go r.StartSessionMonitor(session)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package session
+
+import (
+	"context"
+	"log"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+	"github.com/satimoto/go-lnm/internal/webhook"
+)
+
+func (r *SessionResolver) QueueSessionEvent(ctx context.Context, eventType string, session db.Session) {
+	dto := webhook.NewSessionDto(session)
+
+	r.NotificationOutboxResolver.QueueWebhookEvent(ctx, session.UserID, eventType, dto)
+}
+
+func (r *SessionResolver) QueueSessionInvoiceEvent(ctx context.Context, eventType string, sessionInvoice db.SessionInvoice) {
+	session, err := r.Repository.GetSession(ctx, sessionInvoice.SessionID)
+
+	if err != nil {
+		metrics.RecordError("LNM202", "Error retrieving session", err)
+		log.Printf("LNM202: SessionInvoiceID=%v, SessionID=%v", sessionInvoice.ID, sessionInvoice.SessionID)
+		return
+	}
+
+	dto := webhook.NewSessionInvoiceDto(session, sessionInvoice)
+
+	r.NotificationOutboxResolver.QueueWebhookEvent(ctx, sessionInvoice.UserID, eventType, dto)
+}
@@ -0,0 +1,21 @@
+package sessionevent
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricSessionEventsPublishedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lsp_session_events_published_total",
+		Help: "The total number of session events published",
+	}, []string{"type"})
+	metricSessionEventsDroppedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lsp_session_events_dropped_total",
+		Help: "The total number of session events dropped for slow subscribers",
+	}, []string{"type"})
+	metricSessionEventSubscriptions = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "lsp_session_event_subscriptions",
+		Help: "The number of active session event subscriptions",
+	})
+)
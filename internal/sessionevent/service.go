@@ -0,0 +1,87 @@
+package sessionevent
+
+import (
+	"log"
+	"sync"
+
+	"github.com/satimoto/go-datastore/pkg/util"
+)
+
+type SessionEvent interface {
+	Publish(event Event)
+	Subscribe(sessionID int64) (<-chan Event, func())
+}
+
+type SessionEventService struct {
+	bufferSize    int
+	mutex         sync.RWMutex
+	nextID        int64
+	subscriptions map[int64]map[int64]chan Event
+}
+
+func NewService() SessionEvent {
+	return &SessionEventService{
+		bufferSize:    int(util.GetEnvInt32("SESSION_EVENT_BUFFER_SIZE", 16)),
+		subscriptions: make(map[int64]map[int64]chan Event),
+	}
+}
+
+// Publish sends the event to all subscribers of the session. Subscribers
+// that are not keeping up have the event dropped rather than blocking the
+// publisher.
+func (s *SessionEventService) Publish(event Event) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	metricSessionEventsPublishedTotal.WithLabelValues(event.Type).Inc()
+
+	for _, eventChan := range s.subscriptions[event.Session.ID] {
+		select {
+		case eventChan <- event:
+		default:
+			log.Printf("Dropping %v event for session %v", event.Type, event.Session.Uid)
+			metricSessionEventsDroppedTotal.WithLabelValues(event.Type).Inc()
+		}
+	}
+}
+
+// Subscribe returns a channel receiving events for the session and a
+// function to cancel the subscription, which closes the channel
+func (s *SessionEventService) Subscribe(sessionID int64) (<-chan Event, func()) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.nextID++
+	subscriptionID := s.nextID
+	eventChan := make(chan Event, s.bufferSize)
+
+	if _, ok := s.subscriptions[sessionID]; !ok {
+		s.subscriptions[sessionID] = make(map[int64]chan Event)
+	}
+
+	s.subscriptions[sessionID][subscriptionID] = eventChan
+	metricSessionEventSubscriptions.Inc()
+
+	var once sync.Once
+
+	return eventChan, func() {
+		once.Do(func() {
+			s.unsubscribe(sessionID, subscriptionID)
+		})
+	}
+}
+
+func (s *SessionEventService) unsubscribe(sessionID, subscriptionID int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if eventChan, ok := s.subscriptions[sessionID][subscriptionID]; ok {
+		delete(s.subscriptions[sessionID], subscriptionID)
+		close(eventChan)
+		metricSessionEventSubscriptions.Dec()
+	}
+
+	if len(s.subscriptions[sessionID]) == 0 {
+		delete(s.subscriptions, sessionID)
+	}
+}
@@ -0,0 +1,57 @@
+package sessionevent_test
+
+import (
+	"testing"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+	"github.com/satimoto/go-lnm/internal/sessionevent"
+)
+
+func TestSessionEvent(t *testing.T) {
+	t.Run("Publish to session subscribers", func(t *testing.T) {
+		sessionEventService := sessionevent.NewService()
+		eventChan, unsubscribe := sessionEventService.Subscribe(1)
+		otherChan, unsubscribeOther := sessionEventService.Subscribe(2)
+		defer unsubscribeOther()
+
+		sessionEventService.Publish(sessionevent.NewSessionEvent(sessionevent.SESSION_UPDATED, db.Session{ID: 1}))
+
+		select {
+		case event := <-eventChan:
+			if event.Type != sessionevent.SESSION_UPDATED || event.Session.ID != 1 {
+				t.Errorf("Event mismatch: %#v", event)
+			}
+		default:
+			t.Error("Expected event")
+		}
+
+		select {
+		case event := <-otherChan:
+			t.Errorf("Unexpected event: %#v", event)
+		default:
+		}
+
+		unsubscribe()
+		unsubscribe()
+
+		if _, ok := <-eventChan; ok {
+			t.Error("Expected closed channel")
+		}
+
+		sessionEventService.Publish(sessionevent.NewSessionEvent(sessionevent.SESSION_UPDATED, db.Session{ID: 1}))
+	})
+
+	t.Run("Drop events for slow subscribers", func(t *testing.T) {
+		sessionEventService := sessionevent.NewService()
+		eventChan, unsubscribe := sessionEventService.Subscribe(1)
+		defer unsubscribe()
+
+		for i := 0; i < 100; i++ {
+			sessionEventService.Publish(sessionevent.NewSessionEvent(sessionevent.SESSION_UPDATED, db.Session{ID: 1}))
+		}
+
+		if len(eventChan) != cap(eventChan) {
+			t.Errorf("Buffer mismatch: %v expecting %v", len(eventChan), cap(eventChan))
+		}
+	})
+}
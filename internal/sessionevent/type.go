@@ -0,0 +1,35 @@
+package sessionevent
+
+import (
+	"github.com/satimoto/go-datastore/pkg/db"
+)
+
+const (
+	SESSION_UPDATED  = "SESSION_UPDATED"
+	SESSION_INVOICED = "SESSION_INVOICED"
+	INVOICE_SETTLED  = "INVOICE_SETTLED"
+	INVOICE_EXPIRED  = "INVOICE_EXPIRED"
+)
+
+type Event struct {
+	Type            string
+	Session         db.Session
+	SessionInvoice  *db.SessionInvoice
+	EstimatedEnergy *float64
+	EstimatedTime   *float64
+}
+
+func NewSessionEvent(eventType string, session db.Session) Event {
+	return Event{
+		Type:    eventType,
+		Session: session,
+	}
+}
+
+func NewSessionInvoiceEvent(eventType string, session db.Session, sessionInvoice db.SessionInvoice) Event {
+	return Event{
+		Type:           eventType,
+		Session:        session,
+		SessionInvoice: &sessionInvoice,
+	}
+}
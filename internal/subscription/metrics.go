@@ -0,0 +1,25 @@
+package subscription
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricEventsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lsp_subscription_events_total",
+		Help: "The total number of events received from a stream",
+	}, []string{"pubkey", "stream"})
+	metricReconnectsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lsp_subscription_reconnects_total",
+		Help: "The total number of times a stream was subscribed again",
+	}, []string{"pubkey", "stream"})
+	metricLastEventTimestampSeconds = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "lsp_subscription_last_event_timestamp_seconds",
+		Help: "The time the last event was received from a stream",
+	}, []string{"pubkey", "stream"})
+	metricBufferedEvents = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "lsp_subscription_buffered_events",
+		Help: "The number of received events waiting to be handled",
+	}, []string{"pubkey", "stream"})
+)
@@ -0,0 +1,226 @@
+package subscription
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	dbUtil "github.com/satimoto/go-datastore/pkg/util"
+	"github.com/satimoto/go-lnm/internal/health"
+	"github.com/satimoto/go-lnm/internal/lease"
+	metrics "github.com/satimoto/go-lnm/internal/metric"
+	"github.com/satimoto/go-lnm/pkg/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Stream receives the events of a subscription, such as an LND stream client
+type Stream[T any] interface {
+	Recv() (*T, error)
+}
+
+type SubscribeFunc[T any] func() (Stream[T], error)
+
+type HandleFunc[T any] func(event *T)
+
+type Config[T any] struct {
+	// Name labels the stream in logs and metrics
+	Name string
+	// Pubkey labels the stream with the pubkey of the LND node
+	Pubkey    string
+	Subscribe SubscribeFunc[T]
+	Handle    HandleFunc[T]
+	// BufferSize is the number of received events waiting to be handled,
+	// receiving blocks while the buffer is full. Defaults to SUBSCRIPTION_BUFFER_SIZE.
+	BufferSize int
+	Heartbeat  *health.Heartbeat
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// LeaseService, when set, only runs the stream while the LeaseType lease
+	// of LeaseID is held, so the events are handled by a single instance.
+	// Other instances stand by and try to acquire the lease every LeaseInterval,
+	// which defaults to LEASE_TAKEOVER_INTERVAL.
+	LeaseService  lease.Lease
+	LeaseType     string
+	LeaseID       int64
+	LeaseInterval time.Duration
+}
+
+// Runner subscribes to a stream and handles its events until shutdown. The
+// stream is subscribed again with backoff whenever it ends.
+type Runner[T any] struct {
+	config Config[T]
+}
+
+func NewRunner[T any](config Config[T]) *Runner[T] {
+	if config.BufferSize == 0 {
+		config.BufferSize = int(dbUtil.GetEnvInt32("SUBSCRIPTION_BUFFER_SIZE", 16))
+	}
+
+	if config.MinBackoff == 0 {
+		config.MinBackoff = time.Second
+	}
+
+	if config.MaxBackoff == 0 {
+		config.MaxBackoff = time.Minute
+	}
+
+	if config.LeaseInterval == 0 {
+		config.LeaseInterval = time.Duration(dbUtil.GetEnvInt32("LEASE_TAKEOVER_INTERVAL", 60)) * time.Second
+	}
+
+	return &Runner[T]{
+		config: config,
+	}
+}
+
+// Start receives and handles events in separate goroutines. Handling stops
+// on shutdown. Receiving stops on shutdown once the blocked Recv returns,
+// which happens when the LND connection is closed. The event channel is
+// never closed so a late receive cannot send on a closed channel.
+func (r *Runner[T]) Start(shutdownCtx context.Context, waitGroup *sync.WaitGroup) {
+	if r.config.LeaseService != nil {
+		waitGroup.Add(1)
+		go r.runLeased(shutdownCtx, waitGroup)
+		return
+	}
+
+	r.run(shutdownCtx, waitGroup)
+}
+
+func (r *Runner[T]) run(ctx context.Context, waitGroup *sync.WaitGroup) {
+	eventChan := make(chan *T, r.config.BufferSize)
+	waitGroup.Add(1)
+
+	go r.handleEvents(ctx, eventChan, waitGroup)
+	go r.receiveEvents(ctx, eventChan)
+}
+
+// runLeased runs the stream while the lease is held, until the lease is lost
+// or shut down, then stands by until the lease can be acquired again
+func (r *Runner[T]) runLeased(shutdownCtx context.Context, waitGroup *sync.WaitGroup) {
+	defer waitGroup.Done()
+
+	for {
+		leaseCtx, cancel := context.WithCancel(shutdownCtx)
+		heldLease, err := r.config.LeaseService.Acquire(context.Background(), r.config.LeaseType, r.config.LeaseID, cancel)
+
+		if err == nil {
+			log.Printf("Running %v stream on %v", r.config.Name, r.config.Pubkey)
+			r.config.Heartbeat.SetStandby(false)
+
+			runWaitGroup := &sync.WaitGroup{}
+			r.run(leaseCtx, runWaitGroup)
+
+			<-leaseCtx.Done()
+			runWaitGroup.Wait()
+			r.config.Heartbeat.SetSubscribed(false)
+			r.config.LeaseService.Release(context.Background(), heldLease)
+		} else {
+			r.config.Heartbeat.SetStandby(true)
+		}
+
+		cancel()
+
+		select {
+		case <-shutdownCtx.Done():
+			return
+		case <-time.After(r.config.LeaseInterval):
+		}
+	}
+}
+
+func (r *Runner[T]) handleEvents(ctx context.Context, eventChan chan *T, waitGroup *sync.WaitGroup) {
+	defer waitGroup.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("Shutting down %v stream on %v", r.config.Name, r.config.Pubkey)
+			return
+		case event := <-eventChan:
+			metricBufferedEvents.WithLabelValues(r.config.Pubkey, r.config.Name).Set(float64(len(eventChan)))
+			r.config.Handle(event)
+		}
+	}
+}
+
+func (r *Runner[T]) receiveEvents(shutdownCtx context.Context, eventChan chan *T) {
+	// The backoff is kept across resubscribes so a stream that ends
+	// straight after subscribing is not resubscribed in a tight loop
+	retryBackoff := util.NewBackoff(r.config.MinBackoff, r.config.MaxBackoff)
+
+	for {
+		stream, ok := r.waitForStream(shutdownCtx, retryBackoff)
+
+		if !ok {
+			return
+		}
+
+		r.config.Heartbeat.SetSubscribed(true)
+
+		for {
+			event, err := stream.Recv()
+
+			if err != nil {
+				r.config.Heartbeat.SetSubscribed(false)
+
+				if shutdownCtx.Err() != nil {
+					return
+				}
+
+				log.Printf("Resubscribing %v stream on %v: %v", r.config.Name, r.config.Pubkey, err)
+				metricReconnectsTotal.WithLabelValues(r.config.Pubkey, r.config.Name).Inc()
+				break
+			}
+
+			if shutdownCtx.Err() != nil {
+				// Events received after shutdown are left to the next subscriber
+				return
+			}
+
+			// The stream is healthy once an event is received
+			retryBackoff.Reset()
+
+			r.config.Heartbeat.Beat()
+			metricEventsTotal.WithLabelValues(r.config.Pubkey, r.config.Name).Inc()
+			metricLastEventTimestampSeconds.WithLabelValues(r.config.Pubkey, r.config.Name).SetToCurrentTime()
+
+			select {
+			case <-shutdownCtx.Done():
+				return
+			case eventChan <- event:
+				metricBufferedEvents.WithLabelValues(r.config.Pubkey, r.config.Name).Set(float64(len(eventChan)))
+			}
+		}
+
+		select {
+		case <-shutdownCtx.Done():
+			return
+		case <-time.After(retryBackoff.Next()):
+		}
+	}
+}
+
+// waitForStream subscribes with backoff until subscribed or shut down
+func (r *Runner[T]) waitForStream(shutdownCtx context.Context, retryBackoff *util.Backoff) (Stream[T], bool) {
+	for {
+		stream, err := r.config.Subscribe()
+
+		if err == nil {
+			return stream, true
+		} else if status.Code(err) != codes.Unavailable {
+			metrics.RecordError("LNM247", "Error subscribing to stream", err)
+			log.Printf("LNM247: Stream=%v, Pubkey=%v", r.config.Name, r.config.Pubkey)
+		}
+
+		log.Printf("Waiting for %v stream on %v", r.config.Name, r.config.Pubkey)
+
+		select {
+		case <-shutdownCtx.Done():
+			return nil, false
+		case <-time.After(retryBackoff.Next()):
+		}
+	}
+}
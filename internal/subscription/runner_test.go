@@ -0,0 +1,192 @@
+package subscription_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/satimoto/go-lnm/internal/health"
+	"github.com/satimoto/go-lnm/internal/lease"
+	leaseMocks "github.com/satimoto/go-lnm/internal/lease/mocks"
+	"github.com/satimoto/go-lnm/internal/subscription"
+)
+
+type event struct {
+	value int
+}
+
+type mockStream struct {
+	recvChan <-chan *event
+}
+
+func (s *mockStream) Recv() (*event, error) {
+	receive, ok := <-s.recvChan
+
+	if !ok {
+		return nil, errors.New("EOF")
+	}
+
+	return receive, nil
+}
+
+func TestRunner(t *testing.T) {
+	t.Run("Resubscribe after stream ends", func(t *testing.T) {
+		shutdownCtx, cancel := context.WithCancel(context.Background())
+		waitGroup := &sync.WaitGroup{}
+
+		firstChan := make(chan *event)
+		secondChan := make(chan *event)
+		streams := []subscription.Stream[event]{&mockStream{firstChan}, &mockStream{secondChan}}
+		handledChan := make(chan int)
+		subscribeCount := 0
+
+		runner := subscription.NewRunner(subscription.Config[event]{
+			Name: "test",
+			Subscribe: func() (subscription.Stream[event], error) {
+				if subscribeCount == 0 {
+					subscribeCount++
+					return nil, errors.New("Unavailable")
+				}
+
+				stream := streams[0]
+				streams = streams[1:]
+
+				return stream, nil
+			},
+			Handle: func(e *event) {
+				handledChan <- e.value
+			},
+			BufferSize: 1,
+			MinBackoff: time.Millisecond,
+			MaxBackoff: time.Millisecond,
+		})
+
+		runner.Start(shutdownCtx, waitGroup)
+
+		firstChan <- &event{value: 1}
+
+		if value := <-handledChan; value != 1 {
+			t.Errorf("Value mismatch: %v expecting %v", value, 1)
+		}
+
+		close(firstChan)
+		secondChan <- &event{value: 2}
+
+		if value := <-handledChan; value != 2 {
+			t.Errorf("Value mismatch: %v expecting %v", value, 2)
+		}
+
+		cancel()
+		waitGroup.Wait()
+	})
+
+	t.Run("Shutdown while waiting to subscribe", func(t *testing.T) {
+		shutdownCtx, cancel := context.WithCancel(context.Background())
+		waitGroup := &sync.WaitGroup{}
+
+		runner := subscription.NewRunner(subscription.Config[event]{
+			Name: "test",
+			Subscribe: func() (subscription.Stream[event], error) {
+				return nil, errors.New("Unavailable")
+			},
+			Handle:     func(e *event) {},
+			MinBackoff: time.Hour,
+			MaxBackoff: time.Hour,
+		})
+
+		runner.Start(shutdownCtx, waitGroup)
+		cancel()
+		waitGroup.Wait()
+	})
+	t.Run("Back off when stream ends without events", func(t *testing.T) {
+		shutdownCtx, cancel := context.WithCancel(context.Background())
+		waitGroup := &sync.WaitGroup{}
+
+		var mutex sync.Mutex
+		subscribeCount := 0
+
+		runner := subscription.NewRunner(subscription.Config[event]{
+			Name: "test",
+			Subscribe: func() (subscription.Stream[event], error) {
+				mutex.Lock()
+				defer mutex.Unlock()
+
+				subscribeCount++
+				recvChan := make(chan *event)
+				close(recvChan)
+
+				return &mockStream{recvChan}, nil
+			},
+			Handle:     func(e *event) {},
+			MinBackoff: time.Hour,
+			MaxBackoff: time.Hour,
+		})
+
+		runner.Start(shutdownCtx, waitGroup)
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+		waitGroup.Wait()
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if subscribeCount != 1 {
+			t.Errorf("Subscribe count mismatch: %v expecting %v", subscribeCount, 1)
+		}
+	})
+	t.Run("Stand by while the lease is held elsewhere", func(t *testing.T) {
+		shutdownCtx, cancel := context.WithCancel(context.Background())
+		waitGroup := &sync.WaitGroup{}
+
+		mockLeaseService := leaseMocks.NewService()
+		mockLeaseService.SetAcquireMockData(lease.ErrLeaseHeld)
+		heartbeat := health.RegisterHeartbeat("test_leased_stream", true, 0)
+		recvChan := make(chan *event)
+		subscribedChan := make(chan bool, 1)
+		handledChan := make(chan int)
+
+		runner := subscription.NewRunner(subscription.Config[event]{
+			Name: "test",
+			Subscribe: func() (subscription.Stream[event], error) {
+				subscribedChan <- true
+				return &mockStream{recvChan}, nil
+			},
+			Handle: func(e *event) {
+				handledChan <- e.value
+			},
+			Heartbeat:     heartbeat,
+			MinBackoff:    time.Millisecond,
+			MaxBackoff:    time.Millisecond,
+			LeaseService:  mockLeaseService,
+			LeaseType:     lease.LEASE_CHANNEL_EVENT,
+			LeaseID:       1,
+			LeaseInterval: 100 * time.Millisecond,
+		})
+
+		runner.Start(shutdownCtx, waitGroup)
+		time.Sleep(50 * time.Millisecond)
+
+		if check := heartbeat.Check(true); check.Status != health.STATUS_UP || check.Details["standby"] != true {
+			t.Errorf("Check mismatch: %#v", check)
+		}
+
+		select {
+		case <-subscribedChan:
+			t.Error("Subscribed while standing by")
+		default:
+		}
+
+		<-subscribedChan
+		recvChan <- &event{value: 1}
+
+		if value := <-handledChan; value != 1 {
+			t.Errorf("Value mismatch: %v expecting %v", value, 1)
+		}
+
+		cancel()
+		close(recvChan)
+		waitGroup.Wait()
+	})
+}
@@ -0,0 +1,153 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/satimoto/go-datastore/pkg/db"
+)
+
+type EventDto struct {
+	ID          string      `json:"id"`
+	Type        string      `json:"type"`
+	CreatedDate string      `json:"createdDate"`
+	Data        interface{} `json:"data"`
+}
+
+type SessionDto struct {
+	Uid           string               `json:"uid"`
+	Status        db.SessionStatusType `json:"status"`
+	Currency      string               `json:"currency"`
+	TotalEnergy   float64              `json:"totalEnergy"`
+	TotalCost     *float64             `json:"totalCost,omitempty"`
+	StartDatetime string               `json:"startDatetime"`
+	EndDatetime   *string              `json:"endDatetime,omitempty"`
+}
+
+type SessionInvoiceDto struct {
+	ID             int64       `json:"id"`
+	Session        *SessionDto `json:"session"`
+	Currency       string      `json:"currency"`
+	PriceFiat      float64     `json:"priceFiat"`
+	PriceMsat      int64       `json:"priceMsat"`
+	CommissionFiat float64     `json:"commissionFiat"`
+	CommissionMsat int64       `json:"commissionMsat"`
+	TaxFiat        float64     `json:"taxFiat"`
+	TaxMsat        int64       `json:"taxMsat"`
+	TotalFiat      float64     `json:"totalFiat"`
+	TotalMsat      int64       `json:"totalMsat"`
+	IsSettled      bool        `json:"isSettled"`
+	IsExpired      bool        `json:"isExpired"`
+}
+
+type CdrDto struct {
+	Uid           string      `json:"uid"`
+	Session       *SessionDto `json:"session,omitempty"`
+	Currency      string      `json:"currency"`
+	TotalCost     float64     `json:"totalCost"`
+	TotalEnergy   float64     `json:"totalEnergy"`
+	StartDatetime string      `json:"startDatetime"`
+	EndDatetime   *string     `json:"endDatetime,omitempty"`
+}
+
+type InvoiceRequestDto struct {
+	ID        int64       `json:"id"`
+	Session   *SessionDto `json:"session,omitempty"`
+	Currency  string      `json:"currency"`
+	Memo      string      `json:"memo"`
+	TotalFiat float64     `json:"totalFiat"`
+	TotalMsat int64       `json:"totalMsat"`
+}
+
+func NewEventDto(eventType string, data interface{}) *EventDto {
+	return &EventDto{
+		ID:          newEventID(),
+		Type:        eventType,
+		CreatedDate: time.Now().UTC().Format(time.RFC3339),
+		Data:        data,
+	}
+}
+
+func NewSessionDto(session db.Session) *SessionDto {
+	response := &SessionDto{
+		Uid:           session.Uid,
+		Status:        session.Status,
+		Currency:      session.Currency,
+		TotalEnergy:   session.Kwh,
+		StartDatetime: session.StartDatetime.Format(time.RFC3339),
+	}
+
+	if session.TotalCost.Valid {
+		response.TotalCost = &session.TotalCost.Float64
+	}
+
+	if session.EndDatetime.Valid {
+		endDatetime := session.EndDatetime.Time.Format(time.RFC3339)
+		response.EndDatetime = &endDatetime
+	}
+
+	return response
+}
+
+func NewSessionInvoiceDto(session db.Session, sessionInvoice db.SessionInvoice) *SessionInvoiceDto {
+	return &SessionInvoiceDto{
+		ID:             sessionInvoice.ID,
+		Session:        NewSessionDto(session),
+		Currency:       sessionInvoice.Currency,
+		PriceFiat:      sessionInvoice.PriceFiat,
+		PriceMsat:      sessionInvoice.PriceMsat,
+		CommissionFiat: sessionInvoice.CommissionFiat,
+		CommissionMsat: sessionInvoice.CommissionMsat,
+		TaxFiat:        sessionInvoice.TaxFiat,
+		TaxMsat:        sessionInvoice.TaxMsat,
+		TotalFiat:      sessionInvoice.TotalFiat,
+		TotalMsat:      sessionInvoice.TotalMsat,
+		IsSettled:      sessionInvoice.IsSettled,
+		IsExpired:      sessionInvoice.IsExpired,
+	}
+}
+
+func NewCdrDto(cdr db.Cdr, session *db.Session) *CdrDto {
+	response := &CdrDto{
+		Uid:           cdr.Uid,
+		Currency:      cdr.Currency,
+		TotalCost:     cdr.TotalCost,
+		TotalEnergy:   cdr.TotalEnergy,
+		StartDatetime: cdr.StartDateTime.Format(time.RFC3339),
+	}
+
+	if session != nil {
+		response.Session = NewSessionDto(*session)
+	}
+
+	if cdr.StopDateTime.Valid {
+		endDatetime := cdr.StopDateTime.Time.Format(time.RFC3339)
+		response.EndDatetime = &endDatetime
+	}
+
+	return response
+}
+
+func NewInvoiceRequestDto(invoiceRequest db.InvoiceRequest, session *db.Session) *InvoiceRequestDto {
+	response := &InvoiceRequestDto{
+		ID:        invoiceRequest.ID,
+		Currency:  invoiceRequest.Currency,
+		Memo:      invoiceRequest.Memo,
+		TotalFiat: invoiceRequest.TotalFiat,
+		TotalMsat: invoiceRequest.TotalMsat,
+	}
+
+	if session != nil {
+		response.Session = NewSessionDto(*session)
+	}
+
+	return response
+}
+
+func newEventID() string {
+	bytes := make([]byte, 16)
+	rand.Read(bytes)
+
+	return "evt_" + hex.EncodeToString(bytes)
+}
@@ -0,0 +1,25 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricWebhookDeliveriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "lsp_webhook_deliveries_total",
+		Help: "The total number of webhook delivery attempts",
+	}, []string{"event", "status"})
+	metricWebhookDeliverySeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "lsp_webhook_delivery_seconds",
+		Help:    "The duration of webhook delivery attempts in seconds",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"event"})
+)
+
+func RecordWebhookDelivery(eventType, status string, duration time.Duration) {
+	metricWebhookDeliveriesTotal.WithLabelValues(eventType, status).Inc()
+	metricWebhookDeliverySeconds.WithLabelValues(eventType).Observe(duration.Seconds())
+}
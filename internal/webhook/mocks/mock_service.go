@@ -0,0 +1,42 @@
+package mocks
+
+type MockWebhookRequest struct {
+	Url       string
+	Secret    string
+	EventType string
+	Payload   []byte
+}
+
+type MockWebhookService struct {
+	sendMockData     []error
+	sendRequestsData []MockWebhookRequest
+}
+
+func NewService() *MockWebhookService {
+	return &MockWebhookService{}
+}
+
+func (s *MockWebhookService) Send(url, secret, eventType string, payload []byte) error {
+	s.sendRequestsData = append(s.sendRequestsData, MockWebhookRequest{
+		Url:       url,
+		Secret:    secret,
+		EventType: eventType,
+		Payload:   payload,
+	})
+
+	if len(s.sendMockData) == 0 {
+		return nil
+	}
+
+	err := s.sendMockData[0]
+	s.sendMockData = s.sendMockData[1:]
+	return err
+}
+
+func (s *MockWebhookService) GetSendRequests() []MockWebhookRequest {
+	return s.sendRequestsData
+}
+
+func (s *MockWebhookService) SetSendMockData(err error) {
+	s.sendMockData = append(s.sendMockData, err)
+}
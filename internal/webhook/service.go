@@ -0,0 +1,80 @@
+package webhook
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/satimoto/go-datastore/pkg/util"
+)
+
+var (
+	ErrDeliveryFailed = errors.New("webhook delivery failed")
+	ErrRejected       = errors.New("webhook rejected by endpoint")
+)
+
+type Webhook interface {
+	Send(url, secret, eventType string, payload []byte) error
+}
+
+type WebhookService struct {
+	httpClient *http.Client
+}
+
+func NewService() Webhook {
+	timeout := time.Duration(util.GetEnvInt32("WEBHOOK_TIMEOUT", 10)) * time.Second
+
+	return NewServiceWithClient(NewHttpClient(timeout))
+}
+
+func NewServiceWithClient(httpClient *http.Client) Webhook {
+	return &WebhookService{
+		httpClient: httpClient,
+	}
+}
+
+// Send posts a signed payload to the endpoint. Errors wrapping ErrRejected
+// are permanent and should not be retried.
+func (s *WebhookService) Send(url, secret, eventType string, payload []byte) error {
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrRejected, err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "Satimoto-Webhook/1.0")
+	request.Header.Set(HEADER_EVENT, eventType)
+	request.Header.Set(HEADER_SIGNATURE, SignPayload(secret, time.Now(), payload))
+
+	start := time.Now()
+	response, err := s.httpClient.Do(request)
+
+	if err != nil {
+		RecordWebhookDelivery(eventType, "error", time.Since(start))
+
+		if errors.Is(err, ErrForbiddenAddress) {
+			return fmt.Errorf("%w: %v", ErrRejected, err)
+		}
+
+		return fmt.Errorf("%w: %v", ErrDeliveryFailed, err)
+	}
+
+	defer response.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(response.Body, 4096))
+
+	RecordWebhookDelivery(eventType, fmt.Sprintf("%dxx", response.StatusCode/100), time.Since(start))
+
+	switch {
+	case response.StatusCode >= 200 && response.StatusCode < 300:
+		return nil
+	case response.StatusCode == http.StatusRequestTimeout || response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500:
+		return fmt.Errorf("%w: status %d", ErrDeliveryFailed, response.StatusCode)
+	}
+
+	return fmt.Errorf("%w: status %d", ErrRejected, response.StatusCode)
+}
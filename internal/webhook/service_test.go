@@ -0,0 +1,154 @@
+package webhook_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/satimoto/go-lnm/internal/webhook"
+)
+
+func TestSignature(t *testing.T) {
+	payload := []byte(`{"id":"evt_1","type":"session.started"}`)
+	header := webhook.SignPayload("secret", time.Now(), payload)
+
+	if err := webhook.VerifySignature("secret", header, payload, time.Minute); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if err := webhook.VerifySignature("other", header, payload, time.Minute); err != webhook.ErrInvalidSignature {
+		t.Errorf("Error mismatch: %v expecting %v", err, webhook.ErrInvalidSignature)
+	}
+
+	if err := webhook.VerifySignature("secret", header, []byte(`{}`), time.Minute); err != webhook.ErrInvalidSignature {
+		t.Errorf("Error mismatch: %v expecting %v", err, webhook.ErrInvalidSignature)
+	}
+
+	expiredHeader := webhook.SignPayload("secret", time.Now().Add(-time.Hour), payload)
+
+	if err := webhook.VerifySignature("secret", expiredHeader, payload, time.Minute); err != webhook.ErrSignatureExpired {
+		t.Errorf("Error mismatch: %v expecting %v", err, webhook.ErrSignatureExpired)
+	}
+}
+
+func TestSend(t *testing.T) {
+	cases := []struct {
+		desc       string
+		statusCode int
+		err        error
+	}{{
+		desc:       "Delivered",
+		statusCode: http.StatusNoContent,
+	}, {
+		desc:       "Server error is retryable",
+		statusCode: http.StatusBadGateway,
+		err:        webhook.ErrDeliveryFailed,
+	}, {
+		desc:       "Rate limited is retryable",
+		statusCode: http.StatusTooManyRequests,
+		err:        webhook.ErrDeliveryFailed,
+	}, {
+		desc:       "Client error is rejected",
+		statusCode: http.StatusGone,
+		err:        webhook.ErrRejected,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			payload := []byte(`{"id":"evt_1"}`)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := ioutil.ReadAll(r.Body)
+
+				if err := webhook.VerifySignature("secret", r.Header.Get(webhook.HEADER_SIGNATURE), body, time.Minute); err != nil {
+					t.Errorf("Unexpected signature error: %v", err)
+				}
+
+				if r.Header.Get(webhook.HEADER_EVENT) != webhook.SESSION_STARTED {
+					t.Errorf("Event mismatch: %v expecting %v", r.Header.Get(webhook.HEADER_EVENT), webhook.SESSION_STARTED)
+				}
+
+				w.WriteHeader(tc.statusCode)
+			}))
+			defer server.Close()
+
+			webhookService := webhook.NewServiceWithClient(server.Client())
+			err := webhookService.Send(server.URL, "secret", webhook.SESSION_STARTED, payload)
+
+			if !errors.Is(err, tc.err) {
+				t.Errorf("Error mismatch: %v expecting %v", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestSendForbiddenAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpected request to loopback address")
+	}))
+	defer server.Close()
+
+	webhookService := webhook.NewService()
+	err := webhookService.Send(server.URL, "secret", webhook.SESSION_STARTED, []byte(`{"id":"evt_1"}`))
+
+	if !errors.Is(err, webhook.ErrRejected) || !strings.Contains(err.Error(), webhook.ErrForbiddenAddress.Error()) {
+		t.Errorf("Error mismatch: %v expecting %v", err, webhook.ErrForbiddenAddress)
+	}
+}
+
+func TestSendRedirect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			t.Errorf("Unexpected redirected request: %v", r.URL.Path)
+		}
+
+		http.Redirect(w, r, "/internal", http.StatusFound)
+	}))
+	defer server.Close()
+
+	// Keep the redirect policy but allow the loopback test server
+	httpClient := webhook.NewHttpClient(time.Second)
+	httpClient.Transport = server.Client().Transport
+
+	webhookService := webhook.NewServiceWithClient(httpClient)
+	err := webhookService.Send(server.URL, "secret", webhook.SESSION_STARTED, []byte(`{"id":"evt_1"}`))
+
+	if !errors.Is(err, webhook.ErrRejected) {
+		t.Errorf("Error mismatch: %v expecting %v", err, webhook.ErrRejected)
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	cases := []struct {
+		ip       string
+		isPublic bool
+	}{
+		{ip: "8.8.8.8", isPublic: true},
+		{ip: "2001:4860:4860::8888", isPublic: true},
+		{ip: "127.0.0.1"},
+		{ip: "10.1.2.3"},
+		{ip: "172.16.0.1"},
+		{ip: "192.168.1.1"},
+		{ip: "169.254.169.254"},
+		{ip: "100.64.0.1"},
+		{ip: "0.0.0.0"},
+		{ip: "::1"},
+		{ip: "fd00::1"},
+		{ip: "fe80::1"},
+		{ip: "::ffff:127.0.0.1"},
+		{ip: "64:ff9b::a9fe:a9fe"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.ip, func(t *testing.T) {
+			if isPublic := webhook.IsPublicIP(net.ParseIP(tc.ip)); isPublic != tc.isPublic {
+				t.Errorf("Public mismatch: %v expecting %v", isPublic, tc.isPublic)
+			}
+		})
+	}
+}
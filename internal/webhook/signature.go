@@ -0,0 +1,80 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrSignatureExpired = errors.New("webhook signature timestamp outside tolerance")
+)
+
+// SignPayload returns the signature header value for a payload in the
+// form "t=<unix timestamp>,v1=<hex hmac-sha256 of timestamp.payload>"
+func SignPayload(secret string, timestamp time.Time, payload []byte) string {
+	unix := timestamp.Unix()
+
+	return fmt.Sprintf("t=%d,v1=%s", unix, computeSignature(secret, unix, payload))
+}
+
+// VerifySignature checks a signature header value against the payload,
+// rejecting timestamps older than the tolerance to prevent replays
+func VerifySignature(secret, header string, payload []byte, tolerance time.Duration) error {
+	var unix int64
+	signatures := []string{}
+
+	for _, part := range strings.Split(header, ",") {
+		keyValue := strings.SplitN(strings.TrimSpace(part), "=", 2)
+
+		if len(keyValue) != 2 {
+			continue
+		}
+
+		switch keyValue[0] {
+		case "t":
+			value, err := strconv.ParseInt(keyValue[1], 10, 64)
+
+			if err != nil {
+				return ErrInvalidSignature
+			}
+
+			unix = value
+		case "v1":
+			signatures = append(signatures, keyValue[1])
+		}
+	}
+
+	if unix == 0 || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+
+	if tolerance > 0 && time.Since(time.Unix(unix, 0)) > tolerance {
+		return ErrSignatureExpired
+	}
+
+	expected := computeSignature(secret, unix, payload)
+
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			return nil
+		}
+	}
+
+	return ErrInvalidSignature
+}
+
+func computeSignature(secret string, unix int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(unix, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
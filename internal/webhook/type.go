@@ -0,0 +1,36 @@
+package webhook
+
+const (
+	CDR_PROCESSED    = "cdr.processed"
+	INVOICE_EXPIRED  = "invoice.expired"
+	INVOICE_SETTLED  = "invoice.settled"
+	REBATE_ISSUED    = "rebate.issued"
+	SESSION_INVOICED = "session.invoiced"
+	SESSION_STARTED  = "session.started"
+	SESSION_UPDATED  = "session.updated"
+)
+
+const (
+	HEADER_EVENT     = "X-Lsp-Event"
+	HEADER_SIGNATURE = "X-Lsp-Signature"
+)
+
+var EventTypes = []string{
+	CDR_PROCESSED,
+	INVOICE_EXPIRED,
+	INVOICE_SETTLED,
+	REBATE_ISSUED,
+	SESSION_INVOICED,
+	SESSION_STARTED,
+	SESSION_UPDATED,
+}
+
+func IsEventType(eventType string) bool {
+	for _, t := range EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+
+	return false
+}
@@ -24,6 +24,34 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type BoolFilter int32
+
+const (
+	BoolFilter_BOOL_FILTER_ANY   BoolFilter = 0
+	BoolFilter_BOOL_FILTER_TRUE  BoolFilter = 1
+	BoolFilter_BOOL_FILTER_FALSE BoolFilter = 2
+)
+
+var BoolFilter_name = map[int32]string{
+	0: "BOOL_FILTER_ANY",
+	1: "BOOL_FILTER_TRUE",
+	2: "BOOL_FILTER_FALSE",
+}
+
+var BoolFilter_value = map[string]int32{
+	"BOOL_FILTER_ANY":   0,
+	"BOOL_FILTER_TRUE":  1,
+	"BOOL_FILTER_FALSE": 2,
+}
+
+func (x BoolFilter) String() string {
+	return proto.EnumName(BoolFilter_name, int32(x))
+}
+
+func (BoolFilter) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_5fef7841c2201b9b, []int{0}
+}
+
 type UpdateInvoiceRequestRequest struct {
 	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	UserId               int64    `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
@@ -260,149 +288,1473 @@ func (m *UpdateSessionInvoiceResponse) GetIsExpired() bool {
 	return false
 }
 
-func init() {
-	proto.RegisterType((*UpdateInvoiceRequestRequest)(nil), "invoice.UpdateInvoiceRequestRequest")
-	proto.RegisterType((*UpdateInvoiceRequestResponse)(nil), "invoice.UpdateInvoiceRequestResponse")
-	proto.RegisterType((*UpdateSessionInvoiceRequest)(nil), "invoice.UpdateSessionInvoiceRequest")
-	proto.RegisterType((*UpdateSessionInvoiceResponse)(nil), "invoice.UpdateSessionInvoiceResponse")
+type ListSessionInvoicesRequest struct {
+	UserId               int64      `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SessionUid           string     `protobuf:"bytes,2,opt,name=session_uid,json=sessionUid,proto3" json:"session_uid,omitempty"`
+	IsSettled            BoolFilter `protobuf:"varint,3,opt,name=is_settled,json=isSettled,proto3,enum=invoice.BoolFilter" json:"is_settled,omitempty"`
+	IsExpired            BoolFilter `protobuf:"varint,4,opt,name=is_expired,json=isExpired,proto3,enum=invoice.BoolFilter" json:"is_expired,omitempty"`
+	FromDate             string     `protobuf:"bytes,5,opt,name=from_date,json=fromDate,proto3" json:"from_date,omitempty"`
+	ToDate               string     `protobuf:"bytes,6,opt,name=to_date,json=toDate,proto3" json:"to_date,omitempty"`
+	Limit                int32      `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset               int32      `protobuf:"varint,8,opt,name=offset,proto3" json:"offset,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
 }
 
-func init() { proto.RegisterFile("lsprpc/invoice.proto", fileDescriptor_5fef7841c2201b9b) }
+func (m *ListSessionInvoicesRequest) Reset()         { *m = ListSessionInvoicesRequest{} }
+func (m *ListSessionInvoicesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListSessionInvoicesRequest) ProtoMessage()    {}
+func (*ListSessionInvoicesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fef7841c2201b9b, []int{4}
+}
 
-var fileDescriptor_5fef7841c2201b9b = []byte{
-	// 306 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x53, 0xcf, 0x4a, 0xfb, 0x40,
-	0x10, 0x66, 0xdb, 0xdf, 0xaf, 0xb5, 0x7b, 0xa8, 0xb0, 0x14, 0x0c, 0xfe, 0x81, 0x1a, 0x15, 0x73,
-	0x31, 0x01, 0x7d, 0x03, 0x41, 0xa1, 0xd7, 0x04, 0x2f, 0x5e, 0x42, 0x9a, 0x1d, 0xea, 0x40, 0x92,
-	0x5d, 0x77, 0x36, 0x45, 0x9f, 0xc2, 0x37, 0xf1, 0x81, 0x7c, 0x1a, 0xb1, 0xd9, 0x14, 0x6a, 0x83,
-	0x7a, 0x10, 0x3c, 0x85, 0xf9, 0x26, 0x3b, 0xdf, 0x37, 0xdf, 0xcc, 0xf0, 0x49, 0x41, 0xda, 0xe8,
-	0x3c, 0xc2, 0x6a, 0xa9, 0x30, 0x87, 0x50, 0x1b, 0x65, 0x95, 0x18, 0xba, 0xd0, 0x57, 0xfc, 0xe0,
-	0x4e, 0xcb, 0xcc, 0xc2, 0xac, 0x01, 0x62, 0x78, 0xac, 0x81, 0xac, 0xfb, 0x88, 0x31, 0xef, 0xa1,
-	0xf4, 0xd8, 0x94, 0x05, 0xfd, 0xb8, 0x87, 0x52, 0xec, 0xf1, 0x61, 0x4d, 0x60, 0x52, 0x94, 0x5e,
-	0x6f, 0x05, 0x0e, 0x3e, 0xc2, 0x99, 0x14, 0xe7, 0x7c, 0x57, 0x67, 0xcf, 0x25, 0x54, 0x36, 0x35,
-	0xcd, 0x5b, 0xaf, 0x3f, 0x65, 0xc1, 0x28, 0x1e, 0x3b, 0xd8, 0x55, 0xf4, 0x5f, 0x18, 0x3f, 0xec,
-	0x66, 0x24, 0xad, 0x2a, 0x82, 0xdf, 0xa7, 0x14, 0x47, 0x9c, 0x23, 0xa5, 0x04, 0xd6, 0x16, 0x20,
-	0xbd, 0x7f, 0x53, 0x16, 0xec, 0xc4, 0x23, 0xa4, 0xa4, 0x01, 0xfc, 0xdb, 0xd6, 0x82, 0x04, 0x88,
-	0x50, 0x55, 0x9b, 0xba, 0x7e, 0xac, 0xc7, 0x7f, 0x5d, 0x77, 0xf6, 0xb9, 0xd0, 0x1f, 0x75, 0xe6,
-	0xd2, 0xf0, 0xa4, 0xd1, 0x80, 0xf4, 0xfe, 0xb7, 0xe9, 0x9b, 0x06, 0xb8, 0x7c, 0x63, 0x7c, 0xec,
-	0x34, 0x26, 0x60, 0x96, 0x98, 0x83, 0xc8, 0xf9, 0xa4, 0x6b, 0x38, 0xe2, 0x34, 0x6c, 0xf7, 0xe7,
-	0x8b, 0x6d, 0xd9, 0x3f, 0xfb, 0xe6, 0x2f, 0xe7, 0xc3, 0x9a, 0x64, 0xd3, 0xa7, 0x2d, 0x92, 0xce,
-	0x79, 0x6c, 0x91, 0x74, 0x9b, 0x7d, 0x7d, 0x72, 0x7f, 0xbc, 0x40, 0xfb, 0x50, 0xcf, 0xc3, 0x5c,
-	0x95, 0x11, 0x65, 0x16, 0x4b, 0x65, 0x55, 0xb4, 0x50, 0x17, 0x05, 0xe9, 0xa8, 0x39, 0x8a, 0xf9,
-	0x60, 0x75, 0x0d, 0x57, 0xef, 0x01, 0x00, 0x00, 0xff, 0xff, 0xc8, 0x43, 0x36, 0xd6, 0x25, 0x03,
-	0x00, 0x00,
+func (m *ListSessionInvoicesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSessionInvoicesRequest.Unmarshal(m, b)
+}
+func (m *ListSessionInvoicesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSessionInvoicesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListSessionInvoicesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSessionInvoicesRequest.Merge(m, src)
+}
+func (m *ListSessionInvoicesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListSessionInvoicesRequest.Size(m)
+}
+func (m *ListSessionInvoicesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSessionInvoicesRequest.DiscardUnknown(m)
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+var xxx_messageInfo_ListSessionInvoicesRequest proto.InternalMessageInfo
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (m *ListSessionInvoicesRequest) GetUserId() int64 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
 
-// InvoiceServiceClient is the client API for InvoiceService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type InvoiceServiceClient interface {
-	UpdateInvoiceRequest(ctx context.Context, in *UpdateInvoiceRequestRequest, opts ...grpc.CallOption) (*UpdateInvoiceRequestResponse, error)
-	UpdateSessionInvoice(ctx context.Context, in *UpdateSessionInvoiceRequest, opts ...grpc.CallOption) (*UpdateSessionInvoiceResponse, error)
+func (m *ListSessionInvoicesRequest) GetSessionUid() string {
+	if m != nil {
+		return m.SessionUid
+	}
+	return ""
 }
 
-type invoiceServiceClient struct {
-	cc *grpc.ClientConn
+func (m *ListSessionInvoicesRequest) GetIsSettled() BoolFilter {
+	if m != nil {
+		return m.IsSettled
+	}
+	return BoolFilter_BOOL_FILTER_ANY
 }
 
-func NewInvoiceServiceClient(cc *grpc.ClientConn) InvoiceServiceClient {
-	return &invoiceServiceClient{cc}
+func (m *ListSessionInvoicesRequest) GetIsExpired() BoolFilter {
+	if m != nil {
+		return m.IsExpired
+	}
+	return BoolFilter_BOOL_FILTER_ANY
 }
 
-func (c *invoiceServiceClient) UpdateInvoiceRequest(ctx context.Context, in *UpdateInvoiceRequestRequest, opts ...grpc.CallOption) (*UpdateInvoiceRequestResponse, error) {
-	out := new(UpdateInvoiceRequestResponse)
-	err := c.cc.Invoke(ctx, "/invoice.InvoiceService/UpdateInvoiceRequest", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *ListSessionInvoicesRequest) GetFromDate() string {
+	if m != nil {
+		return m.FromDate
 	}
-	return out, nil
+	return ""
 }
 
-func (c *invoiceServiceClient) UpdateSessionInvoice(ctx context.Context, in *UpdateSessionInvoiceRequest, opts ...grpc.CallOption) (*UpdateSessionInvoiceResponse, error) {
-	out := new(UpdateSessionInvoiceResponse)
-	err := c.cc.Invoke(ctx, "/invoice.InvoiceService/UpdateSessionInvoice", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *ListSessionInvoicesRequest) GetToDate() string {
+	if m != nil {
+		return m.ToDate
 	}
-	return out, nil
+	return ""
 }
 
-// InvoiceServiceServer is the server API for InvoiceService service.
-type InvoiceServiceServer interface {
-	UpdateInvoiceRequest(context.Context, *UpdateInvoiceRequestRequest) (*UpdateInvoiceRequestResponse, error)
-	UpdateSessionInvoice(context.Context, *UpdateSessionInvoiceRequest) (*UpdateSessionInvoiceResponse, error)
+func (m *ListSessionInvoicesRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
 }
 
-// UnimplementedInvoiceServiceServer can be embedded to have forward compatible implementations.
-type UnimplementedInvoiceServiceServer struct {
+func (m *ListSessionInvoicesRequest) GetOffset() int32 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
 }
 
-func (*UnimplementedInvoiceServiceServer) UpdateInvoiceRequest(ctx context.Context, req *UpdateInvoiceRequestRequest) (*UpdateInvoiceRequestResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method UpdateInvoiceRequest not implemented")
+type SessionInvoice struct {
+	Id                    int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId                int64    `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SessionId             int64    `protobuf:"varint,3,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Currency              string   `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	CurrencyRate          int64    `protobuf:"varint,5,opt,name=currency_rate,json=currencyRate,proto3" json:"currency_rate,omitempty"`
+	CurrencyRateMsat      int64    `protobuf:"varint,6,opt,name=currency_rate_msat,json=currencyRateMsat,proto3" json:"currency_rate_msat,omitempty"`
+	CurrencyRateSpreadPpm int64    `protobuf:"varint,7,opt,name=currency_rate_spread_ppm,json=currencyRateSpreadPpm,proto3" json:"currency_rate_spread_ppm,omitempty"`
+	PriceFiat             float64  `protobuf:"fixed64,8,opt,name=price_fiat,json=priceFiat,proto3" json:"price_fiat,omitempty"`
+	PriceMsat             int64    `protobuf:"varint,9,opt,name=price_msat,json=priceMsat,proto3" json:"price_msat,omitempty"`
+	CommissionFiat        float64  `protobuf:"fixed64,10,opt,name=commission_fiat,json=commissionFiat,proto3" json:"commission_fiat,omitempty"`
+	CommissionMsat        int64    `protobuf:"varint,11,opt,name=commission_msat,json=commissionMsat,proto3" json:"commission_msat,omitempty"`
+	TaxFiat               float64  `protobuf:"fixed64,12,opt,name=tax_fiat,json=taxFiat,proto3" json:"tax_fiat,omitempty"`
+	TaxMsat               int64    `protobuf:"varint,13,opt,name=tax_msat,json=taxMsat,proto3" json:"tax_msat,omitempty"`
+	TotalFiat             float64  `protobuf:"fixed64,14,opt,name=total_fiat,json=totalFiat,proto3" json:"total_fiat,omitempty"`
+	TotalMsat             int64    `protobuf:"varint,15,opt,name=total_msat,json=totalMsat,proto3" json:"total_msat,omitempty"`
+	EstimatedEnergy       float64  `protobuf:"fixed64,16,opt,name=estimated_energy,json=estimatedEnergy,proto3" json:"estimated_energy,omitempty"`
+	EstimatedTime         float64  `protobuf:"fixed64,17,opt,name=estimated_time,json=estimatedTime,proto3" json:"estimated_time,omitempty"`
+	MeteredEnergy         float64  `protobuf:"fixed64,18,opt,name=metered_energy,json=meteredEnergy,proto3" json:"metered_energy,omitempty"`
+	MeteredTime           float64  `protobuf:"fixed64,19,opt,name=metered_time,json=meteredTime,proto3" json:"metered_time,omitempty"`
+	PaymentRequest        string   `protobuf:"bytes,20,opt,name=payment_request,json=paymentRequest,proto3" json:"payment_request,omitempty"`
+	Signature             string   `protobuf:"bytes,21,opt,name=signature,proto3" json:"signature,omitempty"`
+	IsSettled             bool     `protobuf:"varint,22,opt,name=is_settled,json=isSettled,proto3" json:"is_settled,omitempty"`
+	IsExpired             bool     `protobuf:"varint,23,opt,name=is_expired,json=isExpired,proto3" json:"is_expired,omitempty"`
+	LastUpdated           string   `protobuf:"bytes,24,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+	XXX_NoUnkeyedLiteral  struct{} `json:"-"`
+	XXX_unrecognized      []byte   `json:"-"`
+	XXX_sizecache         int32    `json:"-"`
 }
-func (*UnimplementedInvoiceServiceServer) UpdateSessionInvoice(ctx context.Context, req *UpdateSessionInvoiceRequest) (*UpdateSessionInvoiceResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method UpdateSessionInvoice not implemented")
+
+func (m *SessionInvoice) Reset()         { *m = SessionInvoice{} }
+func (m *SessionInvoice) String() string { return proto.CompactTextString(m) }
+func (*SessionInvoice) ProtoMessage()    {}
+func (*SessionInvoice) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fef7841c2201b9b, []int{5}
 }
 
-func RegisterInvoiceServiceServer(s *grpc.Server, srv InvoiceServiceServer) {
-	s.RegisterService(&_InvoiceService_serviceDesc, srv)
+func (m *SessionInvoice) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SessionInvoice.Unmarshal(m, b)
+}
+func (m *SessionInvoice) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SessionInvoice.Marshal(b, m, deterministic)
+}
+func (m *SessionInvoice) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SessionInvoice.Merge(m, src)
+}
+func (m *SessionInvoice) XXX_Size() int {
+	return xxx_messageInfo_SessionInvoice.Size(m)
+}
+func (m *SessionInvoice) XXX_DiscardUnknown() {
+	xxx_messageInfo_SessionInvoice.DiscardUnknown(m)
 }
 
-func _InvoiceService_UpdateInvoiceRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateInvoiceRequestRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+var xxx_messageInfo_SessionInvoice proto.InternalMessageInfo
+
+func (m *SessionInvoice) GetId() int64 {
+	if m != nil {
+		return m.Id
 	}
-	if interceptor == nil {
-		return srv.(InvoiceServiceServer).UpdateInvoiceRequest(ctx, in)
+	return 0
+}
+
+func (m *SessionInvoice) GetUserId() int64 {
+	if m != nil {
+		return m.UserId
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/invoice.InvoiceService/UpdateInvoiceRequest",
+	return 0
+}
+
+func (m *SessionInvoice) GetSessionId() int64 {
+	if m != nil {
+		return m.SessionId
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(InvoiceServiceServer).UpdateInvoiceRequest(ctx, req.(*UpdateInvoiceRequestRequest))
+	return 0
+}
+
+func (m *SessionInvoice) GetCurrency() string {
+	if m != nil {
+		return m.Currency
 	}
-	return interceptor(ctx, in, info, handler)
+	return ""
 }
 
-func _InvoiceService_UpdateSessionInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateSessionInvoiceRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+func (m *SessionInvoice) GetCurrencyRate() int64 {
+	if m != nil {
+		return m.CurrencyRate
 	}
-	if interceptor == nil {
-		return srv.(InvoiceServiceServer).UpdateSessionInvoice(ctx, in)
+	return 0
+}
+
+func (m *SessionInvoice) GetCurrencyRateMsat() int64 {
+	if m != nil {
+		return m.CurrencyRateMsat
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/invoice.InvoiceService/UpdateSessionInvoice",
+	return 0
+}
+
+func (m *SessionInvoice) GetCurrencyRateSpreadPpm() int64 {
+	if m != nil {
+		return m.CurrencyRateSpreadPpm
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(InvoiceServiceServer).UpdateSessionInvoice(ctx, req.(*UpdateSessionInvoiceRequest))
+	return 0
+}
+
+func (m *SessionInvoice) GetPriceFiat() float64 {
+	if m != nil {
+		return m.PriceFiat
 	}
-	return interceptor(ctx, in, info, handler)
+	return 0
 }
 
-var _InvoiceService_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "invoice.InvoiceService",
-	HandlerType: (*InvoiceServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "UpdateInvoiceRequest",
-			Handler:    _InvoiceService_UpdateInvoiceRequest_Handler,
-		},
-		{
-			MethodName: "UpdateSessionInvoice",
-			Handler:    _InvoiceService_UpdateSessionInvoice_Handler,
+func (m *SessionInvoice) GetPriceMsat() int64 {
+	if m != nil {
+		return m.PriceMsat
+	}
+	return 0
+}
+
+func (m *SessionInvoice) GetCommissionFiat() float64 {
+	if m != nil {
+		return m.CommissionFiat
+	}
+	return 0
+}
+
+func (m *SessionInvoice) GetCommissionMsat() int64 {
+	if m != nil {
+		return m.CommissionMsat
+	}
+	return 0
+}
+
+func (m *SessionInvoice) GetTaxFiat() float64 {
+	if m != nil {
+		return m.TaxFiat
+	}
+	return 0
+}
+
+func (m *SessionInvoice) GetTaxMsat() int64 {
+	if m != nil {
+		return m.TaxMsat
+	}
+	return 0
+}
+
+func (m *SessionInvoice) GetTotalFiat() float64 {
+	if m != nil {
+		return m.TotalFiat
+	}
+	return 0
+}
+
+func (m *SessionInvoice) GetTotalMsat() int64 {
+	if m != nil {
+		return m.TotalMsat
+	}
+	return 0
+}
+
+func (m *SessionInvoice) GetEstimatedEnergy() float64 {
+	if m != nil {
+		return m.EstimatedEnergy
+	}
+	return 0
+}
+
+func (m *SessionInvoice) GetEstimatedTime() float64 {
+	if m != nil {
+		return m.EstimatedTime
+	}
+	return 0
+}
+
+func (m *SessionInvoice) GetMeteredEnergy() float64 {
+	if m != nil {
+		return m.MeteredEnergy
+	}
+	return 0
+}
+
+func (m *SessionInvoice) GetMeteredTime() float64 {
+	if m != nil {
+		return m.MeteredTime
+	}
+	return 0
+}
+
+func (m *SessionInvoice) GetPaymentRequest() string {
+	if m != nil {
+		return m.PaymentRequest
+	}
+	return ""
+}
+
+func (m *SessionInvoice) GetSignature() string {
+	if m != nil {
+		return m.Signature
+	}
+	return ""
+}
+
+func (m *SessionInvoice) GetIsSettled() bool {
+	if m != nil {
+		return m.IsSettled
+	}
+	return false
+}
+
+func (m *SessionInvoice) GetIsExpired() bool {
+	if m != nil {
+		return m.IsExpired
+	}
+	return false
+}
+
+func (m *SessionInvoice) GetLastUpdated() string {
+	if m != nil {
+		return m.LastUpdated
+	}
+	return ""
+}
+
+type ListSessionInvoicesResponse struct {
+	SessionInvoices      []*SessionInvoice `protobuf:"bytes,1,rep,name=session_invoices,json=sessionInvoices,proto3" json:"session_invoices,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *ListSessionInvoicesResponse) Reset()         { *m = ListSessionInvoicesResponse{} }
+func (m *ListSessionInvoicesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListSessionInvoicesResponse) ProtoMessage()    {}
+func (*ListSessionInvoicesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fef7841c2201b9b, []int{6}
+}
+
+func (m *ListSessionInvoicesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSessionInvoicesResponse.Unmarshal(m, b)
+}
+func (m *ListSessionInvoicesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSessionInvoicesResponse.Marshal(b, m, deterministic)
+}
+func (m *ListSessionInvoicesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSessionInvoicesResponse.Merge(m, src)
+}
+func (m *ListSessionInvoicesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListSessionInvoicesResponse.Size(m)
+}
+func (m *ListSessionInvoicesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSessionInvoicesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSessionInvoicesResponse proto.InternalMessageInfo
+
+func (m *ListSessionInvoicesResponse) GetSessionInvoices() []*SessionInvoice {
+	if m != nil {
+		return m.SessionInvoices
+	}
+	return nil
+}
+
+type GetSessionInvoiceRequest struct {
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId               int64    `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetSessionInvoiceRequest) Reset()         { *m = GetSessionInvoiceRequest{} }
+func (m *GetSessionInvoiceRequest) String() string { return proto.CompactTextString(m) }
+func (*GetSessionInvoiceRequest) ProtoMessage()    {}
+func (*GetSessionInvoiceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fef7841c2201b9b, []int{7}
+}
+
+func (m *GetSessionInvoiceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetSessionInvoiceRequest.Unmarshal(m, b)
+}
+func (m *GetSessionInvoiceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetSessionInvoiceRequest.Marshal(b, m, deterministic)
+}
+func (m *GetSessionInvoiceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetSessionInvoiceRequest.Merge(m, src)
+}
+func (m *GetSessionInvoiceRequest) XXX_Size() int {
+	return xxx_messageInfo_GetSessionInvoiceRequest.Size(m)
+}
+func (m *GetSessionInvoiceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetSessionInvoiceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetSessionInvoiceRequest proto.InternalMessageInfo
+
+func (m *GetSessionInvoiceRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *GetSessionInvoiceRequest) GetUserId() int64 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+type ListInvoiceRequestsRequest struct {
+	UserId               int64      `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	IsSettled            BoolFilter `protobuf:"varint,2,opt,name=is_settled,json=isSettled,proto3,enum=invoice.BoolFilter" json:"is_settled,omitempty"`
+	FromDate             string     `protobuf:"bytes,3,opt,name=from_date,json=fromDate,proto3" json:"from_date,omitempty"`
+	ToDate               string     `protobuf:"bytes,4,opt,name=to_date,json=toDate,proto3" json:"to_date,omitempty"`
+	Limit                int32      `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset               int32      `protobuf:"varint,6,opt,name=offset,proto3" json:"offset,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *ListInvoiceRequestsRequest) Reset()         { *m = ListInvoiceRequestsRequest{} }
+func (m *ListInvoiceRequestsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListInvoiceRequestsRequest) ProtoMessage()    {}
+func (*ListInvoiceRequestsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fef7841c2201b9b, []int{8}
+}
+
+func (m *ListInvoiceRequestsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListInvoiceRequestsRequest.Unmarshal(m, b)
+}
+func (m *ListInvoiceRequestsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListInvoiceRequestsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListInvoiceRequestsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListInvoiceRequestsRequest.Merge(m, src)
+}
+func (m *ListInvoiceRequestsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListInvoiceRequestsRequest.Size(m)
+}
+func (m *ListInvoiceRequestsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListInvoiceRequestsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListInvoiceRequestsRequest proto.InternalMessageInfo
+
+func (m *ListInvoiceRequestsRequest) GetUserId() int64 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *ListInvoiceRequestsRequest) GetIsSettled() BoolFilter {
+	if m != nil {
+		return m.IsSettled
+	}
+	return BoolFilter_BOOL_FILTER_ANY
+}
+
+func (m *ListInvoiceRequestsRequest) GetFromDate() string {
+	if m != nil {
+		return m.FromDate
+	}
+	return ""
+}
+
+func (m *ListInvoiceRequestsRequest) GetToDate() string {
+	if m != nil {
+		return m.ToDate
+	}
+	return ""
+}
+
+func (m *ListInvoiceRequestsRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *ListInvoiceRequestsRequest) GetOffset() int32 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+type InvoiceRequest struct {
+	Id                    int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId                int64    `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Currency              string   `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	Memo                  string   `protobuf:"bytes,4,opt,name=memo,proto3" json:"memo,omitempty"`
+	PriceFiat             float64  `protobuf:"fixed64,5,opt,name=price_fiat,json=priceFiat,proto3" json:"price_fiat,omitempty"`
+	PriceMsat             int64    `protobuf:"varint,6,opt,name=price_msat,json=priceMsat,proto3" json:"price_msat,omitempty"`
+	CommissionFiat        float64  `protobuf:"fixed64,7,opt,name=commission_fiat,json=commissionFiat,proto3" json:"commission_fiat,omitempty"`
+	CommissionMsat        int64    `protobuf:"varint,8,opt,name=commission_msat,json=commissionMsat,proto3" json:"commission_msat,omitempty"`
+	TaxFiat               float64  `protobuf:"fixed64,9,opt,name=tax_fiat,json=taxFiat,proto3" json:"tax_fiat,omitempty"`
+	TaxMsat               int64    `protobuf:"varint,10,opt,name=tax_msat,json=taxMsat,proto3" json:"tax_msat,omitempty"`
+	TotalFiat             float64  `protobuf:"fixed64,11,opt,name=total_fiat,json=totalFiat,proto3" json:"total_fiat,omitempty"`
+	TotalMsat             int64    `protobuf:"varint,12,opt,name=total_msat,json=totalMsat,proto3" json:"total_msat,omitempty"`
+	PaymentRequest        string   `protobuf:"bytes,13,opt,name=payment_request,json=paymentRequest,proto3" json:"payment_request,omitempty"`
+	IsSettled             bool     `protobuf:"varint,14,opt,name=is_settled,json=isSettled,proto3" json:"is_settled,omitempty"`
+	CurrencyRate          int64    `protobuf:"varint,15,opt,name=currency_rate,json=currencyRate,proto3" json:"currency_rate,omitempty"`
+	CurrencyRateMsat      int64    `protobuf:"varint,16,opt,name=currency_rate_msat,json=currencyRateMsat,proto3" json:"currency_rate_msat,omitempty"`
+	CurrencyRateSpreadPpm int64    `protobuf:"varint,17,opt,name=currency_rate_spread_ppm,json=currencyRateSpreadPpm,proto3" json:"currency_rate_spread_ppm,omitempty"`
+	XXX_NoUnkeyedLiteral  struct{} `json:"-"`
+	XXX_unrecognized      []byte   `json:"-"`
+	XXX_sizecache         int32    `json:"-"`
+}
+
+func (m *InvoiceRequest) Reset()         { *m = InvoiceRequest{} }
+func (m *InvoiceRequest) String() string { return proto.CompactTextString(m) }
+func (*InvoiceRequest) ProtoMessage()    {}
+func (*InvoiceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fef7841c2201b9b, []int{9}
+}
+
+func (m *InvoiceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InvoiceRequest.Unmarshal(m, b)
+}
+func (m *InvoiceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InvoiceRequest.Marshal(b, m, deterministic)
+}
+func (m *InvoiceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InvoiceRequest.Merge(m, src)
+}
+func (m *InvoiceRequest) XXX_Size() int {
+	return xxx_messageInfo_InvoiceRequest.Size(m)
+}
+func (m *InvoiceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_InvoiceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InvoiceRequest proto.InternalMessageInfo
+
+func (m *InvoiceRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *InvoiceRequest) GetUserId() int64 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *InvoiceRequest) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+func (m *InvoiceRequest) GetMemo() string {
+	if m != nil {
+		return m.Memo
+	}
+	return ""
+}
+
+func (m *InvoiceRequest) GetPriceFiat() float64 {
+	if m != nil {
+		return m.PriceFiat
+	}
+	return 0
+}
+
+func (m *InvoiceRequest) GetPriceMsat() int64 {
+	if m != nil {
+		return m.PriceMsat
+	}
+	return 0
+}
+
+func (m *InvoiceRequest) GetCommissionFiat() float64 {
+	if m != nil {
+		return m.CommissionFiat
+	}
+	return 0
+}
+
+func (m *InvoiceRequest) GetCommissionMsat() int64 {
+	if m != nil {
+		return m.CommissionMsat
+	}
+	return 0
+}
+
+func (m *InvoiceRequest) GetTaxFiat() float64 {
+	if m != nil {
+		return m.TaxFiat
+	}
+	return 0
+}
+
+func (m *InvoiceRequest) GetTaxMsat() int64 {
+	if m != nil {
+		return m.TaxMsat
+	}
+	return 0
+}
+
+func (m *InvoiceRequest) GetTotalFiat() float64 {
+	if m != nil {
+		return m.TotalFiat
+	}
+	return 0
+}
+
+func (m *InvoiceRequest) GetTotalMsat() int64 {
+	if m != nil {
+		return m.TotalMsat
+	}
+	return 0
+}
+
+func (m *InvoiceRequest) GetPaymentRequest() string {
+	if m != nil {
+		return m.PaymentRequest
+	}
+	return ""
+}
+
+func (m *InvoiceRequest) GetIsSettled() bool {
+	if m != nil {
+		return m.IsSettled
+	}
+	return false
+}
+
+func (m *InvoiceRequest) GetCurrencyRate() int64 {
+	if m != nil {
+		return m.CurrencyRate
+	}
+	return 0
+}
+
+func (m *InvoiceRequest) GetCurrencyRateMsat() int64 {
+	if m != nil {
+		return m.CurrencyRateMsat
+	}
+	return 0
+}
+
+func (m *InvoiceRequest) GetCurrencyRateSpreadPpm() int64 {
+	if m != nil {
+		return m.CurrencyRateSpreadPpm
+	}
+	return 0
+}
+
+type ListInvoiceRequestsResponse struct {
+	InvoiceRequests      []*InvoiceRequest `protobuf:"bytes,1,rep,name=invoice_requests,json=invoiceRequests,proto3" json:"invoice_requests,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *ListInvoiceRequestsResponse) Reset()         { *m = ListInvoiceRequestsResponse{} }
+func (m *ListInvoiceRequestsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListInvoiceRequestsResponse) ProtoMessage()    {}
+func (*ListInvoiceRequestsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fef7841c2201b9b, []int{10}
+}
+
+func (m *ListInvoiceRequestsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListInvoiceRequestsResponse.Unmarshal(m, b)
+}
+func (m *ListInvoiceRequestsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListInvoiceRequestsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListInvoiceRequestsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListInvoiceRequestsResponse.Merge(m, src)
+}
+func (m *ListInvoiceRequestsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListInvoiceRequestsResponse.Size(m)
+}
+func (m *ListInvoiceRequestsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListInvoiceRequestsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListInvoiceRequestsResponse proto.InternalMessageInfo
+
+func (m *ListInvoiceRequestsResponse) GetInvoiceRequests() []*InvoiceRequest {
+	if m != nil {
+		return m.InvoiceRequests
+	}
+	return nil
+}
+
+type GetSessionBillingSummaryRequest struct {
+	UserId               int64    `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SessionUid           string   `protobuf:"bytes,2,opt,name=session_uid,json=sessionUid,proto3" json:"session_uid,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetSessionBillingSummaryRequest) Reset()         { *m = GetSessionBillingSummaryRequest{} }
+func (m *GetSessionBillingSummaryRequest) String() string { return proto.CompactTextString(m) }
+func (*GetSessionBillingSummaryRequest) ProtoMessage()    {}
+func (*GetSessionBillingSummaryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fef7841c2201b9b, []int{11}
+}
+
+func (m *GetSessionBillingSummaryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetSessionBillingSummaryRequest.Unmarshal(m, b)
+}
+func (m *GetSessionBillingSummaryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetSessionBillingSummaryRequest.Marshal(b, m, deterministic)
+}
+func (m *GetSessionBillingSummaryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetSessionBillingSummaryRequest.Merge(m, src)
+}
+func (m *GetSessionBillingSummaryRequest) XXX_Size() int {
+	return xxx_messageInfo_GetSessionBillingSummaryRequest.Size(m)
+}
+func (m *GetSessionBillingSummaryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetSessionBillingSummaryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetSessionBillingSummaryRequest proto.InternalMessageInfo
+
+func (m *GetSessionBillingSummaryRequest) GetUserId() int64 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *GetSessionBillingSummaryRequest) GetSessionUid() string {
+	if m != nil {
+		return m.SessionUid
+	}
+	return ""
+}
+
+type SessionBillingSummary struct {
+	SessionUid           string   `protobuf:"bytes,1,opt,name=session_uid,json=sessionUid,proto3" json:"session_uid,omitempty"`
+	Status               string   `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Currency             string   `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	InvoiceCount         int32    `protobuf:"varint,4,opt,name=invoice_count,json=invoiceCount,proto3" json:"invoice_count,omitempty"`
+	SettledCount         int32    `protobuf:"varint,5,opt,name=settled_count,json=settledCount,proto3" json:"settled_count,omitempty"`
+	PriceFiat            float64  `protobuf:"fixed64,6,opt,name=price_fiat,json=priceFiat,proto3" json:"price_fiat,omitempty"`
+	PriceMsat            int64    `protobuf:"varint,7,opt,name=price_msat,json=priceMsat,proto3" json:"price_msat,omitempty"`
+	CommissionFiat       float64  `protobuf:"fixed64,8,opt,name=commission_fiat,json=commissionFiat,proto3" json:"commission_fiat,omitempty"`
+	CommissionMsat       int64    `protobuf:"varint,9,opt,name=commission_msat,json=commissionMsat,proto3" json:"commission_msat,omitempty"`
+	TaxFiat              float64  `protobuf:"fixed64,10,opt,name=tax_fiat,json=taxFiat,proto3" json:"tax_fiat,omitempty"`
+	TaxMsat              int64    `protobuf:"varint,11,opt,name=tax_msat,json=taxMsat,proto3" json:"tax_msat,omitempty"`
+	TotalFiat            float64  `protobuf:"fixed64,12,opt,name=total_fiat,json=totalFiat,proto3" json:"total_fiat,omitempty"`
+	TotalMsat            int64    `protobuf:"varint,13,opt,name=total_msat,json=totalMsat,proto3" json:"total_msat,omitempty"`
+	SettledFiat          float64  `protobuf:"fixed64,14,opt,name=settled_fiat,json=settledFiat,proto3" json:"settled_fiat,omitempty"`
+	SettledMsat          int64    `protobuf:"varint,15,opt,name=settled_msat,json=settledMsat,proto3" json:"settled_msat,omitempty"`
+	UnsettledFiat        float64  `protobuf:"fixed64,16,opt,name=unsettled_fiat,json=unsettledFiat,proto3" json:"unsettled_fiat,omitempty"`
+	UnsettledMsat        int64    `protobuf:"varint,17,opt,name=unsettled_msat,json=unsettledMsat,proto3" json:"unsettled_msat,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SessionBillingSummary) Reset()         { *m = SessionBillingSummary{} }
+func (m *SessionBillingSummary) String() string { return proto.CompactTextString(m) }
+func (*SessionBillingSummary) ProtoMessage()    {}
+func (*SessionBillingSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fef7841c2201b9b, []int{12}
+}
+
+func (m *SessionBillingSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SessionBillingSummary.Unmarshal(m, b)
+}
+func (m *SessionBillingSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SessionBillingSummary.Marshal(b, m, deterministic)
+}
+func (m *SessionBillingSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SessionBillingSummary.Merge(m, src)
+}
+func (m *SessionBillingSummary) XXX_Size() int {
+	return xxx_messageInfo_SessionBillingSummary.Size(m)
+}
+func (m *SessionBillingSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_SessionBillingSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SessionBillingSummary proto.InternalMessageInfo
+
+func (m *SessionBillingSummary) GetSessionUid() string {
+	if m != nil {
+		return m.SessionUid
+	}
+	return ""
+}
+
+func (m *SessionBillingSummary) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *SessionBillingSummary) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+func (m *SessionBillingSummary) GetInvoiceCount() int32 {
+	if m != nil {
+		return m.InvoiceCount
+	}
+	return 0
+}
+
+func (m *SessionBillingSummary) GetSettledCount() int32 {
+	if m != nil {
+		return m.SettledCount
+	}
+	return 0
+}
+
+func (m *SessionBillingSummary) GetPriceFiat() float64 {
+	if m != nil {
+		return m.PriceFiat
+	}
+	return 0
+}
+
+func (m *SessionBillingSummary) GetPriceMsat() int64 {
+	if m != nil {
+		return m.PriceMsat
+	}
+	return 0
+}
+
+func (m *SessionBillingSummary) GetCommissionFiat() float64 {
+	if m != nil {
+		return m.CommissionFiat
+	}
+	return 0
+}
+
+func (m *SessionBillingSummary) GetCommissionMsat() int64 {
+	if m != nil {
+		return m.CommissionMsat
+	}
+	return 0
+}
+
+func (m *SessionBillingSummary) GetTaxFiat() float64 {
+	if m != nil {
+		return m.TaxFiat
+	}
+	return 0
+}
+
+func (m *SessionBillingSummary) GetTaxMsat() int64 {
+	if m != nil {
+		return m.TaxMsat
+	}
+	return 0
+}
+
+func (m *SessionBillingSummary) GetTotalFiat() float64 {
+	if m != nil {
+		return m.TotalFiat
+	}
+	return 0
+}
+
+func (m *SessionBillingSummary) GetTotalMsat() int64 {
+	if m != nil {
+		return m.TotalMsat
+	}
+	return 0
+}
+
+func (m *SessionBillingSummary) GetSettledFiat() float64 {
+	if m != nil {
+		return m.SettledFiat
+	}
+	return 0
+}
+
+func (m *SessionBillingSummary) GetSettledMsat() int64 {
+	if m != nil {
+		return m.SettledMsat
+	}
+	return 0
+}
+
+func (m *SessionBillingSummary) GetUnsettledFiat() float64 {
+	if m != nil {
+		return m.UnsettledFiat
+	}
+	return 0
+}
+
+func (m *SessionBillingSummary) GetUnsettledMsat() int64 {
+	if m != nil {
+		return m.UnsettledMsat
+	}
+	return 0
+}
+
+type GetInvoiceRequestPaymentStatusRequest struct {
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId               int64    `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetInvoiceRequestPaymentStatusRequest) Reset()         { *m = GetInvoiceRequestPaymentStatusRequest{} }
+func (m *GetInvoiceRequestPaymentStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*GetInvoiceRequestPaymentStatusRequest) ProtoMessage()    {}
+func (*GetInvoiceRequestPaymentStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fef7841c2201b9b, []int{13}
+}
+
+func (m *GetInvoiceRequestPaymentStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetInvoiceRequestPaymentStatusRequest.Unmarshal(m, b)
+}
+func (m *GetInvoiceRequestPaymentStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetInvoiceRequestPaymentStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *GetInvoiceRequestPaymentStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetInvoiceRequestPaymentStatusRequest.Merge(m, src)
+}
+func (m *GetInvoiceRequestPaymentStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_GetInvoiceRequestPaymentStatusRequest.Size(m)
+}
+func (m *GetInvoiceRequestPaymentStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetInvoiceRequestPaymentStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetInvoiceRequestPaymentStatusRequest proto.InternalMessageInfo
+
+func (m *GetInvoiceRequestPaymentStatusRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *GetInvoiceRequestPaymentStatusRequest) GetUserId() int64 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+type InvoiceRequestPaymentStatus struct {
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId               int64    `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	PaymentRequest       string   `protobuf:"bytes,3,opt,name=payment_request,json=paymentRequest,proto3" json:"payment_request,omitempty"`
+	PaymentHash          string   `protobuf:"bytes,4,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"`
+	Status               string   `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Attempts             int32    `protobuf:"varint,6,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	FeeMsat              int64    `protobuf:"varint,7,opt,name=fee_msat,json=feeMsat,proto3" json:"fee_msat,omitempty"`
+	FailureReasons       []string `protobuf:"bytes,8,rep,name=failure_reasons,json=failureReasons,proto3" json:"failure_reasons,omitempty"`
+	IsFinal              bool     `protobuf:"varint,9,opt,name=is_final,json=isFinal,proto3" json:"is_final,omitempty"`
+	NextAttempt          string   `protobuf:"bytes,10,opt,name=next_attempt,json=nextAttempt,proto3" json:"next_attempt,omitempty"`
+	LastUpdated          string   `protobuf:"bytes,11,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InvoiceRequestPaymentStatus) Reset()         { *m = InvoiceRequestPaymentStatus{} }
+func (m *InvoiceRequestPaymentStatus) String() string { return proto.CompactTextString(m) }
+func (*InvoiceRequestPaymentStatus) ProtoMessage()    {}
+func (*InvoiceRequestPaymentStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5fef7841c2201b9b, []int{14}
+}
+
+func (m *InvoiceRequestPaymentStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InvoiceRequestPaymentStatus.Unmarshal(m, b)
+}
+func (m *InvoiceRequestPaymentStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InvoiceRequestPaymentStatus.Marshal(b, m, deterministic)
+}
+func (m *InvoiceRequestPaymentStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InvoiceRequestPaymentStatus.Merge(m, src)
+}
+func (m *InvoiceRequestPaymentStatus) XXX_Size() int {
+	return xxx_messageInfo_InvoiceRequestPaymentStatus.Size(m)
+}
+func (m *InvoiceRequestPaymentStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_InvoiceRequestPaymentStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InvoiceRequestPaymentStatus proto.InternalMessageInfo
+
+func (m *InvoiceRequestPaymentStatus) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *InvoiceRequestPaymentStatus) GetUserId() int64 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *InvoiceRequestPaymentStatus) GetPaymentRequest() string {
+	if m != nil {
+		return m.PaymentRequest
+	}
+	return ""
+}
+
+func (m *InvoiceRequestPaymentStatus) GetPaymentHash() string {
+	if m != nil {
+		return m.PaymentHash
+	}
+	return ""
+}
+
+func (m *InvoiceRequestPaymentStatus) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *InvoiceRequestPaymentStatus) GetAttempts() int32 {
+	if m != nil {
+		return m.Attempts
+	}
+	return 0
+}
+
+func (m *InvoiceRequestPaymentStatus) GetFeeMsat() int64 {
+	if m != nil {
+		return m.FeeMsat
+	}
+	return 0
+}
+
+func (m *InvoiceRequestPaymentStatus) GetFailureReasons() []string {
+	if m != nil {
+		return m.FailureReasons
+	}
+	return nil
+}
+
+func (m *InvoiceRequestPaymentStatus) GetIsFinal() bool {
+	if m != nil {
+		return m.IsFinal
+	}
+	return false
+}
+
+func (m *InvoiceRequestPaymentStatus) GetNextAttempt() string {
+	if m != nil {
+		return m.NextAttempt
+	}
+	return ""
+}
+
+func (m *InvoiceRequestPaymentStatus) GetLastUpdated() string {
+	if m != nil {
+		return m.LastUpdated
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterEnum("invoice.BoolFilter", BoolFilter_name, BoolFilter_value)
+	proto.RegisterType((*UpdateInvoiceRequestRequest)(nil), "invoice.UpdateInvoiceRequestRequest")
+	proto.RegisterType((*UpdateInvoiceRequestResponse)(nil), "invoice.UpdateInvoiceRequestResponse")
+	proto.RegisterType((*UpdateSessionInvoiceRequest)(nil), "invoice.UpdateSessionInvoiceRequest")
+	proto.RegisterType((*UpdateSessionInvoiceResponse)(nil), "invoice.UpdateSessionInvoiceResponse")
+	proto.RegisterType((*ListSessionInvoicesRequest)(nil), "invoice.ListSessionInvoicesRequest")
+	proto.RegisterType((*SessionInvoice)(nil), "invoice.SessionInvoice")
+	proto.RegisterType((*ListSessionInvoicesResponse)(nil), "invoice.ListSessionInvoicesResponse")
+	proto.RegisterType((*GetSessionInvoiceRequest)(nil), "invoice.GetSessionInvoiceRequest")
+	proto.RegisterType((*ListInvoiceRequestsRequest)(nil), "invoice.ListInvoiceRequestsRequest")
+	proto.RegisterType((*InvoiceRequest)(nil), "invoice.InvoiceRequest")
+	proto.RegisterType((*ListInvoiceRequestsResponse)(nil), "invoice.ListInvoiceRequestsResponse")
+	proto.RegisterType((*GetSessionBillingSummaryRequest)(nil), "invoice.GetSessionBillingSummaryRequest")
+	proto.RegisterType((*SessionBillingSummary)(nil), "invoice.SessionBillingSummary")
+	proto.RegisterType((*GetInvoiceRequestPaymentStatusRequest)(nil), "invoice.GetInvoiceRequestPaymentStatusRequest")
+	proto.RegisterType((*InvoiceRequestPaymentStatus)(nil), "invoice.InvoiceRequestPaymentStatus")
+}
+
+func init() { proto.RegisterFile("lsprpc/invoice.proto", fileDescriptor_5fef7841c2201b9b) }
+
+var fileDescriptor_5fef7841c2201b9b = []byte{
+	// 1313 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x58, 0xcb, 0x72, 0xdb, 0x36,
+	0x14, 0x2d, 0x25, 0xeb, 0xc1, 0xab, 0xa7, 0x69, 0x3b, 0x61, 0xe5, 0x3c, 0x64, 0x39, 0x9e, 0xa8,
+	0x9d, 0xd6, 0x9e, 0x71, 0x17, 0x5d, 0xc7, 0xa9, 0x95, 0x7a, 0xc6, 0x4d, 0x32, 0x54, 0xb2, 0x68,
+	0xbb, 0x60, 0x11, 0x11, 0xb2, 0x31, 0xc3, 0x57, 0x09, 0xd0, 0x63, 0x7f, 0x45, 0xff, 0xa1, 0x1f,
+	0xd0, 0x7f, 0xe8, 0xae, 0x9b, 0xf4, 0x47, 0xfa, 0x13, 0x1d, 0x02, 0x20, 0x45, 0x52, 0x14, 0xa5,
+	0x26, 0xed, 0x74, 0x65, 0xe3, 0xe0, 0xdc, 0x83, 0x7b, 0x61, 0xdc, 0x03, 0xd0, 0xb0, 0x6b, 0x53,
+	0x3f, 0xf0, 0x67, 0x27, 0xc4, 0xbd, 0xf1, 0xc8, 0x0c, 0x1f, 0xfb, 0x81, 0xc7, 0x3c, 0xad, 0x21,
+	0x87, 0x23, 0x0f, 0xf6, 0xdf, 0xfa, 0x16, 0x62, 0xf8, 0x42, 0x00, 0x06, 0xfe, 0x39, 0xc4, 0x94,
+	0xc9, 0x1f, 0x5a, 0x17, 0x2a, 0xc4, 0xd2, 0x95, 0xa1, 0x32, 0xae, 0x1a, 0x15, 0x62, 0x69, 0xf7,
+	0xa1, 0x11, 0x52, 0x1c, 0x98, 0xc4, 0xd2, 0x2b, 0x1c, 0xac, 0x47, 0xc3, 0x0b, 0x4b, 0x7b, 0x0a,
+	0x3d, 0x1f, 0xdd, 0x39, 0xd8, 0x65, 0x66, 0x20, 0x62, 0xf5, 0xea, 0x50, 0x19, 0xab, 0x46, 0x57,
+	0xc2, 0x52, 0x71, 0xf4, 0x8b, 0x02, 0x0f, 0x8a, 0x57, 0xa4, 0xbe, 0xe7, 0x52, 0xfc, 0xef, 0x2f,
+	0xa9, 0x3d, 0x04, 0x20, 0xd4, 0xa4, 0x98, 0x31, 0x1b, 0x5b, 0xfa, 0xd6, 0x50, 0x19, 0x37, 0x0d,
+	0x95, 0xd0, 0xa9, 0x00, 0x46, 0x93, 0x78, 0x0b, 0xa6, 0x98, 0x52, 0xe2, 0xb9, 0xd9, 0xbc, 0x36,
+	0xce, 0x67, 0xf4, 0x5b, 0x52, 0x59, 0x5e, 0xe8, 0x7f, 0xaa, 0x4c, 0x4e, 0xe3, 0x5b, 0x9f, 0x04,
+	0xd8, 0xd2, 0x6b, 0xf1, 0xf4, 0xb9, 0x00, 0x46, 0xbf, 0x56, 0x60, 0x70, 0x49, 0x28, 0xcb, 0xa6,
+	0x4b, 0x63, 0xf1, 0x54, 0x7a, 0x4a, 0x26, 0xbd, 0xc7, 0xd0, 0xa2, 0x22, 0xc4, 0x0c, 0x65, 0xee,
+	0xaa, 0x01, 0x12, 0x7a, 0x4b, 0x2c, 0xed, 0x34, 0x93, 0x56, 0x94, 0x7a, 0xf7, 0x74, 0xe7, 0x38,
+	0x3e, 0x81, 0x67, 0x9e, 0x67, 0x4f, 0x88, 0xcd, 0x70, 0x90, 0xce, 0xf5, 0x34, 0x93, 0xeb, 0x56,
+	0x69, 0x8c, 0x2c, 0x40, 0xdb, 0x07, 0x75, 0x1e, 0x78, 0x8e, 0x19, 0xed, 0x39, 0x2f, 0x4f, 0x35,
+	0x9a, 0x11, 0xf0, 0x0d, 0x62, 0x38, 0x4a, 0x9f, 0x79, 0x62, 0xaa, 0xce, 0xa7, 0xea, 0xcc, 0xe3,
+	0x13, 0xbb, 0x50, 0xb3, 0x89, 0x43, 0x98, 0xde, 0x18, 0x2a, 0xe3, 0x9a, 0x21, 0x06, 0xda, 0x3d,
+	0xa8, 0x7b, 0xf3, 0x39, 0xc5, 0x4c, 0x6f, 0x72, 0x58, 0x8e, 0x46, 0x7f, 0xd4, 0xa1, 0x9b, 0xdd,
+	0xa0, 0xcd, 0xff, 0x8e, 0x0f, 0x21, 0xde, 0x15, 0x93, 0x88, 0x7d, 0xa8, 0x1a, 0xaa, 0x44, 0x2e,
+	0x2c, 0x6d, 0x00, 0xcd, 0x59, 0x18, 0x04, 0xd8, 0x9d, 0xdd, 0xf1, 0x82, 0x55, 0x23, 0x19, 0x6b,
+	0x87, 0xd0, 0x89, 0x7f, 0x37, 0x83, 0xb8, 0xbc, 0xaa, 0xd1, 0x8e, 0x41, 0x23, 0xaa, 0xe4, 0x0b,
+	0xd0, 0x32, 0x24, 0xd3, 0xa1, 0x88, 0xf1, 0x6a, 0xab, 0x46, 0x3f, 0xcd, 0xfc, 0x8e, 0x22, 0xa6,
+	0x7d, 0x0d, 0x7a, 0x96, 0x4d, 0xfd, 0x00, 0x23, 0xcb, 0xf4, 0x7d, 0x87, 0x6f, 0x45, 0xd5, 0xd8,
+	0x4b, 0xc7, 0x4c, 0xf9, 0xec, 0x6b, 0xdf, 0x89, 0xca, 0xf0, 0x03, 0x32, 0xc3, 0xe6, 0x9c, 0x20,
+	0xb1, 0x3d, 0x8a, 0xa1, 0x72, 0x64, 0x42, 0x10, 0x5b, 0x4c, 0xf3, 0xd5, 0x55, 0x51, 0x25, 0x47,
+	0xf8, 0xb2, 0x4f, 0xa1, 0x37, 0xf3, 0x1c, 0x87, 0x88, 0x7d, 0xe0, 0x12, 0xc0, 0x25, 0xba, 0x0b,
+	0x78, 0x42, 0x96, 0x88, 0x5c, 0xac, 0xc5, 0xc5, 0x52, 0x44, 0xae, 0xf8, 0x29, 0x34, 0x19, 0xba,
+	0x15, 0x52, 0x6d, 0x2e, 0xd5, 0x60, 0xe8, 0x76, 0x42, 0x16, 0x53, 0x3c, 0xb8, 0xc3, 0x83, 0xa3,
+	0x29, 0x1e, 0xf5, 0x10, 0x80, 0x79, 0x0c, 0xd9, 0x22, 0xae, 0x2b, 0xaa, 0xe0, 0x48, 0x5c, 0x85,
+	0x98, 0xe6, 0xb1, 0x3d, 0x51, 0x05, 0x47, 0x78, 0xf4, 0x67, 0xd0, 0xc7, 0x94, 0x11, 0x07, 0x31,
+	0x6c, 0x99, 0xd8, 0xc5, 0xc1, 0xd5, 0x9d, 0xde, 0xe7, 0x1a, 0xbd, 0x04, 0x3f, 0xe7, 0xb0, 0x76,
+	0x04, 0xdd, 0x05, 0x95, 0x11, 0x07, 0xeb, 0xdb, 0x9c, 0xd8, 0x49, 0xd0, 0x37, 0xc4, 0xc1, 0x11,
+	0xcd, 0xc1, 0x0c, 0x07, 0x0b, 0x3d, 0x4d, 0xd0, 0x24, 0x2a, 0xd5, 0x0e, 0xa0, 0x1d, 0xd3, 0xb8,
+	0xd6, 0x0e, 0x27, 0xb5, 0x24, 0xc6, 0x95, 0x0a, 0xec, 0x62, 0xb7, 0xd0, 0x2e, 0x1e, 0x80, 0x4a,
+	0xc9, 0x95, 0x8b, 0x58, 0x18, 0x60, 0x7d, 0x8f, 0x53, 0x16, 0x40, 0xce, 0x4c, 0xee, 0x95, 0x9b,
+	0xc9, 0xfd, 0x9c, 0x99, 0x44, 0x79, 0xda, 0x88, 0x32, 0x33, 0xe4, 0x0e, 0x68, 0xe9, 0x3a, 0x97,
+	0x6f, 0x45, 0x98, 0x30, 0x45, 0x6b, 0x84, 0x60, 0xbf, 0xd0, 0x6e, 0xa4, 0x3d, 0x9e, 0x41, 0x3f,
+	0xe9, 0x16, 0x39, 0xa7, 0x2b, 0xc3, 0xea, 0xb8, 0x75, 0x7a, 0x3f, 0xf1, 0x81, 0x6c, 0xac, 0xd1,
+	0xa3, 0x59, 0xad, 0xd1, 0x73, 0xd0, 0x5f, 0x60, 0xf6, 0x91, 0x46, 0xfe, 0x5e, 0x11, 0xbe, 0x98,
+	0x8d, 0x5f, 0xef, 0x8b, 0x59, 0xdb, 0xab, 0x6c, 0x64, 0x7b, 0x19, 0x0b, 0xab, 0xae, 0xb6, 0xb0,
+	0xad, 0x62, 0x0b, 0xab, 0x15, 0x5b, 0x58, 0x3d, 0x63, 0x61, 0xbf, 0x6f, 0x41, 0xf7, 0x03, 0xf7,
+	0x22, 0xe3, 0x51, 0xd5, 0x9c, 0x47, 0x69, 0xb0, 0xe5, 0x60, 0xc7, 0x93, 0xb9, 0xf1, 0xdf, 0x73,
+	0x5e, 0x51, 0x2b, 0xf7, 0x8a, 0xfa, 0x06, 0x5e, 0xd1, 0xd8, 0xd4, 0x2b, 0x9a, 0x6b, 0xbd, 0x42,
+	0x5d, 0xed, 0x15, 0x50, 0xe6, 0x15, 0xad, 0x72, 0xaf, 0x68, 0xe7, 0xbd, 0xa2, 0xa0, 0x1f, 0x3b,
+	0x1b, 0x5c, 0xdf, 0xdd, 0x7c, 0xc7, 0x2d, 0xdd, 0x01, 0xbd, 0x8d, 0xef, 0x80, 0xfe, 0x07, 0xdc,
+	0x01, 0xdb, 0x25, 0x77, 0x40, 0xdc, 0xbb, 0x4b, 0x2d, 0xb1, 0xe8, 0x5d, 0x79, 0xce, 0xe3, 0x92,
+	0x97, 0x7b, 0x37, 0x1b, 0x6b, 0xf4, 0x48, 0x56, 0x6b, 0xf4, 0x23, 0x3c, 0x5e, 0xf4, 0xee, 0x19,
+	0xb1, 0x6d, 0xe2, 0x5e, 0x4d, 0x43, 0xc7, 0x41, 0xc1, 0xdd, 0x47, 0x3f, 0x49, 0x46, 0x7f, 0x6e,
+	0xc1, 0x5e, 0xa1, 0x74, 0x3e, 0x54, 0xc9, 0x87, 0x46, 0x6d, 0x45, 0x19, 0x62, 0x21, 0x95, 0xb2,
+	0x72, 0x54, 0xda, 0x1a, 0x87, 0xd0, 0x89, 0xf7, 0x63, 0xe6, 0x85, 0x2e, 0xe3, 0x3d, 0x52, 0x33,
+	0xda, 0x12, 0x7c, 0x1e, 0x61, 0x11, 0x49, 0xfe, 0xed, 0x25, 0x49, 0x74, 0x73, 0x5b, 0x82, 0x82,
+	0x94, 0x6d, 0xa8, 0x7a, 0x79, 0x43, 0x35, 0x36, 0x68, 0xa8, 0xe6, 0xa6, 0x0d, 0xa5, 0xae, 0x6d,
+	0x28, 0x58, 0xdd, 0x50, 0xad, 0xb2, 0x86, 0x6a, 0x97, 0x37, 0x54, 0x27, 0xdf, 0x50, 0x07, 0x10,
+	0xef, 0x49, 0xfa, 0xf2, 0x6e, 0x49, 0x6c, 0x42, 0xb2, 0x94, 0xd4, 0x05, 0x1e, 0x53, 0xb8, 0xca,
+	0x11, 0x74, 0x43, 0x37, 0xa3, 0x23, 0x2e, 0xf0, 0x4e, 0xe8, 0xa6, 0x95, 0x32, 0x34, 0xae, 0x25,
+	0x1a, 0x63, 0x41, 0x8b, 0xd4, 0x46, 0xaf, 0xe1, 0xe8, 0x05, 0xce, 0xf5, 0xc3, 0x6b, 0xd1, 0xde,
+	0x53, 0x7e, 0x3e, 0xfe, 0xf1, 0xb5, 0xf3, 0x57, 0x05, 0xf6, 0x4b, 0xf4, 0xfe, 0x83, 0xcf, 0x87,
+	0x03, 0x68, 0xc7, 0xc4, 0x6b, 0x44, 0xaf, 0xa5, 0x91, 0xb7, 0x24, 0xf6, 0x2d, 0xa2, 0xd7, 0xa9,
+	0xc3, 0x5f, 0xcb, 0x1f, 0x7e, 0xc4, 0x18, 0x76, 0x7c, 0x46, 0xe5, 0x6d, 0x93, 0x8c, 0xa3, 0x73,
+	0x30, 0xc7, 0x99, 0x13, 0xd9, 0x98, 0xe3, 0xe4, 0x3c, 0xce, 0x11, 0xb1, 0xc3, 0x20, 0xf2, 0x09,
+	0x44, 0x3d, 0x97, 0xea, 0xcd, 0x61, 0x35, 0x4a, 0x4d, 0xc2, 0x86, 0x40, 0x23, 0x0d, 0x42, 0xcd,
+	0x39, 0x71, 0x91, 0xcd, 0x0f, 0x62, 0xd3, 0x68, 0x10, 0x3a, 0x89, 0x86, 0x51, 0xd6, 0x2e, 0xbe,
+	0x65, 0xa6, 0x5c, 0x8f, 0x9f, 0x42, 0xd5, 0x68, 0x45, 0xd8, 0x33, 0x01, 0x2d, 0x3d, 0x46, 0x5a,
+	0x4b, 0x8f, 0x91, 0xcf, 0x5f, 0x02, 0x2c, 0x6e, 0x64, 0x6d, 0x07, 0x7a, 0x67, 0xaf, 0x5e, 0x5d,
+	0x9a, 0x93, 0x8b, 0xcb, 0x37, 0xe7, 0x86, 0xf9, 0xec, 0xe5, 0xf7, 0xfd, 0x4f, 0xb4, 0x5d, 0xe8,
+	0xa7, 0xc1, 0x37, 0xc6, 0xdb, 0xf3, 0xbe, 0xa2, 0xed, 0xc1, 0x76, 0x1a, 0x9d, 0x3c, 0xbb, 0x9c,
+	0x9e, 0xf7, 0x2b, 0xa7, 0xef, 0x6b, 0xc9, 0x25, 0x3b, 0xc5, 0xc1, 0x0d, 0x99, 0x61, 0x6d, 0x06,
+	0xbb, 0x45, 0x5f, 0xba, 0xda, 0x93, 0xc4, 0x12, 0x4b, 0x3e, 0xbd, 0x07, 0x47, 0x6b, 0x58, 0xd2,
+	0x79, 0x93, 0x45, 0x72, 0x1f, 0x29, 0xf9, 0x45, 0x0a, 0xdf, 0x44, 0x83, 0xa3, 0x35, 0x2c, 0xb9,
+	0xc8, 0x4f, 0xb0, 0x53, 0xf0, 0x72, 0xd3, 0x0e, 0x93, 0xe8, 0xd5, 0x9f, 0x91, 0x83, 0x27, 0xe5,
+	0x24, 0xb9, 0xc2, 0x2b, 0xd8, 0x5e, 0x7a, 0xb8, 0x69, 0x07, 0x49, 0xe8, 0xaa, 0x47, 0xdd, 0x60,
+	0xd5, 0xd3, 0x30, 0x4e, 0x39, 0x4b, 0xcf, 0xa7, 0x5c, 0xfc, 0xc2, 0x1b, 0x3c, 0x29, 0x27, 0xc9,
+	0x94, 0xad, 0xf4, 0x5b, 0x33, 0x77, 0xa9, 0x8c, 0x0b, 0x32, 0x2f, 0xbc, 0xd2, 0x06, 0x8f, 0xf2,
+	0x05, 0xe4, 0x94, 0x6e, 0xe0, 0x51, 0xb9, 0xcf, 0x68, 0xc7, 0xe9, 0xb5, 0xd6, 0x1b, 0x52, 0xaa,
+	0xba, 0x12, 0xf2, 0xd9, 0xe1, 0x0f, 0x07, 0x57, 0x84, 0x5d, 0x87, 0xef, 0x8e, 0x67, 0x9e, 0x73,
+	0x42, 0x11, 0x23, 0x8e, 0xc7, 0xbc, 0x93, 0x2b, 0xef, 0x4b, 0xdb, 0x75, 0x4e, 0xc4, 0x3f, 0x95,
+	0xde, 0xd5, 0xf9, 0x7f, 0x93, 0xbe, 0xfa, 0x7b, 0x00, 0x2c, 0x7c, 0x34, 0xf5, 0x65, 0x12, 0x00,
+	0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// InvoiceServiceClient is the client API for InvoiceService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type InvoiceServiceClient interface {
+	UpdateInvoiceRequest(ctx context.Context, in *UpdateInvoiceRequestRequest, opts ...grpc.CallOption) (*UpdateInvoiceRequestResponse, error)
+	UpdateSessionInvoice(ctx context.Context, in *UpdateSessionInvoiceRequest, opts ...grpc.CallOption) (*UpdateSessionInvoiceResponse, error)
+	ListSessionInvoices(ctx context.Context, in *ListSessionInvoicesRequest, opts ...grpc.CallOption) (*ListSessionInvoicesResponse, error)
+	GetSessionInvoice(ctx context.Context, in *GetSessionInvoiceRequest, opts ...grpc.CallOption) (*SessionInvoice, error)
+	ListInvoiceRequests(ctx context.Context, in *ListInvoiceRequestsRequest, opts ...grpc.CallOption) (*ListInvoiceRequestsResponse, error)
+	GetSessionBillingSummary(ctx context.Context, in *GetSessionBillingSummaryRequest, opts ...grpc.CallOption) (*SessionBillingSummary, error)
+	GetInvoiceRequestPaymentStatus(ctx context.Context, in *GetInvoiceRequestPaymentStatusRequest, opts ...grpc.CallOption) (*InvoiceRequestPaymentStatus, error)
+}
+
+type invoiceServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewInvoiceServiceClient(cc *grpc.ClientConn) InvoiceServiceClient {
+	return &invoiceServiceClient{cc}
+}
+
+func (c *invoiceServiceClient) UpdateInvoiceRequest(ctx context.Context, in *UpdateInvoiceRequestRequest, opts ...grpc.CallOption) (*UpdateInvoiceRequestResponse, error) {
+	out := new(UpdateInvoiceRequestResponse)
+	err := c.cc.Invoke(ctx, "/invoice.InvoiceService/UpdateInvoiceRequest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) UpdateSessionInvoice(ctx context.Context, in *UpdateSessionInvoiceRequest, opts ...grpc.CallOption) (*UpdateSessionInvoiceResponse, error) {
+	out := new(UpdateSessionInvoiceResponse)
+	err := c.cc.Invoke(ctx, "/invoice.InvoiceService/UpdateSessionInvoice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) ListSessionInvoices(ctx context.Context, in *ListSessionInvoicesRequest, opts ...grpc.CallOption) (*ListSessionInvoicesResponse, error) {
+	out := new(ListSessionInvoicesResponse)
+	err := c.cc.Invoke(ctx, "/invoice.InvoiceService/ListSessionInvoices", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) GetSessionInvoice(ctx context.Context, in *GetSessionInvoiceRequest, opts ...grpc.CallOption) (*SessionInvoice, error) {
+	out := new(SessionInvoice)
+	err := c.cc.Invoke(ctx, "/invoice.InvoiceService/GetSessionInvoice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) ListInvoiceRequests(ctx context.Context, in *ListInvoiceRequestsRequest, opts ...grpc.CallOption) (*ListInvoiceRequestsResponse, error) {
+	out := new(ListInvoiceRequestsResponse)
+	err := c.cc.Invoke(ctx, "/invoice.InvoiceService/ListInvoiceRequests", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) GetSessionBillingSummary(ctx context.Context, in *GetSessionBillingSummaryRequest, opts ...grpc.CallOption) (*SessionBillingSummary, error) {
+	out := new(SessionBillingSummary)
+	err := c.cc.Invoke(ctx, "/invoice.InvoiceService/GetSessionBillingSummary", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoiceServiceClient) GetInvoiceRequestPaymentStatus(ctx context.Context, in *GetInvoiceRequestPaymentStatusRequest, opts ...grpc.CallOption) (*InvoiceRequestPaymentStatus, error) {
+	out := new(InvoiceRequestPaymentStatus)
+	err := c.cc.Invoke(ctx, "/invoice.InvoiceService/GetInvoiceRequestPaymentStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InvoiceServiceServer is the server API for InvoiceService service.
+type InvoiceServiceServer interface {
+	UpdateInvoiceRequest(context.Context, *UpdateInvoiceRequestRequest) (*UpdateInvoiceRequestResponse, error)
+	UpdateSessionInvoice(context.Context, *UpdateSessionInvoiceRequest) (*UpdateSessionInvoiceResponse, error)
+	ListSessionInvoices(context.Context, *ListSessionInvoicesRequest) (*ListSessionInvoicesResponse, error)
+	GetSessionInvoice(context.Context, *GetSessionInvoiceRequest) (*SessionInvoice, error)
+	ListInvoiceRequests(context.Context, *ListInvoiceRequestsRequest) (*ListInvoiceRequestsResponse, error)
+	GetSessionBillingSummary(context.Context, *GetSessionBillingSummaryRequest) (*SessionBillingSummary, error)
+	GetInvoiceRequestPaymentStatus(context.Context, *GetInvoiceRequestPaymentStatusRequest) (*InvoiceRequestPaymentStatus, error)
+}
+
+// UnimplementedInvoiceServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedInvoiceServiceServer struct {
+}
+
+func (*UnimplementedInvoiceServiceServer) UpdateInvoiceRequest(ctx context.Context, req *UpdateInvoiceRequestRequest) (*UpdateInvoiceRequestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateInvoiceRequest not implemented")
+}
+func (*UnimplementedInvoiceServiceServer) UpdateSessionInvoice(ctx context.Context, req *UpdateSessionInvoiceRequest) (*UpdateSessionInvoiceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSessionInvoice not implemented")
+}
+func (*UnimplementedInvoiceServiceServer) ListSessionInvoices(ctx context.Context, req *ListSessionInvoicesRequest) (*ListSessionInvoicesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessionInvoices not implemented")
+}
+func (*UnimplementedInvoiceServiceServer) GetSessionInvoice(ctx context.Context, req *GetSessionInvoiceRequest) (*SessionInvoice, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSessionInvoice not implemented")
+}
+func (*UnimplementedInvoiceServiceServer) ListInvoiceRequests(ctx context.Context, req *ListInvoiceRequestsRequest) (*ListInvoiceRequestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInvoiceRequests not implemented")
+}
+func (*UnimplementedInvoiceServiceServer) GetSessionBillingSummary(ctx context.Context, req *GetSessionBillingSummaryRequest) (*SessionBillingSummary, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSessionBillingSummary not implemented")
+}
+func (*UnimplementedInvoiceServiceServer) GetInvoiceRequestPaymentStatus(ctx context.Context, req *GetInvoiceRequestPaymentStatusRequest) (*InvoiceRequestPaymentStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInvoiceRequestPaymentStatus not implemented")
+}
+
+func RegisterInvoiceServiceServer(s *grpc.Server, srv InvoiceServiceServer) {
+	s.RegisterService(&_InvoiceService_serviceDesc, srv)
+}
+
+func _InvoiceService_UpdateInvoiceRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateInvoiceRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).UpdateInvoiceRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoice.InvoiceService/UpdateInvoiceRequest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).UpdateInvoiceRequest(ctx, req.(*UpdateInvoiceRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_UpdateSessionInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateSessionInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).UpdateSessionInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoice.InvoiceService/UpdateSessionInvoice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).UpdateSessionInvoice(ctx, req.(*UpdateSessionInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_ListSessionInvoices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSessionInvoicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).ListSessionInvoices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoice.InvoiceService/ListSessionInvoices",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).ListSessionInvoices(ctx, req.(*ListSessionInvoicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_GetSessionInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSessionInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).GetSessionInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoice.InvoiceService/GetSessionInvoice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).GetSessionInvoice(ctx, req.(*GetSessionInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_ListInvoiceRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInvoiceRequestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).ListInvoiceRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoice.InvoiceService/ListInvoiceRequests",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).ListInvoiceRequests(ctx, req.(*ListInvoiceRequestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_GetSessionBillingSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSessionBillingSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).GetSessionBillingSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoice.InvoiceService/GetSessionBillingSummary",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).GetSessionBillingSummary(ctx, req.(*GetSessionBillingSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InvoiceService_GetInvoiceRequestPaymentStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInvoiceRequestPaymentStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoiceServiceServer).GetInvoiceRequestPaymentStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoice.InvoiceService/GetInvoiceRequestPaymentStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoiceServiceServer).GetInvoiceRequestPaymentStatus(ctx, req.(*GetInvoiceRequestPaymentStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _InvoiceService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "invoice.InvoiceService",
+	HandlerType: (*InvoiceServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "UpdateInvoiceRequest",
+			Handler:    _InvoiceService_UpdateInvoiceRequest_Handler,
+		},
+		{
+			MethodName: "UpdateSessionInvoice",
+			Handler:    _InvoiceService_UpdateSessionInvoice_Handler,
+		},
+		{
+			MethodName: "ListSessionInvoices",
+			Handler:    _InvoiceService_ListSessionInvoices_Handler,
+		},
+		{
+			MethodName: "GetSessionInvoice",
+			Handler:    _InvoiceService_GetSessionInvoice_Handler,
+		},
+		{
+			MethodName: "ListInvoiceRequests",
+			Handler:    _InvoiceService_ListInvoiceRequests_Handler,
+		},
+		{
+			MethodName: "GetSessionBillingSummary",
+			Handler:    _InvoiceService_GetSessionBillingSummary_Handler,
+		},
+		{
+			MethodName: "GetInvoiceRequestPaymentStatus",
+			Handler:    _InvoiceService_GetInvoiceRequestPaymentStatus_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
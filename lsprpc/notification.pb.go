@@ -0,0 +1,724 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: lsprpc/notification.proto
+
+package lsprpc
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type ListUndeliveredNotificationsRequest struct {
+	UserId               int64    `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListUndeliveredNotificationsRequest) Reset()         { *m = ListUndeliveredNotificationsRequest{} }
+func (m *ListUndeliveredNotificationsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListUndeliveredNotificationsRequest) ProtoMessage()    {}
+func (*ListUndeliveredNotificationsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6861d798868a0b71, []int{0}
+}
+
+func (m *ListUndeliveredNotificationsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListUndeliveredNotificationsRequest.Unmarshal(m, b)
+}
+func (m *ListUndeliveredNotificationsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListUndeliveredNotificationsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListUndeliveredNotificationsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListUndeliveredNotificationsRequest.Merge(m, src)
+}
+func (m *ListUndeliveredNotificationsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListUndeliveredNotificationsRequest.Size(m)
+}
+func (m *ListUndeliveredNotificationsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListUndeliveredNotificationsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListUndeliveredNotificationsRequest proto.InternalMessageInfo
+
+func (m *ListUndeliveredNotificationsRequest) GetUserId() int64 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+type UndeliveredNotification struct {
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	NotificationType     string   `protobuf:"bytes,2,opt,name=notification_type,json=notificationType,proto3" json:"notification_type,omitempty"`
+	Status               string   `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Attempts             int32    `protobuf:"varint,4,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	LastError            string   `protobuf:"bytes,5,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	NextAttemptDate      string   `protobuf:"bytes,6,opt,name=next_attempt_date,json=nextAttemptDate,proto3" json:"next_attempt_date,omitempty"`
+	CreatedDate          string   `protobuf:"bytes,7,opt,name=created_date,json=createdDate,proto3" json:"created_date,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UndeliveredNotification) Reset()         { *m = UndeliveredNotification{} }
+func (m *UndeliveredNotification) String() string { return proto.CompactTextString(m) }
+func (*UndeliveredNotification) ProtoMessage()    {}
+func (*UndeliveredNotification) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6861d798868a0b71, []int{1}
+}
+
+func (m *UndeliveredNotification) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UndeliveredNotification.Unmarshal(m, b)
+}
+func (m *UndeliveredNotification) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UndeliveredNotification.Marshal(b, m, deterministic)
+}
+func (m *UndeliveredNotification) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UndeliveredNotification.Merge(m, src)
+}
+func (m *UndeliveredNotification) XXX_Size() int {
+	return xxx_messageInfo_UndeliveredNotification.Size(m)
+}
+func (m *UndeliveredNotification) XXX_DiscardUnknown() {
+	xxx_messageInfo_UndeliveredNotification.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UndeliveredNotification proto.InternalMessageInfo
+
+func (m *UndeliveredNotification) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *UndeliveredNotification) GetNotificationType() string {
+	if m != nil {
+		return m.NotificationType
+	}
+	return ""
+}
+
+func (m *UndeliveredNotification) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *UndeliveredNotification) GetAttempts() int32 {
+	if m != nil {
+		return m.Attempts
+	}
+	return 0
+}
+
+func (m *UndeliveredNotification) GetLastError() string {
+	if m != nil {
+		return m.LastError
+	}
+	return ""
+}
+
+func (m *UndeliveredNotification) GetNextAttemptDate() string {
+	if m != nil {
+		return m.NextAttemptDate
+	}
+	return ""
+}
+
+func (m *UndeliveredNotification) GetCreatedDate() string {
+	if m != nil {
+		return m.CreatedDate
+	}
+	return ""
+}
+
+type ListUndeliveredNotificationsResponse struct {
+	Notifications        []*UndeliveredNotification `protobuf:"bytes,1,rep,name=notifications,proto3" json:"notifications,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
+	XXX_unrecognized     []byte                     `json:"-"`
+	XXX_sizecache        int32                      `json:"-"`
+}
+
+func (m *ListUndeliveredNotificationsResponse) Reset()         { *m = ListUndeliveredNotificationsResponse{} }
+func (m *ListUndeliveredNotificationsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListUndeliveredNotificationsResponse) ProtoMessage()    {}
+func (*ListUndeliveredNotificationsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6861d798868a0b71, []int{2}
+}
+
+func (m *ListUndeliveredNotificationsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListUndeliveredNotificationsResponse.Unmarshal(m, b)
+}
+func (m *ListUndeliveredNotificationsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListUndeliveredNotificationsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListUndeliveredNotificationsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListUndeliveredNotificationsResponse.Merge(m, src)
+}
+func (m *ListUndeliveredNotificationsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListUndeliveredNotificationsResponse.Size(m)
+}
+func (m *ListUndeliveredNotificationsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListUndeliveredNotificationsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListUndeliveredNotificationsResponse proto.InternalMessageInfo
+
+func (m *ListUndeliveredNotificationsResponse) GetNotifications() []*UndeliveredNotification {
+	if m != nil {
+		return m.Notifications
+	}
+	return nil
+}
+
+type CreateWebhookRequest struct {
+	// 0 for an operator webhook receiving the events of all users
+	UserId               int64    `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Url                  string   `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	EventTypes           []string `protobuf:"bytes,3,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateWebhookRequest) Reset()         { *m = CreateWebhookRequest{} }
+func (m *CreateWebhookRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateWebhookRequest) ProtoMessage()    {}
+func (*CreateWebhookRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6861d798868a0b71, []int{3}
+}
+
+func (m *CreateWebhookRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateWebhookRequest.Unmarshal(m, b)
+}
+func (m *CreateWebhookRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateWebhookRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateWebhookRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateWebhookRequest.Merge(m, src)
+}
+func (m *CreateWebhookRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateWebhookRequest.Size(m)
+}
+func (m *CreateWebhookRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateWebhookRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateWebhookRequest proto.InternalMessageInfo
+
+func (m *CreateWebhookRequest) GetUserId() int64 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *CreateWebhookRequest) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *CreateWebhookRequest) GetEventTypes() []string {
+	if m != nil {
+		return m.EventTypes
+	}
+	return nil
+}
+
+type Webhook struct {
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url                  string   `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Secret               string   `protobuf:"bytes,3,opt,name=secret,proto3" json:"secret,omitempty"`
+	EventTypes           []string `protobuf:"bytes,4,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`
+	IsActive             bool     `protobuf:"varint,5,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	CreatedDate          string   `protobuf:"bytes,6,opt,name=created_date,json=createdDate,proto3" json:"created_date,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Webhook) Reset()         { *m = Webhook{} }
+func (m *Webhook) String() string { return proto.CompactTextString(m) }
+func (*Webhook) ProtoMessage()    {}
+func (*Webhook) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6861d798868a0b71, []int{4}
+}
+
+func (m *Webhook) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Webhook.Unmarshal(m, b)
+}
+func (m *Webhook) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Webhook.Marshal(b, m, deterministic)
+}
+func (m *Webhook) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Webhook.Merge(m, src)
+}
+func (m *Webhook) XXX_Size() int {
+	return xxx_messageInfo_Webhook.Size(m)
+}
+func (m *Webhook) XXX_DiscardUnknown() {
+	xxx_messageInfo_Webhook.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Webhook proto.InternalMessageInfo
+
+func (m *Webhook) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Webhook) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *Webhook) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+func (m *Webhook) GetEventTypes() []string {
+	if m != nil {
+		return m.EventTypes
+	}
+	return nil
+}
+
+func (m *Webhook) GetIsActive() bool {
+	if m != nil {
+		return m.IsActive
+	}
+	return false
+}
+
+func (m *Webhook) GetCreatedDate() string {
+	if m != nil {
+		return m.CreatedDate
+	}
+	return ""
+}
+
+type ListWebhooksRequest struct {
+	// 0 for operator webhooks
+	UserId               int64    `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListWebhooksRequest) Reset()         { *m = ListWebhooksRequest{} }
+func (m *ListWebhooksRequest) String() string { return proto.CompactTextString(m) }
+func (*ListWebhooksRequest) ProtoMessage()    {}
+func (*ListWebhooksRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6861d798868a0b71, []int{5}
+}
+
+func (m *ListWebhooksRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListWebhooksRequest.Unmarshal(m, b)
+}
+func (m *ListWebhooksRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListWebhooksRequest.Marshal(b, m, deterministic)
+}
+func (m *ListWebhooksRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListWebhooksRequest.Merge(m, src)
+}
+func (m *ListWebhooksRequest) XXX_Size() int {
+	return xxx_messageInfo_ListWebhooksRequest.Size(m)
+}
+func (m *ListWebhooksRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListWebhooksRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListWebhooksRequest proto.InternalMessageInfo
+
+func (m *ListWebhooksRequest) GetUserId() int64 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+type ListWebhooksResponse struct {
+	Webhooks             []*Webhook `protobuf:"bytes,1,rep,name=webhooks,proto3" json:"webhooks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *ListWebhooksResponse) Reset()         { *m = ListWebhooksResponse{} }
+func (m *ListWebhooksResponse) String() string { return proto.CompactTextString(m) }
+func (*ListWebhooksResponse) ProtoMessage()    {}
+func (*ListWebhooksResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6861d798868a0b71, []int{6}
+}
+
+func (m *ListWebhooksResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListWebhooksResponse.Unmarshal(m, b)
+}
+func (m *ListWebhooksResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListWebhooksResponse.Marshal(b, m, deterministic)
+}
+func (m *ListWebhooksResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListWebhooksResponse.Merge(m, src)
+}
+func (m *ListWebhooksResponse) XXX_Size() int {
+	return xxx_messageInfo_ListWebhooksResponse.Size(m)
+}
+func (m *ListWebhooksResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListWebhooksResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListWebhooksResponse proto.InternalMessageInfo
+
+func (m *ListWebhooksResponse) GetWebhooks() []*Webhook {
+	if m != nil {
+		return m.Webhooks
+	}
+	return nil
+}
+
+type DeleteWebhookRequest struct {
+	// 0 for an operator webhook
+	UserId               int64    `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Id                   int64    `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteWebhookRequest) Reset()         { *m = DeleteWebhookRequest{} }
+func (m *DeleteWebhookRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteWebhookRequest) ProtoMessage()    {}
+func (*DeleteWebhookRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6861d798868a0b71, []int{7}
+}
+
+func (m *DeleteWebhookRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteWebhookRequest.Unmarshal(m, b)
+}
+func (m *DeleteWebhookRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteWebhookRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteWebhookRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteWebhookRequest.Merge(m, src)
+}
+func (m *DeleteWebhookRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteWebhookRequest.Size(m)
+}
+func (m *DeleteWebhookRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteWebhookRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteWebhookRequest proto.InternalMessageInfo
+
+func (m *DeleteWebhookRequest) GetUserId() int64 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *DeleteWebhookRequest) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+type DeleteWebhookResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteWebhookResponse) Reset()         { *m = DeleteWebhookResponse{} }
+func (m *DeleteWebhookResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteWebhookResponse) ProtoMessage()    {}
+func (*DeleteWebhookResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6861d798868a0b71, []int{8}
+}
+
+func (m *DeleteWebhookResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteWebhookResponse.Unmarshal(m, b)
+}
+func (m *DeleteWebhookResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteWebhookResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteWebhookResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteWebhookResponse.Merge(m, src)
+}
+func (m *DeleteWebhookResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteWebhookResponse.Size(m)
+}
+func (m *DeleteWebhookResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteWebhookResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteWebhookResponse proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*ListUndeliveredNotificationsRequest)(nil), "notification.ListUndeliveredNotificationsRequest")
+	proto.RegisterType((*UndeliveredNotification)(nil), "notification.UndeliveredNotification")
+	proto.RegisterType((*ListUndeliveredNotificationsResponse)(nil), "notification.ListUndeliveredNotificationsResponse")
+	proto.RegisterType((*CreateWebhookRequest)(nil), "notification.CreateWebhookRequest")
+	proto.RegisterType((*Webhook)(nil), "notification.Webhook")
+	proto.RegisterType((*ListWebhooksRequest)(nil), "notification.ListWebhooksRequest")
+	proto.RegisterType((*ListWebhooksResponse)(nil), "notification.ListWebhooksResponse")
+	proto.RegisterType((*DeleteWebhookRequest)(nil), "notification.DeleteWebhookRequest")
+	proto.RegisterType((*DeleteWebhookResponse)(nil), "notification.DeleteWebhookResponse")
+}
+
+func init() { proto.RegisterFile("lsprpc/notification.proto", fileDescriptor_6861d798868a0b71) }
+
+var fileDescriptor_6861d798868a0b71 = []byte{
+	// 547 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x94, 0xcf, 0x6e, 0xd3, 0x40,
+	0x10, 0xc6, 0xe5, 0xb8, 0xcd, 0x9f, 0x49, 0x02, 0xed, 0x36, 0x25, 0x26, 0x80, 0x48, 0x1c, 0x90,
+	0x22, 0x10, 0x89, 0x1a, 0xee, 0xa0, 0x42, 0x39, 0x14, 0x10, 0x07, 0x43, 0x05, 0xe2, 0x62, 0x39,
+	0xf6, 0xd0, 0xae, 0x70, 0xbc, 0xc6, 0x3b, 0x09, 0xf4, 0x01, 0x78, 0x09, 0xce, 0x3c, 0x24, 0x47,
+	0xe4, 0xf5, 0x36, 0xb2, 0x93, 0x34, 0x6d, 0x6f, 0x99, 0xd9, 0x6f, 0x66, 0x27, 0xdf, 0x6f, 0xbc,
+	0x70, 0x37, 0x94, 0x71, 0x12, 0xfb, 0xa3, 0x48, 0x10, 0xff, 0xc6, 0x7d, 0x8f, 0xb8, 0x88, 0x86,
+	0x71, 0x22, 0x48, 0xb0, 0x46, 0x3e, 0x67, 0xbf, 0x80, 0xfe, 0x7b, 0x2e, 0xe9, 0x24, 0x0a, 0x30,
+	0xe4, 0x73, 0x4c, 0x30, 0xf8, 0x90, 0x3b, 0x96, 0x0e, 0xfe, 0x98, 0xa1, 0x24, 0xd6, 0x86, 0xca,
+	0x4c, 0x62, 0xe2, 0xf2, 0xc0, 0x32, 0xba, 0xc6, 0xc0, 0x74, 0xca, 0x69, 0x78, 0x1c, 0xd8, 0xff,
+	0x0c, 0x68, 0x5f, 0x52, 0xcc, 0x6e, 0x41, 0x69, 0xa1, 0x2f, 0xf1, 0x80, 0x3d, 0x85, 0xdd, 0xfc,
+	0xdd, 0x2e, 0x9d, 0xc7, 0x68, 0x95, 0xba, 0xc6, 0xa0, 0xe6, 0xec, 0xe4, 0x0f, 0x3e, 0x9d, 0xc7,
+	0xc8, 0xee, 0x40, 0x59, 0x92, 0x47, 0x33, 0x69, 0x99, 0x4a, 0xa1, 0x23, 0xd6, 0x81, 0xaa, 0x47,
+	0x84, 0xd3, 0x98, 0xa4, 0xb5, 0xd5, 0x35, 0x06, 0xdb, 0xce, 0x22, 0x66, 0x0f, 0x00, 0x42, 0x4f,
+	0x92, 0x8b, 0x49, 0x22, 0x12, 0x6b, 0x5b, 0xd5, 0xd5, 0xd2, 0xcc, 0x9b, 0x34, 0xc1, 0x9e, 0xc0,
+	0x6e, 0x84, 0xbf, 0xc8, 0xd5, 0x7a, 0x37, 0xf0, 0x08, 0xad, 0xb2, 0x52, 0xdd, 0x4e, 0x0f, 0x0e,
+	0xb3, 0xfc, 0x91, 0x47, 0xc8, 0x7a, 0xd0, 0xf0, 0x13, 0xf4, 0x08, 0x83, 0x4c, 0x56, 0x51, 0xb2,
+	0xba, 0xce, 0xa5, 0x12, 0x5b, 0xc2, 0xa3, 0xcd, 0xd6, 0xc9, 0x58, 0x44, 0x12, 0xd9, 0x3b, 0x68,
+	0xe6, 0xff, 0x9d, 0xb4, 0x8c, 0xae, 0x39, 0xa8, 0x8f, 0x1f, 0x0f, 0x0b, 0x70, 0x2e, 0x69, 0xe3,
+	0x14, 0x6b, 0xed, 0x09, 0xb4, 0x5e, 0xab, 0x19, 0x3e, 0xe3, 0xe4, 0x4c, 0x88, 0xef, 0x57, 0x01,
+	0x62, 0x3b, 0x60, 0xce, 0x92, 0x50, 0xdb, 0x9c, 0xfe, 0x64, 0x0f, 0xa1, 0x8e, 0x73, 0x8c, 0x48,
+	0xf9, 0x9f, 0xda, 0x6b, 0x0e, 0x6a, 0x0e, 0xa8, 0x54, 0xea, 0xbc, 0xb4, 0xff, 0x1a, 0x50, 0xd1,
+	0xed, 0x57, 0x18, 0xae, 0xb6, 0x4b, 0x41, 0xa1, 0x9f, 0x20, 0x2d, 0x40, 0xa9, 0x68, 0xf9, 0x9a,
+	0xad, 0xe5, 0x6b, 0xd8, 0x3d, 0xa8, 0x71, 0xe9, 0x7a, 0x3e, 0xf1, 0x39, 0x2a, 0x58, 0x55, 0xa7,
+	0xca, 0xe5, 0xa1, 0x8a, 0x57, 0xfc, 0x2f, 0xaf, 0xfa, 0x3f, 0x84, 0xbd, 0xd4, 0x7f, 0x3d, 0xe9,
+	0xd5, 0xab, 0x7a, 0x0c, 0xad, 0xa2, 0x5e, 0xf3, 0x39, 0x80, 0xea, 0x4f, 0x9d, 0xd3, 0x68, 0xf6,
+	0x8b, 0x68, 0x2e, 0xac, 0x5e, 0xc8, 0xec, 0x97, 0xd0, 0x3a, 0xc2, 0x10, 0xaf, 0x4f, 0x21, 0xb3,
+	0xb1, 0x74, 0x61, 0xa3, 0xdd, 0x86, 0xfd, 0xa5, 0x06, 0xd9, 0x30, 0xe3, 0x3f, 0x26, 0xec, 0xe5,
+	0xf9, 0x7f, 0xc4, 0x64, 0xce, 0x7d, 0x64, 0xbf, 0x0d, 0xb8, 0xbf, 0x69, 0xdb, 0xd8, 0x41, 0x71,
+	0xe6, 0x6b, 0x7c, 0xd4, 0x9d, 0xf1, 0x4d, 0x4a, 0xb4, 0x59, 0x6f, 0xa1, 0x59, 0xd8, 0x3f, 0x66,
+	0x17, 0x9b, 0xac, 0x5b, 0xce, 0xce, 0x7a, 0x3f, 0xd9, 0x09, 0x34, 0xf2, 0x40, 0x58, 0x6f, 0x75,
+	0x9e, 0x25, 0xb8, 0x1d, 0x7b, 0x93, 0x44, 0x8f, 0xf8, 0x05, 0x9a, 0x05, 0x6f, 0x97, 0x47, 0x5c,
+	0x47, 0xae, 0xd3, 0xdf, 0xa8, 0xc9, 0x3a, 0xbf, 0xea, 0x7f, 0xed, 0x9d, 0x72, 0x3a, 0x9b, 0x4d,
+	0x86, 0xbe, 0x98, 0x8e, 0xa4, 0x47, 0x7c, 0x2a, 0x48, 0x8c, 0x4e, 0xc5, 0xb3, 0x30, 0x9a, 0x8e,
+	0xb2, 0x27, 0x77, 0x52, 0x56, 0xcf, 0xec, 0xf3, 0xff, 0x03, 0x00, 0x1d, 0xf7, 0x2c, 0xf9, 0x83,
+	0x05, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// NotificationServiceClient is the client API for NotificationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type NotificationServiceClient interface {
+	ListUndeliveredNotifications(ctx context.Context, in *ListUndeliveredNotificationsRequest, opts ...grpc.CallOption) (*ListUndeliveredNotificationsResponse, error)
+	CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*Webhook, error)
+	ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error)
+	DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error)
+}
+
+type notificationServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewNotificationServiceClient(cc *grpc.ClientConn) NotificationServiceClient {
+	return &notificationServiceClient{cc}
+}
+
+func (c *notificationServiceClient) ListUndeliveredNotifications(ctx context.Context, in *ListUndeliveredNotificationsRequest, opts ...grpc.CallOption) (*ListUndeliveredNotificationsResponse, error) {
+	out := new(ListUndeliveredNotificationsResponse)
+	err := c.cc.Invoke(ctx, "/notification.NotificationService/ListUndeliveredNotifications", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceClient) CreateWebhook(ctx context.Context, in *CreateWebhookRequest, opts ...grpc.CallOption) (*Webhook, error) {
+	out := new(Webhook)
+	err := c.cc.Invoke(ctx, "/notification.NotificationService/CreateWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceClient) ListWebhooks(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error) {
+	out := new(ListWebhooksResponse)
+	err := c.cc.Invoke(ctx, "/notification.NotificationService/ListWebhooks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notificationServiceClient) DeleteWebhook(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error) {
+	out := new(DeleteWebhookResponse)
+	err := c.cc.Invoke(ctx, "/notification.NotificationService/DeleteWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// NotificationServiceServer is the server API for NotificationService service.
+type NotificationServiceServer interface {
+	ListUndeliveredNotifications(context.Context, *ListUndeliveredNotificationsRequest) (*ListUndeliveredNotificationsResponse, error)
+	CreateWebhook(context.Context, *CreateWebhookRequest) (*Webhook, error)
+	ListWebhooks(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error)
+	DeleteWebhook(context.Context, *DeleteWebhookRequest) (*DeleteWebhookResponse, error)
+}
+
+// UnimplementedNotificationServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedNotificationServiceServer struct {
+}
+
+func (*UnimplementedNotificationServiceServer) ListUndeliveredNotifications(ctx context.Context, req *ListUndeliveredNotificationsRequest) (*ListUndeliveredNotificationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUndeliveredNotifications not implemented")
+}
+func (*UnimplementedNotificationServiceServer) CreateWebhook(ctx context.Context, req *CreateWebhookRequest) (*Webhook, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWebhook not implemented")
+}
+func (*UnimplementedNotificationServiceServer) ListWebhooks(ctx context.Context, req *ListWebhooksRequest) (*ListWebhooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhooks not implemented")
+}
+func (*UnimplementedNotificationServiceServer) DeleteWebhook(ctx context.Context, req *DeleteWebhookRequest) (*DeleteWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhook not implemented")
+}
+
+func RegisterNotificationServiceServer(s *grpc.Server, srv NotificationServiceServer) {
+	s.RegisterService(&_NotificationService_serviceDesc, srv)
+}
+
+func _NotificationService_ListUndeliveredNotifications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUndeliveredNotificationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).ListUndeliveredNotifications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/notification.NotificationService/ListUndeliveredNotifications",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).ListUndeliveredNotifications(ctx, req.(*ListUndeliveredNotificationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationService_CreateWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).CreateWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/notification.NotificationService/CreateWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).CreateWebhook(ctx, req.(*CreateWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationService_ListWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).ListWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/notification.NotificationService/ListWebhooks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).ListWebhooks(ctx, req.(*ListWebhooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotificationService_DeleteWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotificationServiceServer).DeleteWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/notification.NotificationService/DeleteWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotificationServiceServer).DeleteWebhook(ctx, req.(*DeleteWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _NotificationService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "notification.NotificationService",
+	HandlerType: (*NotificationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListUndeliveredNotifications",
+			Handler:    _NotificationService_ListUndeliveredNotifications_Handler,
+		},
+		{
+			MethodName: "CreateWebhook",
+			Handler:    _NotificationService_CreateWebhook_Handler,
+		},
+		{
+			MethodName: "ListWebhooks",
+			Handler:    _NotificationService_ListWebhooks_Handler,
+		},
+		{
+			MethodName: "DeleteWebhook",
+			Handler:    _NotificationService_DeleteWebhook_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "lsprpc/notification.proto",
+}
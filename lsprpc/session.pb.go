@@ -0,0 +1,430 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: lsprpc/session.proto
+
+// Not "session", which is the ocpirpc SessionService served alongside
+
+package lsprpc
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type WatchSessionRequest struct {
+	UserId               int64    `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SessionUid           string   `protobuf:"bytes,2,opt,name=session_uid,json=sessionUid,proto3" json:"session_uid,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchSessionRequest) Reset()         { *m = WatchSessionRequest{} }
+func (m *WatchSessionRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchSessionRequest) ProtoMessage()    {}
+func (*WatchSessionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8194367d482c682d, []int{0}
+}
+
+func (m *WatchSessionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchSessionRequest.Unmarshal(m, b)
+}
+func (m *WatchSessionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchSessionRequest.Marshal(b, m, deterministic)
+}
+func (m *WatchSessionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchSessionRequest.Merge(m, src)
+}
+func (m *WatchSessionRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchSessionRequest.Size(m)
+}
+func (m *WatchSessionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchSessionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchSessionRequest proto.InternalMessageInfo
+
+func (m *WatchSessionRequest) GetUserId() int64 {
+	if m != nil {
+		return m.UserId
+	}
+	return 0
+}
+
+func (m *WatchSessionRequest) GetSessionUid() string {
+	if m != nil {
+		return m.SessionUid
+	}
+	return ""
+}
+
+type SessionEventInvoice struct {
+	Id                   int64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	PaymentRequest       string   `protobuf:"bytes,2,opt,name=payment_request,json=paymentRequest,proto3" json:"payment_request,omitempty"`
+	TotalFiat            float64  `protobuf:"fixed64,3,opt,name=total_fiat,json=totalFiat,proto3" json:"total_fiat,omitempty"`
+	TotalMsat            int64    `protobuf:"varint,4,opt,name=total_msat,json=totalMsat,proto3" json:"total_msat,omitempty"`
+	IsSettled            bool     `protobuf:"varint,5,opt,name=is_settled,json=isSettled,proto3" json:"is_settled,omitempty"`
+	IsExpired            bool     `protobuf:"varint,6,opt,name=is_expired,json=isExpired,proto3" json:"is_expired,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SessionEventInvoice) Reset()         { *m = SessionEventInvoice{} }
+func (m *SessionEventInvoice) String() string { return proto.CompactTextString(m) }
+func (*SessionEventInvoice) ProtoMessage()    {}
+func (*SessionEventInvoice) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8194367d482c682d, []int{1}
+}
+
+func (m *SessionEventInvoice) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SessionEventInvoice.Unmarshal(m, b)
+}
+func (m *SessionEventInvoice) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SessionEventInvoice.Marshal(b, m, deterministic)
+}
+func (m *SessionEventInvoice) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SessionEventInvoice.Merge(m, src)
+}
+func (m *SessionEventInvoice) XXX_Size() int {
+	return xxx_messageInfo_SessionEventInvoice.Size(m)
+}
+func (m *SessionEventInvoice) XXX_DiscardUnknown() {
+	xxx_messageInfo_SessionEventInvoice.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SessionEventInvoice proto.InternalMessageInfo
+
+func (m *SessionEventInvoice) GetId() int64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *SessionEventInvoice) GetPaymentRequest() string {
+	if m != nil {
+		return m.PaymentRequest
+	}
+	return ""
+}
+
+func (m *SessionEventInvoice) GetTotalFiat() float64 {
+	if m != nil {
+		return m.TotalFiat
+	}
+	return 0
+}
+
+func (m *SessionEventInvoice) GetTotalMsat() int64 {
+	if m != nil {
+		return m.TotalMsat
+	}
+	return 0
+}
+
+func (m *SessionEventInvoice) GetIsSettled() bool {
+	if m != nil {
+		return m.IsSettled
+	}
+	return false
+}
+
+func (m *SessionEventInvoice) GetIsExpired() bool {
+	if m != nil {
+		return m.IsExpired
+	}
+	return false
+}
+
+type SessionEvent struct {
+	EventType            string               `protobuf:"bytes,1,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	SessionUid           string               `protobuf:"bytes,2,opt,name=session_uid,json=sessionUid,proto3" json:"session_uid,omitempty"`
+	Status               string               `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Currency             string               `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	MeteredEnergy        float64              `protobuf:"fixed64,5,opt,name=metered_energy,json=meteredEnergy,proto3" json:"metered_energy,omitempty"`
+	MeteredTime          float64              `protobuf:"fixed64,6,opt,name=metered_time,json=meteredTime,proto3" json:"metered_time,omitempty"`
+	EstimatedEnergy      float64              `protobuf:"fixed64,7,opt,name=estimated_energy,json=estimatedEnergy,proto3" json:"estimated_energy,omitempty"`
+	EstimatedTime        float64              `protobuf:"fixed64,8,opt,name=estimated_time,json=estimatedTime,proto3" json:"estimated_time,omitempty"`
+	InvoicedFiat         float64              `protobuf:"fixed64,9,opt,name=invoiced_fiat,json=invoicedFiat,proto3" json:"invoiced_fiat,omitempty"`
+	InvoicedMsat         int64                `protobuf:"varint,10,opt,name=invoiced_msat,json=invoicedMsat,proto3" json:"invoiced_msat,omitempty"`
+	SessionInvoice       *SessionEventInvoice `protobuf:"bytes,11,opt,name=session_invoice,json=sessionInvoice,proto3" json:"session_invoice,omitempty"`
+	LastUpdated          string               `protobuf:"bytes,12,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *SessionEvent) Reset()         { *m = SessionEvent{} }
+func (m *SessionEvent) String() string { return proto.CompactTextString(m) }
+func (*SessionEvent) ProtoMessage()    {}
+func (*SessionEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8194367d482c682d, []int{2}
+}
+
+func (m *SessionEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SessionEvent.Unmarshal(m, b)
+}
+func (m *SessionEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SessionEvent.Marshal(b, m, deterministic)
+}
+func (m *SessionEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SessionEvent.Merge(m, src)
+}
+func (m *SessionEvent) XXX_Size() int {
+	return xxx_messageInfo_SessionEvent.Size(m)
+}
+func (m *SessionEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_SessionEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SessionEvent proto.InternalMessageInfo
+
+func (m *SessionEvent) GetEventType() string {
+	if m != nil {
+		return m.EventType
+	}
+	return ""
+}
+
+func (m *SessionEvent) GetSessionUid() string {
+	if m != nil {
+		return m.SessionUid
+	}
+	return ""
+}
+
+func (m *SessionEvent) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *SessionEvent) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+func (m *SessionEvent) GetMeteredEnergy() float64 {
+	if m != nil {
+		return m.MeteredEnergy
+	}
+	return 0
+}
+
+func (m *SessionEvent) GetMeteredTime() float64 {
+	if m != nil {
+		return m.MeteredTime
+	}
+	return 0
+}
+
+func (m *SessionEvent) GetEstimatedEnergy() float64 {
+	if m != nil {
+		return m.EstimatedEnergy
+	}
+	return 0
+}
+
+func (m *SessionEvent) GetEstimatedTime() float64 {
+	if m != nil {
+		return m.EstimatedTime
+	}
+	return 0
+}
+
+func (m *SessionEvent) GetInvoicedFiat() float64 {
+	if m != nil {
+		return m.InvoicedFiat
+	}
+	return 0
+}
+
+func (m *SessionEvent) GetInvoicedMsat() int64 {
+	if m != nil {
+		return m.InvoicedMsat
+	}
+	return 0
+}
+
+func (m *SessionEvent) GetSessionInvoice() *SessionEventInvoice {
+	if m != nil {
+		return m.SessionInvoice
+	}
+	return nil
+}
+
+func (m *SessionEvent) GetLastUpdated() string {
+	if m != nil {
+		return m.LastUpdated
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*WatchSessionRequest)(nil), "lspsession.WatchSessionRequest")
+	proto.RegisterType((*SessionEventInvoice)(nil), "lspsession.SessionEventInvoice")
+	proto.RegisterType((*SessionEvent)(nil), "lspsession.SessionEvent")
+}
+
+func init() { proto.RegisterFile("lsprpc/session.proto", fileDescriptor_8194367d482c682d) }
+
+var fileDescriptor_8194367d482c682d = []byte{
+	// 489 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x93, 0x6d, 0x6b, 0x13, 0x41,
+	0x10, 0xc7, 0xd9, 0x44, 0xd3, 0xdc, 0x24, 0xbd, 0xc8, 0x56, 0xf4, 0x28, 0x48, 0xd3, 0x94, 0x62,
+	0x7c, 0x61, 0x22, 0xf5, 0x1b, 0x08, 0x11, 0x8b, 0x88, 0xb0, 0x69, 0x11, 0x04, 0x39, 0xb6, 0xb7,
+	0x63, 0xba, 0x70, 0x4f, 0xee, 0xce, 0x05, 0xf3, 0x15, 0x7d, 0xef, 0xf7, 0x91, 0xdb, 0xdd, 0x3c,
+	0x08, 0x15, 0xdf, 0xdd, 0xfc, 0xe6, 0x7f, 0xff, 0xdd, 0x99, 0xd9, 0x81, 0xa7, 0xb9, 0xad, 0x4d,
+	0x9d, 0xcd, 0x2d, 0x5a, 0xab, 0xab, 0x72, 0x56, 0x9b, 0x8a, 0x2a, 0x0e, 0xb9, 0xad, 0x03, 0x99,
+	0x7c, 0x86, 0x93, 0x2f, 0x92, 0xb2, 0xfb, 0xa5, 0x8f, 0x05, 0xfe, 0x68, 0xd0, 0x12, 0x7f, 0x0e,
+	0x47, 0x8d, 0x45, 0x93, 0x6a, 0x95, 0xb0, 0x31, 0x9b, 0x76, 0x45, 0xaf, 0x0d, 0xaf, 0x15, 0x3f,
+	0x83, 0x41, 0xf8, 0x35, 0x6d, 0xb4, 0x4a, 0x3a, 0x63, 0x36, 0x8d, 0x04, 0x04, 0x74, 0xab, 0xd5,
+	0xe4, 0x17, 0x83, 0x93, 0x60, 0xb6, 0x58, 0x63, 0x49, 0xd7, 0xe5, 0xba, 0xd2, 0x19, 0xf2, 0x18,
+	0x3a, 0x3b, 0xb3, 0x8e, 0x56, 0xfc, 0x25, 0x8c, 0x6a, 0xb9, 0x29, 0xb0, 0xa4, 0xd4, 0xf8, 0x43,
+	0x83, 0x59, 0x1c, 0xf0, 0xf6, 0x2a, 0x2f, 0x00, 0xa8, 0x22, 0x99, 0xa7, 0xdf, 0xb5, 0xa4, 0xa4,
+	0x3b, 0x66, 0x53, 0x26, 0x22, 0x47, 0xde, 0x6b, 0x79, 0x90, 0x2e, 0xac, 0xa4, 0xe4, 0x91, 0xf3,
+	0xf7, 0xe9, 0x4f, 0xd6, 0xa7, 0xb5, 0x4d, 0x2d, 0x12, 0xe5, 0xa8, 0x92, 0xc7, 0x63, 0x36, 0xed,
+	0x8b, 0x48, 0xdb, 0xa5, 0x07, 0x21, 0x8d, 0x3f, 0x6b, 0x6d, 0x50, 0x25, 0xbd, 0x6d, 0x7a, 0xe1,
+	0xc1, 0xe4, 0x77, 0x17, 0x86, 0x87, 0xc5, 0xb4, 0x7a, 0x6c, 0x3f, 0x52, 0xda, 0xd4, 0xe8, 0xaa,
+	0x89, 0x44, 0xe4, 0xc8, 0xcd, 0xa6, 0xc6, 0xff, 0x76, 0x87, 0x3f, 0x83, 0x9e, 0x25, 0x49, 0x8d,
+	0x75, 0x85, 0x44, 0x22, 0x44, 0xfc, 0x14, 0xfa, 0x59, 0x63, 0x0c, 0x96, 0xd9, 0xc6, 0xd5, 0x10,
+	0x89, 0x5d, 0xcc, 0x2f, 0x21, 0x2e, 0x90, 0xd0, 0xa0, 0x4a, 0xb1, 0x44, 0xb3, 0xda, 0xb8, 0x32,
+	0x98, 0x38, 0x0e, 0x74, 0xe1, 0x20, 0x3f, 0x87, 0xe1, 0x56, 0x46, 0xba, 0x40, 0x57, 0x0c, 0x13,
+	0x83, 0xc0, 0x6e, 0x74, 0x81, 0xfc, 0x15, 0x3c, 0x41, 0x4b, 0xba, 0x90, 0xb4, 0xf7, 0x3a, 0x72,
+	0xb2, 0xd1, 0x8e, 0x07, 0xb7, 0x4b, 0x88, 0xf7, 0x52, 0xe7, 0xd7, 0xf7, 0x87, 0xee, 0xa8, 0x73,
+	0xbc, 0x80, 0x63, 0xed, 0x07, 0xac, 0xfc, 0x7c, 0x22, 0xa7, 0x1a, 0x6e, 0xa1, 0x1b, 0xd1, 0xa1,
+	0xc8, 0x4d, 0x09, 0xdc, 0x94, 0x76, 0x22, 0x37, 0xa8, 0x0f, 0x30, 0xda, 0xb6, 0x2e, 0xf0, 0x64,
+	0x30, 0x66, 0xd3, 0xc1, 0xd5, 0xd9, 0x6c, 0xff, 0x5c, 0x67, 0x0f, 0xbc, 0x2c, 0x11, 0x87, 0x64,
+	0x88, 0xdb, 0x46, 0xe4, 0xd2, 0x52, 0xda, 0xd4, 0xaa, 0xbd, 0x67, 0x32, 0x74, 0xfd, 0x1c, 0xb4,
+	0xec, 0xd6, 0xa3, 0xab, 0x6f, 0x10, 0x07, 0xa7, 0x25, 0x9a, 0x75, 0xfb, 0xd3, 0x47, 0x18, 0x1e,
+	0xee, 0x01, 0xff, 0xeb, 0xd4, 0x07, 0x36, 0xe4, 0x34, 0xf9, 0xd7, 0xb5, 0xde, 0xb0, 0x77, 0x17,
+	0x5f, 0xcf, 0x57, 0x9a, 0xee, 0x9b, 0xbb, 0x59, 0x56, 0x15, 0x73, 0x2b, 0x49, 0x17, 0x15, 0x55,
+	0xf3, 0x55, 0xf5, 0x3a, 0x2f, 0x8b, 0xb9, 0xdf, 0xc9, 0xbb, 0x9e, 0x5b, 0xc6, 0xb7, 0x7f, 0x06,
+	0x00, 0x7f, 0x3f, 0x4a, 0xd3, 0xa4, 0x03, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// SessionServiceClient is the client API for SessionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type SessionServiceClient interface {
+	WatchSession(ctx context.Context, in *WatchSessionRequest, opts ...grpc.CallOption) (SessionService_WatchSessionClient, error)
+}
+
+type sessionServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewSessionServiceClient(cc *grpc.ClientConn) SessionServiceClient {
+	return &sessionServiceClient{cc}
+}
+
+func (c *sessionServiceClient) WatchSession(ctx context.Context, in *WatchSessionRequest, opts ...grpc.CallOption) (SessionService_WatchSessionClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_SessionService_serviceDesc.Streams[0], "/lspsession.SessionService/WatchSession", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &sessionServiceWatchSessionClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SessionService_WatchSessionClient interface {
+	Recv() (*SessionEvent, error)
+	grpc.ClientStream
+}
+
+type sessionServiceWatchSessionClient struct {
+	grpc.ClientStream
+}
+
+func (x *sessionServiceWatchSessionClient) Recv() (*SessionEvent, error) {
+	m := new(SessionEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// SessionServiceServer is the server API for SessionService service.
+type SessionServiceServer interface {
+	WatchSession(*WatchSessionRequest, SessionService_WatchSessionServer) error
+}
+
+// UnimplementedSessionServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedSessionServiceServer struct {
+}
+
+func (*UnimplementedSessionServiceServer) WatchSession(req *WatchSessionRequest, srv SessionService_WatchSessionServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchSession not implemented")
+}
+
+func RegisterSessionServiceServer(s *grpc.Server, srv SessionServiceServer) {
+	s.RegisterService(&_SessionService_serviceDesc, srv)
+}
+
+func _SessionService_WatchSession_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchSessionRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SessionServiceServer).WatchSession(m, &sessionServiceWatchSessionServer{stream})
+}
+
+type SessionService_WatchSessionServer interface {
+	Send(*SessionEvent) error
+	grpc.ServerStream
+}
+
+type sessionServiceWatchSessionServer struct {
+	grpc.ServerStream
+}
+
+func (x *sessionServiceWatchSessionServer) Send(m *SessionEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _SessionService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "lspsession.SessionService",
+	HandlerType: (*SessionServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchSession",
+			Handler:       _SessionService_WatchSession_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "lsprpc/session.proto",
+}
@@ -0,0 +1,42 @@
+package lsp
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/satimoto/go-datastore/pkg/util"
+	lnmUtil "github.com/satimoto/go-lnm/pkg/util"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+)
+
+// METADATA_USER_ID is the request metadata key asserting the user
+// the request is made on behalf of
+const METADATA_USER_ID = "x-lsp-user-id"
+
+type ServiceOption func(*serviceOptions)
+
+type serviceOptions struct {
+	credentials credentials.TransportCredentials
+}
+
+// WithTransportCredentials sets the credentials used to connect to the LSP
+func WithTransportCredentials(transportCredentials credentials.TransportCredentials) ServiceOption {
+	return func(o *serviceOptions) {
+		o.credentials = transportCredentials
+	}
+}
+
+// WithClientCertificate connects to the LSP using mutual TLS. The common name
+// of the certificate is the identity of the client, either api, ocpi or admin.
+func WithClientCertificate(certificate, key, caCertificate string) ServiceOption {
+	transportCredentials, err := lnmUtil.NewClientCredential(certificate, key, caCertificate)
+	util.PanicOnError("LNM223", "Error creating LSP client credentials", err)
+
+	return WithTransportCredentials(transportCredentials)
+}
+
+// WithUserID asserts the user a request is made on behalf of
+func WithUserID(ctx context.Context, userID int64) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, METADATA_USER_ID, strconv.FormatInt(userID, 10))
+}
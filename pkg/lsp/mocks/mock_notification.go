@@ -0,0 +1,65 @@
+package mocks
+
+import (
+	"context"
+	"errors"
+
+	"github.com/satimoto/go-lnm/lsprpc"
+	"google.golang.org/grpc"
+)
+
+func (s *MockLspService) ListUndeliveredNotifications(ctx context.Context, in *lsprpc.ListUndeliveredNotificationsRequest, opts ...grpc.CallOption) (*lsprpc.ListUndeliveredNotificationsResponse, error) {
+	if len(s.listUndeliveredNotificationsMockData) == 0 {
+		return &lsprpc.ListUndeliveredNotificationsResponse{}, errors.New("NotFound")
+	}
+
+	response := s.listUndeliveredNotificationsMockData[0]
+	s.listUndeliveredNotificationsMockData = s.listUndeliveredNotificationsMockData[1:]
+	return response, nil
+}
+
+func (s *MockLspService) SetListUndeliveredNotificationsMockData(mockData *lsprpc.ListUndeliveredNotificationsResponse) {
+	s.listUndeliveredNotificationsMockData = append(s.listUndeliveredNotificationsMockData, mockData)
+}
+
+func (s *MockLspService) CreateWebhook(ctx context.Context, in *lsprpc.CreateWebhookRequest, opts ...grpc.CallOption) (*lsprpc.Webhook, error) {
+	if len(s.createWebhookMockData) == 0 {
+		return &lsprpc.Webhook{}, errors.New("NotFound")
+	}
+
+	response := s.createWebhookMockData[0]
+	s.createWebhookMockData = s.createWebhookMockData[1:]
+	return response, nil
+}
+
+func (s *MockLspService) SetCreateWebhookMockData(mockData *lsprpc.Webhook) {
+	s.createWebhookMockData = append(s.createWebhookMockData, mockData)
+}
+
+func (s *MockLspService) ListWebhooks(ctx context.Context, in *lsprpc.ListWebhooksRequest, opts ...grpc.CallOption) (*lsprpc.ListWebhooksResponse, error) {
+	if len(s.listWebhooksMockData) == 0 {
+		return &lsprpc.ListWebhooksResponse{}, errors.New("NotFound")
+	}
+
+	response := s.listWebhooksMockData[0]
+	s.listWebhooksMockData = s.listWebhooksMockData[1:]
+	return response, nil
+}
+
+func (s *MockLspService) SetListWebhooksMockData(mockData *lsprpc.ListWebhooksResponse) {
+	s.listWebhooksMockData = append(s.listWebhooksMockData, mockData)
+}
+
+func (s *MockLspService) DeleteWebhook(ctx context.Context, in *lsprpc.DeleteWebhookRequest, opts ...grpc.CallOption) (*lsprpc.DeleteWebhookResponse, error) {
+	if len(s.deleteWebhookMockData) == 0 {
+		return &lsprpc.DeleteWebhookResponse{}, errors.New("NotFound")
+	}
+
+	response := s.deleteWebhookMockData[0]
+	s.deleteWebhookMockData = s.deleteWebhookMockData[1:]
+	return response, nil
+}
+
+func (s *MockLspService) SetDeleteWebhookMockData(mockData *lsprpc.DeleteWebhookResponse) {
+	s.deleteWebhookMockData = append(s.deleteWebhookMockData, mockData)
+}
@@ -5,8 +5,12 @@ import (
 )
 
 type MockLspService struct {
-	openChannelMockData  []*lsprpc.OpenChannelResponse
-	listChannelsMockData []*lsprpc.ListChannelsResponse
+	openChannelMockData                  []*lsprpc.OpenChannelResponse
+	listChannelsMockData                 []*lsprpc.ListChannelsResponse
+	listUndeliveredNotificationsMockData []*lsprpc.ListUndeliveredNotificationsResponse
+	createWebhookMockData                []*lsprpc.Webhook
+	listWebhooksMockData                 []*lsprpc.ListWebhooksResponse
+	deleteWebhookMockData                []*lsprpc.DeleteWebhookResponse
 }
 
 func NewService() *MockLspService {
@@ -0,0 +1,59 @@
+package lsp
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/satimoto/go-lnm/lsprpc"
+	"google.golang.org/grpc"
+)
+
+func (s *LspService) ListUndeliveredNotifications(ctx context.Context, in *lsprpc.ListUndeliveredNotificationsRequest, opts ...grpc.CallOption) (*lsprpc.ListUndeliveredNotificationsResponse, error) {
+	timerStart := time.Now()
+	response, err := s.getNotificationClient().ListUndeliveredNotifications(ctx, in, opts...)
+	timerStop := time.Now()
+
+	log.Printf("ListUndeliveredNotifications responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+
+	return response, err
+}
+
+func (s *LspService) CreateWebhook(ctx context.Context, in *lsprpc.CreateWebhookRequest, opts ...grpc.CallOption) (*lsprpc.Webhook, error) {
+	timerStart := time.Now()
+	response, err := s.getNotificationClient().CreateWebhook(ctx, in, opts...)
+	timerStop := time.Now()
+
+	log.Printf("CreateWebhook responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+
+	return response, err
+}
+
+func (s *LspService) ListWebhooks(ctx context.Context, in *lsprpc.ListWebhooksRequest, opts ...grpc.CallOption) (*lsprpc.ListWebhooksResponse, error) {
+	timerStart := time.Now()
+	response, err := s.getNotificationClient().ListWebhooks(ctx, in, opts...)
+	timerStop := time.Now()
+
+	log.Printf("ListWebhooks responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+
+	return response, err
+}
+
+func (s *LspService) DeleteWebhook(ctx context.Context, in *lsprpc.DeleteWebhookRequest, opts ...grpc.CallOption) (*lsprpc.DeleteWebhookResponse, error) {
+	timerStart := time.Now()
+	response, err := s.getNotificationClient().DeleteWebhook(ctx, in, opts...)
+	timerStop := time.Now()
+
+	log.Printf("DeleteWebhook responded in %f seconds", timerStop.Sub(timerStart).Seconds())
+
+	return response, err
+}
+
+func (s *LspService) getNotificationClient() lsprpc.NotificationServiceClient {
+	if s.notificationClient == nil {
+		client := lsprpc.NewNotificationServiceClient(s.clientConn)
+		s.notificationClient = &client
+	}
+
+	return *s.notificationClient
+}
@@ -16,17 +16,30 @@ type Lsp interface {
 	ListChannels(ctx context.Context, in *lsprpc.ListChannelsRequest, opts ...grpc.CallOption) (*lsprpc.ListChannelsResponse, error)
 	UpdateInvoiceRequest(ctx context.Context, in *lsprpc.UpdateInvoiceRequestRequest, opts ...grpc.CallOption) (*lsprpc.UpdateInvoiceRequestResponse, error)
 	UpdateSessionInvoice(ctx context.Context, in *lsprpc.UpdateSessionInvoiceRequest, opts ...grpc.CallOption) (*lsprpc.UpdateSessionInvoiceResponse, error)
+	ListUndeliveredNotifications(ctx context.Context, in *lsprpc.ListUndeliveredNotificationsRequest, opts ...grpc.CallOption) (*lsprpc.ListUndeliveredNotificationsResponse, error)
+	CreateWebhook(ctx context.Context, in *lsprpc.CreateWebhookRequest, opts ...grpc.CallOption) (*lsprpc.Webhook, error)
+	ListWebhooks(ctx context.Context, in *lsprpc.ListWebhooksRequest, opts ...grpc.CallOption) (*lsprpc.ListWebhooksResponse, error)
+	DeleteWebhook(ctx context.Context, in *lsprpc.DeleteWebhookRequest, opts ...grpc.CallOption) (*lsprpc.DeleteWebhookResponse, error)
 }
 
 type LspService struct {
-	clientConn    *grpc.ClientConn
-	channelClient *lsprpc.ChannelServiceClient
-	invoiceClient *lsprpc.InvoiceServiceClient
+	clientConn         *grpc.ClientConn
+	channelClient      *lsprpc.ChannelServiceClient
+	invoiceClient      *lsprpc.InvoiceServiceClient
+	notificationClient *lsprpc.NotificationServiceClient
 }
 
-func NewService(address string) Lsp {
+func NewService(address string, options ...ServiceOption) Lsp {
+	serviceOptions := &serviceOptions{
+		credentials: insecure.NewCredentials(),
+	}
+
+	for _, option := range options {
+		option(serviceOptions)
+	}
+
 	timerStart := time.Now()
-	clientConn, err := grpc.Dial(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	clientConn, err := grpc.Dial(address, grpc.WithTransportCredentials(serviceOptions.credentials))
 	timerStop := time.Now()
 
 	util.PanicOnError("LNM108", "Error connecting to LSP RPC address", err)
@@ -0,0 +1,39 @@
+package util
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Backoff returns exponentially increasing delays between retries,
+// with jitter so retries from many clients do not align
+type Backoff struct {
+	min     time.Duration
+	max     time.Duration
+	current time.Duration
+}
+
+func NewBackoff(min, max time.Duration) *Backoff {
+	return &Backoff{
+		min: min,
+		max: max,
+	}
+}
+
+// Next returns the delay before the next retry
+func (b *Backoff) Next() time.Duration {
+	if b.current == 0 {
+		b.current = b.min
+	} else if b.current = b.current * 2; b.current > b.max {
+		b.current = b.max
+	}
+
+	jitter := time.Duration(rand.Int63n(int64(b.current)/5 + 1))
+
+	return b.current - jitter
+}
+
+// Reset starts the delays again from the minimum, call it after a successful attempt
+func (b *Backoff) Reset() {
+	b.current = 0
+}
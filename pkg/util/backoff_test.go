@@ -0,0 +1,27 @@
+package util_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/satimoto/go-lnm/pkg/util"
+)
+
+func TestBackoff(t *testing.T) {
+	backoff := util.NewBackoff(time.Second, 5*time.Second)
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+
+	for i, max := range expected {
+		delay := backoff.Next()
+
+		if delay > max || delay < max-max/5 {
+			t.Errorf("Delay %v mismatch: %v expecting %v less jitter", i, delay, max)
+		}
+	}
+
+	backoff.Reset()
+
+	if delay := backoff.Next(); delay > time.Second {
+		t.Errorf("Delay mismatch after reset: %v expecting %v", delay, time.Second)
+	}
+}
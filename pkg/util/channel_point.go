@@ -23,4 +23,8 @@ func ConvertChannelPoint(op string) ([]byte, uint32, error) {
 		return nil, 0, fmt.Errorf("invalid output index: %v", err)
 	}
 	return ReverseBytes(txid), uint32(outputIndex), nil
-}
\ No newline at end of file
+}
+
+func FormatChannelPoint(txid []byte, outputIndex uint32) string {
+	return fmt.Sprintf("%s:%d", hex.EncodeToString(ReverseBytes(txid)), outputIndex)
+}
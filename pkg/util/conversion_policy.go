@@ -0,0 +1,118 @@
+package util
+
+import (
+	"database/sql"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	dbUtil "github.com/satimoto/go-datastore/pkg/util"
+)
+
+type RoundingMode string
+
+const (
+	ROUNDING_MODE_MSAT    RoundingMode = "MSAT"
+	ROUNDING_MODE_SAT_UP  RoundingMode = "SAT_UP"
+	ROUNDING_MODE_DEFAULT RoundingMode = ROUNDING_MODE_MSAT
+)
+
+type ConversionPolicy struct {
+	SpreadPpm         int64
+	CurrencySpreadPpm map[string]int64
+	RoundingMode      RoundingMode
+	MinInvoiceMsat    int64
+}
+
+func NewConversionPolicy() *ConversionPolicy {
+	currencySpreadPpm := make(map[string]int64)
+
+	// Currency spreads are configured as a list of CURRENCY:PPM pairs
+	for _, value := range GetEnvStrings("FX_CURRENCY_SPREAD_PPM") {
+		if pair := strings.SplitN(value, ":", 2); len(pair) == 2 {
+			if spreadPpm, err := strconv.ParseInt(strings.TrimSpace(pair[1]), 10, 64); err == nil {
+				currencySpreadPpm[strings.ToUpper(strings.TrimSpace(pair[0]))] = spreadPpm
+			}
+		}
+	}
+
+	roundingMode := RoundingMode(strings.ToUpper(os.Getenv("FX_ROUNDING_MODE")))
+
+	if roundingMode != ROUNDING_MODE_MSAT && roundingMode != ROUNDING_MODE_SAT_UP {
+		roundingMode = ROUNDING_MODE_DEFAULT
+	}
+
+	return &ConversionPolicy{
+		SpreadPpm:         int64(dbUtil.GetEnvInt32("FX_SPREAD_PPM", 0)),
+		CurrencySpreadPpm: currencySpreadPpm,
+		RoundingMode:      roundingMode,
+		MinInvoiceMsat:    int64(dbUtil.GetEnvInt32("FX_MIN_INVOICE_MSAT", 0)),
+	}
+}
+
+func (p *ConversionPolicy) GetSpreadPpm(currency string) int64 {
+	if spreadPpm, ok := p.CurrencySpreadPpm[strings.ToUpper(currency)]; ok {
+		return spreadPpm
+	}
+
+	return p.SpreadPpm
+}
+
+func (p *ConversionPolicy) GetRateMsat(currency string, rateMsat int64) float64 {
+	return float64(rateMsat) * (1 + float64(p.GetSpreadPpm(currency))/1000000)
+}
+
+func (p *ConversionPolicy) ConvertFiat(currency string, amountFiat float64, rateMsat int64) int64 {
+	return p.round(amountFiat * p.GetRateMsat(currency, rateMsat))
+}
+
+func (p *ConversionPolicy) FillInvoiceRequestParams(invoiceParams InvoiceParams, rateMsat int64) InvoiceParams {
+	spreadRateMsat := p.GetRateMsat(invoiceParams.Currency, rateMsat)
+
+	invoiceParams.PriceFiat, invoiceParams.PriceMsat = p.fillInvoiceRequestParam(invoiceParams.PriceFiat, invoiceParams.PriceMsat, spreadRateMsat)
+	invoiceParams.CommissionFiat, invoiceParams.CommissionMsat = p.fillInvoiceRequestParam(invoiceParams.CommissionFiat, invoiceParams.CommissionMsat, spreadRateMsat)
+	invoiceParams.TaxFiat, invoiceParams.TaxMsat = p.fillInvoiceRequestParam(invoiceParams.TaxFiat, invoiceParams.TaxMsat, spreadRateMsat)
+	invoiceParams.TotalFiat, invoiceParams.TotalMsat = p.fillInvoiceRequestParam(invoiceParams.TotalFiat, invoiceParams.TotalMsat, spreadRateMsat)
+
+	if invoiceParams.TotalMsat.Valid && invoiceParams.TotalMsat.Int64 > 0 && invoiceParams.TotalMsat.Int64 < p.MinInvoiceMsat {
+		// Scale the components with the total so they still add up to it
+		scale := float64(p.MinInvoiceMsat) / float64(invoiceParams.TotalMsat.Int64)
+
+		invoiceParams.PriceMsat = scaleNullInt64(invoiceParams.PriceMsat, scale)
+		invoiceParams.CommissionMsat = scaleNullInt64(invoiceParams.CommissionMsat, scale)
+		invoiceParams.TaxMsat = scaleNullInt64(invoiceParams.TaxMsat, scale)
+		invoiceParams.TotalMsat = dbUtil.SqlNullInt64(p.MinInvoiceMsat)
+	}
+
+	return invoiceParams
+}
+
+func (p *ConversionPolicy) fillInvoiceRequestParam(amountFiat sql.NullFloat64, amountMsat sql.NullInt64, rateMsat float64) (sql.NullFloat64, sql.NullInt64) {
+	if amountMsat.Valid && !amountFiat.Valid {
+		amountFiat = dbUtil.SqlNullFloat64(float64(amountMsat.Int64) / rateMsat)
+	}
+
+	if amountFiat.Valid && !amountMsat.Valid {
+		amountMsat = dbUtil.SqlNullInt64(p.round(amountFiat.Float64 * rateMsat))
+	}
+
+	return amountFiat, amountMsat
+}
+
+func (p *ConversionPolicy) round(amountMsat float64) int64 {
+	switch p.RoundingMode {
+	case ROUNDING_MODE_SAT_UP:
+		return int64(math.Ceil(amountMsat/1000)) * 1000
+	default:
+		return int64(math.Round(amountMsat))
+	}
+}
+
+func scaleNullInt64(amountMsat sql.NullInt64, scale float64) sql.NullInt64 {
+	if amountMsat.Valid {
+		return dbUtil.SqlNullInt64(int64(math.Round(float64(amountMsat.Int64) * scale)))
+	}
+
+	return amountMsat
+}
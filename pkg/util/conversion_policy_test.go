@@ -0,0 +1,88 @@
+package util_test
+
+import (
+	"testing"
+
+	dbUtil "github.com/satimoto/go-datastore/pkg/util"
+	"github.com/satimoto/go-lnm/pkg/util"
+)
+
+func TestFillInvoiceRequestParams(t *testing.T) {
+	cases := []struct {
+		desc      string
+		policy    util.ConversionPolicy
+		currency  string
+		priceFiat float64
+		totalFiat float64
+		priceMsat int64
+		totalMsat int64
+		spreadPpm int64
+	}{{
+		desc:      "No spread",
+		policy:    util.ConversionPolicy{RoundingMode: util.ROUNDING_MODE_MSAT},
+		currency:  "EUR",
+		priceFiat: 0.3852,
+		totalFiat: 0.49047,
+		priceMsat: 1733400,
+		totalMsat: 2207115,
+	}, {
+		desc: "Currency spread",
+		policy: util.ConversionPolicy{
+			SpreadPpm:         10000,
+			CurrencySpreadPpm: map[string]int64{"EUR": 20000},
+			RoundingMode:      util.ROUNDING_MODE_MSAT,
+		},
+		currency:  "EUR",
+		priceFiat: 0.3852,
+		totalFiat: 0.49047,
+		priceMsat: 1768068,
+		totalMsat: 2251257,
+		spreadPpm: 20000,
+	}, {
+		desc: "Default spread rounded up to sat",
+		policy: util.ConversionPolicy{
+			SpreadPpm:         10000,
+			CurrencySpreadPpm: map[string]int64{"EUR": 20000},
+			RoundingMode:      util.ROUNDING_MODE_SAT_UP,
+		},
+		currency:  "USD",
+		priceFiat: 0.3852,
+		totalFiat: 0.49047,
+		priceMsat: 1751000,
+		totalMsat: 2230000,
+		spreadPpm: 10000,
+	}, {
+		desc: "Minimum invoice amount",
+		policy: util.ConversionPolicy{
+			RoundingMode:   util.ROUNDING_MODE_MSAT,
+			MinInvoiceMsat: 5000000,
+		},
+		currency:  "EUR",
+		priceFiat: 0.3852,
+		totalFiat: 0.49047,
+		priceMsat: 3926846,
+		totalMsat: 5000000,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.desc, func(t *testing.T) {
+			invoiceParams := tc.policy.FillInvoiceRequestParams(util.InvoiceParams{
+				Currency:  tc.currency,
+				PriceFiat: dbUtil.SqlNullFloat64(tc.priceFiat),
+				TotalFiat: dbUtil.SqlNullFloat64(tc.totalFiat),
+			}, 4500000)
+
+			if invoiceParams.PriceMsat.Int64 != tc.priceMsat {
+				t.Errorf("Price mismatch: %v expecting %v", invoiceParams.PriceMsat.Int64, tc.priceMsat)
+			}
+
+			if invoiceParams.TotalMsat.Int64 != tc.totalMsat {
+				t.Errorf("Total mismatch: %v expecting %v", invoiceParams.TotalMsat.Int64, tc.totalMsat)
+			}
+
+			if spreadPpm := tc.policy.GetSpreadPpm(tc.currency); spreadPpm != tc.spreadPpm {
+				t.Errorf("Spread mismatch: %v expecting %v", spreadPpm, tc.spreadPpm)
+			}
+		})
+	}
+}
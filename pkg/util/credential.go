@@ -1,6 +1,7 @@
 package util
 
 import (
+	"crypto/tls"
 	"crypto/x509"
 	"fmt"
 	"strings"
@@ -9,11 +10,71 @@ import (
 )
 
 func NewCredential(certificate string) (credentials.TransportCredentials, error) {
+	certPool, err := newCertPool(certificate)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials.NewClientTLSFromCert(certPool, ""), nil
+}
+
+// NewClientCredential creates mutual TLS client credentials presenting the
+// certificate and key, verifying the server against the CA certificate
+func NewClientCredential(certificate, key, caCertificate string) (credentials.TransportCredentials, error) {
+	keyPair, err := tls.X509KeyPair(decodePem(certificate), decodePem(key))
+
+	if err != nil {
+		return nil, err
+	}
+
+	certPool, err := newCertPool(caCertificate)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{keyPair},
+		RootCAs:      certPool,
+		MinVersion:   tls.VersionTLS12,
+	}), nil
+}
+
+// NewServerCredential creates mutual TLS server credentials presenting the
+// certificate and key, requiring client certificates signed by the client CA certificate
+func NewServerCredential(certificate, key, clientCaCertificate string) (credentials.TransportCredentials, error) {
+	keyPair, err := tls.X509KeyPair(decodePem(certificate), decodePem(key))
+
+	if err != nil {
+		return nil, err
+	}
+
+	certPool, err := newCertPool(clientCaCertificate)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{keyPair},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    certPool,
+		MinVersion:   tls.VersionTLS12,
+	}), nil
+}
+
+func newCertPool(certificate string) (*x509.CertPool, error) {
 	certPool := x509.NewCertPool()
 
-	if !certPool.AppendCertsFromPEM([]byte(strings.Replace(certificate, "\\n", "\n", -1))) {
+	if !certPool.AppendCertsFromPEM(decodePem(certificate)) {
 		return nil, fmt.Errorf("Error appending certificates")
 	}
 
-	return credentials.NewClientTLSFromCert(certPool, ""), nil
+	return certPool, nil
+}
+
+// decodePem restores newlines in PEM data escaped for env variables
+func decodePem(pem string) []byte {
+	return []byte(strings.Replace(pem, "\\n", "\n", -1))
 }
@@ -0,0 +1,50 @@
+package util
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func GetEnvDurations(key string, defaultValue []time.Duration) []time.Duration {
+	values := GetEnvStrings(key)
+
+	if len(values) == 0 {
+		return defaultValue
+	}
+
+	durations := []time.Duration{}
+
+	for _, value := range values {
+		if duration, err := time.ParseDuration(value); err == nil {
+			durations = append(durations, duration)
+		}
+	}
+
+	return durations
+}
+
+func GetEnvStrings(key string) []string {
+	list := []string{}
+
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); len(value) > 0 {
+			list = append(list, value)
+		}
+	}
+
+	return list
+}
+
+func GetEnvUint64s(key string) []uint64 {
+	list := []uint64{}
+
+	for _, value := range GetEnvStrings(key) {
+		if i, err := strconv.ParseUint(value, 10, 64); err == nil {
+			list = append(list, i)
+		}
+	}
+
+	return list
+}
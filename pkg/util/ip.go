@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"os"
 )
 
 func GetIPAddress() (string, error) {
@@ -29,6 +30,24 @@ func GetIPAddress() (string, error) {
 	return "", errors.New("No IP address found")
 }
 
+// GetRpcAddress returns the address the RPC service is reachable at,
+// using RPC_HOST or else the IP address of the host
+func GetRpcAddress() (string, error) {
+	rpcHost := os.Getenv("RPC_HOST")
+
+	if len(rpcHost) == 0 {
+		ipAddr, err := GetIPAddress()
+
+		if err != nil {
+			return "", err
+		}
+
+		rpcHost = ipAddr
+	}
+
+	return fmt.Sprintf("%s:%s", rpcHost, os.Getenv("RPC_PORT")), nil
+}
+
 func formatIPAddress(ip net.IP) string {
 	if ip.To4() == nil {
 		return fmt.Sprintf("[%s]", ip.String())
@@ -1,2 +1,4 @@
 protoc lsprpc/channel.proto --go_out=plugins=grpc:$GOPATH/src
 protoc lsprpc/invoice.proto --go_out=plugins=grpc:$GOPATH/src
+protoc lsprpc/notification.proto --go_out=plugins=grpc:$GOPATH/src
+protoc lsprpc/session.proto --go_out=plugins=grpc:$GOPATH/src
@@ -112,6 +112,14 @@ data "aws_ssm_parameter" "lnd_macaroon" {
   name = var.lnm_lnd_macaroon_ssm_key
 }
 
+data "aws_ssm_parameter" "fcm_service_account" {
+  name = var.lnm_fcm_service_account_ssm_key
+}
+
+data "aws_ssm_parameter" "apns_key" {
+  name = var.lnm_apns_key_ssm_key
+}
+
 module "service-lnm" {
   source             = "git::https://github.com/satimoto/terraform-infrastructure.git//modules/service?ref=f9cad99f17c1d7c14273b9433e249922a2b92544"
   availability_zones = var.availability_zones
@@ -152,6 +160,10 @@ module "service-lnm" {
     rpc_container_port               = var.env_rpc_port
     task_network_mode                = var.task_network_mode
     env_accounting_currency          = var.env_accounting_currency
+    env_apns_key                     = data.aws_ssm_parameter.apns_key.value
+    env_apns_key_id                  = var.env_apns_key_id
+    env_apns_team_id                 = var.env_apns_team_id
+    env_apns_topic                   = var.env_apns_topic
     env_backup_aws_region            = var.region
     env_backup_aws_access_key_id     = aws_iam_access_key.backup_saccess_key.id
     env_backup_aws_secret_access_key = aws_iam_access_key.backup_saccess_key.secret
@@ -162,7 +174,7 @@ module "service-lnm" {
     env_db_host                      = "${data.terraform_remote_state.infrastructure.outputs.rds_cluster_endpoint}:${data.terraform_remote_state.infrastructure.outputs.rds_cluster_port}"
     env_db_name                      = "satimoto"
     env_default_tax_percent          = var.env_default_tax_percent
-    env_fcm_api_key                  = var.env_fcm_api_key
+    env_fcm_service_account_json     = data.aws_ssm_parameter.fcm_service_account.value
     env_ferp_rpc_address             = "ferp.${data.terraform_remote_state.infrastructure.outputs.ecs_service_discovery_namespace_name}:${var.env_ferp_rpc_port}"
     env_lnd_tls_cert                 = var.env_lnd_tls_cert
     env_lnd_grpc_host                = var.env_lnd_grpc_host
@@ -30,6 +30,14 @@ variable "lnm_lnd_macaroon_ssm_key" {
   description = "Systems Manager key where the macaroon for LND is stored"
 }
 
+variable "lnm_fcm_service_account_ssm_key" {
+  description = "Systems Manager key where the FCM service account JSON is stored"
+}
+
+variable "lnm_apns_key_ssm_key" {
+  description = "Systems Manager key where the APNs .p8 key is stored"
+}
+
 variable "service_name" {
   description = "The name of the service"
 }
@@ -90,8 +98,16 @@ variable "env_default_tax_percent" {
   description = "The environment variable to set the default tax percent"
 }
 
-variable "env_fcm_api_key" {
-  description = "The environment variable to set the FCM API key"
+variable "env_apns_key_id" {
+  description = "The environment variable to set the APNs key ID"
+}
+
+variable "env_apns_team_id" {
+  description = "The environment variable to set the APNs team ID"
+}
+
+variable "env_apns_topic" {
+  description = "The environment variable to set the APNs topic"
 }
 
 variable "env_ferp_rpc_port" {